@@ -0,0 +1,105 @@
+package csa
+
+// Move of a game played by an engine with the score of its search for the maximizing player
+type ScoredMove struct {
+	Maximizing bool // player who played the move
+	Score      int
+}
+
+// Result of a game ended before its terminal node
+type Adjudication struct {
+	WDL    int    // for the maximizing player, 1 for win, 0 for draw, -1 for loss
+	Reason string // resignation, draw or tablebase
+}
+
+// Ends the games of engines early by the scores of their searches, e.g. in the match runners
+// Zero value never adjudicates.
+type Adjudicator struct {
+	// A player resigns after ResignMoves own moves in a row scored at most -ResignScore for it, zero moves disable it
+	ResignScore int
+	ResignMoves int
+	// The game is a draw after DrawMoves moves in a row of both players scored within ±DrawScore, zero moves disable it
+	DrawScore int
+	DrawMoves int
+	// Moves played before the draw can be adjudicated
+	DrawStart int
+	// Optional tablebase ending the games in its positions with its result
+	Tablebase TablebaseProber
+	// Only PieceCountNode nodes with at most this number of pieces are probed, all nodes if zero
+	TablebasePieces int
+}
+
+// Adjudication of the game in the node after the played moves, false if the game goes on
+func (adjudicator Adjudicator) Adjudicate(node SearchNode, history []ScoredMove) (Adjudication, bool) {
+	if node.IsTerminal() {
+		return Adjudication{}, false
+	}
+	if result, ok := adjudicator.probe(node); ok {
+		return Adjudication{WDL: result.WDL, Reason: "tablebase"}, true
+	}
+	if len(history) == 0 {
+		return Adjudication{}, false
+	}
+	// the player of the last move first, its score has just changed
+	last := history[len(history)-1].Maximizing
+	for _, maximizing := range []bool{last, !last} {
+		if adjudicator.resigns(history, maximizing) {
+			wdl := -1
+			if !maximizing {
+				wdl = 1
+			}
+			return Adjudication{WDL: wdl, Reason: "resignation"}, true
+		}
+	}
+	if adjudicator.isDraw(history) {
+		return Adjudication{WDL: 0, Reason: "draw"}, true
+	}
+	return Adjudication{}, false
+}
+
+func (adjudicator Adjudicator) probe(node SearchNode) (TablebaseResult, bool) {
+	if adjudicator.Tablebase == nil {
+		return TablebaseResult{}, false
+	}
+	if adjudicator.TablebasePieces > 0 {
+		if pieceNode, ok := node.(PieceCountNode); !ok || pieceNode.PieceCount() > adjudicator.TablebasePieces {
+			return TablebaseResult{}, false
+		}
+	}
+	return adjudicator.Tablebase.Probe(node)
+}
+
+// Whether the last ResignMoves moves of the player are scored as lost for it
+func (adjudicator Adjudicator) resigns(history []ScoredMove, maximizing bool) bool {
+	if adjudicator.ResignMoves <= 0 {
+		return false
+	}
+	moves := 0
+	for i := len(history) - 1; i >= 0 && moves < adjudicator.ResignMoves; i-- {
+		if history[i].Maximizing != maximizing {
+			continue
+		}
+		score := history[i].Score
+		if !maximizing {
+			score = -score
+		}
+		if score > -adjudicator.ResignScore {
+			return false
+		}
+		moves++
+	}
+	return moves == adjudicator.ResignMoves
+}
+
+// Whether the last DrawMoves moves are scored close to the draw
+func (adjudicator Adjudicator) isDraw(history []ScoredMove) bool {
+	if adjudicator.DrawMoves <= 0 || len(history) < max(adjudicator.DrawMoves, adjudicator.DrawStart) {
+		return false
+	}
+	for _, move := range history[len(history)-adjudicator.DrawMoves:] {
+		if move.Score > adjudicator.DrawScore || move.Score < -adjudicator.DrawScore {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package csa
+
+import "testing"
+
+func TestTTTAdjudicator(t *testing.T) {
+	adjudicator := Adjudicator{ResignScore: 50, ResignMoves: 2, DrawScore: 5, DrawMoves: 4, DrawStart: 6}
+	node := tttNode{}
+	if _, ok := adjudicator.Adjudicate(node, nil); ok {
+		t.Error("Expected no adjudication of the empty history")
+	}
+	history := []ScoredMove{{true, 10}, {false, -60}, {true, 70}, {false, 60}}
+	if _, ok := adjudicator.Adjudicate(node, history); ok {
+		t.Error("Expected no resignation after a single lost move of the minimizing player")
+	}
+	history = append(history, ScoredMove{true, 80}, ScoredMove{false, 90})
+	if adjudication, ok := adjudicator.Adjudicate(node, history); !ok || adjudication != (Adjudication{1, "resignation"}) {
+		t.Errorf("Expected the resignation of the minimizing player, got %+v", adjudication)
+	}
+	// scores near zero, but not enough moves played
+	history = []ScoredMove{{true, 1}, {false, -3}, {true, 5}, {false, 0}}
+	if _, ok := adjudicator.Adjudicate(node, history); ok {
+		t.Error("Expected no draw before DrawStart")
+	}
+	history = append(history, ScoredMove{true, 2}, ScoredMove{false, -5})
+	if adjudication, ok := adjudicator.Adjudicate(node, history); !ok || adjudication != (Adjudication{0, "draw"}) {
+		t.Errorf("Expected the draw, got %+v", adjudication)
+	}
+	if _, ok := adjudicator.Adjudicate(node, append(history, ScoredMove{true, 6})); ok {
+		t.Error("Expected no draw with the score outside DrawScore")
+	}
+	if _, ok := (Adjudicator{}).Adjudicate(node, history); ok {
+		t.Error("Expected zero Adjudicator to never adjudicate")
+	}
+
+	adjudicator = Adjudicator{Tablebase: &tttTablebase{}}
+	// circle has two in the first column and moves
+	node = tttNode{board: [3][3]int{{circle, cross, cross}, {circle, circle, cross}, {empty, empty, empty}}}
+	if adjudication, ok := adjudicator.Adjudicate(node, nil); !ok || adjudication != (Adjudication{1, "tablebase"}) {
+		t.Errorf("Expected the tablebase win, got %+v", adjudication)
+	}
+	node.board[1][1] = empty
+	if _, ok := adjudicator.Adjudicate(node, nil); ok {
+		t.Error("Expected no adjudication outside the tablebase")
+	}
+}
@@ -0,0 +1,162 @@
+package csa
+
+import (
+	"math"
+	"sort"
+)
+
+// Root child with its exact score
+type ScoredNode struct {
+	Node  SearchNode
+	Score int
+}
+
+// Every root child with its exact alpha-beta score, sorted from the best one for the player to move
+// Equally scored children keep the generator order.
+func AnalyzeRoot(node SearchNode, depth int, maximizing bool) []ScoredNode {
+	return Searcher{}.AnalyzeRoot(node, depth, maximizing)
+}
+
+func (s Searcher) AnalyzeRoot(node SearchNode, depth int, maximizing bool) []ScoredNode {
+	variations := s.analyzeRoot(node, depth, maximizing, false)
+	children := make([]ScoredNode, len(variations))
+	for i, variation := range variations {
+		children[i] = variation.ScoredNode
+	}
+	return children
+}
+
+// Root child with its exact score and the line expected to follow it
+// Line of a rejected child starts with the opponent's refutation.
+type Variation struct {
+	ScoredNode
+	Line []SearchNode
+}
+
+// Best root child and up to given number of the next best alternatives explaining why they are worse
+func Hint(node SearchNode, depth int, maximizing bool, alternatives int) (Variation, []Variation) {
+	return Searcher{}.Hint(node, depth, maximizing, alternatives)
+}
+
+func (s Searcher) Hint(node SearchNode, depth int, maximizing bool, alternatives int) (Variation, []Variation) {
+	variations := s.analyzeRoot(node, depth, maximizing, true)
+	if len(variations) == 0 {
+		return Variation{}, nil
+	}
+	return variations[0], variations[1:min(len(variations), alternatives+1)]
+}
+
+func (s *Searcher) analyzeRoot(node SearchNode, depth int, maximizing, lines bool) []Variation {
+	if depth <= 0 || s.isTerminal(node) {
+		return nil
+	}
+	maximizing = playerToMove(node, maximizing)
+	search := s.newSearch()
+	if lines {
+		search.pv = &pvTable{}
+	}
+	return search.rootVariations(node, depth, maximizing)
+}
+
+// Root children of the node scored by the search sorted from the best, aborted with the searcher's limits
+func (s *Searcher) rootVariations(node SearchNode, depth int, maximizing bool) []Variation {
+	s.pushPath(node)
+	defer s.popPath(node)
+	score := s.interiorScore(node, nil, 0)
+	var variations []Variation
+	for generator := nodeGenerator(node); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			break
+		}
+		// full window, so every score is exact
+		_, childScore := s.minimaxAlphaBetaPrunningImpl(childNode, node, score, depth-1, 1, math.MinInt, math.MaxInt, nextPlayer(childNode, maximizing))
+		variations = append(variations, Variation{ScoredNode{childNode, childScore}, s.pv.line(1)})
+	}
+	sort.SliceStable(variations, func(i, j int) bool {
+		if maximizing {
+			return variations[i].Score > variations[j].Score
+		}
+		return variations[i].Score < variations[j].Score
+	})
+	return variations
+}
+
+// Classification of a played move by its loss against the best move
+type MoveClass int
+
+const (
+	MoveOk MoveClass = iota
+	MoveInaccuracy
+	MoveMistake
+	MoveBlunder
+)
+
+func (class MoveClass) String() string {
+	return [...]string{"ok", "inaccuracy", "mistake", "blunder"}[class]
+}
+
+// Minimal losses of the move classes in units of node's score, zero threshold is not used
+// Without any threshold every loss is an inaccuracy.
+type MoveThresholds struct {
+	Inaccuracy int
+	Mistake    int
+	Blunder    int
+}
+
+func (thresholds MoveThresholds) Classify(loss int) MoveClass {
+	switch {
+	case loss <= 0:
+		return MoveOk
+	case thresholds.Blunder > 0 && loss >= thresholds.Blunder:
+		return MoveBlunder
+	case thresholds.Mistake > 0 && loss >= thresholds.Mistake:
+		return MoveMistake
+	case loss >= thresholds.Inaccuracy:
+		return MoveInaccuracy
+	}
+	return MoveOk
+}
+
+// Played move compared to the best one
+type MoveEvaluation struct {
+	Best   ScoredNode
+	Played ScoredNode
+	Loss   int // how much worse the played move is for the player who played it
+	Class  MoveClass
+}
+
+// Compares the played child of the node with the best one, both searched to the given depth from the node
+func EvaluateMove(node, played SearchNode, depth int, maximizing bool, thresholds MoveThresholds) MoveEvaluation {
+	return Searcher{}.EvaluateMove(node, played, depth, maximizing, thresholds)
+}
+
+func (s Searcher) EvaluateMove(node, played SearchNode, depth int, maximizing bool, thresholds MoveThresholds) MoveEvaluation {
+	maximizing = playerToMove(node, maximizing)
+	bestNode, bestScore := s.MinimaxAlphaBetaPrunning(node, depth, maximizing)
+	s = s.newSearch()
+	s.pushPath(node)
+	defer s.popPath(node)
+	_, playedScore := s.minimaxAlphaBetaPrunningImpl(played, node, s.interiorScore(node, nil, 0), depth-1, 1, math.MinInt, math.MaxInt, nextPlayer(played, maximizing))
+	loss := saturatingSub(bestScore, playedScore)
+	if !maximizing {
+		loss = saturatingSub(playedScore, bestScore)
+	}
+	return MoveEvaluation{
+		Best:   ScoredNode{bestNode, bestScore},
+		Played: ScoredNode{played, playedScore},
+		Loss:   max(loss, 0),
+		Class:  thresholds.Classify(loss),
+	}
+}
+
+// Difference clamped to the int range, e.g. of the extreme no-move scores
+func saturatingSub(a, b int) int {
+	switch {
+	case b < 0 && a > math.MaxInt+b:
+		return math.MaxInt
+	case b > 0 && a < math.MinInt+b:
+		return math.MinInt
+	}
+	return a - b
+}
@@ -0,0 +1,114 @@
+package csa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTTTAnalyzeRoot(t *testing.T) {
+	node := tttNode{}
+	node.board[0][0] = cross
+	node.board[1][1] = circle
+	node.board[2][2] = cross
+	children := AnalyzeRoot(node, 9, true)
+	if len(children) != 6 {
+		t.Fatalf("Expected all six moves, got %d", len(children))
+	}
+	_, best := Minimax(node, 9, true)
+	if children[0].Score != best {
+		t.Errorf("Expected best score %d first, got %d", best, children[0].Score)
+	}
+	for i, child := range children {
+		if _, score := Minimax(child.Node, 8, false); score != child.Score {
+			t.Errorf("Expected exact score %d, got %d %s", score, child.Score, child.Node)
+		}
+		if i > 0 && child.Score > children[i-1].Score {
+			t.Error("Children are not sorted")
+		}
+	}
+	// corners lose, edges draw
+	if children[4].Score >= 0 || children[3].Score != 0 {
+		t.Errorf("Expected four draws and two losses %v", children)
+	}
+}
+
+func TestTTTHint(t *testing.T) {
+	node := tttNode{}
+	node.board[0][0] = cross
+	node.board[1][1] = circle
+	node.board[2][2] = cross
+	best, alternatives := Hint(node, 9, true, 3)
+	if best.Score != 0 || len(best.Line) != 5 || len(alternatives) != 3 {
+		t.Fatalf("Expected drawing move with the line to the end and three alternatives %+v", best)
+	}
+	// corner move is refuted by the other corner with a double threat
+	_, alternatives = Hint(node, 9, true, 5)
+	refuted := alternatives[len(alternatives)-1]
+	if refuted.Score >= 0 || !refuted.Line[len(refuted.Line)-1].IsTerminal() {
+		t.Fatalf("Expected losing alternative %+v", refuted)
+	}
+	reply := refuted.Line[0].(tttNode)
+	if reply.board[0][2] != cross && reply.board[2][0] != cross {
+		t.Errorf("Expected corner refutation %s", reply)
+	}
+}
+
+func TestTTTEvaluateMove(t *testing.T) {
+	node := tttNode{}
+	node.board[0][0] = cross
+	node.board[1][1] = circle
+	node.board[2][2] = cross
+	thresholds := MoveThresholds{Inaccuracy: 1, Mistake: 2, Blunder: 3}
+	edge, corner := node, node
+	edge.board[0][1] = circle
+	corner.board[0][2] = circle
+	if evaluation := EvaluateMove(node, edge, 9, true, thresholds); evaluation.Class != MoveOk || evaluation.Loss != 0 {
+		t.Errorf("Edge holds the draw %+v", evaluation)
+	}
+	evaluation := EvaluateMove(node, corner, 9, true, thresholds)
+	if evaluation.Class != MoveBlunder || evaluation.Loss != -evaluation.Played.Score || evaluation.Best.Score != 0 {
+		t.Errorf("Corner loses %+v", evaluation)
+	}
+	if class := (MoveThresholds{}).Classify(evaluation.Loss); class != MoveInaccuracy {
+		t.Errorf("Expected inaccuracy without thresholds, got %s", class)
+	}
+}
+
+// Non-terminal node, the player to move has no move in the node without children
+type stuckNode struct {
+	children []*stuckNode
+}
+
+func (node *stuckNode) Score() int {
+	return 0
+}
+
+func (node *stuckNode) IsTerminal() bool {
+	return false
+}
+
+func (node *stuckNode) SearchNodeGenerator() SearchNodeGenerator {
+	i := 0
+	return func(bool) SearchNode {
+		if i++; i > len(node.children) {
+			return nil
+		}
+		return node.children[i-1]
+	}
+}
+
+func TestEvaluateMoveNoMove(t *testing.T) {
+	// the best move leaves the minimizing player without a move, the played one the maximizing player
+	best, played := &stuckNode{}, &stuckNode{children: []*stuckNode{{}}}
+	node := &stuckNode{children: []*stuckNode{best, played}}
+	thresholds := MoveThresholds{Inaccuracy: 1, Mistake: 2, Blunder: 3}
+	evaluation := EvaluateMove(node, played, 3, true, thresholds)
+	if evaluation.Best.Score != math.MaxInt || evaluation.Played.Score != math.MinInt ||
+		evaluation.Loss != math.MaxInt || evaluation.Class != MoveBlunder {
+		t.Errorf("Expected the maximal loss of the blunder, got %+v", evaluation)
+	}
+	// played by the minimizing player, the child leaves the maximizing player without a move
+	if evaluation := EvaluateMove(node, best, 3, false, thresholds); evaluation.Loss != 0 || evaluation.Class != MoveOk {
+		t.Errorf("Expected no loss of the best move, got %+v", evaluation)
+	}
+}
@@ -0,0 +1,279 @@
+package csa
+
+import (
+	"container/heap"
+	"context"
+)
+
+// Node of a single-agent problem, e.g. a puzzle or a path finding, searched by AStar
+// The nodes are compared as the map keys, so their types have to be comparable,
+// or they implement HashNode whose equal hashes are the same states.
+type StateNode interface {
+	Neighbors() []Neighbor
+	IsGoal() bool
+}
+
+// Node reachable by a single step of given non-negative cost
+type Neighbor struct {
+	Node StateNode
+	Cost int
+}
+
+// Optional interface for nodes estimating their cost to the nearest goal
+type HeuristicNode interface {
+	Heuristic() int
+}
+
+// Estimate of the cost from the node to the nearest goal
+// Admissible heuristic, never overestimating the cost, finds the optimal path.
+// Consistent one, never decreasing by more than the step's cost, expands every node at most once.
+type Heuristic interface {
+	Estimate(node StateNode) int
+}
+
+type HeuristicFunc func(node StateNode) int
+
+func (fn HeuristicFunc) Estimate(node StateNode) int {
+	return fn(node)
+}
+
+// A* search of the cheapest path from the start node to a goal
+// Zero value uses the estimates of HeuristicNode nodes and zero for the other ones, i.e. Dijkstra's algorithm.
+// Weighted A* multiplies the estimates by Weight, with an admissible heuristic its paths cost at most Weight times
+// the optimal cost, but they are usually found by expanding far fewer nodes.
+type AStar struct {
+	Heuristic     Heuristic // overrides HeuristicNode's estimate if set
+	Weight        float64   // of the estimates, at least one, one if zero
+	MaxExpansions int       // the search gives up after expanding this many nodes, zero means unlimited
+}
+
+// Statistics of the last search
+type AStarStats struct {
+	Expanded  int // nodes whose neighbors were generated
+	Generated int // neighbors
+}
+
+// Path found by the anytime search
+type AStarSolution struct {
+	Path  []StateNode // from the start to a goal including both, nil if none was found
+	Cost  int
+	Bound float64 // the cost is at most Bound times the optimal one for an admissible heuristic, one if it is optimal
+	Stats AStarStats
+}
+
+// Cheapest path from the start to a goal including both and its cost, nil path if no goal is reachable
+// or the search gives up
+func (a AStar) Search(start StateNode) ([]StateNode, int) {
+	path, cost, _ := a.SearchStats(start)
+	return path, cost
+}
+
+// Same as Search with the statistics of the search
+func (a AStar) SearchStats(start StateNode) ([]StateNode, int, AStarStats) {
+	search := a.newSearch(start)
+	for state := search.pop(); state != nil; state = search.pop() {
+		if state.node.IsGoal() {
+			return state.path(), state.cost, search.stats
+		}
+		if search.givesUp() {
+			break
+		}
+		search.expand(state)
+	}
+	return nil, 0, search.stats
+}
+
+// Anytime weighted A* (Hansen and Zhou), continues the weighted search after the first path and reports every
+// cheaper one with its suboptimality bound until the open list runs out, which proves the last path optimal,
+// the search gives up or the context is done. Returns the cheapest path found.
+// The bounds and the optimality hold for an admissible heuristic.
+func (a AStar) SearchAnytime(ctx context.Context, start StateNode, improved func(solution AStarSolution)) AStarSolution {
+	search := a.newSearch(start)
+	best := AStarSolution{Bound: 1}
+	for !search.givesUp() {
+		if search.stats.Expanded%256 == 0 && ctx.Err() != nil {
+			break
+		}
+		state := search.pop()
+		if state == nil {
+			break
+		}
+		if best.Path != nil && state.cost+state.estimate >= best.Cost {
+			// cannot lead to a cheaper path
+			continue
+		}
+		if state.node.IsGoal() {
+			// closed until a cheaper path reopens it
+			state.closed = true
+			best.Path, best.Cost = state.path(), state.cost
+			if improved != nil {
+				improved(search.solution(best))
+			}
+			continue
+		}
+		search.expand(state)
+	}
+	return search.solution(best)
+}
+
+// Solution of the best path with the bound of the open states
+func (search *astarSearch) solution(best AStarSolution) AStarSolution {
+	best.Stats = search.stats
+	if best.Path == nil {
+		return best
+	}
+	best.Bound = 1
+	if lower := search.lowerBound(best.Cost); lower < best.Cost {
+		best.Bound = float64(best.Cost) / float64(max(lower, 1))
+	}
+	return best
+}
+
+// Open and closed states of a search
+type astarSearch struct {
+	a      AStar
+	weight float64
+	states map[any]*astarState
+	open   *astarQueue
+	stats  AStarStats
+}
+
+func (a AStar) newSearch(start StateNode) *astarSearch {
+	search := &astarSearch{a: a, weight: max(a.Weight, 1), states: map[any]*astarState{}, open: &astarQueue{}}
+	search.push(start, 0, nil)
+	return search
+}
+
+// Adds the node to the open list unless it has already been reached as cheaply
+func (search *astarSearch) push(node StateNode, cost int, parent *astarState) {
+	key := stateKey(node)
+	state, ok := search.states[key]
+	if ok && state.cost <= cost {
+		return
+	}
+	if !ok {
+		state = &astarState{node: node, estimate: search.a.estimate(node)}
+		search.states[key] = state
+	}
+	// a cheaper path reopens the closed state of an inconsistent or weighted heuristic
+	state.cost, state.parent, state.closed = cost, parent, false
+	priority := float64(cost) + search.weight*float64(state.estimate)
+	heap.Push(search.open, astarEntry{state: state, cost: cost, priority: priority, order: search.open.pushed})
+}
+
+// Open state of the lowest priority, nil if there is none
+func (search *astarSearch) pop() *astarState {
+	for search.open.Len() > 0 {
+		entry := heap.Pop(search.open).(astarEntry)
+		// skips the stale entries of the states reached later by a cheaper path
+		if state := entry.state; !state.closed && entry.cost == state.cost {
+			return state
+		}
+	}
+	return nil
+}
+
+func (search *astarSearch) givesUp() bool {
+	return search.a.MaxExpansions > 0 && search.stats.Expanded >= search.a.MaxExpansions
+}
+
+// Closes the state and opens its neighbors
+func (search *astarSearch) expand(state *astarState) {
+	state.closed = true
+	search.stats.Expanded++
+	for _, neighbor := range state.node.Neighbors() {
+		search.stats.Generated++
+		search.push(neighbor.Node, state.cost+neighbor.Cost, state)
+	}
+}
+
+// Lower bound of the optimal cost given the cost of the best path, the least unweighted priority of the open states
+func (search *astarSearch) lowerBound(best int) int {
+	lower := best
+	for _, entry := range search.open.entries {
+		if state := entry.state; !state.closed && entry.cost == state.cost {
+			lower = min(lower, state.cost+state.estimate)
+		}
+	}
+	return lower
+}
+
+func (a AStar) estimate(node StateNode) int {
+	if a.Heuristic != nil {
+		return a.Heuristic.Estimate(node)
+	}
+	if heuristicNode, ok := node.(HeuristicNode); ok {
+		return heuristicNode.Heuristic()
+	}
+	return 0
+}
+
+func stateKey(node StateNode) any {
+	if hashNode, ok := node.(HashNode); ok {
+		return hashNode.Hash()
+	}
+	return node
+}
+
+// Best known path to a node
+type astarState struct {
+	node     StateNode
+	cost     int // of the path from the start
+	estimate int // of the cost to the goal
+	parent   *astarState
+	closed   bool // expanded with the current cost
+}
+
+func (state *astarState) path() []StateNode {
+	var path []StateNode
+	for ; state != nil; state = state.parent {
+		path = append(path, state.node)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+type astarEntry struct {
+	state    *astarState
+	cost     int     // of the state when pushed
+	priority float64 // cost plus weighted estimate
+	order    int     // of the push
+}
+
+// Open list ordered by the priority, ties prefer the deeper entries and then the older ones
+type astarQueue struct {
+	entries []astarEntry
+	pushed  int
+}
+
+func (queue *astarQueue) Len() int {
+	return len(queue.entries)
+}
+
+func (queue *astarQueue) Less(i, j int) bool {
+	a, b := queue.entries[i], queue.entries[j]
+	if a.priority != b.priority {
+		return a.priority < b.priority
+	}
+	if a.cost != b.cost {
+		return a.cost > b.cost
+	}
+	return a.order < b.order
+}
+
+func (queue *astarQueue) Swap(i, j int) {
+	queue.entries[i], queue.entries[j] = queue.entries[j], queue.entries[i]
+}
+
+func (queue *astarQueue) Push(x any) {
+	queue.entries = append(queue.entries, x.(astarEntry))
+	queue.pushed++
+}
+
+func (queue *astarQueue) Pop() any {
+	last := queue.entries[len(queue.entries)-1]
+	queue.entries = queue.entries[:len(queue.entries)-1]
+	return last
+}
@@ -0,0 +1,128 @@
+package csa
+
+import (
+	"context"
+	"testing"
+)
+
+// Square of the grid maze, the walls are #, the goals G and the steps to ~ cost two
+type gridNode struct {
+	maze       []string
+	row, col   int
+	overshoots bool
+}
+
+func (node gridNode) Neighbors() []Neighbor {
+	var neighbors []Neighbor
+	for _, step := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		row, col := node.row+step[0], node.col+step[1]
+		if row < 0 || row >= len(node.maze) || col < 0 || col >= len(node.maze[row]) || node.maze[row][col] == '#' {
+			continue
+		}
+		cost := 1
+		if node.maze[row][col] == '~' {
+			cost = 2
+		}
+		neighbor := node
+		neighbor.row, neighbor.col = row, col
+		neighbors = append(neighbors, Neighbor{Node: neighbor, Cost: cost})
+	}
+	return neighbors
+}
+
+func (node gridNode) IsGoal() bool {
+	return node.maze[node.row][node.col] == 'G'
+}
+
+func (node gridNode) Hash() uint64 {
+	return uint64(node.row)<<32 | uint64(node.col)
+}
+
+// Manhattan distance to the nearest goal, ten times more if it overshoots
+func (node gridNode) Heuristic() int {
+	best := -1
+	for row, line := range node.maze {
+		for col := range line {
+			if line[col] == 'G' {
+				distance := abs(row-node.row) + abs(col-node.col)
+				if best < 0 || distance < best {
+					best = distance
+				}
+			}
+		}
+	}
+	if node.overshoots {
+		return 10 * max(best, 0)
+	}
+	return max(best, 0)
+}
+
+var maze = []string{
+	"S....#....",
+	".###.#.##.",
+	".#...~..#.",
+	".#.####.#G",
+	"...#......",
+}
+
+func TestAStar(t *testing.T) {
+	start := gridNode{maze: maze}
+	path, cost, stats := AStar{}.SearchStats(start)
+	if cost != 15 || len(path) != 15 || !path[len(path)-1].IsGoal() || path[0].(gridNode).Hash() != start.Hash() {
+		t.Fatalf("Expected the path of cost 15 through the ~ square, got %d of %d nodes", cost, len(path))
+	}
+	// Dijkstra's algorithm finds the same cost expanding more nodes
+	_, dijkstraCost, dijkstraStats := AStar{Heuristic: HeuristicFunc(func(StateNode) int { return 0 })}.SearchStats(start)
+	if dijkstraCost != cost || dijkstraStats.Expanded <= stats.Expanded {
+		t.Errorf("Expected Dijkstra's cost %d with more than %d expansions, got %d with %d",
+			cost, stats.Expanded, dijkstraCost, dijkstraStats.Expanded)
+	}
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1].(gridNode), path[i].(gridNode)
+		if abs(a.row-b.row)+abs(a.col-b.col) != 1 {
+			t.Fatalf("Invalid step from %v to %v", a, b)
+		}
+	}
+	if path, cost := (AStar{}).Search(gridNode{maze: maze, row: 3, col: 9}); len(path) != 1 || cost != 0 {
+		t.Errorf("Expected the start at the goal, got %d nodes of cost %d", len(path), cost)
+	}
+	walled := []string{"S#.", "##G"}
+	if path, _ := (AStar{}).Search(gridNode{maze: walled}); path != nil {
+		t.Errorf("Expected the unreachable goal, got %d nodes", len(path))
+	}
+	if path, _ := (AStar{MaxExpansions: 3}).Search(start); path != nil {
+		t.Errorf("Expected the search to give up, got %d nodes", len(path))
+	}
+	// inadmissible heuristic still finds a path, not the cheapest one
+	if path, overshotCost := (AStar{}).Search(gridNode{maze: maze, overshoots: true}); path == nil || overshotCost < cost {
+		t.Errorf("Expected a path not cheaper than %d, got %d", cost, overshotCost)
+	}
+}
+
+func TestAStarAnytime(t *testing.T) {
+	start := gridNode{maze: maze}
+	_, optimal, stats := AStar{}.SearchStats(start)
+	path, cost, weightedStats := AStar{Weight: 3}.SearchStats(start)
+	if path == nil || cost < optimal || cost > 3*optimal || weightedStats.Expanded > stats.Expanded {
+		t.Errorf("Expected the weighted path of cost at most %d with at most %d expansions, got %d with %d",
+			3*optimal, stats.Expanded, cost, weightedStats.Expanded)
+	}
+	var solutions []AStarSolution
+	solution := AStar{Weight: 3}.SearchAnytime(context.Background(), start, func(solution AStarSolution) {
+		solutions = append(solutions, solution)
+	})
+	if solution.Cost != optimal || solution.Bound != 1 || len(solutions) == 0 || solutions[len(solutions)-1].Cost != optimal {
+		t.Fatalf("Expected the optimal cost %d, got %+v", optimal, solution)
+	}
+	for i, solution := range solutions {
+		if solution.Bound < 1 || float64(solution.Cost) > solution.Bound*float64(optimal) ||
+			i > 0 && solution.Cost >= solutions[i-1].Cost {
+			t.Errorf("Unexpected solution %d of cost %d and bound %f", i, solution.Cost, solution.Bound)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if solution := (AStar{}).SearchAnytime(ctx, start, nil); solution.Path != nil || solution.Stats.Expanded != 0 {
+		t.Errorf("Expected the cancelled search, got %+v", solution)
+	}
+}
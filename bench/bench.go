@@ -0,0 +1,180 @@
+// Package bench measures csa engine configurations on suites of test positions with known best moves
+//
+// Suite is a text with one position per line, fields are separated by semicolons:
+//
+//	<position>; bm <move> [<move>...]; am <move> [<move>...]; id <name>
+//
+// The position is parsed by the game's PositionParser, moves are compared with the fmt.Sprint of csa.MoveOf
+// of the best root child. Opcode bm lists the best moves, am the moves to avoid, both are optional.
+// Empty lines and lines starting with # are ignored.
+package bench
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Parses the game specific position field, returns the node and whether the maximizing player is to move
+type PositionParser func(position string) (csa.SearchNode, bool, error)
+
+type Position struct {
+	ID         string
+	Node       csa.SearchNode
+	Maximizing bool
+	BestMoves  []string
+	AvoidMoves []string
+}
+
+// Whether the move solves the position
+func (position *Position) Solved(move string) bool {
+	if len(position.BestMoves) > 0 && !slices.Contains(position.BestMoves, move) {
+		return false
+	}
+	return !slices.Contains(position.AvoidMoves, move)
+}
+
+func Load(r io.Reader, parse PositionParser) ([]Position, error) {
+	var positions []Position
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		node, maximizing, err := parse(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("bench: line %d: %w", lineNumber, err)
+		}
+		position := Position{ID: fmt.Sprint(len(positions) + 1), Node: node, Maximizing: maximizing}
+		for _, field := range fields[1:] {
+			operands := strings.Fields(field)
+			if len(operands) == 0 {
+				continue
+			}
+			switch operands[0] {
+			case "bm":
+				position.BestMoves = append(position.BestMoves, operands[1:]...)
+			case "am":
+				position.AvoidMoves = append(position.AvoidMoves, operands[1:]...)
+			case "id":
+				position.ID = strings.Trim(strings.Join(operands[1:], " "), `"`)
+			default:
+				return nil, fmt.Errorf("bench: line %d: unknown opcode %q", lineNumber, operands[0])
+			}
+		}
+		positions = append(positions, position)
+	}
+	return positions, scanner.Err()
+}
+
+// Named engine configuration, every position is searched by a new engine
+// Info callback of the options is replaced by the one measuring the time to solution.
+type Config struct {
+	Name    string
+	Options []csa.EngineOption
+}
+
+type PositionResult struct {
+	ID             string
+	Move           string // best move found by the engine
+	Solved         bool
+	TimeToSolution time.Duration // since which the engine kept a solving move, zero if not solved
+	DepthSolved    int           // depth of the time to solution
+	Depth          int           // last finished depth
+	Nodes          int64
+	Time           time.Duration
+}
+
+type Result struct {
+	Config    string
+	Positions []PositionResult
+	Solved    int
+	Nodes     int64
+	Time      time.Duration
+}
+
+// Ratio of the solved positions
+func (result *Result) Accuracy() float64 {
+	if len(result.Positions) == 0 {
+		return 0
+	}
+	return float64(result.Solved) / float64(len(result.Positions))
+}
+
+// Searches every position by every configuration
+func Run(positions []Position, configs []Config) []Result {
+	results := make([]Result, len(configs))
+	for i, config := range configs {
+		results[i].Config = config.Name
+		for j := range positions {
+			positionResult := runPosition(&positions[j], config)
+			results[i].Positions = append(results[i].Positions, positionResult)
+			results[i].Nodes += positionResult.Nodes
+			results[i].Time += positionResult.Time
+			if positionResult.Solved {
+				results[i].Solved++
+			}
+		}
+	}
+	return results
+}
+
+func runPosition(position *Position, config Config) PositionResult {
+	result := PositionResult{ID: position.ID}
+	solvedAt, solvedDepth := time.Duration(-1), 0
+	options := append(slices.Clip(config.Options), csa.WithInfo(func(info csa.SearchInfo) {
+		result.Depth = info.Depth
+		if len(info.PV) == 0 || !position.Solved(moveString(info.PV[0])) {
+			solvedAt = -1
+		} else if solvedAt < 0 {
+			solvedAt, solvedDepth = info.Time, info.Depth
+		}
+	}))
+	engine := csa.NewEngine(options...)
+	defer engine.Close()
+	start := time.Now()
+	bestNode, _ := engine.BestMove(position.Node, position.Maximizing)
+	result.Time = time.Since(start)
+	result.Nodes = engine.Stats().Nodes
+	if bestNode != nil {
+		result.Move = moveString(bestNode)
+		result.Solved = position.Solved(result.Move)
+	}
+	if result.Solved && solvedAt >= 0 {
+		result.TimeToSolution, result.DepthSolved = solvedAt, solvedDepth
+	} else if result.Solved {
+		// solved by the search aborted before reporting the depth
+		result.TimeToSolution = result.Time
+	}
+	return result
+}
+
+func moveString(node csa.SearchNode) string {
+	if move := csa.MoveOf(node); move != nil {
+		return fmt.Sprint(move)
+	}
+	return fmt.Sprint(node)
+}
+
+// Writes a table of the results with a row per configuration and position
+func Write(w io.Writer, results []Result) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "config\tposition\tmove\tsolved\ttime to solution\tdepth\tnodes\ttime\t")
+	for _, result := range results {
+		for _, position := range result.Positions {
+			fmt.Fprintf(table, "%s\t%s\t%s\t%t\t%s\t%d\t%d\t%s\t\n", result.Config, position.ID, position.Move,
+				position.Solved, position.TimeToSolution, position.DepthSolved, position.Nodes, position.Time)
+		}
+		fmt.Fprintf(table, "%s\ttotal\t\t%d/%d\t\t\t%d\t%s\t\n",
+			result.Config, result.Solved, len(result.Positions), result.Nodes, result.Time)
+	}
+	return table.Flush()
+}
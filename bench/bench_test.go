@@ -0,0 +1,57 @@
+package bench
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/internal/subtraction"
+)
+
+func parseTake(position string) (csa.SearchNode, bool, error) {
+	stones, player, _ := strings.Cut(position, " ")
+	count, err := strconv.Atoi(stones)
+	if err != nil || (player != "max" && player != "min") {
+		return nil, false, errors.New("invalid position " + position)
+	}
+	return subtraction.New(count), player == "max", nil
+}
+
+const suite = `
+# winning move leaves a multiple of four
+11 max; bm 3; id "eleven"
+10 min; bm 2; am 1 3
+8 max; id lost
+`
+
+func TestRun(t *testing.T) {
+	positions, err := Load(strings.NewReader(suite), parseTake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(positions) != 3 || positions[0].ID != "eleven" || positions[1].ID != "2" || len(positions[1].AvoidMoves) != 2 {
+		t.Fatalf("Invalid positions %+v", positions)
+	}
+	results := Run(positions, []Config{
+		{"shallow", []csa.EngineOption{csa.WithMaxDepth(1)}},
+		{"deep", []csa.EngineOption{csa.WithMaxDepth(12)}},
+	})
+	if results[0].Solved != 1 || results[1].Solved != 3 || results[1].Accuracy() != 1 {
+		t.Errorf("Unexpected solved positions %+v", results)
+	}
+	if deep := results[1].Positions[0]; deep.Move != "3" || deep.DepthSolved == 0 || deep.DepthSolved > deep.Depth || deep.Nodes == 0 {
+		t.Errorf("Unexpected result %+v", deep)
+	}
+	var table strings.Builder
+	if err := Write(&table, results); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(table.String(), "3/3") {
+		t.Errorf("Missing total in\n%s", table.String())
+	}
+	if _, err := Load(strings.NewReader("9 max; xx 1"), parseTake); err == nil {
+		t.Error("Expected unknown opcode error")
+	}
+}
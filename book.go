@@ -0,0 +1,208 @@
+package csa
+
+import (
+	"encoding/gob"
+	"io"
+	"math/rand"
+	"sync"
+)
+
+// Move of the opening book identified by the canonical hash of the resulting child
+type BookEntry struct {
+	Child  uint64
+	Weight float64 // relative probability of playing the move
+	Wins   int     // of the player who made the move in the learned games
+	Draws  int
+	Losses int
+}
+
+// Weight changes of the played book moves by the results of the games for the player who made them
+type BookLearning struct {
+	Win, Draw, Loss float64
+	MinWeight       float64 // weights never drop below it, zero weight moves are never played
+	MaxPlies        int     // only the first plies of the games are learned, zero means all
+	NewWeight       float64 // if positive, the played moves missing in the book are added with this weight
+}
+
+// Source of the book moves played by the Engine, e.g. Book or BinaryBook
+type OpeningBook interface {
+	// Child of the node chosen from the book, nil if the node is out of the book
+	Probe(node SearchNode, maximizing bool) SearchNode
+}
+
+// Opening book of weighted moves for hashable nodes (HashNode or SymmetryNode), safe for concurrent use
+// Book moves are played by the Engine with WithBook before any search.
+type Book struct {
+	Learning BookLearning
+	Rand     *rand.Rand // book moves are chosen randomly by their weights, nil always plays the heaviest one
+
+	mutex   sync.Mutex
+	entries map[uint64][]BookEntry // keyed as the transposition table, by the node and the player to move
+}
+
+func NewBook() *Book {
+	return &Book{entries: make(map[uint64][]BookEntry)}
+}
+
+func bookKey(node SearchNode, maximizing bool) (uint64, bool) {
+	hash, ok := canonicalHash(node)
+	if maximizing {
+		hash ^= 0x9e3779b97f4a7c15
+	}
+	return hash, ok
+}
+
+// Adds the move from the node to the child or replaces the weight of the already added one
+func (book *Book) Add(node, child SearchNode, maximizing bool, weight float64) bool {
+	key, ok := bookKey(node, playerToMove(node, maximizing))
+	childHash, childOk := canonicalHash(child)
+	if !ok || !childOk {
+		return false
+	}
+	book.mutex.Lock()
+	defer book.mutex.Unlock()
+	book.entry(key, childHash, true).Weight = weight
+	return true
+}
+
+// Entry of the move, optionally added with zero weight
+func (book *Book) entry(key, child uint64, add bool) *BookEntry {
+	entries := book.entries[key]
+	for i := range entries {
+		if entries[i].Child == child {
+			return &entries[i]
+		}
+	}
+	if !add {
+		return nil
+	}
+	book.entries[key] = append(entries, BookEntry{Child: child})
+	return &book.entries[key][len(entries)]
+}
+
+// Moves of the node in the order they were added
+func (book *Book) Entries(node SearchNode, maximizing bool) []BookEntry {
+	key, ok := bookKey(node, playerToMove(node, maximizing))
+	if !ok {
+		return nil
+	}
+	book.mutex.Lock()
+	defer book.mutex.Unlock()
+	return append([]BookEntry(nil), book.entries[key]...)
+}
+
+// Child of the node chosen from the book, nil if the book has no move with positive weight for the node
+func (book *Book) Probe(node SearchNode, maximizing bool) SearchNode {
+	maximizing = playerToMove(node, maximizing)
+	return chooseBookChild(node, maximizing, book.Entries(node, maximizing), book.Rand, &book.mutex)
+}
+
+// Child chosen by the entries' weights, randomly if rnd is set, guarded by the mutex
+func chooseBookChild(node SearchNode, maximizing bool, entries []BookEntry, rnd *rand.Rand, mutex *sync.Mutex) SearchNode {
+	weights := make(map[uint64]float64, len(entries))
+	total := 0.0
+	for _, entry := range entries {
+		if entry.Weight > 0 {
+			weights[entry.Child] = entry.Weight
+			total += entry.Weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	threshold := -1.0
+	if rnd != nil {
+		mutex.Lock()
+		threshold = rnd.Float64() * total
+		mutex.Unlock()
+	}
+	var best SearchNode
+	bestWeight := 0.0
+	for generator := nodeGenerator(node); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			return best
+		}
+		hash, _ := canonicalHash(childNode)
+		weight := weights[hash]
+		if weight <= 0 {
+			continue
+		}
+		if threshold >= 0 {
+			if threshold -= weight; threshold < 0 {
+				return childNode
+			}
+			// rounding errors
+			best = childNode
+		} else if weight > bestWeight {
+			best, bestWeight = childNode, weight
+		}
+	}
+}
+
+// Updates the weights of the book moves played in the game by its outcome for the maximizing player,
+// 1 for win, 0 for draw, -1 for loss
+// Game is the sequence of the played nodes starting with the initial one, the maximizing player moves first.
+func (book *Book) Learn(game []SearchNode, outcome int) {
+	book.mutex.Lock()
+	defer book.mutex.Unlock()
+	learning := book.Learning
+	maximizing := true
+	for ply := 0; ply+1 < len(game) && (learning.MaxPlies <= 0 || ply < learning.MaxPlies); ply++ {
+		node, child := game[ply], game[ply+1]
+		maximizing = playerToMove(node, maximizing)
+		key, ok := bookKey(node, maximizing)
+		childHash, childOk := canonicalHash(child)
+		if ok && childOk {
+			result := outcome
+			if !maximizing {
+				result = -outcome
+			}
+			if entry := book.entry(key, childHash, false); entry != nil || learning.NewWeight > 0 {
+				if entry == nil {
+					entry = book.entry(key, childHash, true)
+					entry.Weight = learning.NewWeight
+				}
+				switch {
+				case result > 0:
+					entry.Wins++
+					entry.Weight += learning.Win
+				case result < 0:
+					entry.Losses++
+					entry.Weight += learning.Loss
+				default:
+					entry.Draws++
+					entry.Weight += learning.Draw
+				}
+				entry.Weight = max(entry.Weight, learning.MinWeight)
+			}
+		}
+		maximizing = nextPlayer(child, maximizing)
+	}
+}
+
+type bookData struct {
+	Learning BookLearning
+	Entries  map[uint64][]BookEntry
+}
+
+// Writes the book including its learning configuration
+func (book *Book) Save(w io.Writer) error {
+	book.mutex.Lock()
+	defer book.mutex.Unlock()
+	return gob.NewEncoder(w).Encode(bookData{book.Learning, book.entries})
+}
+
+// Reads the book written by Save
+func LoadBook(r io.Reader) (*Book, error) {
+	var data bookData
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	book := NewBook()
+	book.Learning = data.Learning
+	if data.Entries != nil {
+		book.entries = data.Entries
+	}
+	return book, nil
+}
@@ -0,0 +1,154 @@
+package csa
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Binary book file is the magic followed by fixed size big-endian records sorted by the key and the child:
+//
+//	key uint64, child uint64, weight float64, wins uint32, draws uint32, losses uint32, reserved uint32
+//
+// Records are 8 byte aligned, so the file can be memory mapped and searched in place.
+// The learning configuration of the book is not stored.
+const (
+	bookMagic      = "CSABOOK1"
+	bookRecordSize = 40
+)
+
+var errInvalidBook = errors.New("csa: invalid binary book")
+
+// Writes the entries of the book in the binary format readable by OpenBinaryBook and LoadBinaryBook
+func (book *Book) WriteBinary(w io.Writer) error {
+	book.mutex.Lock()
+	keys := make([]uint64, 0, len(book.entries))
+	for key := range book.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	var records []byte
+	for _, key := range keys {
+		entries := append([]BookEntry(nil), book.entries[key]...)
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Child < entries[j].Child })
+		for _, entry := range entries {
+			records = appendBookRecord(records, key, entry)
+		}
+	}
+	book.mutex.Unlock()
+	writer := bufio.NewWriter(w)
+	writer.WriteString(bookMagic)
+	writer.Write(records)
+	return writer.Flush()
+}
+
+func appendBookRecord(record []byte, key uint64, entry BookEntry) []byte {
+	record = binary.BigEndian.AppendUint64(record, key)
+	record = binary.BigEndian.AppendUint64(record, entry.Child)
+	record = binary.BigEndian.AppendUint64(record, math.Float64bits(entry.Weight))
+	record = binary.BigEndian.AppendUint32(record, uint32(entry.Wins))
+	record = binary.BigEndian.AppendUint32(record, uint32(entry.Draws))
+	record = binary.BigEndian.AppendUint32(record, uint32(entry.Losses))
+	return binary.BigEndian.AppendUint32(record, 0)
+}
+
+func parseBookRecord(record []byte) (uint64, BookEntry) {
+	return binary.BigEndian.Uint64(record), BookEntry{
+		Child:  binary.BigEndian.Uint64(record[8:]),
+		Weight: math.Float64frombits(binary.BigEndian.Uint64(record[16:])),
+		Wins:   int(binary.BigEndian.Uint32(record[24:])),
+		Draws:  int(binary.BigEndian.Uint32(record[28:])),
+		Losses: int(binary.BigEndian.Uint32(record[32:])),
+	}
+}
+
+// Reads the whole binary book into memory, e.g. to continue its learning
+func LoadBinaryBook(r io.Reader) (*Book, error) {
+	reader := bufio.NewReader(r)
+	magic := make([]byte, len(bookMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || string(magic) != bookMagic {
+		return nil, errInvalidBook
+	}
+	book := NewBook()
+	record := make([]byte, bookRecordSize)
+	for {
+		if _, err := io.ReadFull(reader, record); err == io.EOF {
+			return book, nil
+		} else if err != nil {
+			return nil, errInvalidBook
+		}
+		key, entry := parseBookRecord(record)
+		book.entries[key] = append(book.entries[key], entry)
+	}
+}
+
+// Read-only binary book searched in place by binary search of the records, safe for concurrent use
+type BinaryBook struct {
+	Rand *rand.Rand // book moves are chosen randomly by their weights, nil always plays the heaviest one
+
+	mutex   sync.Mutex
+	r       io.ReaderAt
+	records int
+}
+
+// Opens the binary book of given size in bytes, e.g. *os.File or bytes.Reader of memory mapped file
+func OpenBinaryBook(r io.ReaderAt, size int64) (*BinaryBook, error) {
+	magic := make([]byte, len(bookMagic))
+	if _, err := r.ReadAt(magic, 0); err != nil || string(magic) != bookMagic {
+		return nil, errInvalidBook
+	}
+	if (size-int64(len(bookMagic)))%bookRecordSize != 0 {
+		return nil, errInvalidBook
+	}
+	return &BinaryBook{r: r, records: int((size - int64(len(bookMagic))) / bookRecordSize)}, nil
+}
+
+func (book *BinaryBook) record(index int) (uint64, BookEntry, error) {
+	record := make([]byte, bookRecordSize)
+	if _, err := book.r.ReadAt(record, int64(len(bookMagic))+int64(index)*bookRecordSize); err != nil {
+		return 0, BookEntry{}, err
+	}
+	key, entry := parseBookRecord(record)
+	return key, entry, nil
+}
+
+// Moves of the node sorted by their child hashes
+func (book *BinaryBook) Entries(node SearchNode, maximizing bool) ([]BookEntry, error) {
+	key, ok := bookKey(node, playerToMove(node, maximizing))
+	if !ok {
+		return nil, nil
+	}
+	var err error
+	first := sort.Search(book.records, func(i int) bool {
+		recordKey, _, recordErr := book.record(i)
+		if recordErr != nil {
+			err = recordErr
+			return true
+		}
+		return recordKey >= key
+	})
+	var entries []BookEntry
+	for i := first; err == nil && i < book.records; i++ {
+		recordKey, entry, recordErr := book.record(i)
+		if err = recordErr; err != nil || recordKey != key {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, err
+}
+
+// Child of the node chosen from the book, nil if the node is out of the book or the book cannot be read
+func (book *BinaryBook) Probe(node SearchNode, maximizing bool) SearchNode {
+	maximizing = playerToMove(node, maximizing)
+	entries, err := book.Entries(node, maximizing)
+	if err != nil {
+		return nil
+	}
+	return chooseBookChild(node, maximizing, entries, book.Rand, &book.mutex)
+}
@@ -0,0 +1,55 @@
+package csa
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTTTBinaryBook(t *testing.T) {
+	book := NewBook()
+	var nodes []tttNode
+	generator := tttNode{}.SearchNodeGenerator()
+	for child := generator(true); child != nil; child = generator(true) {
+		book.Add(tttNode{}, child, true, float64(child.(tttNode).Hash()%7))
+		nodes = append(nodes, child.(tttNode))
+		grandGenerator := child.SearchNodeGenerator()
+		for grandChild := grandGenerator(false); grandChild != nil; grandChild = grandGenerator(false) {
+			book.Add(child, grandChild, false, float64(grandChild.(tttNode).Hash()%5))
+		}
+	}
+	var data bytes.Buffer
+	if err := book.WriteBinary(&data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Len() != 8+(9+9*8)*40 {
+		t.Errorf("Unexpected book size %d", data.Len())
+	}
+	binaryBook, err := OpenBinaryBook(bytes.NewReader(data.Bytes()), int64(data.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBinaryBook(bytes.NewReader(data.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, node := range append(nodes, tttNode{}) {
+		maximizing := node == tttNode{}
+		entries, err := binaryBook.Entries(node, maximizing)
+		if err != nil || len(entries) != len(book.Entries(node, maximizing)) || len(loaded.Entries(node, maximizing)) != len(entries) {
+			t.Errorf("Entries differ for\n%v", node)
+		}
+		if binaryBook.Probe(node, maximizing) != book.Probe(node, maximizing) || loaded.Probe(node, maximizing) != book.Probe(node, maximizing) {
+			t.Errorf("Book moves differ for\n%v", node)
+		}
+	}
+	if entries, _ := binaryBook.Entries(tttNode{}, false); entries != nil {
+		t.Error("Position with the other player to move has to be out of the book")
+	}
+	engine := NewEngine(WithBook(binaryBook), WithMaxDepth(1))
+	if bestNode, _ := engine.BestMove(tttNode{}, true); bestNode != book.Probe(tttNode{}, true) {
+		t.Error("Engine has to play the binary book move")
+	}
+	if _, err := OpenBinaryBook(bytes.NewReader(data.Bytes()), int64(data.Len()-1)); err == nil {
+		t.Error("Expected error of truncated book")
+	}
+}
@@ -0,0 +1,59 @@
+package csa
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestTTTBook(t *testing.T) {
+	center, corner := tttNode{}, tttNode{}
+	center.board[1][1] = circle
+	corner.board[0][0] = circle
+	book := NewBook()
+	book.Add(tttNode{}, corner, true, 1)
+	book.Add(tttNode{}, center, true, 2)
+	engine := NewEngine(WithBook(book), WithMaxDepth(1))
+	if bestNode, _ := engine.BestMove(tttNode{}, true); bestNode != center {
+		t.Errorf("Expected the heaviest book move, got\n%v", bestNode)
+	}
+	if bestNode, _ := engine.BestMove(center, false); bestNode == nil {
+		t.Error("Position out of book has to be searched")
+	}
+
+	// center lost twice, dropping to the min weight, and drawn once, corner won
+	book.Learning = BookLearning{Win: 1, Draw: 0.5, Loss: -1, MinWeight: 0.1, MaxPlies: 1}
+	afterCenter, afterCorner := center, corner
+	afterCenter.board[0][0] = cross
+	afterCorner.board[1][1] = cross
+	book.Learn([]SearchNode{tttNode{}, center, afterCenter}, -1)
+	book.Learn([]SearchNode{tttNode{}, center, afterCenter}, -1)
+	book.Learn([]SearchNode{tttNode{}, center}, 0)
+	book.Learn([]SearchNode{tttNode{}, corner, afterCorner}, 1)
+	if entries := book.Entries(tttNode{}, true); entries[0].Weight != 2 || entries[0].Wins != 1 ||
+		entries[1].Weight != 0.6 || entries[1].Losses != 2 || entries[1].Draws != 1 || len(entries) != 2 {
+		t.Errorf("Unexpected learned entries %+v", entries)
+	}
+	if len(book.Entries(center, false)) != 0 {
+		t.Error("Only the first ply should be learned")
+	}
+	var data bytes.Buffer
+	if err := book.Save(&data); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBook(&data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Probe(tttNode{}, true) != corner || loaded.Learning != book.Learning {
+		t.Error("Loaded book has to prefer the learned corner move")
+	}
+	loaded.Rand = rand.New(rand.NewSource(1))
+	counts := map[SearchNode]int{}
+	for i := 0; i < 1000; i++ {
+		counts[loaded.Probe(tttNode{}, true)]++
+	}
+	if counts[corner]+counts[center] != 1000 || counts[center] < 100 || counts[center] > 300 {
+		t.Errorf("Unexpected random book moves %v", counts)
+	}
+}
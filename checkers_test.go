@@ -1,14 +1,22 @@
 package csa
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"math/bits"
 	"reflect"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 )
 
 // Rules of this checkers game:
 // - king can move and jump only by one square
 // - there is no necessity for a jump if available; if the figure wont jump it wont be taken away
+// - optionally (multiJump) a figure which jumped and can jump again has to continue, its player moves again
 
 // Algorithm:
 // - anticycling technique using node history
@@ -52,12 +60,28 @@ type cNodeHistory map[uint64][]cNode
 type cNode struct {
 	board       [2][2]uint64 // board[units][color]
 	nodeHistory cNodeHistory // always passed by reference
+	scoreDelta  int          // score difference made by the last move
+	multiJump   bool         // rules option
+	extraTurn   bool         // jump chain continues with the figure at jumpIndex
+	jumpIndex   int
+	lastMove    cMove // move which created this node
+}
+
+type cMove struct {
+	from, to int
+	jump     bool
+}
+
+func (move cMove) String() string {
+	if move.jump {
+		return fmt.Sprintf("%dx%d", move.from, move.to)
+	}
+	return fmt.Sprintf("%d-%d", move.from, move.to)
 }
 
 func (node cNode) Score() int {
 	score := 0
-	// There is actually a better option - store score and modify it after each move (add/subtract move's difference)
-	// Recalculate everytime for sake of simplicity (relax it's just a test not a professional checkers engine)
+	// Full recalculation, the search uses ScoreDelta (move's difference stored during generation) whenever it can
 	for i := 0; i < 64; i++ {
 		if isBit(node.board[pawns][white], i) {
 			score += pawnScore * whiteCoef
@@ -72,6 +96,32 @@ func (node cNode) Score() int {
 	return score
 }
 
+func (node cNode) ScoreDelta(parent SearchNode) int {
+	return node.scoreDelta
+}
+
+func (node cNode) Move() Move {
+	return node.lastMove
+}
+
+func (node cNode) ExtraTurn() bool {
+	return node.extraTurn
+}
+
+// The same board is always the same player to move, figures cannot return in odd number of plies
+func (node cNode) RepetitionKey() uint64 {
+	b := &node.board
+	hash := uint64(14695981039346656037)
+	for _, bits := range []uint64{b[pawns][white], b[pawns][black], b[kings][white], b[kings][black]} {
+		hash = (hash ^ bits) * 1099511628211
+	}
+	return hash
+}
+
+func (node cNode) Hash() uint64 {
+	return node.RepetitionKey()
+}
+
 func (node cNode) IsTerminal() bool {
 	for color := range []int{white, black} {
 		if node.board[pawns][color]|node.board[kings][color] == 0 {
@@ -98,6 +148,11 @@ func (node cNode) SearchNodeGenerator() SearchNodeGenerator {
 				pawnDir = whitePawnDir
 				color = white
 			}
+			if node.extraTurn && index < 64 {
+				// jump chain continues only with the figure which jumped
+				nodeQueue = node.generateChainJumps(color, node.jumpIndex)
+				index = 64
+			}
 			for ; index < 64; index++ {
 				if node.placeOccupiedFigureColor(pawns, color, index) {
 					nodeQueue = node.generatePawnMoves(color, index, pawnDir)
@@ -186,6 +241,7 @@ func (node cNode) upgradeToKing(color, index int) cNode {
 			clone := node.cloneNode()
 			clone.board[pawns][color] = clearBit(clone.board[pawns][color], index)
 			clone.board[kings][color] = setBit(clone.board[kings][color], index)
+			clone.scoreDelta += (kingScore - pawnScore) * colorCoef(color)
 			return clone
 		}
 	}
@@ -200,6 +256,9 @@ func (node cNode) figureMove(figure, color, index, offset int) (bool, cNode) {
 		return false, cNode{}
 	}
 	clone := node.cloneNode()
+	clone.scoreDelta = 0
+	clone.extraTurn = false
+	clone.lastMove = cMove{index, index + offset, false}
 	clone.board[figure][color] = clearBit(clone.board[figure][color], index)
 	clone.board[figure][color] = setBit(clone.board[figure][color], index+offset)
 	return true, clone.upgradeToKing(color, index+offset)
@@ -216,7 +275,14 @@ func (node cNode) figureJump(figure, color, index, offset int) (bool, cNode) {
 		return false, cNode{}
 	}
 	clone := node.cloneNode()
+	clone.extraTurn = false
+	clone.lastMove = cMove{index, index + 2*offset, true}
 	enemyCol := enemyColor(color)
+	if node.placeOccupiedFigureColor(pawns, enemyCol, index+offset) {
+		clone.scoreDelta = -pawnScore * colorCoef(enemyCol)
+	} else {
+		clone.scoreDelta = -kingScore * colorCoef(enemyCol)
+	}
 	clone.board[figure][color] = clearBit(clone.board[figure][color], index)
 	clone.board[pawns][enemyCol] = clearBit(clone.board[pawns][enemyCol], index+offset)
 	clone.board[kings][enemyCol] = clearBit(clone.board[kings][enemyCol], index+offset)
@@ -234,7 +300,7 @@ func (node cNode) generateFigureMoves(figure, color, index, dir int) []cNode {
 		}
 		ok, move = node.figureJump(figure, color, index, offset*dir)
 		if ok {
-			moves = append(moves, move)
+			moves = append(moves, move.continueJump(figure, color, index+2*offset*dir))
 		}
 	}
 	return moves
@@ -251,6 +317,40 @@ func (node cNode) generateKingMoves(color, index int) []cNode {
 	return append(moves, node.generateFigureMoves(kings, color, index, 1)...)
 }
 
+// With multiJump rules the figure which can jump again continues the turn
+func (node cNode) continueJump(figure, color, index int) cNode {
+	if !node.multiJump || !isBit(node.board[figure][color], index) {
+		// promoted pawn ends the turn
+		return node
+	}
+	for _, dir := range figureDirs(figure, color) {
+		for _, offset := range []int{7, 9} {
+			if ok, _ := node.figureJump(figure, color, index, offset*dir); ok {
+				node.extraTurn = true
+				node.jumpIndex = index
+				return node
+			}
+		}
+	}
+	return node
+}
+
+func (node cNode) generateChainJumps(color, index int) []cNode {
+	figure := pawns
+	if node.placeOccupiedFigureColor(kings, color, index) {
+		figure = kings
+	}
+	var jumps []cNode
+	for _, dir := range figureDirs(figure, color) {
+		for _, offset := range []int{7, 9} {
+			if ok, jump := node.figureJump(figure, color, index, offset*dir); ok {
+				jumps = append(jumps, jump.continueJump(figure, color, index+2*offset*dir))
+			}
+		}
+	}
+	return jumps
+}
+
 func (node cNode) placeOccupiedFigureColor(figure, color, index int) bool {
 	return isBit(node.board[figure][color], index)
 }
@@ -306,6 +406,23 @@ func enemyColor(color int) int {
 	return white
 }
 
+func figureDirs(figure, color int) []int {
+	if figure == kings {
+		return []int{-1, 1}
+	}
+	if color == white {
+		return []int{whitePawnDir}
+	}
+	return []int{blackPawnDir}
+}
+
+func colorCoef(color int) int {
+	if color == white {
+		return whiteCoef
+	}
+	return blackCoef
+}
+
 func abs(val int) int {
 	if val < 0 {
 		return -val
@@ -655,6 +772,144 @@ func TestCheckersSearchNodeGenerator(t *testing.T) {
 	}
 }
 
+func TestCheckersScoreDelta(t *testing.T) {
+	node := cNodeEmpty()
+	node.board[pawns][white] = setBit(0, 10) | setBit(0, 49)
+	node.board[kings][white] = setBit(0, 28)
+	node.board[pawns][black] = setBit(0, 3) | setBit(0, 19) | setBit(0, 48)
+	node.board[kings][black] = setBit(0, 21)
+	for _, maximizing := range []bool{true, false} {
+		children := 0
+		for generator := node.SearchNodeGenerator(); ; children++ {
+			child := generator(maximizing)
+			if child == nil {
+				break
+			}
+			if node.Score()+child.(cNode).ScoreDelta(node) != child.Score() {
+				t.Errorf("Invalid score delta %d for move\n%s", child.(cNode).ScoreDelta(node), child)
+			}
+		}
+		if children == 0 {
+			t.Error("Expected some moves")
+		}
+	}
+	// full rescoring via evaluator must give the same results
+	fullScore := Searcher{Evaluator: EvaluatorFunc(func(node SearchNode) int {
+		return node.Score()
+	})}
+	for _, maximizing := range []bool{true, false} {
+		_, score := MinimaxAlphaBetaPrunning(cNodeFullBoard(), 5, maximizing)
+		_, fullRescore := fullScore.MinimaxAlphaBetaPrunning(cNodeFullBoard(), 5, maximizing)
+		if score != fullRescore {
+			t.Errorf("Incremental score %d differs from full score %d", score, fullRescore)
+		}
+	}
+}
+
+func TestCheckersMultiJump(t *testing.T) {
+	node := cNodeEmpty()
+	node.multiJump = true
+	node.board[pawns][white] = setBit(0, 44)
+	node.board[pawns][black] = setBit(0, 37) | setBit(0, 21)
+	node.board[kings][black] = setBit(0, 7)
+	moves := node.generatePawnMoves(white, 44, whitePawnDir)
+	if len(moves) != 2 || !moves[0].extraTurn || moves[0].jumpIndex != 30 || moves[1].extraTurn {
+		t.Fatal("Expected jump with extra turn and single move")
+	}
+	// only the jumping figure continues
+	chain := moves[0].SearchNodeGenerator()
+	next := chain(false)
+	if next == nil || chain(false) != nil || next.(cNode).extraTurn || !isBit(next.(cNode).board[pawns][white], 12) {
+		t.Errorf("Expected one continuing jump %s", next)
+	}
+	// white captures both pawns within its turn
+	for _, minimax := range []minimaxFn{
+		Minimax,
+		MinimaxAlphaBetaPrunning,
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			return MinimaxConcurrent(node, depth, maximizing, 2)
+		},
+	} {
+		sn, score := minimax(node, 2, false)
+		if sn == nil || !sn.(cNode).extraTurn || score != kingScore-pawnScore {
+			t.Errorf("Expected double jump with score %d, got %d", kingScore-pawnScore, score)
+		}
+	}
+	// black moves twice, e.g. the king runs away, if the jumps are not chained
+	node.multiJump = false
+	if _, score := MinimaxAlphaBetaPrunning(node, 2, false); score == kingScore-pawnScore {
+		t.Error("Black has to move in between the jumps")
+	}
+}
+
+// Node generating all moves at once instead of queuing them in generator
+type cSliceNode struct {
+	cNode
+}
+
+func (node cSliceNode) Children(maximizing bool) []SearchNode {
+	color, pawnDir := white, whitePawnDir
+	if maximizing {
+		color, pawnDir = black, blackPawnDir
+	}
+	var moves []cNode
+	for index := 0; index < 64; index++ {
+		if node.placeOccupiedFigureColor(pawns, color, index) {
+			moves = append(moves, node.generatePawnMoves(color, index, pawnDir)...)
+		} else if node.placeOccupiedFigureColor(kings, color, index) {
+			moves = append(moves, node.generateKingMoves(color, index)...)
+		}
+	}
+	children := make([]SearchNode, 0, len(moves))
+	for _, move := range moves {
+		if !node.inNodeHistory(move) {
+			children = append(children, cSliceNode{move})
+		}
+	}
+	return children
+}
+
+func (node cSliceNode) SearchNodeGenerator() SearchNodeGenerator {
+	return ChildrenGenerator(node)
+}
+
+func TestCheckersChildren(t *testing.T) {
+	for _, maximizing := range []bool{true, false} {
+		node, score := MinimaxAlphaBetaPrunning(cNodeFullBoard(), 5, maximizing)
+		sliceNode, sliceScore := MinimaxAlphaBetaPrunning(cSliceNode{cNodeFullBoard()}, 5, maximizing)
+		if score != sliceScore || node.(cNode).board != sliceNode.(cSliceNode).board {
+			t.Errorf("Children based search differs %s%s", node, sliceNode)
+		}
+	}
+	if len(cSliceNode{cNodeFullBoard()}.Children(true)) != 7 {
+		t.Error("Expected 7 moves")
+	}
+}
+
+func TestCheckersMove(t *testing.T) {
+	node := cNodeEmpty()
+	node.board[pawns][white] = setBit(0, 44)
+	node.board[pawns][black] = setBit(0, 37)
+	moves := []string{}
+	for generator := node.SearchNodeGenerator(); ; {
+		child := generator(false)
+		if child == nil {
+			break
+		}
+		moves = append(moves, fmt.Sprint(MoveOf(child)))
+	}
+	if strings.Join(moves, " ") != "44x30 44-35" {
+		t.Errorf("Invalid moves %v", moves)
+	}
+	best, _ := MinimaxAlphaBetaPrunning(node, 1, false)
+	if MoveOf(best) != (cMove{44, 30, true}) {
+		t.Errorf("Expected jump, got %v", MoveOf(best))
+	}
+	if MoveOf(tttNode{}) != nil {
+		t.Error("Node without move descriptor")
+	}
+}
+
 type minimaxFn func(node SearchNode, depth int, maximizing bool) (SearchNode, int)
 
 func TestCheckersMinimaxFullgame(t *testing.T) {
@@ -707,3 +962,417 @@ func TestCheckersMinimaxFullgame(t *testing.T) {
 	run(true, minimaxConcurrent, blackDepth, blackScore)
 	run(false, minimaxConcurrent, whiteDepth, whiteScore)
 }
+
+func TestCheckersMinimaxRollout(t *testing.T) {
+	for _, maximizing := range []bool{true, false} {
+		_, score := MinimaxAlphaBetaPrunning(cNodeFullBoard(), 4, maximizing)
+		_, rolloutScore := MinimaxRollout(cNodeFullBoard(), 4, maximizing)
+		if score != rolloutScore {
+			t.Errorf("Rollout score %d differs from alpha-beta score %d", rolloutScore, score)
+		}
+	}
+}
+
+func TestCheckersNoMove(t *testing.T) {
+	// white pawn blocked by black pawns, white cannot move
+	blocked := func() cNode {
+		node := cNodeEmpty()
+		node.board[pawns][white] = setBit(0, 17)
+		node.board[pawns][black] = setBit(0, 8) | setBit(0, 10) | setBit(0, 3)
+		return node
+	}
+	_, passScore := Minimax(blocked(), 3, true)
+	for _, test := range []struct {
+		searcher Searcher
+		score    int
+	}{
+		{Searcher{}, MinimaxInitScore(false)},
+		{Searcher{WinScore: 100}, 100},
+		{Searcher{NoMove: NoMoveDraw, DrawScore: 7}, 7},
+		{Searcher{NoMove: NoMovePass}, passScore},
+		{Searcher{NoMove: NoMovePass, NoMoveScore: func(SearchNode, bool) int { return 42 }}, 42},
+	} {
+		for _, search := range []func(node SearchNode, depth int, maximizing bool) (SearchNode, int){
+			test.searcher.Minimax,
+			test.searcher.MinimaxAlphaBetaPrunning,
+			test.searcher.MinimaxAlphaBetaIterative,
+			test.searcher.MinimaxRollout,
+			func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+				return test.searcher.MinimaxConcurrent(node, depth, maximizing, 2)
+			},
+		} {
+			if best, score := search(blocked(), 4, false); best != nil || score != test.score {
+				t.Errorf("Expected no move with score %d, got %d", test.score, score)
+			}
+		}
+	}
+}
+
+func TestCheckersRepetition(t *testing.T) {
+	node := cNodeEmpty()
+	node.board[kings][black] = setBit(0, 0)
+	node.board[kings][white] = setBit(0, 63)
+	// black king has the only move, which has been already played
+	played := node
+	played.board[kings][black] = setBit(0, 9)
+	history := []uint64{played.RepetitionKey()}
+	for _, test := range []struct {
+		searcher Searcher
+		score    int
+	}{
+		{Searcher{DrawScore: 5}, 0},
+		{Searcher{DrawScore: 5, History: history}, 5},
+		{Searcher{DrawScore: 5, History: history, Repetitions: 3}, 0},
+		{Searcher{DrawScore: 5, History: append(history, history...), Repetitions: 3}, 5},
+	} {
+		for _, search := range []func(node SearchNode, depth int, maximizing bool) (SearchNode, int){
+			test.searcher.Minimax,
+			test.searcher.MinimaxAlphaBetaPrunning,
+			test.searcher.MinimaxAlphaBetaIterative,
+			test.searcher.MinimaxRollout,
+			func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+				return test.searcher.MinimaxConcurrent(node, depth, maximizing, 2)
+			},
+		} {
+			if _, score := search(node, 3, true); score != test.score {
+				t.Errorf("Expected score %d, got %d", test.score, score)
+			}
+		}
+	}
+}
+
+func TestCheckersEngine(t *testing.T) {
+	for _, maximizing := range []bool{true, false} {
+		_, expected := MinimaxAlphaBetaPrunning(cNodeFullBoard(), 6, maximizing)
+		for _, algorithm := range []Algorithm{AlgorithmAlphaBeta, AlgorithmConcurrent, AlgorithmLazySMP} {
+			engine := NewEngine(WithAlgorithm(algorithm), WithMaxDepth(6), WithTT(1<<16), WithWorkers(2))
+			if _, score := engine.BestMove(cNodeFullBoard(), maximizing); score != expected {
+				t.Errorf("Algorithm %d: expected score %d, got %d", algorithm, expected, score)
+			}
+			engine.Close()
+		}
+	}
+	for _, algorithm := range []Algorithm{AlgorithmAlphaBeta, AlgorithmLazySMP} {
+		start := time.Now()
+		engine := NewEngine(WithAlgorithm(algorithm), WithTimeLimit(50*time.Millisecond), WithTT(1<<16), WithWorkers(4))
+		if best, _ := engine.BestMove(cNodeFullBoard(), true); best == nil || time.Since(start) > time.Second {
+			t.Errorf("Algorithm %d: expected a move within the time limit, got %s after %s", algorithm, best, time.Since(start))
+		}
+		engine.Close()
+	}
+}
+
+func TestCheckersEngineContext(t *testing.T) {
+	for _, algorithm := range []Algorithm{AlgorithmAlphaBeta, AlgorithmConcurrent, AlgorithmWorkStealing} {
+		var depths []int
+		engine := NewEngine(WithAlgorithm(algorithm), WithTT(1<<16), WithWorkers(4), WithInfo(func(info SearchInfo) {
+			depths = append(depths, info.Depth)
+		}))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		best, _ := engine.BestMoveContext(ctx, cNodeFullBoard(), true)
+		if best == nil || len(depths) == 0 || time.Since(start) > time.Second {
+			t.Errorf("Algorithm %d: expected a move of depth %v after cancellation, got %s after %s", algorithm, depths, best, time.Since(start))
+		}
+		cancel()
+		engine.Close()
+	}
+}
+
+func TestCheckersEnginePoll(t *testing.T) {
+	// the poll cancels the search like a JavaScript callback which got the control
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	polls := 0
+	engine := NewEngine(WithTT(1<<16), WithPoll(func() {
+		if polls++; polls == 10 {
+			cancel()
+		}
+	}))
+	defer engine.Close()
+	start := time.Now()
+	best, _ := engine.BestMoveContext(ctx, cNodeFullBoard(), true)
+	if best == nil || polls < 10 || time.Since(start) > time.Second {
+		t.Errorf("Expected a move after %d polls, got %s after %s", polls, best, time.Since(start))
+	}
+}
+
+func TestCheckersEngineClock(t *testing.T) {
+	engine := NewEngine(WithTT(1<<16), WithTimeManager(TimeManager{FailLowMargin: 1}))
+	start := time.Now()
+	best, _ := engine.BestMoveWithClock(cNodeFullBoard(), true, Clock{Remaining: time.Second, MovesToGo: 10})
+	if best == nil || time.Since(start) > 600*time.Millisecond {
+		t.Errorf("Expected a move within the hard limit, got %s after %s", best, time.Since(start))
+	}
+}
+
+func TestCheckersEngineCheckpoint(t *testing.T) {
+	var checkpoint bytes.Buffer
+	var engine *Engine
+	engine = NewEngine(WithMaxDepth(4), WithTT(1<<16), WithInfo(func(info SearchInfo) {
+		checkpoint.Reset()
+		if err := engine.Save(&checkpoint); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	engine.BestMove(cNodeFullBoard(), true)
+	// search interrupted after the fourth depth continues by the fifth one
+	var depths []int
+	resumed := NewEngine(WithMaxDepth(6), WithTT(1<<16), WithInfo(func(info SearchInfo) {
+		depths = append(depths, info.Depth)
+	}))
+	if err := resumed.Load(&checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	_, expected := MinimaxAlphaBetaPrunning(cNodeFullBoard(), 6, true)
+	if best, score := resumed.BestMove(cNodeFullBoard(), true); best == nil || score != expected || !reflect.DeepEqual(depths, []int{5, 6}) {
+		t.Errorf("Expected score %d after depths 5 and 6, got %d after %v", expected, score, depths)
+	}
+	// different node starts from scratch
+	depths = nil
+	resumed.BestMove(cNodeFullBoard(), false)
+	if len(depths) != 6 {
+		t.Errorf("Expected all depths, got %v", depths)
+	}
+	if err := resumed.Load(strings.NewReader("garbage")); err == nil {
+		t.Error("Expected decoding error")
+	}
+}
+
+func TestCheckersEngineWorkers(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	engine := NewEngine(WithAlgorithm(AlgorithmConcurrent), WithMaxDepth(4), WithWorkers(4))
+	_, expected := MinimaxAlphaBetaPrunning(cNodeFullBoard(), 4, true)
+	// workers are reused across the searches
+	for i := 0; i < 3; i++ {
+		if _, score := engine.BestMove(cNodeFullBoard(), true); score != expected {
+			t.Errorf("Expected score %d, got %d", expected, score)
+		}
+		if runtime.NumGoroutine() != goroutines+4 {
+			t.Errorf("Expected four workers, got %d goroutines", runtime.NumGoroutine()-goroutines)
+		}
+	}
+	engine.Close()
+	if runtime.NumGoroutine() != goroutines {
+		t.Errorf("Workers did not stop, %d goroutines left", runtime.NumGoroutine()-goroutines)
+	}
+}
+
+func TestCheckersMinimaxParallel(t *testing.T) {
+	_, expected := MinimaxAlphaBetaPrunning(cNodeFullBoard(), 5, false)
+	if _, score := MinimaxParallel(cNodeFullBoard(), 5, false); score != expected {
+		t.Errorf("Expected score %d, got %d", expected, score)
+	}
+	if _, score := MinimaxConcurrent(cNodeFullBoard(), 5, false, -1); score != expected {
+		t.Errorf("Expected score %d with default workers, got %d", expected, score)
+	}
+	if workerCount(0) != runtime.GOMAXPROCS(0) || workerCount(1<<20) != maxWorkers || workerCount(3) != 3 {
+		t.Error("Invalid number of workers")
+	}
+}
+
+func TestCheckersMinimaxAlphaBetaIterative(t *testing.T) {
+	for _, maximizing := range []bool{true, false} {
+		expected, expectedScore := MinimaxAlphaBetaPrunning(cNodeFullBoard(), 6, maximizing)
+		best, score := MinimaxAlphaBetaIterative(cNodeFullBoard(), 6, maximizing)
+		if score != expectedScore || best.(cNode).board != expected.(cNode).board {
+			t.Errorf("Iterative alpha-beta differs %d %d%s%s", score, expectedScore, best, expected)
+		}
+	}
+}
+
+func TestCheckersMinimaxWorkStealing(t *testing.T) {
+	// few moves at the root, the work has to be split deeper
+	forced := cNodeEmpty()
+	forced.board[pawns][white] = setBit(0, 44) | setBit(0, 62)
+	forced.board[pawns][black] = setBit(0, 1) | setBit(0, 3) | setBit(0, 12) | setBit(0, 14)
+	for _, node := range []cNode{cNodeFullBoard(), forced} {
+		for _, maximizing := range []bool{true, false} {
+			expected, expectedScore := MinimaxAlphaBetaPrunning(node, 6, maximizing)
+			best, score := MinimaxWorkStealing(node, 6, maximizing, 4)
+			if score != expectedScore || best.(cNode).board != expected.(cNode).board {
+				t.Errorf("Work stealing differs from alpha-beta %d %d%s%s", score, expectedScore, best, expected)
+			}
+			best, score = MinimaxYBWC(node, 6, maximizing, 4)
+			if score != expectedScore || best.(cNode).board != expected.(cNode).board {
+				t.Errorf("YBWC differs from alpha-beta %d %d%s%s", score, expectedScore, best, expected)
+			}
+			best, score = Searcher{SplitDepth: 3}.MinimaxConcurrent(node, 6, maximizing, 4)
+			if score != expectedScore || best.(cNode).board != expected.(cNode).board {
+				t.Errorf("Split concurrent search differs from alpha-beta %d %d%s%s", score, expectedScore, best, expected)
+			}
+		}
+	}
+	engine := NewEngine(WithAlgorithm(AlgorithmWorkStealing), WithMaxDepth(6), WithTT(1<<16), WithWorkers(4))
+	defer engine.Close()
+	_, expected := MinimaxAlphaBetaPrunning(cNodeFullBoard(), 6, true)
+	if _, score := engine.BestMove(cNodeFullBoard(), true); score != expected {
+		t.Errorf("Expected score %d, got %d", expected, score)
+	}
+}
+
+func TestCheckersReproducible(t *testing.T) {
+	var expected []SearchInfo
+	for run := 0; run < 3; run++ {
+		var infos []SearchInfo
+		engine := NewEngine(WithSearcher(Searcher{Reproducible: true}), WithAlgorithm(AlgorithmConcurrent), WithMaxDepth(5),
+			WithTT(1<<16), WithWorkers(4), WithInfo(func(info SearchInfo) {
+				infos = append(infos, info)
+			}))
+		engine.BestMove(cNodeFullBoard(), true)
+		engine.Close()
+		if run == 0 {
+			expected = infos
+			continue
+		}
+		for i, info := range infos {
+			if info.Score != expected[i].Score || info.Nodes != expected[i].Nodes ||
+				info.PV[0].(cNode).board != expected[i].PV[0].(cNode).board {
+				t.Errorf("Run %d differs at depth %d: %d %d nodes, expected %d %d nodes",
+					run, info.Depth, info.Score, info.Nodes, expected[i].Score, expected[i].Nodes)
+			}
+		}
+	}
+}
+
+func BenchmarkCheckersAlphaBeta(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		MinimaxAlphaBetaPrunning(cNodeFullBoard(), 6, true)
+	}
+}
+
+func BenchmarkCheckersMinimax(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Minimax(cNodeFullBoard(), 4, true)
+	}
+}
+
+func TestCheckersEngineStats(t *testing.T) {
+	engine := NewEngine(WithAlgorithm(AlgorithmConcurrent), WithMaxDepth(5), WithTT(1<<16), WithWorkers(3))
+	defer engine.Close()
+	if stats := engine.Stats(); stats != (EngineStats{}) {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+	engine.BestMove(cNodeFullBoard(), true)
+	engine.BestMove(cNodeFullBoard(), true)
+	stats := engine.Stats()
+	if stats.Searches != 2 || stats.Nodes == 0 || stats.Depth != 5 || stats.TTProbes == 0 || stats.TTHits > stats.TTProbes ||
+		stats.Workers != 3 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	name       string
+	parent     string
+	attributes map[string]int64
+	ended      bool
+}
+
+func (span *testSpan) SetAttribute(key string, value int64) {
+	span.attributes[key] = value
+}
+
+func (span *testSpan) End() {
+	span.ended = true
+}
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (tracer *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &testSpan{name: name, attributes: make(map[string]int64)}
+	if parent, ok := ctx.Value(testSpanKey{}).(*testSpan); ok {
+		span.parent = parent.name
+	}
+	tracer.spans = append(tracer.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func TestCheckersEngineTracer(t *testing.T) {
+	tracer := &testTracer{}
+	engine := NewEngine(WithMaxDepth(3), WithTracer(tracer), WithInfo(func(SearchInfo) {}))
+	_, score := engine.BestMove(cNodeFullBoard(), true)
+	if len(tracer.spans) != 4 {
+		t.Fatalf("Expected search span and three iteration spans, got %d", len(tracer.spans))
+	}
+	search := tracer.spans[0]
+	if search.name != "csa.BestMove" || !search.ended || search.attributes["csa.depth"] != 3 ||
+		search.attributes["csa.score"] != int64(score) || search.attributes["csa.nodes"] == 0 {
+		t.Errorf("Unexpected search span %+v", search)
+	}
+	for i, iteration := range tracer.spans[1:] {
+		if iteration.name != "csa.iteration" || iteration.parent != "csa.BestMove" || !iteration.ended ||
+			iteration.attributes["csa.depth"] != int64(i+1) {
+			t.Errorf("Unexpected iteration span %+v", iteration)
+		}
+	}
+}
+
+func TestCheckersPerft(t *testing.T) {
+	for _, maximizing := range []bool{true, false} {
+		if count := Perft(cNodeFullBoard(), 1, maximizing); count != 7 {
+			t.Errorf("Expected 7 opening moves, got %d", count)
+		}
+	}
+	if Perft(cNodeFullBoard(), 2, true) != 49 {
+		t.Error("Expected 49 positions after both opening moves")
+	}
+}
+
+func TestCheckersValidateNode(t *testing.T) {
+	for _, multiJump := range []bool{false, true} {
+		node := cNodeFullBoard()
+		node.multiJump = multiJump
+		for _, violation := range ValidateNode(node, 5, true) {
+			t.Errorf("Unexpected violation %v", violation)
+		}
+	}
+}
+
+func TestCheckersTexelTuner(t *testing.T) {
+	material := func(node SearchNode) []float64 {
+		b := node.(cNode).board
+		count := func(figures uint64) float64 { return float64(bits.OnesCount64(figures)) }
+		return []float64{count(b[pawns][black]) - count(b[pawns][white]), count(b[kings][black]) - count(b[kings][white])}
+	}
+	truth := &LinearEvaluator{Weights: []float64{100, 300}, Features: material}
+	var positions []LabeledPosition
+	for i := 0; i < 200; i++ {
+		node := cNodeEmpty()
+		// figures on distinct squares, pawns in the first half of the board
+		for square := 0; square < 32; square++ {
+			switch (i*7 + square*square) % 11 {
+			case 0:
+				node.board[pawns][black] = setBit(node.board[pawns][black], square)
+			case 1, 2:
+				node.board[pawns][white] = setBit(node.board[pawns][white], square)
+			case 3:
+				node.board[kings][black] = setBit(node.board[kings][black], square+32*(i%2))
+			case 4:
+				if i%3 == 0 {
+					node.board[kings][white] = setBit(node.board[kings][white], square+32*(1-i%2))
+				}
+			}
+		}
+		positions = append(positions, LabeledPosition{node, WinProbability(truth.Evaluate(node), 400)})
+	}
+	evaluator := &LinearEvaluator{Weights: []float64{50, 50}, Features: material}
+	tuner := TexelTuner{Scale: 400, Step: 16, MinStep: 0.5}
+	if err := tuner.Tune(evaluator, positions); err > 1e-5 {
+		t.Errorf("Tuning error %f of weights %v", err, evaluator.Weights)
+	}
+	for i, weight := range evaluator.Weights {
+		if math.Abs(weight-truth.Weights[i]) > 2 {
+			t.Errorf("Weight %f differs from %f", weight, truth.Weights[i])
+		}
+	}
+	if scale := tuner.FitScale(truth, positions); math.Abs(scale-400) > 1 {
+		t.Errorf("Fitted scale %f instead of 400", scale)
+	}
+}
@@ -0,0 +1,95 @@
+package csa
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+)
+
+// Progress of the iterative deepening of a hashable root, kept so the search can be resumed after Load
+type searchProgress struct {
+	Valid      bool
+	Root       uint64 // canonical hash of the searched node
+	Maximizing bool
+	Depth      int // last finished depth
+	Score      int
+	Best       uint64 // canonical hash of the best child
+}
+
+type checkpointEntry struct {
+	Key   uint64
+	Depth int
+	Score int
+	Bound uint8
+}
+
+type checkpoint struct {
+	Progress searchProgress
+	Entries  []checkpointEntry
+}
+
+var errNoProgress = errors.New("csa: checkpoint does not match the searched node")
+
+func newSearchProgress(node SearchNode, maximizing bool) searchProgress {
+	root, ok := canonicalHash(node)
+	return searchProgress{Valid: ok, Root: root, Maximizing: maximizing}
+}
+
+func (progress *searchProgress) record(depth, score int, best SearchNode) {
+	hash, ok := canonicalHash(best)
+	progress.Valid = progress.Valid && ok
+	progress.Depth, progress.Score, progress.Best = depth, score, hash
+}
+
+// Writes the transposition table and the progress of the current or the last search,
+// e.g. from the info callback during a long analysis
+func (engine *Engine) Save(w io.Writer) error {
+	data := checkpoint{Progress: engine.progress}
+	if engine.tt != nil {
+		engine.tt.mutex.Lock()
+		for _, entry := range engine.tt.entries {
+			if entry.bound != 0 {
+				data.Entries = append(data.Entries, checkpointEntry{entry.key, entry.depth, entry.score, uint8(entry.bound)})
+			}
+		}
+		engine.tt.mutex.Unlock()
+	}
+	return gob.NewEncoder(w).Encode(data)
+}
+
+// Restores the state written by Save, the next search of the same node continues
+// from the depth following the last finished one
+// Entries of the transposition table are dropped if the engine has no table.
+func (engine *Engine) Load(r io.Reader) error {
+	var data checkpoint
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return err
+	}
+	if engine.tt != nil {
+		for _, entry := range data.Entries {
+			engine.tt.store(entry.Key, entry.Depth, entry.Score, ttBound(entry.Bound))
+		}
+	}
+	engine.resumed = data.Progress
+	return nil
+}
+
+// Best child and score of the finished depths of the loaded search of the node
+func (engine *Engine) resume(node SearchNode, maximizing bool) (SearchNode, int, int, error) {
+	progress := engine.resumed
+	engine.resumed = searchProgress{}
+	current := newSearchProgress(node, maximizing)
+	if !progress.Valid || progress.Depth <= 0 || !current.Valid ||
+		current.Root != progress.Root || current.Maximizing != progress.Maximizing {
+		return nil, 0, 0, errNoProgress
+	}
+	for generator := nodeGenerator(node); ; {
+		childNode := generator(playerToMove(node, maximizing))
+		if childNode == nil {
+			return nil, 0, 0, errNoProgress
+		}
+		if hash, ok := canonicalHash(childNode); ok && hash == progress.Best {
+			return childNode, progress.Score, progress.Depth, nil
+		}
+	}
+}
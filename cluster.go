@@ -0,0 +1,179 @@
+package csa
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/rpc"
+	"slices"
+	"sync"
+)
+
+var errNoClusterWorkers = errors.New("csa: no cluster workers")
+
+// Serialization of nodes sent to the cluster workers
+type NodeCodec interface {
+	EncodeNode(node SearchNode) ([]byte, error)
+	DecodeNode(data []byte) (SearchNode, error)
+}
+
+// Alpha-beta search of a subtree requested by the cluster coordinator
+type SubtreeArgs struct {
+	Node       []byte
+	Depth      int
+	Ply        int // of the node in the coordinator's tree
+	Alpha      int
+	Beta       int
+	Maximizing bool
+	History    []uint64 // repetition keys of the positions before the node
+}
+
+type SubtreeReply struct {
+	Score int
+}
+
+// RPC service searching the subtrees of the cluster coordinator, its Searcher has to be configured
+// the same way as the coordinator's one
+type ClusterWorker struct {
+	Searcher Searcher
+	Codec    NodeCodec
+}
+
+func (worker *ClusterWorker) Search(args SubtreeArgs, reply *SubtreeReply) error {
+	node, err := worker.Codec.DecodeNode(args.Node)
+	if err != nil {
+		return err
+	}
+	s := worker.Searcher
+	s.History = args.History
+	s = s.newSearch()
+	_, reply.Score = s.minimaxAlphaBetaPrunningImpl(node, nil, 0, args.Depth, args.Ply, args.Alpha, args.Beta, args.Maximizing)
+	return nil
+}
+
+// Transport of the subtree searches to a single cluster worker
+// The module has no dependencies, so it provides only the net/rpc transport (RPCClusterClient). Other transports,
+// e.g. the client of a gRPC service defined by the user, call ClusterWorker.Search on the worker's side.
+type ClusterClient interface {
+	SearchSubtree(args SubtreeArgs) (SubtreeReply, error)
+}
+
+// Client of the worker served by ServeClusterWorker, connected by rpc.Dial
+func RPCClusterClient(client *rpc.Client) ClusterClient {
+	return rpcClusterClient{client}
+}
+
+type rpcClusterClient struct {
+	client *rpc.Client
+}
+
+func (client rpcClusterClient) SearchSubtree(args SubtreeArgs) (SubtreeReply, error) {
+	var reply SubtreeReply
+	err := client.client.Call("ClusterWorker.Search", args, &reply)
+	return reply, err
+}
+
+// Serves the worker's searches over net/rpc on the listener until it is closed
+func ServeClusterWorker(listener net.Listener, worker *ClusterWorker) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("ClusterWorker", worker); err != nil {
+		return err
+	}
+	server.Accept(listener)
+	return nil
+}
+
+func MinimaxCluster(node SearchNode, depth int, maximizing bool, workers []ClusterClient, codec NodeCodec) (SearchNode, int, error) {
+	return Searcher{}.MinimaxCluster(node, depth, maximizing, workers, codec)
+}
+
+// Root children are searched by the remote cluster workers, each worker searches one child at a time
+// The best root score found so far is sent with the next children like in MinimaxConcurrent.
+// The first failed call aborts the search and its error is returned.
+func (s Searcher) MinimaxCluster(node SearchNode, depth int, maximizing bool, workers []ClusterClient, codec NodeCodec) (SearchNode, int, error) {
+	if len(workers) == 0 {
+		return nil, 0, errNoClusterWorkers
+	}
+	s = s.newSearch()
+	if leaf := s.probeLeaf(node, 0); depth <= 0 || leaf.leaf {
+		return node, s.leafScore(leaf, node, nil, 0, 0), nil
+	}
+	maximizing = playerToMove(node, maximizing)
+	var children []SearchNode
+	for generator := nodeGenerator(node); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			break
+		}
+		children = append(children, childNode)
+	}
+	if len(children) == 0 {
+		return nil, s.noMoveScore(node, nil, 0, 0, maximizing, func() int {
+			s.pushPath(node)
+			defer s.popPath(node)
+			_, passScore := s.minimaxAlphaBetaPrunningImpl(node, nil, 0, depth-1, 1, math.MinInt, math.MaxInt, !maximizing)
+			return passScore
+		}), nil
+	}
+	history := slices.Clone(s.History)
+	if repetitionNode, ok := node.(RepetitionNode); ok {
+		history = append(history, repetitionNode.RepetitionKey())
+	}
+	jobs := make(chan int, len(children))
+	for id := range children {
+		jobs <- id
+	}
+	close(jobs)
+	results := make(chan workerResult, len(children))
+	bound := newRootBound(maximizing)
+	var mutex sync.Mutex
+	var searchErr error
+	for _, worker := range workers {
+		go func() {
+			for id := range jobs {
+				mutex.Lock()
+				failed := searchErr != nil
+				mutex.Unlock()
+				score := MinimaxInitScore(maximizing)
+				if !failed {
+					var err error
+					score, err = s.clusterSearch(worker, codec, children[id], depth, maximizing, bound, history)
+					mutex.Lock()
+					if searchErr == nil {
+						searchErr = err
+					}
+					mutex.Unlock()
+				}
+				bound.update(score, maximizing)
+				results <- workerResult{id, children[id], score}
+			}
+		}()
+	}
+	bestNode, bestScore := s.minimaxConcurrentConsumer(maximizing, results, len(children))
+	if searchErr != nil {
+		return nil, 0, searchErr
+	}
+	return bestNode, bestScore, nil
+}
+
+func (s *Searcher) clusterSearch(worker ClusterClient, codec NodeCodec, childNode SearchNode, depth int, maximizing bool, bound *rootBound, history []uint64) (int, error) {
+	data, err := codec.EncodeNode(childNode)
+	if err != nil {
+		return MinimaxInitScore(maximizing), err
+	}
+	alpha, beta := bound.window(maximizing)
+	args := SubtreeArgs{
+		Node:       data,
+		Depth:      depth - 1,
+		Ply:        1,
+		Alpha:      alpha,
+		Beta:       beta,
+		Maximizing: nextPlayer(childNode, maximizing),
+		History:    history,
+	}
+	reply, err := worker.SearchSubtree(args)
+	if err != nil {
+		return MinimaxInitScore(maximizing), err
+	}
+	return reply.Score, nil
+}
@@ -0,0 +1,74 @@
+package csa
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"testing"
+)
+
+type tttCodec struct{}
+
+func (tttCodec) EncodeNode(node SearchNode) ([]byte, error) {
+	var data []byte
+	for _, row := range node.(tttNode).board {
+		for _, symbol := range row {
+			data = append(data, byte(symbol+1))
+		}
+	}
+	return data, nil
+}
+
+func (tttCodec) DecodeNode(data []byte) (SearchNode, error) {
+	if len(data) != 9 {
+		return nil, errors.New("invalid board")
+	}
+	var node tttNode
+	for i, symbol := range data {
+		node.board[i/3][i%3] = int(symbol) - 1
+	}
+	return node, nil
+}
+
+// Transport calling the worker in the same process
+type tttLocalClient struct {
+	worker *ClusterWorker
+}
+
+func (client tttLocalClient) SearchSubtree(args SubtreeArgs) (SubtreeReply, error) {
+	var reply SubtreeReply
+	err := client.worker.Search(args, &reply)
+	return reply, err
+}
+
+func TestTTTMinimaxCluster(t *testing.T) {
+	var clients []ClusterClient
+	for i := 0; i < 2; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Skip("Cannot listen:", err)
+		}
+		defer listener.Close()
+		go ServeClusterWorker(listener, &ClusterWorker{Codec: tttCodec{}})
+		client, err := rpc.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+		clients = append(clients, RPCClusterClient(client))
+	}
+	// any transport calling the worker
+	clients = append(clients, tttLocalClient{&ClusterWorker{Codec: tttCodec{}}})
+	node := tttNode{}
+	node.board[1][1] = cross
+	for _, maximizing := range []bool{true, false} {
+		expected, expectedScore := MinimaxAlphaBetaPrunning(node, 8, maximizing)
+		best, score, err := MinimaxCluster(node, 8, maximizing, clients, tttCodec{})
+		if err != nil || best != expected || score != expectedScore {
+			t.Errorf("Cluster search differs from alpha-beta %d %d %v%s%s", score, expectedScore, err, best, expected)
+		}
+	}
+	if _, _, err := MinimaxCluster(node, 8, true, nil, tttCodec{}); err == nil {
+		t.Error("Expected error without workers")
+	}
+}
@@ -0,0 +1,50 @@
+// Command csa-gtp is a Go engine searching by MCTS and speaking the Go Text Protocol over stdin and stdout,
+// it can be loaded by GoGui, Sabaki and the match tools, e.g. gogui-twogtp against GnuGo.
+//
+// Usage:
+//
+//	csa-gtp [-iterations 10000] [-exploration 1000] [-minimax 0] [-size 9] [-komi 7.5] [-seed 1]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/goban"
+)
+
+func main() {
+	iterations := flag.Int("iterations", 10000, "MCTS iterations per move")
+	exploration := flag.Float64("exploration", goban.WinScore, "UCT exploration constant")
+	minimaxWeight := flag.Float64("minimax", 0, "weight of the implicit minimax backups")
+	size := flag.Int("size", 9, "initial board size")
+	komi := flag.Float64("komi", goban.DefaultKomi, "initial komi")
+	seed := flag.Int64("seed", 1, "seed of the random playouts")
+	flag.Parse()
+
+	if *iterations < 1 || *size < 2 || *size > goban.MaxSize {
+		fatalf("invalid iterations %d or size %d", *iterations, *size)
+	}
+	gtp := &goban.GTP{
+		Name:    "csa",
+		Version: "1",
+		Rules:   goban.Rules{Size: *size, Komi: *komi},
+		MCTS: csa.MCTS{
+			Iterations:    *iterations,
+			Exploration:   *exploration,
+			MinimaxWeight: *minimaxWeight,
+			Rand:          rand.New(rand.NewSource(*seed)),
+		},
+	}
+	if err := gtp.Run(os.Stdin, os.Stdout); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "csa-gtp: "+format+"\n", args...)
+	os.Exit(2)
+}
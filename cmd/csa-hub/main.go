@@ -0,0 +1,66 @@
+// Command csa-hub is a draughts engine speaking the Hub protocol over stdin and stdout,
+// it can be loaded by the GUIs supporting Hub engines, e.g. Scan's GUI.
+//
+// Usage:
+//
+//	csa-hub [-size 10] [-algorithm alpha-beta] [-workers 0] [-endgame 0]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/checkers"
+)
+
+func main() {
+	algorithmName := flag.String("algorithm", csa.AlgorithmAlphaBeta.String(), "search algorithm of the engine")
+	workers := flag.Int("workers", 0, "workers of the parallel algorithms, zero means GOMAXPROCS")
+	size := flag.Int("size", 10, "board size")
+	multiJump := flag.Bool("multijump", true, "figure which jumped has to continue jumping")
+	flyingKings := flag.Bool("flyingkings", true, "kings move and jump any distance")
+	endgame := flag.Int("endgame", 0, "pieces of the endgame database built by the engine, e.g. 3")
+	flag.Parse()
+
+	algorithm, ok := parseAlgorithm(*algorithmName)
+	if !ok {
+		fatalf("unknown algorithm %q", *algorithmName)
+	}
+	if *size < 4 || *size > 16 || *size%2 != 0 {
+		fatalf("invalid board size %d", *size)
+	}
+	rules := checkers.Rules{Size: *size, MultiJump: *multiJump, FlyingKings: *flyingKings}
+	searcher := csa.Searcher{}
+	if *endgame > 0 {
+		searcher = csa.Searcher{Tablebase: checkers.NewEndgame(rules, *endgame), TablebasePieces: *endgame}
+	}
+	hub := &checkers.Hub{
+		Name:  "csa",
+		Rules: rules,
+		Options: []csa.EngineOption{
+			csa.WithSearcher(searcher),
+			csa.WithAlgorithm(algorithm),
+			csa.WithWorkers(*workers),
+			csa.WithTT(1 << 20),
+		},
+	}
+	if err := hub.Run(os.Stdin, os.Stdout); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func parseAlgorithm(name string) (csa.Algorithm, bool) {
+	for algorithm := csa.Algorithm(0); algorithm.String() != "unknown"; algorithm++ {
+		if algorithm.String() == name {
+			return algorithm, true
+		}
+	}
+	return 0, false
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "csa-hub: "+format+"\n", args...)
+	os.Exit(2)
+}
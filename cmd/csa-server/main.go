@@ -0,0 +1,47 @@
+// Command csa-server serves the analysis of the bundled games over HTTP with JSON bodies, see package server.
+//
+// Usage:
+//
+//	csa-server [-addr :8080] [-max-time 10s] [-max-depth 0] [-tt 65536] [-workers 0] [-concurrency 0] [-rate 0] [-burst 1] [-max-queued 0]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/stepulak/combinatorial-search-algoritms/server"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	maxTime := flag.Duration("max-time", 10*time.Second, "max search time of a request, zero means unlimited")
+	maxDepth := flag.Int("max-depth", 0, "max search depth of a request, zero means unlimited")
+	ttSize := flag.Int("tt", 1<<16, "entries of the transposition table of every request")
+	workers := flag.Int("workers", 0, "workers of the parallel algorithms, zero means GOMAXPROCS")
+	concurrency := flag.Int("concurrency", 0, "searches running at once, zero means no queue")
+	rate := flag.Float64("rate", 0, "searches per second of a client in the queue, zero means unlimited")
+	burst := flag.Int("burst", 1, "searches a client can submit at once above the rate")
+	maxQueued := flag.Int("max-queued", 0, "waiting searches of a client in the queue, zero means unlimited")
+	flag.Parse()
+
+	handler := &server.Server{MaxDepth: *maxDepth, MaxTime: *maxTime, TT: *ttSize, Workers: *workers}
+	if *concurrency > 0 {
+		handler.Queue = &server.Queue{Concurrency: *concurrency, Rate: *rate, Burst: *burst, MaxQueued: *maxQueued}
+	}
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if err := httpServer.ListenAndServe(); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "csa-server: "+format+"\n", args...)
+	os.Exit(2)
+}
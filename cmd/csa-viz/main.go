@@ -0,0 +1,289 @@
+// Command csa-viz plays the bundled games against the csa engine in the terminal
+// and displays the board together with the live statistics of the search.
+//
+// Usage:
+//
+//	csa-viz [-game nim|tictactoe|gomoku|connect4|hex|kalah|othello|checkers|chess] [-gdl rules.kif] [-human first|second|none] [-time 1s] [-depth 0] [-algorithm alpha-beta]
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/checkers"
+	"github.com/stepulak/combinatorial-search-algoritms/games/chess"
+	"github.com/stepulak/combinatorial-search-algoritms/games/connect4"
+	"github.com/stepulak/combinatorial-search-algoritms/games/hex"
+	"github.com/stepulak/combinatorial-search-algoritms/games/kalah"
+	"github.com/stepulak/combinatorial-search-algoritms/games/mnk"
+	"github.com/stepulak/combinatorial-search-algoritms/games/nim"
+	"github.com/stepulak/combinatorial-search-algoritms/games/othello"
+	"github.com/stepulak/combinatorial-search-algoritms/ggp"
+)
+
+type game struct {
+	start   func() csa.SearchNode
+	players [2]string                 // names of the maximizing and minimizing player, the maximizing one moves first
+	played  func(node csa.SearchNode) // called with every played node
+	pieces  int                       // of the endgame tablebase, zero if the game has none
+	probe   csa.TablebaseProber
+}
+
+var games = map[string]func(rules checkers.Rules, pieces int) game{
+	"chess": func(checkers.Rules, int) game {
+		return game{start: func() csa.SearchNode { return chess.New() }, players: [2]string{"white", "black"}, played: func(csa.SearchNode) {}}
+	},
+	"nim": func(checkers.Rules, int) game {
+		return game{start: func() csa.SearchNode { return nim.New(false, 3, 4, 5) }, players: [2]string{"first", "second"}, played: func(csa.SearchNode) {}}
+	},
+	"tictactoe": func(checkers.Rules, int) game {
+		return game{start: func() csa.SearchNode { return mnk.New(mnk.Rules{}) }, players: [2]string{"X", "O"}, played: func(csa.SearchNode) {}}
+	},
+	"gomoku": func(checkers.Rules, int) game {
+		start := mnk.New(mnk.Rules{M: 15, N: 15, K: 5, Radius: 2})
+		return game{start: func() csa.SearchNode { return start }, players: [2]string{"X", "O"}, played: func(csa.SearchNode) {}}
+	},
+	"connect4": func(checkers.Rules, int) game {
+		return game{start: func() csa.SearchNode { return connect4.New() }, players: [2]string{"red", "yellow"}, played: func(csa.SearchNode) {}}
+	},
+	"hex": func(checkers.Rules, int) game {
+		return game{start: func() csa.SearchNode { return hex.New(11) }, players: [2]string{"black", "white"}, played: func(csa.SearchNode) {}}
+	},
+	"kalah": func(checkers.Rules, int) game {
+		return game{start: func() csa.SearchNode { return kalah.New(kalah.Rules{}) }, players: [2]string{"south", "north"}, played: func(csa.SearchNode) {}}
+	},
+	"othello": func(checkers.Rules, int) game {
+		return game{start: func() csa.SearchNode { return othello.New() }, players: [2]string{"black", "white"}, played: func(csa.SearchNode) {}}
+	},
+	"checkers": func(rules checkers.Rules, pieces int) game {
+		start := checkers.New(rules)
+		return game{
+			pieces:  pieces,
+			probe:   checkers.NewEndgame(rules, pieces),
+			start:   func() csa.SearchNode { return start },
+			players: [2]string{"black", "white"},
+			played: func(node csa.SearchNode) {
+				// anticycling, the history is shared by all the nodes of the game
+				start.AddHistory(node.(checkers.Board))
+			},
+		}
+	},
+}
+
+type viz struct {
+	out        io.Writer
+	in         *bufio.Scanner
+	plain      bool // no ANSI escape sequences
+	game       game
+	node       csa.SearchNode
+	maximizing bool
+	moves      []string
+	info       *csa.SearchInfo // of the last engine search
+}
+
+func main() {
+	gameName := flag.String("game", "tictactoe", "game to play: nim, tictactoe, gomoku, connect4, hex, kalah, othello, checkers or chess")
+	gdl := flag.String("gdl", "", "file of the two-player game described in GDL, played instead of the game")
+	human := flag.String("human", "first", "side of the human player: first, second or none")
+	timeLimit := flag.Duration("time", time.Second, "engine's time per move")
+	depth := flag.Int("depth", 0, "engine's max search depth, zero means unlimited")
+	algorithmName := flag.String("algorithm", csa.AlgorithmAlphaBeta.String(), "search algorithm of the engine")
+	workers := flag.Int("workers", 0, "workers of the parallel algorithms, zero means GOMAXPROCS")
+	multiJump := flag.Bool("multijump", false, "checkers figure which jumped has to continue jumping")
+	flyingKings := flag.Bool("flyingkings", false, "checkers kings move and jump any distance")
+	size := flag.Int("size", 8, "checkers board size, e.g. 10 for international draughts")
+	endgame := flag.Int("endgame", 0, "pieces of the checkers endgame database built by the engine, e.g. 3")
+	plain := flag.Bool("plain", false, "do not clear the screen between the moves")
+	flag.Parse()
+
+	newGame, ok := games[*gameName]
+	if !ok {
+		fatalf("unknown game %q", *gameName)
+	}
+	if *gdl != "" {
+		newGame = loadGDL(*gdl)
+	}
+	algorithm, ok := parseAlgorithm(*algorithmName)
+	if !ok {
+		fatalf("unknown algorithm %q", *algorithmName)
+	}
+	humanSide := map[string]int{"first": 0, "second": 1, "none": -1}
+	side, ok := humanSide[*human]
+	if !ok {
+		fatalf("unknown human side %q", *human)
+	}
+
+	if *size < 4 || *size > 16 || *size%2 != 0 {
+		fatalf("invalid board size %d", *size)
+	}
+	rules := checkers.Rules{Size: *size, MultiJump: *multiJump, FlyingKings: *flyingKings}
+
+	v := &viz{out: os.Stdout, in: bufio.NewScanner(os.Stdin), plain: *plain, game: newGame(rules, *endgame), maximizing: true}
+	v.node = v.game.start()
+	searcher := csa.Searcher{}
+	if v.game.pieces > 0 {
+		searcher = csa.Searcher{Tablebase: v.game.probe, TablebasePieces: v.game.pieces}
+	}
+	engine := csa.NewEngine(
+		csa.WithSearcher(searcher),
+		csa.WithAlgorithm(algorithm),
+		csa.WithMaxDepth(*depth),
+		csa.WithTimeLimit(*timeLimit),
+		csa.WithWorkers(*workers),
+		csa.WithTT(1<<20),
+		csa.WithInfo(func(info csa.SearchInfo) {
+			v.info = &info
+			v.render()
+		}),
+	)
+	defer engine.Close()
+
+	for !v.node.IsTerminal() {
+		v.render()
+		var child csa.SearchNode
+		if v.toMove() == side {
+			child = v.ask()
+		} else {
+			child, _ = engine.BestMove(v.node, v.maximizing)
+		}
+		if child == nil {
+			break
+		}
+		v.play(child)
+	}
+	v.render()
+	fmt.Fprintln(v.out, v.result())
+}
+
+// Index of the player to move
+func (v *viz) toMove() int {
+	if v.maximizing {
+		return 0
+	}
+	return 1
+}
+
+func (v *viz) play(child csa.SearchNode) {
+	v.moves = append(v.moves, moveString(child))
+	v.game.played(child)
+	v.node = child
+	if extraTurn, ok := child.(csa.ExtraTurnNode); !ok || !extraTurn.ExtraTurn() {
+		v.maximizing = !v.maximizing
+	}
+}
+
+func (v *viz) children() []csa.SearchNode {
+	var children []csa.SearchNode
+	generator := v.node.SearchNodeGenerator()
+	for child := generator(v.maximizing); child != nil; child = generator(v.maximizing) {
+		children = append(children, child)
+	}
+	return children
+}
+
+// Human's move chosen by its number or notation, nil if the human quits or has no move
+func (v *viz) ask() csa.SearchNode {
+	children := v.children()
+	if len(children) == 0 {
+		return nil
+	}
+	for i, child := range children {
+		fmt.Fprintf(v.out, "%2d) %s\n", i+1, moveString(child))
+	}
+	for {
+		fmt.Fprintf(v.out, "%s to move (number or move, q to quit): ", v.game.players[v.toMove()])
+		if !v.in.Scan() {
+			return nil
+		}
+		answer := strings.TrimSpace(v.in.Text())
+		if answer == "q" {
+			return nil
+		}
+		// the notation first, connect4's moves are numbers too
+		for _, child := range children {
+			if moveString(child) == answer {
+				return child
+			}
+		}
+		if number, err := strconv.Atoi(answer); err == nil && number >= 1 && number <= len(children) {
+			return children[number-1]
+		}
+	}
+}
+
+func (v *viz) render() {
+	if !v.plain {
+		// clear the screen and move the cursor home
+		fmt.Fprint(v.out, "\x1b[H\x1b[2J")
+	}
+	fmt.Fprintln(v.out, v.node)
+	fmt.Fprintf(v.out, "moves: %s\n", strings.Join(v.moves, " "))
+	fmt.Fprintf(v.out, "to move: %s, static score: %d\n", v.game.players[v.toMove()], v.node.Score())
+	if info := v.info; info != nil {
+		fmt.Fprintf(v.out, "depth %d  score %d  nodes %d  nps %d  time %s\n",
+			info.Depth, info.Score, info.Nodes, info.NPS, info.Time.Round(time.Millisecond))
+		line := make([]string, 0, len(info.PV))
+		for _, node := range info.PV {
+			line = append(line, moveString(node))
+		}
+		fmt.Fprintf(v.out, "pv: %s\n", strings.Join(line, " "))
+	}
+	fmt.Fprintln(v.out)
+}
+
+func (v *viz) result() string {
+	if drawNode, ok := v.node.(csa.DrawNode); ok && drawNode.IsDraw() {
+		return "draw"
+	}
+	switch score := v.node.Score(); {
+	case score > 0:
+		return v.game.players[0] + " wins"
+	case score < 0:
+		return v.game.players[1] + " wins"
+	}
+	return "draw"
+}
+
+// Move of the child in the game's notation, or the resulting board if the node does not describe it
+func moveString(child csa.SearchNode) string {
+	if move := csa.MoveOf(child); move != nil {
+		return fmt.Sprint(move)
+	}
+	return strings.ReplaceAll(fmt.Sprint(child), "\n", "/")
+}
+
+// Game of the description in the file, the roles are the players
+func loadGDL(path string) func(checkers.Rules, int) game {
+	description, err := os.ReadFile(path)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	described, err := ggp.Parse(string(description))
+	if err != nil {
+		fatalf("%s: %v", path, err)
+	}
+	return func(checkers.Rules, int) game {
+		return game{start: func() csa.SearchNode { return described.Start() }, players: described.Roles(), played: func(csa.SearchNode) {}}
+	}
+}
+
+func parseAlgorithm(name string) (csa.Algorithm, bool) {
+	for algorithm := csa.Algorithm(0); algorithm.String() != "unknown"; algorithm++ {
+		if algorithm.String() == name {
+			return algorithm, true
+		}
+	}
+	return 0, false
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "csa-viz: "+format+"\n", args...)
+	os.Exit(2)
+}
@@ -0,0 +1,13 @@
+// Loads csa.wasm built from cmd/csa-wasm and resolves to the exported csa object,
+// wasm_exec.js of the Go distribution ($(go env GOROOT)/lib/wasm or misc/wasm) has to be loaded before.
+//
+//	const csa = await loadCSA("csa.wasm");
+//	const game = csa.newGame("connect4", "", []);
+//	const best = await csa.bestMove(game.id, 1000, 0, info => console.log(info.depth, info.pv));
+//	csa.play(game.id, best.move);
+export async function loadCSA(url = "csa.wasm") {
+  const go = new Go();
+  const { instance } = await WebAssembly.instantiateStreaming(fetch(url), go.importObject);
+  go.run(instance);
+  return globalThis.csa;
+}
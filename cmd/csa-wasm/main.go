@@ -0,0 +1,233 @@
+//go:build js && wasm
+
+// Command csa-wasm runs the csa engine in the browser as WebAssembly, it exports the games of games/catalog
+// to JavaScript as the global object csa, see csa.js for loading it:
+//
+//	csa.games() → ["checkers", "chess", ...]
+//	csa.newGame(game, position, moves) → {id, board, moves, toMove, legal, result} or {error}
+//	csa.state(id), csa.play(id, move) → {id, board, moves, toMove, legal, result} or {error}
+//	csa.bestMove(id, timeMs, depth, onInfo) → Promise of {move, score, depth, pv}, the move is not played
+//	csa.stop(id) → the pending bestMove resolves with the finished depths
+//	csa.deleteGame(id)
+//
+// The search is single-threaded in the browser, it yields to the event loop every few milliseconds
+// so that the page stays responsive and stop can cancel it.
+//
+// Build:
+//
+//	GOOS=js GOARCH=wasm go build -o csa.wasm ./cmd/csa-wasm
+package main
+
+import (
+	"context"
+	"fmt"
+	"syscall/js"
+	"time"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/catalog"
+)
+
+// Interval of the search between yielding to the event loop
+const yieldInterval = 20 * time.Millisecond
+
+type game struct {
+	game       catalog.Game
+	node       csa.SearchNode
+	maximizing bool
+	moves      []string
+	cancel     context.CancelFunc // of the running search, nil if there is none
+}
+
+var (
+	games  = map[int]*game{}
+	nextID = 1
+)
+
+func main() {
+	exports := map[string]any{
+		"games": js.FuncOf(func(this js.Value, args []js.Value) any {
+			names := []any{}
+			for _, name := range catalog.Names() {
+				names = append(names, name)
+			}
+			return names
+		}),
+		"newGame":    js.FuncOf(newGame),
+		"state":      js.FuncOf(withGame(func(id int, g *game, args []js.Value) any { return g.state(id) })),
+		"play":       js.FuncOf(withGame(play)),
+		"bestMove":   js.FuncOf(withGame(bestMove)),
+		"stop":       js.FuncOf(withGame(stop)),
+		"deleteGame": js.FuncOf(withGame(deleteGame)),
+	}
+	js.Global().Set("csa", js.ValueOf(exports))
+	// the exported functions are called until the page is closed
+	select {}
+}
+
+func errorValue(message string) any {
+	return map[string]any{"error": message}
+}
+
+// Function of the game of the first argument
+func withGame(f func(id int, g *game, args []js.Value) any) func(js.Value, []js.Value) any {
+	return func(this js.Value, args []js.Value) any {
+		if len(args) == 0 || args[0].Type() != js.TypeNumber {
+			return errorValue("missing game id")
+		}
+		id := args[0].Int()
+		g, ok := games[id]
+		if !ok {
+			return errorValue("unknown game id")
+		}
+		return f(id, g, args[1:])
+	}
+}
+
+// newGame(game, position, moves)
+func newGame(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return errorValue("missing game")
+	}
+	definition, ok := catalog.Games[args[0].String()]
+	if !ok {
+		return errorValue("unknown game " + args[0].String())
+	}
+	position := ""
+	if len(args) > 1 && args[1].Type() == js.TypeString {
+		position = args[1].String()
+	}
+	var moves []string
+	if len(args) > 2 && args[2].Type() == js.TypeObject {
+		for i := 0; i < args[2].Length(); i++ {
+			moves = append(moves, args[2].Index(i).String())
+		}
+	}
+	node, maximizing, err := definition.Play(position, moves)
+	if err != nil {
+		return errorValue(err.Error())
+	}
+	id := nextID
+	nextID++
+	games[id] = &game{game: definition, node: node, maximizing: maximizing, moves: moves}
+	return games[id].state(id)
+}
+
+func (g *game) state(id int) any {
+	moves, legal := []any{}, []any{}
+	for _, move := range g.moves {
+		moves = append(moves, move)
+	}
+	state := map[string]any{"id": id, "board": fmt.Sprint(g.node), "moves": moves}
+	if g.node.IsTerminal() {
+		state["result"] = g.game.Result(g.node)
+	} else {
+		state["toMove"] = g.game.Players[0]
+		if !g.maximizing {
+			state["toMove"] = g.game.Players[1]
+		}
+		for _, child := range catalog.Children(g.node, g.maximizing) {
+			legal = append(legal, catalog.MoveString(child))
+		}
+	}
+	state["legal"] = legal
+	return state
+}
+
+// play(id, move)
+func play(id int, g *game, args []js.Value) any {
+	if g.cancel != nil {
+		return errorValue("engine is thinking")
+	}
+	if len(args) == 0 || g.node.IsTerminal() {
+		return errorValue("illegal move")
+	}
+	child := catalog.Child(g.node, g.maximizing, args[0].String())
+	if child == nil {
+		return errorValue("illegal move " + args[0].String())
+	}
+	g.moves = append(g.moves, args[0].String())
+	g.node, g.maximizing = child, catalog.NextPlayer(child, g.maximizing)
+	return g.state(id)
+}
+
+// bestMove(id, timeMs, depth, onInfo)
+func bestMove(id int, g *game, args []js.Value) any {
+	if g.cancel != nil {
+		return errorValue("engine is thinking")
+	}
+	limit, depth, onInfo := time.Second, 0, js.Undefined()
+	if len(args) > 0 && args[0].Type() == js.TypeNumber {
+		limit = time.Duration(args[0].Int()) * time.Millisecond
+	}
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		depth = args[1].Int()
+	}
+	if len(args) > 2 && args[2].Type() == js.TypeFunction {
+		onInfo = args[2]
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	g.cancel = cancel
+	node, maximizing := g.node, g.maximizing
+	lastYield := time.Now()
+	var last csa.SearchInfo
+	engine := csa.NewEngine(
+		csa.WithSearcher(csa.Searcher{WinScore: g.game.WinScore}),
+		csa.WithMaxDepth(depth),
+		csa.WithTimeLimit(limit),
+		csa.WithTT(1<<16),
+		csa.WithPoll(func() {
+			if time.Since(lastYield) >= yieldInterval {
+				// the sleeping goroutine returns the control to the event loop
+				time.Sleep(time.Millisecond)
+				lastYield = time.Now()
+			}
+		}),
+		csa.WithInfo(func(info csa.SearchInfo) {
+			last = info
+			if onInfo.Type() == js.TypeFunction {
+				onInfo.Invoke(js.ValueOf(infoValue(info.Depth, info.Score, info.PV)))
+			}
+		}),
+	)
+	executor := js.FuncOf(func(this js.Value, promise []js.Value) any {
+		resolve := promise[0]
+		go func() {
+			defer engine.Close()
+			defer cancel()
+			child, score := engine.BestMoveContext(ctx, node, maximizing)
+			g.cancel = nil
+			result := infoValue(last.Depth, score, last.PV)
+			if child != nil {
+				result["move"] = catalog.MoveString(child)
+			}
+			resolve.Invoke(js.ValueOf(result))
+		}()
+		return nil
+	})
+	defer executor.Release()
+	return js.Global().Get("Promise").New(executor)
+}
+
+func infoValue(depth, score int, pv []csa.SearchNode) map[string]any {
+	moves := []any{}
+	for _, node := range pv {
+		moves = append(moves, catalog.MoveString(node))
+	}
+	return map[string]any{"depth": depth, "score": score, "pv": moves}
+}
+
+// stop(id)
+func stop(id int, g *game, args []js.Value) any {
+	if g.cancel != nil {
+		g.cancel()
+	}
+	return nil
+}
+
+// deleteGame(id)
+func deleteGame(id int, g *game, args []js.Value) any {
+	stop(id, g, args)
+	delete(games, id)
+	return nil
+}
@@ -0,0 +1,57 @@
+// Command csa-xboard is a chess engine speaking the XBoard/CECP protocol over stdin and stdout,
+// it can be loaded by XBoard, WinBoard and the tournament tools supporting the protocol, e.g. cutechess-cli.
+//
+// Usage:
+//
+//	csa-xboard [-algorithm alpha-beta] [-workers 0] [-tt 1048576]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/chess"
+	"github.com/stepulak/combinatorial-search-algoritms/xboard"
+)
+
+func main() {
+	algorithmName := flag.String("algorithm", csa.AlgorithmAlphaBeta.String(), "search algorithm of the engine")
+	workers := flag.Int("workers", 0, "workers of the parallel algorithms, zero means GOMAXPROCS")
+	ttSize := flag.Int("tt", 1<<20, "entries of the transposition table")
+	flag.Parse()
+
+	algorithm, ok := parseAlgorithm(*algorithmName)
+	if !ok {
+		fatalf("unknown algorithm %q", *algorithmName)
+	}
+	engine := &xboard.Engine{
+		Name:    "csa",
+		Variant: "normal",
+		Game:    chess.Protocol{},
+		Options: []csa.EngineOption{
+			csa.WithSearcher(csa.Searcher{WinScore: chess.WinScore}),
+			csa.WithAlgorithm(algorithm),
+			csa.WithWorkers(*workers),
+			csa.WithTT(*ttSize),
+		},
+	}
+	if err := engine.Run(os.Stdin, os.Stdout); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func parseAlgorithm(name string) (csa.Algorithm, bool) {
+	for algorithm := csa.Algorithm(0); algorithm.String() != "unknown"; algorithm++ {
+		if algorithm.String() == name {
+			return algorithm, true
+		}
+	}
+	return 0, false
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "csa-xboard: "+format+"\n", args...)
+	os.Exit(2)
+}
@@ -0,0 +1,290 @@
+// Command csa plays and analyzes the bundled games with the csa engine on the command line,
+// a human against the engine, two humans, the engine against itself or a single search of a position.
+// The position is the game's FEN-like string if it has one, followed by the moves in the game's notation.
+//
+// Usage:
+//
+//	csa play [-game tictactoe] [-position ""] [-moves ""] [-human first|second|both|none] [-time 1s] [-depth 0] [-algorithm alpha-beta]
+//	csa analyze [-game tictactoe] [-position ""] [-moves ""] [-time 1s] [-depth 0] [-algorithm alpha-beta]
+//	csa match [-game tictactoe] [-games 2] [-time 0] [-inc 0] [-movetime 100ms] [-depth1 0] [-depth2 0] [-algorithm1 alpha-beta] [-algorithm2 alpha-beta] [-resign-score 0 -resign-moves 0] [-draw-score 0 -draw-moves 0] [-pgn file]
+//	csa games
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/catalog"
+	"github.com/stepulak/combinatorial-search-algoritms/tournament"
+)
+
+// Position of the game after the moves with the engine of the flags
+type session struct {
+	game       catalog.Game
+	node       csa.SearchNode
+	maximizing bool
+	moves      []string
+	engine     *csa.Engine
+	out        io.Writer
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch command := os.Args[1]; command {
+	case "play":
+		play(os.Args[2:])
+	case "analyze":
+		analyze(os.Args[2:])
+	case "match":
+		match(os.Args[2:])
+	case "games":
+		for _, name := range catalog.Names() {
+			position := catalog.Games[name].Position
+			if position == "" {
+				position = "moves only"
+			}
+			fmt.Printf("%-10s %s\n", name, position)
+		}
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: csa play|analyze|match|games [flags], csa <command> -h for the flags")
+	os.Exit(2)
+}
+
+// Parses the common flags of the commands and sets up the session
+func newSession(flags *flag.FlagSet, args []string, info func(csa.SearchInfo)) *session {
+	gameName := flags.String("game", "tictactoe", "game, see csa games")
+	position := flags.String("position", "", "position in the game's format, the initial one if empty")
+	moves := flags.String("moves", "", "moves played from the position separated by spaces, in the game's notation")
+	timeLimit := flags.Duration("time", time.Second, "engine's time per move")
+	depth := flags.Int("depth", 0, "engine's max search depth, zero means unlimited")
+	algorithmName := flags.String("algorithm", csa.AlgorithmAlphaBeta.String(), "search algorithm of the engine")
+	workers := flags.Int("workers", 0, "workers of the parallel algorithms, zero means GOMAXPROCS")
+	ttSize := flags.Int("tt", 1<<20, "entries of the transposition table")
+	flags.Parse(args)
+
+	g, ok := catalog.Games[*gameName]
+	if !ok {
+		fatalf("unknown game %q", *gameName)
+	}
+	algorithm, ok := parseAlgorithm(*algorithmName)
+	if !ok {
+		fatalf("unknown algorithm %q", *algorithmName)
+	}
+	node, maximizing, err := g.Play(*position, strings.Fields(*moves))
+	if err != nil {
+		fatalf("invalid position: %v", err)
+	}
+	s := &session{game: g, node: node, maximizing: maximizing, moves: strings.Fields(*moves), out: os.Stdout}
+	options := []csa.EngineOption{
+		csa.WithSearcher(csa.Searcher{WinScore: g.WinScore}),
+		csa.WithAlgorithm(algorithm),
+		csa.WithMaxDepth(*depth),
+		csa.WithTimeLimit(*timeLimit),
+		csa.WithWorkers(*workers),
+		csa.WithTT(*ttSize),
+	}
+	if info != nil {
+		options = append(options, csa.WithInfo(info))
+	}
+	s.engine = csa.NewEngine(options...)
+	return s
+}
+
+func play(args []string) {
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	human := flags.String("human", "first", "sides of the human players: first, second, both or none")
+	s := newSession(flags, args, nil)
+	defer s.engine.Close()
+	humanSides := map[string][2]bool{"first": {true, false}, "second": {false, true}, "both": {true, true}, "none": {}}
+	sides, ok := humanSides[*human]
+	if !ok {
+		fatalf("unknown human side %q", *human)
+	}
+	in := bufio.NewScanner(os.Stdin)
+	for !s.node.IsTerminal() {
+		fmt.Fprintf(s.out, "%s\n\n", s.node)
+		children := catalog.Children(s.node, s.maximizing)
+		if len(children) == 0 {
+			break
+		}
+		player := s.game.Players[s.toMove()]
+		var child csa.SearchNode
+		if sides[s.toMove()] {
+			child = s.ask(in, player, children)
+			if child == nil {
+				return
+			}
+		} else {
+			var score int
+			child, score = s.engine.BestMove(s.node, s.maximizing)
+			if child == nil {
+				break
+			}
+			fmt.Fprintf(s.out, "%s plays %s, score %d\n", player, catalog.MoveString(child), score)
+		}
+		s.play(child)
+	}
+	fmt.Fprintf(s.out, "%s\n\nmoves: %s\n%s\n", s.node, strings.Join(s.moves, " "), s.game.Result(s.node))
+}
+
+func analyze(args []string) {
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	out := os.Stdout
+	s := newSession(flags, args, func(info csa.SearchInfo) {
+		line := make([]string, 0, len(info.PV))
+		for _, node := range info.PV {
+			line = append(line, catalog.MoveString(node))
+		}
+		fmt.Fprintf(out, "depth %d score %d nodes %d nps %d time %s pv %s\n", info.Depth, info.Score, info.Nodes,
+			info.NPS, info.Time.Round(time.Millisecond), strings.Join(line, " "))
+	})
+	defer s.engine.Close()
+	fmt.Fprintf(out, "%s\n\n", s.node)
+	if s.node.IsTerminal() {
+		fmt.Fprintln(out, s.game.Result(s.node))
+		return
+	}
+	child, score := s.engine.BestMove(s.node, s.maximizing)
+	if child == nil {
+		fmt.Fprintln(out, "no move")
+		return
+	}
+	fmt.Fprintf(out, "best move %s score %d\n", catalog.MoveString(child), score)
+}
+
+// Plays the engines of two configurations against each other, prints the records and the score
+func match(args []string) {
+	flags := flag.NewFlagSet("match", flag.ExitOnError)
+	gameName := flags.String("game", "tictactoe", "game, see csa games")
+	position := flags.String("position", "", "start position of the games in the game's format, the initial one if empty")
+	moves := flags.String("moves", "", "moves played from the position before the engines start")
+	games := flags.Int("games", 2, "games of the match, the engines swap the sides after every game")
+	base := flags.Duration("time", 0, "engine's time for the whole game, zero uses movetime")
+	increment := flags.Duration("inc", 0, "time added to the engine's clock after every move")
+	moveTime := flags.Duration("movetime", 100*time.Millisecond, "engine's time per move without the game time")
+	maxPlies := flags.Int("max-plies", 0, "longer games are adjudicated as draws, zero means unlimited")
+	resignScore := flags.Int("resign-score", 0, "engine resigns with its score at most -resign-score for resign-moves moves")
+	resignMoves := flags.Int("resign-moves", 0, "own moves of the resignation score in a row, zero disables resignation")
+	drawScore := flags.Int("draw-score", 0, "game is a draw with the scores within ±draw-score for draw-moves moves")
+	drawMoves := flags.Int("draw-moves", 0, "moves of the draw scores in a row, zero disables the draw adjudication")
+	drawStart := flags.Int("draw-start", 0, "moves played before the draw can be adjudicated")
+	ttSize := flags.Int("tt", 1<<20, "entries of the transposition table of each engine")
+	event := flags.String("event", "", "event tag of the records")
+	pgn := flags.String("pgn", "", "file of the game records, standard output if empty")
+	var players [2]tournament.Player
+	var depths [2]*int
+	var algorithmNames [2]*string
+	for i := range players {
+		n := i + 1
+		depths[i] = flags.Int(fmt.Sprintf("depth%d", n), 0, fmt.Sprintf("max search depth of engine %d, zero means unlimited", n))
+		algorithmNames[i] = flags.String(fmt.Sprintf("algorithm%d", n), csa.AlgorithmAlphaBeta.String(), fmt.Sprintf("search algorithm of engine %d", n))
+	}
+	flags.Parse(args)
+
+	for i := range players {
+		algorithm, ok := parseAlgorithm(*algorithmNames[i])
+		if !ok {
+			fatalf("unknown algorithm %q", *algorithmNames[i])
+		}
+		players[i] = tournament.Player{
+			Name:    fmt.Sprintf("%s-%d", algorithm, *depths[i]),
+			Options: []csa.EngineOption{csa.WithAlgorithm(algorithm), csa.WithMaxDepth(*depths[i]), csa.WithTT(*ttSize)},
+		}
+	}
+	if players[0].Name == players[1].Name {
+		players[0].Name, players[1].Name = players[0].Name+" (1)", players[1].Name+" (2)"
+	}
+	var records io.Writer = os.Stdout
+	if *pgn != "" {
+		file, err := os.Create(*pgn)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		defer file.Close()
+		records = file
+	}
+	adjudicator := csa.Adjudicator{ResignScore: *resignScore, ResignMoves: *resignMoves, DrawScore: *drawScore,
+		DrawMoves: *drawMoves, DrawStart: *drawStart}
+	m := tournament.Match{
+		Game:        *gameName,
+		Position:    *position,
+		Moves:       strings.Fields(*moves),
+		Players:     players,
+		Games:       *games,
+		TimeControl: tournament.TimeControl{Base: *base, Increment: *increment, MoveTime: *moveTime},
+		MaxPlies:    *maxPlies,
+		Adjudicator: adjudicator,
+		Event:       *event,
+		Records:     records,
+	}
+	result, err := m.Run()
+	if err != nil {
+		fatalf("%v", err)
+	}
+	elo, margin := result.Elo()
+	fmt.Printf("%s vs %s: +%d =%d -%d, Elo %+.1f ± %.1f\n", players[0].Name, players[1].Name,
+		result.Wins, result.Draws, result.Losses, elo, margin)
+}
+
+// Index of the player to move
+func (s *session) toMove() int {
+	if s.maximizing {
+		return 0
+	}
+	return 1
+}
+
+func (s *session) play(child csa.SearchNode) {
+	s.moves = append(s.moves, catalog.MoveString(child))
+	s.node, s.maximizing = child, catalog.NextPlayer(child, s.maximizing)
+}
+
+// Human's move, nil if the human quits
+func (s *session) ask(in *bufio.Scanner, player string, children []csa.SearchNode) csa.SearchNode {
+	moves := make([]string, len(children))
+	for i, child := range children {
+		moves[i] = catalog.MoveString(child)
+	}
+	fmt.Fprintf(s.out, "moves: %s\n", strings.Join(moves, " "))
+	for {
+		fmt.Fprintf(s.out, "%s to move (q to quit): ", player)
+		if !in.Scan() {
+			return nil
+		}
+		answer := strings.TrimSpace(in.Text())
+		if answer == "q" {
+			return nil
+		}
+		if child := catalog.Child(s.node, s.maximizing, answer); child != nil {
+			return child
+		}
+		fmt.Fprintf(s.out, "illegal move %q\n", answer)
+	}
+}
+
+func parseAlgorithm(name string) (csa.Algorithm, bool) {
+	for algorithm := csa.Algorithm(0); algorithm.String() != "unknown"; algorithm++ {
+		if algorithm.String() == name {
+			return algorithm, true
+		}
+	}
+	return 0, false
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "csa: "+format+"\n", args...)
+	os.Exit(2)
+}
@@ -0,0 +1,76 @@
+package csa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Search compared by DebugCompare, e.g. MinimaxAlphaBetaPrunning or a configured Searcher's method
+type SearchFunc func(node SearchNode, depth int, maximizing bool) (SearchNode, int)
+
+// Node whose scores of the compared searches differ while they agree on all of its children,
+// or which was searched to the depth zero
+type Disagreement struct {
+	Line       []SearchNode // from the root to the diverging node, the root itself is not included
+	Depth      int          // remaining search depth of the diverging node
+	Maximizing bool         // player to move in the diverging node
+	Scores     [2]int
+	BestNodes  [2]SearchNode // best children of the diverging node found by the searches
+}
+
+func (disagreement *Disagreement) String() string {
+	line := make([]string, len(disagreement.Line))
+	for i, node := range disagreement.Line {
+		if move := MoveOf(node); move != nil {
+			line[i] = fmt.Sprint(move)
+		} else {
+			line[i] = fmt.Sprint(node)
+		}
+	}
+	return fmt.Sprintf("scores %d and %d differ at depth %d after [%s]",
+		disagreement.Scores[0], disagreement.Scores[1], disagreement.Depth, strings.Join(line, " "))
+}
+
+// Searches the node by both searches and follows the children with differing scores down to
+// the node where the searches diverge, nil if they agree on the root's score
+// Every visited node is searched by both searches again, so it is meant for small depths only.
+func DebugCompare(node SearchNode, depth int, maximizing bool, a, b SearchFunc) *Disagreement {
+	maximizing = playerToMove(node, maximizing)
+	bestA, scoreA := a(node, depth, maximizing)
+	bestB, scoreB := b(node, depth, maximizing)
+	if scoreA == scoreB {
+		return nil
+	}
+	disagreement := &Disagreement{}
+	for {
+		disagreement.Depth = depth
+		disagreement.Maximizing = maximizing
+		disagreement.Scores = [2]int{scoreA, scoreB}
+		disagreement.BestNodes = [2]SearchNode{bestA, bestB}
+		if depth <= 0 || node.IsTerminal() {
+			return disagreement
+		}
+		diverging := false
+		for generator := nodeGenerator(node); ; {
+			childNode := generator(maximizing)
+			if childNode == nil {
+				break
+			}
+			childMaximizing := playerToMove(childNode, nextPlayer(childNode, maximizing))
+			childBestA, childScoreA := a(childNode, depth-1, childMaximizing)
+			childBestB, childScoreB := b(childNode, depth-1, childMaximizing)
+			if childScoreA != childScoreB {
+				node, maximizing = childNode, childMaximizing
+				bestA, bestB, scoreA, scoreB = childBestA, childBestB, childScoreA, childScoreB
+				diverging = true
+				break
+			}
+		}
+		if !diverging {
+			// the children agree, the searches differ in backing up their scores
+			return disagreement
+		}
+		disagreement.Line = append(disagreement.Line, node)
+		depth--
+	}
+}
@@ -0,0 +1,24 @@
+package csa
+
+import "testing"
+
+func TestTTTDebugCompare(t *testing.T) {
+	node := tttNode{}
+	node.board = [3][3]int{{cross, circle, cross}, {circle, empty, empty}, {empty, empty, empty}}
+	for _, search := range []SearchFunc{MinimaxAlphaBetaPrunning, MinimaxAlphaBetaIterative} {
+		if disagreement := DebugCompare(node, 9, true, Minimax, search); disagreement != nil {
+			t.Errorf("Unexpected disagreement %v", disagreement)
+		}
+	}
+	shifted := Searcher{Evaluator: EvaluatorFunc(func(node SearchNode) int {
+		return node.Score() + 1
+	})}
+	disagreement := DebugCompare(node, 9, true, MinimaxAlphaBetaPrunning, shifted.MinimaxAlphaBetaPrunning)
+	if disagreement == nil || len(disagreement.Line) == 0 {
+		t.Fatal("Expected disagreement")
+	}
+	last := disagreement.Line[len(disagreement.Line)-1]
+	if !last.IsTerminal() || disagreement.Scores != [2]int{last.Score(), last.Score() + 1} {
+		t.Errorf("Invalid disagreement %v", disagreement)
+	}
+}
@@ -0,0 +1,121 @@
+package csa
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Observer recording the explored tree, which can be rendered by Graphviz from WriteDOT output
+// Nodes are labeled by their fmt representation, e.g. String method, with the alpha-beta window and the score.
+// Not safe for concurrent use, so it should observe sequential searches only.
+type TreeRecorder struct {
+	MaxNodes  int // nodes entered after the limit are left out, unlimited if zero
+	Truncated int // number of left out nodes
+	nodes     []recordedNode
+	stack     []int // of the entered nodes, -1 for left out ones
+	lastChild int
+}
+
+type recordedNode struct {
+	parent    int
+	label     string
+	ply       int
+	alpha     int
+	beta      int
+	score     int
+	leaf      bool
+	cutoff    bool
+	bestChild int
+}
+
+func (recorder *TreeRecorder) Observe(event SearchEvent) {
+	top := -1
+	if len(recorder.stack) > 0 {
+		top = recorder.stack[len(recorder.stack)-1]
+	}
+	switch event.Kind {
+	case EventEnter:
+		if (recorder.MaxNodes > 0 && len(recorder.nodes) >= recorder.MaxNodes) || (top == -1 && len(recorder.stack) > 0) {
+			recorder.Truncated++
+			recorder.stack = append(recorder.stack, -1)
+			return
+		}
+		recorder.nodes = append(recorder.nodes, recordedNode{
+			parent:    top,
+			label:     fmt.Sprint(event.Node),
+			ply:       event.Ply,
+			alpha:     event.Alpha,
+			beta:      event.Beta,
+			bestChild: -1,
+		})
+		recorder.stack = append(recorder.stack, len(recorder.nodes)-1)
+	case EventBest:
+		if top != -1 {
+			recorder.nodes[top].bestChild = recorder.lastChild
+		}
+	case EventCutoff:
+		if top != -1 {
+			recorder.nodes[top].cutoff = true
+		}
+	case EventLeaf, EventLeave:
+		if top != -1 {
+			recorder.nodes[top].score = event.Score
+			recorder.nodes[top].leaf = event.Kind == EventLeaf
+		}
+		recorder.stack = recorder.stack[:len(recorder.stack)-1]
+		recorder.lastChild = top
+	}
+}
+
+// Forgets the recorded tree, so the recorder can be used by the next search
+func (recorder *TreeRecorder) Reset() {
+	recorder.nodes = nil
+	recorder.stack = nil
+	recorder.Truncated = 0
+}
+
+// Writes the recorded tree in Graphviz DOT format
+// Edges to the best children are bold, the nodes whose remaining children were prunned are red.
+func (recorder *TreeRecorder) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph search {\n\tnode [shape=box, fontname=\"monospace\"];\n")
+	for id, node := range recorder.nodes {
+		label := fmt.Sprintf("%s\\lα=%s β=%s\\lscore=%s\\l", dotEscape(node.label), dotScore(node.alpha),
+			dotScore(node.beta), dotScore(node.score))
+		attributes := ""
+		if node.cutoff {
+			label += "cutoff\\l"
+			attributes = ", color=red"
+		} else if node.leaf {
+			attributes = ", style=rounded"
+		}
+		fmt.Fprintf(&sb, "\tn%d [label=\"%s\"%s];\n", id, label, attributes)
+		if node.parent != -1 {
+			style := ""
+			if recorder.nodes[node.parent].bestChild == id {
+				style = " [style=bold]"
+			}
+			fmt.Fprintf(&sb, "\tn%d -> n%d%s;\n", node.parent, id, style)
+		}
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func dotEscape(label string) string {
+	label = strings.TrimRight(label, "\n")
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\l`).Replace(label)
+}
+
+func dotScore(score int) string {
+	switch score {
+	case math.MinInt:
+		return "-∞"
+	case math.MaxInt:
+		return "+∞"
+	}
+	return fmt.Sprint(score)
+}
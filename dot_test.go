@@ -0,0 +1,36 @@
+package csa
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTTTTreeRecorder(t *testing.T) {
+	node := tttNode{}
+	node.board = [3][3]int{{cross, circle, cross}, {circle, cross, empty}, {empty, empty, circle}}
+	recorder := &TreeRecorder{}
+	_, score := Searcher{Observer: recorder}.MinimaxAlphaBetaPrunning(node, 9, true)
+	var dot strings.Builder
+	if err := recorder.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range []string{
+		"digraph search {",
+		`n0 [label="X O X \lO X _ \l_ _ O \lα=-∞ β=+∞\lscore=` + fmt.Sprint(score) + `\l"];`,
+		"n0 -> n1",
+		"[style=bold]",
+		"cutoff\\l\", color=red]",
+	} {
+		if !strings.Contains(dot.String(), part) {
+			t.Errorf("Missing %s in\n%s", part, dot.String())
+		}
+	}
+	recorder = &TreeRecorder{MaxNodes: 3}
+	Searcher{Observer: recorder}.MinimaxAlphaBetaPrunning(node, 9, true)
+	dot.Reset()
+	recorder.WriteDOT(&dot)
+	if strings.Count(dot.String(), "[label=") != 3 || recorder.Truncated == 0 {
+		t.Errorf("Expected three recorded nodes, got\n%s", dot.String())
+	}
+}
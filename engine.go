@@ -0,0 +1,407 @@
+package csa
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Search algorithm used by Engine
+type Algorithm int
+
+const (
+	AlgorithmAlphaBeta Algorithm = iota
+	AlgorithmMinimax
+	AlgorithmConcurrent
+	AlgorithmRollout
+	AlgorithmWorkStealing
+	AlgorithmYBWC
+	// Alpha-beta with helper workers filling the transposition table, the table of lazySMPTableSize entries
+	// is created if not configured by WithTT
+	AlgorithmLazySMP
+)
+
+// Search configured once and called repeatedly, keeps its transposition table and workers between the searches
+// Not safe for concurrent use, concurrent search is configured by WithWorkers. Close stops the workers.
+type Engine struct {
+	searcher    Searcher
+	algorithm   Algorithm
+	maxDepth    int
+	timeLimit   time.Duration
+	workers     int
+	tt          *transpositionTable
+	info        func(info SearchInfo)
+	skill       *engineSkill
+	timeManager TimeManager
+	progress    searchProgress // of the current or the last search
+	resumed     searchProgress // loaded from checkpoint
+	pool        *workerPool    // workers of the concurrent algorithm, created by the first search
+	stats       engineStats
+	tracer      Tracer
+	logger      *slog.Logger
+	book        OpeningBook
+	poll        func()
+}
+
+// Report of a finished depth of iterative deepening, similar to UCI info line
+type SearchInfo struct {
+	Depth int
+	Score int
+	PV    []SearchNode // principal variation starting with the best root child, could be cut by the transposition table
+	Nodes int64        // searched so far including the previous depths
+	NPS   int64
+	Time  time.Duration
+}
+
+type EngineOption func(engine *Engine)
+
+// Engine searching by alpha-beta, without any limit it searches the whole game tree
+func NewEngine(options ...EngineOption) *Engine {
+	engine := &Engine{}
+	for _, option := range options {
+		option(engine)
+	}
+	return engine
+}
+
+// Scoring, tie-breaking and other search configuration
+func WithSearcher(searcher Searcher) EngineOption {
+	return func(engine *Engine) {
+		engine.searcher = searcher
+	}
+}
+
+func WithAlgorithm(algorithm Algorithm) EngineOption {
+	return func(engine *Engine) {
+		engine.algorithm = algorithm
+	}
+}
+
+// Max search depth, zero means unlimited
+func WithMaxDepth(depth int) EngineOption {
+	return func(engine *Engine) {
+		engine.maxDepth = depth
+	}
+}
+
+// Search iteratively deepens until the time limit, the result of the last finished depth is returned
+// The first depth is always finished.
+func WithTimeLimit(limit time.Duration) EngineOption {
+	return func(engine *Engine) {
+		engine.timeLimit = limit
+	}
+}
+
+// Time manager used by BestMoveWithClock
+func WithTimeManager(tm TimeManager) EngineOption {
+	return func(engine *Engine) {
+		engine.timeManager = tm
+	}
+}
+
+// Transposition table with given number of entries used by alpha-beta based algorithms for HashNode nodes,
+// the rollouts store only the solved nodes
+// With WinScore set, the scores above WinScore/2 are considered to be wins adjusted by distance.
+func WithTT(size int) EngineOption {
+	return func(engine *Engine) {
+		engine.tt = newTranspositionTable(size)
+	}
+}
+
+// Callback called after every finished depth, the search iteratively deepens even without the time limit
+func WithInfo(callback func(info SearchInfo)) EngineOption {
+	return func(engine *Engine) {
+		engine.info = callback
+	}
+}
+
+// Number of workers of the concurrent algorithm, runtime.GOMAXPROCS(0) by default
+func WithWorkers(workers int) EngineOption {
+	return func(engine *Engine) {
+		engine.workers = workers
+	}
+}
+
+// Opening book consulted before every search, the score of a book move is the child's static score
+func WithBook(book OpeningBook) EngineOption {
+	return func(engine *Engine) {
+		engine.book = book
+	}
+}
+
+// Function called every 1024 searched nodes by the searching goroutines, e.g. to yield to the JavaScript event loop
+// under WebAssembly, where the cancellation of the context could not be delivered during the search otherwise
+func WithPoll(poll func()) EngineOption {
+	return func(engine *Engine) {
+		engine.poll = poll
+	}
+}
+
+// Best child of the node and its score
+func (engine *Engine) BestMove(node SearchNode, maximizing bool) (SearchNode, int) {
+	return engine.bestMove(context.Background(), node, maximizing, engine.timeLimit, engine.timeLimit, nil)
+}
+
+// BestMove which can be cancelled by the context, also in the middle of a parallel search
+// Result of the last finished depth is returned, the first depth is always finished like with the time limit.
+func (engine *Engine) BestMoveContext(ctx context.Context, node SearchNode, maximizing bool) (SearchNode, int) {
+	return engine.bestMove(ctx, node, maximizing, engine.timeLimit, engine.timeLimit, nil)
+}
+
+// Best child of the node searched for the time decided by the time manager according to the clock
+// The time manager is configured by WithTimeManager, the time limit of the engine is ignored.
+func (engine *Engine) BestMoveWithClock(node SearchNode, maximizing bool, clock Clock) (SearchNode, int) {
+	soft, hard := engine.timeManager.Allocate(clock)
+	return engine.bestMove(context.Background(), node, maximizing, soft, hard, &engine.timeManager)
+}
+
+// Iterative deepening does not start the next depth after the soft limit and aborts the search after the hard limit
+// The time manager can extend the soft limit up to the hard one according to the results of the finished depths.
+func (engine *Engine) bestMove(ctx context.Context, node SearchNode, maximizing bool, soft, hard time.Duration, tm *TimeManager) (bestNode SearchNode, bestScore int) {
+	ctx, span := engine.startSpan(ctx, "csa.BestMove")
+	defer func() {
+		span.SetAttribute("csa.score", int64(bestScore))
+		span.End()
+	}()
+	if engine.book != nil {
+		if bookNode := engine.book.Probe(node, maximizing); bookNode != nil {
+			engine.log(ctx, slog.LevelDebug, "book move")
+			return bookNode, engine.searcher.score(bookNode)
+		}
+	}
+	if engine.skill != nil {
+		return engine.skillMove(ctx, node, maximizing, soft, hard)
+	}
+	if engine.algorithm == AlgorithmLazySMP && engine.tt == nil {
+		engine.tt = newTranspositionTable(lazySMPTableSize)
+		engine.log(ctx, slog.LevelInfo, "transposition table created", "entries", lazySMPTableSize)
+	}
+	s := engine.searcher.newSearch()
+	s.tt = engine.tt
+	s.limits = &searchLimits{poll: engine.poll}
+	s.pv = &pvTable{}
+	start := time.Now()
+	engine.stats.tt.Store(engine.tt)
+	finished := engine.maxDepth
+	engine.log(ctx, slog.LevelInfo, "search started", "algorithm", engine.algorithm, "max_depth", engine.maxDepth,
+		"soft_limit", soft, "hard_limit", hard)
+	defer func() {
+		engine.stats.record(s.limits, start, finished)
+		span.SetAttribute("csa.depth", int64(finished))
+		span.SetAttribute("csa.nodes", s.limits.nodes.Load())
+		engine.log(ctx, slog.LevelInfo, "search finished", "depth", finished, "score", bestScore,
+			"nodes", s.limits.nodes.Load(), "elapsed", time.Since(start))
+	}()
+	defer engine.logPanic(ctx)
+	if engine.algorithm == AlgorithmRollout {
+		labeled(ctx, s.ProfileLabels, func() {
+			bestNode, bestScore = engine.rollout(ctx, s, node, maximizing, hard)
+		}, PhaseLabel, "rollout")
+		engine.report(s, start, engine.depth(), bestScore, []SearchNode{bestNode})
+		return bestNode, bestScore
+	}
+	if engine.algorithm == AlgorithmLazySMP {
+		defer engine.startHelpers(s, node, maximizing)()
+	}
+	if hard <= 0 && engine.info == nil && ctx.Done() == nil {
+		return engine.search(s, node, engine.depth(), maximizing)
+	}
+	// iterative deepening
+	bestNode, bestScore, firstDepth, err := engine.resume(node, maximizing)
+	if err == nil {
+		// the result of the loaded depth is known, so even the first depth can be aborted
+		s.limits.abortAfter(ctx, start, hard)
+	}
+	engine.progress = newSearchProgress(node, maximizing)
+	if err == nil {
+		engine.progress.record(firstDepth, bestScore, bestNode)
+		engine.log(ctx, slog.LevelInfo, "checkpoint resumed", "depth", firstDepth, "score", bestScore)
+	}
+	finished = firstDepth
+	var feedback searchFeedback
+	for depth := firstDepth + 1; depth <= engine.depth(); depth++ {
+		s.limits.horizon.Store(0)
+		var childNode SearchNode
+		var score int
+		_, iterationSpan := engine.startSpan(ctx, "csa.iteration")
+		labeled(ctx, s.ProfileLabels, func() {
+			childNode, score = engine.search(s, node, depth, maximizing)
+		}, PhaseLabel, "iteration", "csa.depth", strconv.Itoa(depth))
+		iterationSpan.SetAttribute("csa.depth", int64(depth))
+		iterationSpan.SetAttribute("csa.nodes", s.limits.nodes.Load())
+		if s.limits.stopped.Load() {
+			iterationSpan.SetAttribute("csa.aborted", 1)
+			iterationSpan.End()
+			break
+		}
+		iterationSpan.SetAttribute("csa.score", int64(score))
+		iterationSpan.End()
+		if bestNode != nil {
+			feedback.update(bestNode, childNode, bestScore, score, maximizing)
+		}
+		bestNode, bestScore = childNode, score
+		engine.progress.record(depth, score, bestNode)
+		finished = depth
+		engine.log(ctx, slog.LevelDebug, "depth finished", "depth", depth, "score", score,
+			"nodes", s.limits.nodes.Load(), "elapsed", time.Since(start))
+		engine.report(s, start, depth, score, s.pv.line(0))
+		limit := soft
+		if tm != nil {
+			limit = tm.extend(soft, hard, feedback)
+		}
+		if s.limits.horizon.Load() == 0 || (limit > 0 && time.Since(start) >= limit) || ctx.Err() != nil {
+			// whole game tree searched, out of time or cancelled
+			break
+		}
+		s.limits.abortAfter(ctx, start, hard)
+	}
+	return bestNode, bestScore
+}
+
+func (engine *Engine) report(s Searcher, start time.Time, depth, score int, pv []SearchNode) {
+	if engine.info == nil {
+		return
+	}
+	elapsed := time.Since(start)
+	nodes := s.limits.nodes.Load()
+	engine.info(SearchInfo{
+		Depth: depth,
+		Score: score,
+		PV:    pv,
+		Nodes: nodes,
+		NPS:   int64(float64(nodes) / max(elapsed.Seconds(), 1e-9)),
+		Time:  elapsed,
+	})
+}
+
+// Stops the workers of the concurrent algorithm, the next search would start new ones
+func (engine *Engine) Close() {
+	if engine.pool != nil {
+		engine.pool.close()
+		engine.pool = nil
+		engine.stats.pool.Store(nil)
+	}
+}
+
+// Forgets everything learned in the previous searches
+func (engine *Engine) Clear() {
+	if engine.tt != nil {
+		engine.tt.clear()
+	}
+}
+
+func (engine *Engine) depth() int {
+	if engine.maxDepth <= 0 {
+		return math.MaxInt
+	}
+	return engine.maxDepth
+}
+
+func (engine *Engine) search(s Searcher, node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	switch engine.algorithm {
+	case AlgorithmMinimax:
+		return s.minimaxImpl(node, nil, 0, depth, 0, maximizing)
+	case AlgorithmConcurrent:
+		pool := engine.workerPool(func() *workerPool {
+			return newWorkerPool(engine.workers)
+		})
+		return s.minimaxConcurrent(node, depth, maximizing, pool)
+	case AlgorithmWorkStealing, AlgorithmYBWC:
+		pool := engine.workerPool(func() *workerPool {
+			// the searching goroutine is one of the workers
+			return startWorkerPool(workerCount(engine.workers) - 1)
+		})
+		return s.minimaxWorkStealing(node, depth, maximizing, pool, engine.algorithm == AlgorithmYBWC)
+	}
+	return s.minimaxAlphaBetaPrunningImpl(node, nil, 0, depth, 0, math.MinInt, math.MaxInt, maximizing)
+}
+
+// Worker pool of the engine, started by the first search which needs it
+func (engine *Engine) workerPool(start func() *workerPool) *workerPool {
+	if engine.pool == nil {
+		engine.pool = start()
+		engine.stats.pool.Store(engine.pool)
+	}
+	return engine.pool
+}
+
+// Rollouts until the root is solved, out of time or cancelled, the first rollout is always finished
+func (engine *Engine) rollout(ctx context.Context, s Searcher, node SearchNode, maximizing bool, limit time.Duration) (SearchNode, int) {
+	search := s.rolloutSearch(node, engine.depth(), maximizing)
+	deadline := time.Now().Add(limit)
+	for !search.Rollout() && ctx.Err() == nil && (limit <= 0 || time.Now().Before(deadline)) {
+	}
+	return search.Best()
+}
+
+// Limits and statistics shared by all nodes (and workers) of a single search
+type searchLimits struct {
+	deadline time.Time
+	done     <-chan struct{} // of the context cancelling the search
+	nodes    atomic.Int64
+	stopped  atomic.Bool
+	horizon  atomic.Int64 // number of nodes cut by the depth, deeper search can change the result
+	repeated atomic.Int64 // number of repetition draws, other paths to the nodes can change the result
+	poll     func()       // called with the periodic checks
+}
+
+// Counts the searched node, returns true if the search has to be abandoned
+// Scores of the abandoned search are meaningless.
+func (limits *searchLimits) stop() bool {
+	if limits == nil {
+		return false
+	}
+	nodes := limits.nodes.Add(1)
+	if nodes%1024 == 0 && limits.poll != nil {
+		limits.poll()
+	}
+	if nodes%1024 == 0 && (limits.isCancelled() || (!limits.deadline.IsZero() && time.Now().After(limits.deadline))) {
+		limits.stopped.Store(true)
+	}
+	return limits.stopped.Load()
+}
+
+func (limits *searchLimits) isCancelled() bool {
+	select {
+	case <-limits.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Search is abandoned after given time from the start or when the context is done
+func (limits *searchLimits) abortAfter(ctx context.Context, start time.Time, limit time.Duration) {
+	if limit > 0 {
+		limits.deadline = start.Add(limit)
+	}
+	limits.done = ctx.Done()
+}
+
+func (limits *searchLimits) isStopped() bool {
+	return limits != nil && limits.stopped.Load()
+}
+
+// Number of the leaves whose score is not final, cut by the depth or drawn by repetition
+func (limits *searchLimits) horizonCount() int64 {
+	if limits == nil {
+		return 0
+	}
+	return limits.horizon.Load() + limits.repeated.Load()
+}
+
+// Marks the search as incomplete if the leaf is cut by the depth, or as path dependent if it is a repetition
+func (s *Searcher) markHorizon(leaf leafProbe, depth int) {
+	if s.limits == nil {
+		return
+	}
+	if depth <= 0 && !leaf.leaf {
+		s.limits.horizon.Add(1)
+	}
+	if leaf.repetition {
+		s.limits.repeated.Add(1)
+	}
+}
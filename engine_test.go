@@ -0,0 +1,101 @@
+package csa
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTTTEngine(t *testing.T) {
+	winSearcher := Searcher{
+		Evaluator: EvaluatorFunc(func(node SearchNode) int {
+			_, symbol := node.(tttNode).anyFullRow()
+			return symbol * 100
+		}),
+		WinScore: 100,
+	}
+	for _, searcher := range []Searcher{{}, winSearcher} {
+		for _, algorithm := range []Algorithm{AlgorithmAlphaBeta, AlgorithmMinimax, AlgorithmConcurrent, AlgorithmRollout, AlgorithmWorkStealing, AlgorithmYBWC, AlgorithmLazySMP} {
+			engine := NewEngine(WithSearcher(searcher), WithAlgorithm(algorithm), WithTT(1<<12), WithWorkers(2))
+			// the table is reused along the game
+			var node SearchNode = tttNode{}
+			for maximizing := false; !node.IsTerminal(); maximizing = !maximizing {
+				_, expected := searcher.Minimax(node, 9, maximizing)
+				best, score := engine.BestMove(node, maximizing)
+				if score != expected {
+					t.Errorf("Algorithm %d: expected score %d, got %d %s", algorithm, expected, score, node)
+				}
+				node = best
+			}
+			engine.Close()
+		}
+	}
+	// iterative deepening finishes the whole tree before the time limit
+	engine := NewEngine(WithTimeLimit(time.Minute), WithTT(1<<12))
+	if best, score := engine.BestMove(tttNode{}, true); best == nil || score != 0 {
+		t.Errorf("Expected a draw, got %d", score)
+	}
+	engine = NewEngine(WithMaxDepth(1))
+	if best, _ := engine.BestMove(tttNode{}, true); best.(tttNode).board[0][0] != circle {
+		t.Errorf("Expected first move %s", best)
+	}
+	// cancelled rollouts return the best move of the first one
+	scored := 0
+	counting := Searcher{Evaluator: EvaluatorFunc(func(node SearchNode) int {
+		scored++
+		return node.Score()
+	})}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	engine = NewEngine(WithSearcher(counting), WithAlgorithm(AlgorithmRollout))
+	if best, _ := engine.BestMoveContext(ctx, tttNode{}, true); best == nil {
+		t.Fatal("Expected any move")
+	}
+	cancelled := scored
+	engine.BestMove(tttNode{}, true)
+	if cancelled*10 > scored-cancelled {
+		t.Errorf("Expected the cancelled rollouts to stop early, scored %d of %d nodes", cancelled, scored-cancelled)
+	}
+}
+
+func TestTTTEngineRolloutTT(t *testing.T) {
+	engine := NewEngine(WithAlgorithm(AlgorithmRollout), WithTT(1<<12))
+	_, expected := Minimax(tttNode{}, 9, true)
+	for i := 0; i < 2; i++ {
+		if _, score := engine.BestMove(tttNode{}, true); score != expected {
+			t.Errorf("Expected score %d, got %d", expected, score)
+		}
+	}
+	// the second search finds the solved root children in the table
+	if stats := engine.Stats(); stats.TTHits == 0 || stats.TTHits > stats.TTProbes {
+		t.Errorf("Expected the solved nodes in the table, got %+v", stats)
+	}
+}
+
+func TestTTTEngineInfo(t *testing.T) {
+	var infos []SearchInfo
+	engine := NewEngine(WithInfo(func(info SearchInfo) {
+		infos = append(infos, info)
+	}))
+	best, score := engine.BestMove(tttNode{}, true)
+	if len(infos) != 9 {
+		t.Fatalf("Expected report of every depth, got %d", len(infos))
+	}
+	for i, info := range infos {
+		if info.Depth != i+1 || len(info.PV) != info.Depth || (i > 0 && info.Nodes <= infos[i-1].Nodes) {
+			t.Errorf("Invalid report %+v", info)
+		}
+	}
+	last := infos[len(infos)-1]
+	if last.Score != score || last.PV[0] != best || !last.PV[8].IsTerminal() {
+		t.Errorf("Last report differs from the result %+v", last)
+	}
+	// every node of the line follows the previous one
+	previous := tttNode{}
+	for _, node := range last.PV {
+		if 9-node.(tttNode).numberEmptySquares() != 10-previous.numberEmptySquares() {
+			t.Errorf("Invalid line %s%s", previous, node)
+		}
+		previous = node.(tttNode)
+	}
+}
@@ -0,0 +1,88 @@
+package csa
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Optional interface for nodes which can be identified by hash, e.g. Zobrist hash
+// Different positions are expected to have different hashes
+type HashNode interface {
+	Hash() uint64
+}
+
+// Bounded LRU cache of evaluations keyed by node's Hash, safe for concurrent use
+// Reuse the same cache across searches to share evaluations of transpositions.
+// Nodes not implementing HashNode are evaluated without caching, SymmetryNode's CanonicalHash is preferred.
+type EvalCache struct {
+	mutex     sync.Mutex
+	size      int
+	evaluator Evaluator
+	entries   map[uint64]*list.Element
+	order     *list.List // front = most recently used
+	hits      int
+	misses    int
+}
+
+type evalCacheEntry struct {
+	hash  uint64
+	score int
+}
+
+// Cache results of evaluator (node's Score if evaluator is nil), storing at most size entries
+func NewEvalCache(evaluator Evaluator, size int) *EvalCache {
+	if evaluator == nil {
+		evaluator = EvaluatorFunc(func(node SearchNode) int {
+			return node.Score()
+		})
+	}
+	return &EvalCache{
+		size:      size,
+		evaluator: evaluator,
+		entries:   make(map[uint64]*list.Element),
+		order:     list.New(),
+	}
+}
+
+func (cache *EvalCache) Evaluate(node SearchNode) int {
+	hash, ok := canonicalHash(node)
+	if !ok || cache.size <= 0 {
+		return cache.evaluator.Evaluate(node)
+	}
+	cache.mutex.Lock()
+	if element, found := cache.entries[hash]; found {
+		cache.order.MoveToFront(element)
+		cache.hits++
+		score := element.Value.(evalCacheEntry).score
+		cache.mutex.Unlock()
+		return score
+	}
+	cache.misses++
+	cache.mutex.Unlock()
+	// evaluate without lock, expensive evaluations can run in parallel
+	score := cache.evaluator.Evaluate(node)
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if _, found := cache.entries[hash]; !found {
+		cache.entries[hash] = cache.order.PushFront(evalCacheEntry{hash, score})
+		if cache.order.Len() > cache.size {
+			oldest := cache.order.Back()
+			cache.order.Remove(oldest)
+			delete(cache.entries, oldest.Value.(evalCacheEntry).hash)
+		}
+	}
+	return score
+}
+
+func (cache *EvalCache) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.order.Len()
+}
+
+// Number of cache hits and misses so far
+func (cache *EvalCache) Stats() (int, int) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.hits, cache.misses
+}
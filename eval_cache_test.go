@@ -0,0 +1,28 @@
+package csa
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestTTTEvalCache(t *testing.T) {
+	var evaluations atomic.Int64
+	cache := NewEvalCache(EvaluatorFunc(func(node SearchNode) int {
+		evaluations.Add(1)
+		return node.Score()
+	}), 500)
+	searcher := Searcher{Evaluator: cache}
+	_, score := searcher.Minimax(tttNode{}, 9, true)
+	hits, misses := cache.Stats()
+	if score != 0 || misses != int(evaluations.Load()) || hits == 0 || cache.Len() != 500 {
+		t.Errorf("Unexpected cache stats %d hits, %d misses", hits, misses)
+	}
+	// second search reuses cached evaluations
+	_, score = searcher.MinimaxConcurrent(tttNode{}, 9, true, 4)
+	if newHits, _ := cache.Stats(); score != 0 || newHits <= hits {
+		t.Error("Cache must be reused across searches")
+	}
+	if (tttNode{}).Hash() == (tttNode{board: [3][3]int{{cross}}}).Hash() {
+		t.Error("Different boards must have different hashes")
+	}
+}
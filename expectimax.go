@@ -0,0 +1,191 @@
+package csa
+
+import "math"
+
+// Outcome of a chance node with its probability
+type ChanceOutcome struct {
+	Node        SearchNode
+	Probability float64
+}
+
+// Optional interface for chance nodes, e.g. a dice roll or a spawned tile, scored by the expected score of their outcomes
+// The player to move in the outcomes is the one of the chance node, e.g. the chance node is an ExtraTurnNode child
+// if the player who moved rolls the dice. The outcome probabilities sum to one.
+type ChanceNode interface {
+	IsChance() bool
+	Outcomes() []ChanceOutcome
+}
+
+func chanceOutcomes(node SearchNode) ([]ChanceOutcome, bool) {
+	if chanceNode, ok := node.(ChanceNode); ok && chanceNode.IsChance() {
+		return chanceNode.Outcomes(), true
+	}
+	return nil, false
+}
+
+// Minimax where the chance nodes are scored by the expected score of their outcomes, each chance node is a ply
+// The best child is nil if the root is a chance node.
+func Expectiminimax(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	return Searcher{}.Expectiminimax(node, depth, maximizing)
+}
+
+// Expectiminimax with the Star1 prunning of the chance nodes and alpha-beta prunning of the others,
+// all leaf scores have to be within [lower, upper]. The tighter the bounds, the more is prunned.
+func Star1(node SearchNode, depth int, maximizing bool, lower, upper int) (SearchNode, int) {
+	return Searcher{}.Star1(node, depth, maximizing, lower, upper)
+}
+
+// Star1 which first probes a single child of each outcome of the chance nodes, the bounds it gives could prune
+// the chance node before any outcome is fully searched. Best with good move ordering and regular games
+// where the chance nodes alternate with the players' moves, e.g. backgammon.
+func Star2(node SearchNode, depth int, maximizing bool, lower, upper int) (SearchNode, int) {
+	return Searcher{}.Star2(node, depth, maximizing, lower, upper)
+}
+
+func (s Searcher) Expectiminimax(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	s = s.newSearch()
+	return s.expectimaxImpl(node, nil, 0, depth, 0, math.MinInt, math.MaxInt, maximizing, nil)
+}
+
+func (s Searcher) Star1(node SearchNode, depth int, maximizing bool, lower, upper int) (SearchNode, int) {
+	s = s.newSearch()
+	return s.expectimaxImpl(node, nil, 0, depth, 0, lower, upper, maximizing, &starBounds{lower, upper, false})
+}
+
+func (s Searcher) Star2(node SearchNode, depth int, maximizing bool, lower, upper int) (SearchNode, int) {
+	s = s.newSearch()
+	return s.expectimaxImpl(node, nil, 0, depth, 0, lower, upper, maximizing, &starBounds{lower, upper, true})
+}
+
+// Bounds of the leaf scores, nil disables the prunning
+type starBounds struct {
+	lower, upper int
+	probe        bool // Star2
+}
+
+func (s *Searcher) expectimaxImpl(node, parent SearchNode, parentScore, depth, ply, alpha, beta int, maximizing bool, bounds *starBounds) (SearchNode, int) {
+	if leaf := s.probeLeaf(node, ply); depth <= 0 || leaf.leaf {
+		score := s.leafScore(leaf, node, parent, parentScore, ply)
+		if bounds != nil {
+			score = min(max(score, bounds.lower), bounds.upper)
+		}
+		return node, score
+	}
+	s.pushPath(node)
+	defer s.popPath(node)
+	score := s.interiorScore(node, parent, parentScore)
+	if outcomes, ok := chanceOutcomes(node); ok {
+		return nil, s.chanceScore(node, outcomes, score, depth, ply, alpha, beta, maximizing, bounds)
+	}
+	maximizing = playerToMove(node, maximizing)
+	var bestNode SearchNode
+	bestScore := MinimaxInitScore(maximizing)
+	bestIndex := -1
+	generator := nodeGenerator(node)
+	for index := 0; ; index++ {
+		childNode := generator(maximizing)
+		if childNode == nil && index == 0 {
+			return nil, s.noMoveScore(node, parent, parentScore, ply, maximizing, func() int {
+				_, passScore := s.expectimaxImpl(node, parent, parentScore, depth-1, ply+1, alpha, beta, !maximizing, bounds)
+				return passScore
+			})
+		}
+		if childNode == nil {
+			break
+		}
+		_, newScore := s.expectimaxImpl(childNode, node, score, depth-1, ply+1, alpha, beta, nextPlayer(childNode, maximizing), bounds)
+		if bestNode == nil || (maximizing && newScore > bestScore) || (!maximizing && newScore < bestScore) ||
+			(newScore == bestScore && s.preferTie(childNode, bestNode, index, bestIndex, ply)) {
+			bestNode, bestScore, bestIndex = childNode, newScore, index
+		}
+		if bounds == nil {
+			continue
+		}
+		if maximizing {
+			alpha = max(alpha, bestScore)
+		} else {
+			beta = min(beta, bestScore)
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return bestNode, bestScore
+}
+
+// Expected score of the outcomes, Star1 stops once the rest of the outcomes cannot get it into the window
+func (s *Searcher) chanceScore(node SearchNode, outcomes []ChanceOutcome, score, depth, ply, alpha, beta int, maximizing bool, bounds *starBounds) int {
+	if bounds == nil {
+		expected := 0.0
+		for _, outcome := range outcomes {
+			_, outcomeScore := s.expectimaxImpl(outcome.Node, node, score, depth-1, ply+1, alpha, beta, maximizing, nil)
+			expected += outcome.Probability * float64(outcomeScore)
+		}
+		return int(math.Round(expected))
+	}
+	// bounds of the outcome scores, tightened by the probes and replaced by the searched scores
+	lowers, uppers := make([]float64, len(outcomes)), make([]float64, len(outcomes))
+	lowerSum, upperSum := 0.0, 0.0
+	for i, outcome := range outcomes {
+		lowers[i], uppers[i] = float64(bounds.lower), float64(bounds.upper)
+		lowerSum += outcome.Probability * lowers[i]
+		upperSum += outcome.Probability * uppers[i]
+	}
+	// the window of the outcome which could still get the expected score into the window of the chance node
+	window := func(i int) (float64, float64, int, int) {
+		p := outcomes[i].Probability
+		outcomeAlpha := (float64(alpha) - upperSum + p*uppers[i]) / p
+		outcomeBeta := (float64(beta) - lowerSum + p*lowers[i]) / p
+		childAlpha := int(math.Max(math.Floor(outcomeAlpha), lowers[i]))
+		childBeta := int(math.Min(math.Ceil(outcomeBeta), uppers[i]))
+		return outcomeAlpha, outcomeBeta, childAlpha, childBeta
+	}
+	update := func(i int, lower, upper float64) {
+		p := outcomes[i].Probability
+		lowerSum += p * (lower - lowers[i])
+		upperSum += p * (upper - uppers[i])
+		lowers[i], uppers[i] = lower, upper
+	}
+	if bounds.probe {
+		// Star2 searches only the first child of each outcome, which bounds its score from one side
+		for i, outcome := range outcomes {
+			if depth < 2 || s.isLeaf(outcome.Node, ply+1) {
+				continue
+			}
+			if _, chance := chanceOutcomes(outcome.Node); chance {
+				continue
+			}
+			outcomeMaximizing := playerToMove(outcome.Node, maximizing)
+			child := nodeGenerator(outcome.Node)(outcomeMaximizing)
+			if child == nil {
+				continue
+			}
+			_, _, childAlpha, childBeta := window(i)
+			_, probe := s.expectimaxImpl(child, outcome.Node, s.interiorScore(outcome.Node, node, score), depth-2, ply+2,
+				childAlpha, childBeta, nextPlayer(child, outcomeMaximizing), bounds)
+			if outcomeMaximizing && probe > childAlpha {
+				update(i, float64(probe), uppers[i])
+			} else if !outcomeMaximizing && probe < childBeta {
+				update(i, lowers[i], float64(probe))
+			}
+			if lowerSum >= float64(beta) {
+				return int(math.Round(lowerSum))
+			}
+			if upperSum <= float64(alpha) {
+				return int(math.Round(upperSum))
+			}
+		}
+	}
+	for i, outcome := range outcomes {
+		outcomeAlpha, outcomeBeta, childAlpha, childBeta := window(i)
+		_, outcomeScore := s.expectimaxImpl(outcome.Node, node, score, depth-1, ply+1, childAlpha, childBeta, maximizing, bounds)
+		update(i, float64(outcomeScore), float64(outcomeScore))
+		if float64(outcomeScore) <= outcomeAlpha {
+			return int(math.Round(upperSum))
+		}
+		if float64(outcomeScore) >= outcomeBeta {
+			return int(math.Round(lowerSum))
+		}
+	}
+	return int(math.Round(lowerSum))
+}
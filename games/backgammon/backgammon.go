@@ -0,0 +1,390 @@
+// Package backgammon is a simplified backgammon of the csa package, searched by expectiminimax
+//
+// White is the maximizing player and rolls first. Each player moves its 15 checkers from its 24-point towards
+// its 1-point by the numbers of the two dice, the doubles are played four times. A single checker hit by the opponent
+// is placed on the bar and has to enter the opponent's home board before any other move. The checkers are borne off
+// once all of them are in the home board, the first player who bears off all of them wins.
+// Unlike the full game there is no doubling cube, no gammons and any sequence using the most dice may be played.
+//
+// The board before the roll is a csa.ChanceNode with an outcome per roll, the points are numbered from the point
+// of view of the player to move, 25 is the bar and 0 is off the board.
+package backgammon
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+const (
+	Points   = 24
+	Checkers = 15
+
+	// Score of the finished game, the heuristic scores stay below it. Use [-WinScore, WinScore] as the bounds
+	// of csa.Star1 and csa.Star2.
+	WinScore = 10000
+
+	// point number of the bar and of the borne off checkers
+	Bar = 25
+	Off = 0
+
+	// points of the home board
+	homePoints = 6
+)
+
+// Evaluation weights
+const (
+	pipWeight  = 4
+	blotWeight = 12
+)
+
+type Color int
+
+const (
+	White Color = iota
+	Black
+)
+
+// Move of a checker by a single die, e.g. 24/18, bar/22 or 6/off
+type Step struct {
+	From, To int
+}
+
+func pointName(point int) string {
+	switch point {
+	case Bar:
+		return "bar"
+	case Off:
+		return "off"
+	}
+	return strconv.Itoa(point)
+}
+
+func (step Step) String() string {
+	return pointName(step.From) + "/" + pointName(step.To)
+}
+
+// Steps of the player's turn, empty if it cannot move
+type Move []Step
+
+func (move Move) String() string {
+	if len(move) == 0 {
+		return "pass"
+	}
+	steps := make([]string, len(move))
+	for i, step := range move {
+		steps[i] = step.String()
+	}
+	return strings.Join(steps, " ")
+}
+
+// Board with the checkers of both players, implements csa.SearchNode and csa.ChanceNode
+// Intentionally passed by value everywhere, the last move is shared by the copies
+type Board struct {
+	points   [Points]int8 // white checkers are positive, black negative, white's 1-point first
+	bar, off [2]int8
+	side     Color   // to roll and move
+	dice     [2]int8 // zero before the roll
+	lastMove Move
+}
+
+// Initial position, white to roll
+func New() Board {
+	node := Board{}
+	for _, stack := range [][2]int{{24, 2}, {13, 5}, {8, 3}, {6, 5}} {
+		node.points[index(White, stack[0])] = int8(stack[1])
+		node.points[index(Black, stack[0])] = -int8(stack[1])
+	}
+	return node
+}
+
+// Index of the player's point in the points
+func index(color Color, point int) int {
+	if color == White {
+		return point - 1
+	}
+	return Points - point
+}
+
+// Checkers of the color on its point, including the bar
+func (node Board) Checkers(color Color, point int) int {
+	if point == Bar {
+		return int(node.bar[color])
+	}
+	if point == Off {
+		return int(node.off[color])
+	}
+	count := int(node.points[index(color, point)])
+	if color == Black {
+		count = -count
+	}
+	return max(count, 0)
+}
+
+func (node Board) SideToMove() Color {
+	return node.side
+}
+
+func (node Board) PlayerToMove() csa.Player {
+	if node.side == White {
+		return csa.MaximizingPlayer
+	}
+	return csa.MinimizingPlayer
+}
+
+// Dice of the player to move, zeros before the roll
+func (node Board) Dice() (int, int) {
+	return int(node.dice[0]), int(node.dice[1])
+}
+
+// Board after the roll
+func (node Board) WithDice(a, b int) (Board, error) {
+	if a < 1 || a > 6 || b < 1 || b > 6 {
+		return Board{}, fmt.Errorf("backgammon: invalid roll %d-%d", a, b)
+	}
+	node.dice = [2]int8{int8(a), int8(b)}
+	return node, nil
+}
+
+// Board after a random roll of the chance node, the board itself otherwise
+func (node Board) Roll(rng *rand.Rand) Board {
+	if node.IsChance() {
+		node, _ = node.WithDice(rng.Intn(6)+1, rng.Intn(6)+1)
+	}
+	return node
+}
+
+// Pip count of the color, the sum of the distances of its checkers from off
+func (node Board) Pips(color Color) int {
+	pips := Bar * node.Checkers(color, Bar)
+	for point := 1; point <= Points; point++ {
+		pips += point * node.Checkers(color, point)
+	}
+	return pips
+}
+
+func (node Board) Winner() (Color, bool) {
+	for color := White; color <= Black; color++ {
+		if node.off[color] == Checkers {
+			return color, true
+		}
+	}
+	return 0, false
+}
+
+func (node Board) canBearOff(color Color) bool {
+	if node.bar[color] > 0 {
+		return false
+	}
+	for point := homePoints + 1; point <= Points; point++ {
+		if node.Checkers(color, point) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Board after the step of the player to move by the die, false if it is illegal
+func (node Board) step(from, die int) (Board, Step, bool) {
+	color, opponent := node.side, 1-node.side
+	to := max(from-die, Off)
+	switch {
+	case node.Checkers(color, from) == 0:
+		return node, Step{}, false
+	case node.bar[color] > 0 && from != Bar:
+		return node, Step{}, false
+	case to == Off && !node.canBearOff(color):
+		return node, Step{}, false
+	case to > Off && node.Checkers(opponent, Bar-to) > 1:
+		return node, Step{}, false
+	}
+	if to == Off && from-die < Off {
+		// the higher die bears off from the highest point only
+		for point := from + 1; point <= homePoints; point++ {
+			if node.Checkers(color, point) > 0 {
+				return node, Step{}, false
+			}
+		}
+	}
+	sign := int8(1)
+	if color == Black {
+		sign = -1
+	}
+	if from == Bar {
+		node.bar[color]--
+	} else {
+		node.points[index(color, from)] -= sign
+	}
+	if to == Off {
+		node.off[color]++
+		return node, Step{from, to}, true
+	}
+	if node.Checkers(opponent, Bar-to) == 1 {
+		node.points[index(color, to)] = 0
+		node.bar[opponent]++
+	}
+	node.points[index(color, to)] += sign
+	return node, Step{from, to}, true
+}
+
+// Position key of the board without the move and the dice
+func (node Board) key() string {
+	node.lastMove, node.dice = nil, [2]int8{}
+	return fmt.Sprint(node.points, node.bar, node.off, node.side)
+}
+
+// Boards after the legal moves of the rolled dice, before the opponent's roll
+// Only the moves using the most dice are legal, the moves to the same position are generated once.
+func (node Board) Children() []Board {
+	if node.IsChance() || node.IsTerminal() {
+		return nil
+	}
+	dice := []int{int(node.dice[0]), int(node.dice[1])}
+	orders := [][]int{dice, {dice[1], dice[0]}}
+	if dice[0] == dice[1] {
+		orders = [][]int{{dice[0], dice[0], dice[0], dice[0]}}
+	}
+	var children []Board
+	seen := make(map[string]int) // index of the child
+	most := 0
+	var play func(board Board, dice []int, steps Move)
+	play = func(board Board, dice []int, steps Move) {
+		moved := false
+		if len(dice) > 0 {
+			for from := Bar; from > Off; from-- {
+				child, step, ok := board.step(from, dice[0])
+				if !ok {
+					continue
+				}
+				moved = true
+				play(child, dice[1:], append(steps[:len(steps):len(steps)], step))
+			}
+		}
+		if moved || len(steps) < most {
+			return
+		}
+		if len(steps) > most {
+			most, children, seen = len(steps), children[:0], make(map[string]int)
+		}
+		board.side, board.dice, board.lastMove = 1-node.side, [2]int8{}, steps
+		key := board.key()
+		if _, ok := seen[key]; !ok {
+			seen[key] = len(children)
+			children = append(children, board)
+		}
+	}
+	for _, order := range orders {
+		play(node, order, Move{})
+	}
+	return children
+}
+
+// Board after the move of the rolled dice in the notation of Move, e.g. "24/18 13/11", false if it is illegal
+func (node Board) Play(move string) (Board, bool) {
+	for _, child := range node.Children() {
+		if child.lastMove.String() == move {
+			return child, true
+		}
+	}
+	return Board{}, false
+}
+
+func (node Board) IsChance() bool {
+	return node.dice[0] == 0 && !node.IsTerminal()
+}
+
+// Rolls of the two dice, the doubles with probability 1/36 and the others 2/36
+func (node Board) Outcomes() []csa.ChanceOutcome {
+	outcomes := make([]csa.ChanceOutcome, 0, 21)
+	for a := 1; a <= 6; a++ {
+		for b := a; b <= 6; b++ {
+			child, _ := node.WithDice(a, b)
+			probability := 2.0 / 36
+			if a == b {
+				probability = 1.0 / 36
+			}
+			outcomes = append(outcomes, csa.ChanceOutcome{Node: child, Probability: probability})
+		}
+	}
+	return outcomes
+}
+
+func (node Board) IsTerminal() bool {
+	_, over := node.Winner()
+	return over
+}
+
+// Pip count difference and the blots, single checkers which could be hit, from white's point of view
+func (node Board) Score() int {
+	if winner, ok := node.Winner(); ok {
+		return []int{WinScore, -WinScore}[winner]
+	}
+	score := pipWeight * (node.Pips(Black) - node.Pips(White))
+	for point := 1; point <= Points; point++ {
+		if node.Checkers(White, point) == 1 {
+			score -= blotWeight
+		}
+		if node.Checkers(Black, point) == 1 {
+			score += blotWeight
+		}
+	}
+	return score
+}
+
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	var children []Board
+	started := false
+	return func(maximizing bool) csa.SearchNode {
+		if !started {
+			children, started = node.Children(), true
+		}
+		if len(children) == 0 {
+			return nil
+		}
+		child := children[0]
+		children = children[1:]
+		return child
+	}
+}
+
+// Move which created this board, backgammon.Move, nil for the initial board
+func (node Board) Move() csa.Move {
+	if node.lastMove == nil {
+		return nil
+	}
+	return node.lastMove
+}
+
+// Points from white's 24-point to its 1-point, e.g. "W2" for two white checkers, followed by the bars and the borne off checkers
+func (node Board) String() string {
+	sb := strings.Builder{}
+	for point := Points; point >= 1; point-- {
+		switch count := node.points[point-1]; {
+		case count > 0:
+			fmt.Fprintf(&sb, "W%d ", count)
+		case count < 0:
+			fmt.Fprintf(&sb, "B%d ", -count)
+		default:
+			sb.WriteString(".. ")
+		}
+	}
+	fmt.Fprintf(&sb, "| bar W%d B%d | off W%d B%d | %s", node.bar[White], node.bar[Black], node.off[White], node.off[Black],
+		[]string{"white", "black"}[node.side])
+	if node.dice[0] != 0 {
+		fmt.Fprintf(&sb, " %d-%d", node.dice[0], node.dice[1])
+	}
+	return sb.String()
+}
+
+// Best move of the rolled dice found by the expectiminimax search of depth plies with the Star2 prunning,
+// the moves and the rolls are plies, e.g. depth 3 considers every reply to every roll of the opponent
+func BestMove(s csa.Searcher, node Board, depth int) (Board, int) {
+	child, score := s.Star2(node, depth, node.side == White, -WinScore, WinScore)
+	if child == nil {
+		return Board{}, score
+	}
+	return child.(Board), score
+}
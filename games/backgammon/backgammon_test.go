@@ -0,0 +1,123 @@
+package backgammon
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+func rolled(t *testing.T, node Board, a, b int) Board {
+	node, err := node.WithDice(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
+
+func TestMoves(t *testing.T) {
+	node := New()
+	if !node.IsChance() || node.Pips(White) != 167 || node.Pips(Black) != 167 || node.Score() != 0 {
+		t.Fatalf("Invalid initial board %s", node)
+	}
+	sum := 0.0
+	for _, outcome := range node.Outcomes() {
+		sum += outcome.Probability
+	}
+	if len(node.Outcomes()) != 21 || math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Expected 21 rolls, got %d with the probability %f", len(node.Outcomes()), sum)
+	}
+	// the opening 3-1 making the 5-point
+	child, ok := rolled(t, node, 3, 1).Play("8/5 6/5")
+	if !ok || child.Checkers(White, 5) != 2 || child.SideToMove() != Black || !child.IsChance() || child.Pips(White) != 163 {
+		t.Errorf("Invalid move %s", child)
+	}
+	if _, ok := rolled(t, node, 6, 5).Play("24/18 18/12"); ok {
+		t.Error("Expected the blocked point of black")
+	}
+	if children := rolled(t, node, 6, 6).Children(); len(children) == 0 || len(children[0].lastMove) != 4 {
+		t.Error("Expected the doubles played four times")
+	}
+}
+
+func TestHits(t *testing.T) {
+	// black blot on white's 5-point is hit and has to enter first
+	node := New()
+	node.points[index(Black, 24)] = -1
+	node.points[index(Black, 20)] = -1
+	child, ok := rolled(t, node, 3, 1).Play("8/5 6/5")
+	if !ok || child.Checkers(Black, Bar) != 1 || child.Checkers(White, 5) != 2 {
+		t.Fatalf("Expected the hit %s", child)
+	}
+	// the white point blocks the entry with 5
+	if replies := rolled(t, child, 5, 5).Children(); len(replies) != 1 || len(replies[0].lastMove) != 0 ||
+		replies[0].SideToMove() != White || replies[0].Move().(Move).String() != "pass" {
+		t.Errorf("Expected a pass, got %d moves", len(replies))
+	}
+	for _, reply := range rolled(t, child, 6, 3).Children() {
+		if reply.lastMove[0].From != Bar {
+			t.Errorf("Expected the entry first, got %s", reply.lastMove)
+		}
+	}
+}
+
+func TestBearOff(t *testing.T) {
+	node := Board{}
+	node.points[index(White, 6)] = 1
+	node.points[index(White, 2)] = 1
+	node.off[White] = Checkers - 2
+	node.points[index(Black, 20)] = -Checkers
+	if _, ok := rolled(t, node, 6, 5).Play("6/off 2/off"); !ok {
+		t.Error("Expected the higher die to bear off from the highest point")
+	}
+	if _, ok := rolled(t, node, 6, 5).Play("6/off"); ok {
+		t.Error("Expected only the moves using both dice")
+	}
+	child, ok := rolled(t, node, 6, 2).Play("6/off 2/off")
+	if winner, over := child.Winner(); !ok || !over || winner != White || child.Score() != WinScore || child.IsChance() {
+		t.Errorf("Expected the win of white %s", child)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	node := New().Roll(rng)
+	for i := 0; i < 4; i++ {
+		maximizing := node.side == White
+		expectedNode, expected := csa.Expectiminimax(node, 3, maximizing)
+		star1Node, star1 := csa.Star1(node, 3, maximizing, -WinScore, WinScore)
+		star2Node, star2 := csa.Star2(node, 3, maximizing, -WinScore, WinScore)
+		if star1 != expected || star2 != expected {
+			t.Errorf("Expectiminimax %d, Star1 %d and Star2 %d of %s", expected, star1, star2, node)
+		}
+		if star1Node.(Board).key() != expectedNode.(Board).key() || star2Node.(Board).key() != expectedNode.(Board).key() {
+			t.Errorf("Different moves %v %v %v", expectedNode.(csa.MoveNode).Move(), star1Node.(csa.MoveNode).Move(),
+				star2Node.(csa.MoveNode).Move())
+		}
+		node = expectedNode.(Board).Roll(rng)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	wins := 0
+	for game := 0; game < 10; game++ {
+		node := New().Roll(rng)
+		for !node.IsTerminal() {
+			if node.side == White {
+				node, _ = BestMove(csa.Searcher{}, node, 1)
+			} else {
+				children := node.Children()
+				node = children[rng.Intn(len(children))]
+			}
+			node = node.Roll(rng)
+		}
+		if winner, _ := node.Winner(); winner == White {
+			wins++
+		}
+	}
+	if wins < 8 {
+		t.Errorf("The pip count evaluation won %d out of 10 games against the random moves", wins)
+	}
+}
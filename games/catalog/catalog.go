@@ -0,0 +1,176 @@
+// Package catalog lists the bundled games of the csa package by name for the commands and servers,
+// with their positions in the FEN-like strings and the moves in the games' notations.
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/checkers"
+	"github.com/stepulak/combinatorial-search-algoritms/games/chess"
+	"github.com/stepulak/combinatorial-search-algoritms/games/connect4"
+	"github.com/stepulak/combinatorial-search-algoritms/games/hex"
+	"github.com/stepulak/combinatorial-search-algoritms/games/kalah"
+	"github.com/stepulak/combinatorial-search-algoritms/games/mnk"
+	"github.com/stepulak/combinatorial-search-algoritms/games/nim"
+	"github.com/stepulak/combinatorial-search-algoritms/games/othello"
+)
+
+type Game struct {
+	Players  [2]string // names of the maximizing and minimizing player
+	WinScore int       // of csa.Searcher
+	Position string    // description of the position format, empty if the game has none
+	// Node of the position, the initial one if empty, and whether the maximizing player is to move
+	Parse func(position string) (csa.SearchNode, bool, error)
+}
+
+// Initial node of the games without a position format
+func initial(node csa.SearchNode) func(string) (csa.SearchNode, bool, error) {
+	return func(position string) (csa.SearchNode, bool, error) {
+		if position != "" {
+			return nil, false, fmt.Errorf("catalog: the game has no position format, use the moves")
+		}
+		return node, true, nil
+	}
+}
+
+// Games searchable by csa.Engine, the games of the chance nodes and Go are not included
+var Games = map[string]Game{
+	"chess": {
+		Players:  [2]string{"white", "black"},
+		WinScore: chess.WinScore,
+		Position: "FEN, e.g. rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		Parse: func(position string) (csa.SearchNode, bool, error) {
+			if position == "" {
+				return chess.New(), true, nil
+			}
+			node, err := chess.ParseFEN(position)
+			return node, node.PlayerToMove() == csa.MaximizingPlayer, err
+		},
+	},
+	"checkers": {
+		Players:  [2]string{"black", "white"},
+		Position: "draughts FEN, e.g. B:W21,22,K30:B1-4,K12",
+		Parse: func(position string) (csa.SearchNode, bool, error) {
+			if position == "" {
+				return checkers.New(checkers.Rules{}), true, nil
+			}
+			return checkers.ParsePosition(position, checkers.Rules{})
+		},
+	},
+	"connect4": {
+		Players:  [2]string{"red", "yellow"},
+		WinScore: connect4.WinScore,
+		Position: "columns of the dropped discs numbered from 1, e.g. 4453",
+		Parse: func(position string) (csa.SearchNode, bool, error) {
+			node, err := connect4.Parse(position)
+			return node, node.PlayerToMove() == csa.MaximizingPlayer, err
+		},
+	},
+	"nim": {
+		Players:  [2]string{"first", "second"},
+		Position: "piles separated by commas, e.g. 3,4,5",
+		Parse: func(position string) (csa.SearchNode, bool, error) {
+			if position == "" {
+				position = "3,4,5"
+			}
+			var piles []int
+			for _, field := range strings.Split(position, ",") {
+				pile, err := strconv.Atoi(strings.TrimSpace(field))
+				if err != nil || pile < 0 {
+					return nil, false, fmt.Errorf("catalog: invalid pile %q", field)
+				}
+				piles = append(piles, pile)
+			}
+			return nim.New(false, piles...), true, nil
+		},
+	},
+	"tictactoe": {Players: [2]string{"X", "O"}, WinScore: mnk.WinScore, Parse: initial(mnk.New(mnk.Rules{}))},
+	"gomoku": {
+		Players:  [2]string{"X", "O"},
+		WinScore: mnk.WinScore,
+		Parse:    initial(mnk.New(mnk.Rules{M: 15, N: 15, K: 5, Radius: 2})),
+	},
+	"hex":     {Players: [2]string{"black", "white"}, WinScore: hex.WinScore, Parse: initial(hex.New(11))},
+	"kalah":   {Players: [2]string{"south", "north"}, Parse: initial(kalah.New(kalah.Rules{}))},
+	"othello": {Players: [2]string{"black", "white"}, WinScore: othello.WinScore, Parse: initial(othello.New())},
+}
+
+// Names of the games sorted
+func Names() []string {
+	names := make([]string, 0, len(Games))
+	for name := range Games {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Node of the position after the moves and whether the maximizing player is to move
+func (game Game) Play(position string, moves []string) (csa.SearchNode, bool, error) {
+	node, maximizing, err := game.Parse(position)
+	if err != nil {
+		return nil, false, err
+	}
+	for i, move := range moves {
+		child := Child(node, maximizing, move)
+		if child == nil {
+			return nil, false, fmt.Errorf("catalog: illegal move %q at %d", move, i+1)
+		}
+		node, maximizing = child, NextPlayer(child, maximizing)
+	}
+	return node, maximizing, nil
+}
+
+// Children of the node in the generator's order
+func Children(node csa.SearchNode, maximizing bool) []csa.SearchNode {
+	var children []csa.SearchNode
+	generator := node.SearchNodeGenerator()
+	for child := generator(maximizing); child != nil; child = generator(maximizing) {
+		children = append(children, child)
+	}
+	return children
+}
+
+// Child of the move in the game's notation, nil if it is illegal
+func Child(node csa.SearchNode, maximizing bool, move string) csa.SearchNode {
+	for _, child := range Children(node, maximizing) {
+		if MoveString(child) == move {
+			return child
+		}
+	}
+	return nil
+}
+
+// Whether the maximizing player is to move in the child, the same player again after csa.ExtraTurnNode's extra turn
+func NextPlayer(child csa.SearchNode, maximizing bool) bool {
+	if extraTurn, ok := child.(csa.ExtraTurnNode); ok && extraTurn.ExtraTurn() {
+		return maximizing
+	}
+	return !maximizing
+}
+
+// Move of the child in the game's notation, or the resulting board if the node does not describe it
+func MoveString(child csa.SearchNode) string {
+	if move := csa.MoveOf(child); move != nil {
+		return fmt.Sprint(move)
+	}
+	return strings.ReplaceAll(fmt.Sprint(child), "\n", "/")
+}
+
+// Result of the terminal node, e.g. white wins or draw
+func (game Game) Result(node csa.SearchNode) string {
+	if drawNode, ok := node.(csa.DrawNode); ok && drawNode.IsDraw() {
+		return "draw"
+	}
+	switch score := node.Score(); {
+	case score > 0:
+		return game.Players[0] + " wins"
+	case score < 0:
+		return game.Players[1] + " wins"
+	}
+	return "draw"
+}
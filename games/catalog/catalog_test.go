@@ -0,0 +1,29 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func TestGames(t *testing.T) {
+	for _, name := range Names() {
+		game := Games[name]
+		node, maximizing, err := game.Parse("")
+		if err != nil || !maximizing || len(Children(node, maximizing)) == 0 {
+			t.Errorf("%s: expected the initial position with moves, got %v", name, err)
+		}
+	}
+	node, maximizing, err := Games["connect4"].Play("44", []string{"3", "5"})
+	if err != nil || !maximizing || MoveString(node) != "5" {
+		t.Fatalf("Expected red to move after 5, got %v", err)
+	}
+	if _, _, err := Games["connect4"].Play("", []string{"8"}); err == nil {
+		t.Error("Expected the illegal move")
+	}
+	if _, _, err := Games["othello"].Play("x", nil); err == nil {
+		t.Error("Expected no position format")
+	}
+	node, _, _ = Games["nim"].Play("0,1", []string{"2:1"})
+	if !node.IsTerminal() || Games["nim"].Result(node) != "first wins" {
+		t.Errorf("Expected the first player to win\n%s", node)
+	}
+}
@@ -0,0 +1,635 @@
+// Package checkers is a reference game of the csa package, checkers on bitboards of even sizes from 4x4 to 16x16
+//
+// Rules of this checkers game:
+//   - king can move and jump only by one square, unless Rules.FlyingKings
+//   - there is no necessity for a jump if available; if the figure wont jump it wont be taken away
+//   - optionally (Rules.MultiJump) a figure which jumped and can jump again has to continue, its player moves again
+//   - optionally (Rules.FlyingKings) king moves any distance and jumps a figure any distance away,
+//     landing on any empty square behind it; the jumped figure is removed at once, not at the end of the chain
+//
+// Algorithm:
+//   - anticycling technique using node history
+//   - draw detection if there is no more moves without cycling
+//   - win/loss detection (all enemy pieces are dead)
+//   - node is considered terminal if it's win/loss
+//   - if player can't move, it's not a draw (unlike in chess)
+//
+// Black is the maximizing player, its pawns start on the rows 0-2 and move to the higher indices.
+// Squares are indexed 0 to Size*Size-1 by rows from the top left corner.
+// International draughts is played with Size 10, FlyingKings and MultiJump, except that the jumps stay optional.
+package checkers
+
+import (
+	"fmt"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+const (
+	// single figure scores
+	pawnScore = 1
+	kingScore = 3
+
+	// color indices
+	white = 0
+	black = 1
+
+	// figure indices
+	pawns = 0
+	kings = 1
+
+	// directions on board
+	whitePawnDir = -1
+	blackPawnDir = 1
+
+	// score coefficient
+	whiteCoef = -1
+	blackCoef = 1
+
+	// runes
+	whitePawn = '♟'
+	whiteKing = '♛'
+	blackPawn = '♙'
+	blackKing = '♕'
+)
+
+type Color int
+
+const (
+	White Color = white
+	Black Color = black
+)
+
+type Figure int
+
+const (
+	Pawn Figure = pawns
+	King Figure = kings
+)
+
+// Optional rules of the game
+type Rules struct {
+	Size        int // of the board, default 8, every player starts with (Size-2)/2 rows of pawns
+	MultiJump   bool
+	FlyingKings bool
+}
+
+const maxSize = 16
+
+// Bit per square indexed by rows
+type bitboard [maxSize * maxSize / 64]uint64
+
+type nodeHistory map[uint64][]Board
+
+// Board with the history of the played boards, implements csa.SearchNode
+// Intentionally passed by value everywhere
+type Board struct {
+	board       [2][2]bitboard // board[units][color]
+	size        int
+	nodeHistory nodeHistory // always passed by reference
+	scoreDelta  int         // score difference made by the last move
+	multiJump   bool        // rules option
+	flyingKings bool        // rules option
+	extraTurn   bool        // jump chain continues with the figure at jumpIndex
+	jumpIndex   int
+	lastMove    Move // move which created this node
+}
+
+type Move struct {
+	From, To int
+	Jump     bool
+}
+
+func (move Move) String() string {
+	if move.Jump {
+		return fmt.Sprintf("%dx%d", move.From, move.To)
+	}
+	return fmt.Sprintf("%d-%d", move.From, move.To)
+}
+
+func (node Board) Score() int {
+	score := 0
+	// Full recalculation, the search uses ScoreDelta (move's difference stored during generation) whenever it can
+	for i := 0; i < node.squares(); i++ {
+		if isBit(node.board[pawns][white], i) {
+			score += pawnScore * whiteCoef
+		} else if isBit(node.board[kings][white], i) {
+			score += kingScore * whiteCoef
+		} else if isBit(node.board[pawns][black], i) {
+			score += pawnScore * blackCoef
+		} else if isBit(node.board[kings][black], i) {
+			score += kingScore * blackCoef
+		}
+	}
+	return score
+}
+
+func (node Board) ScoreDelta(parent csa.SearchNode) int {
+	return node.scoreDelta
+}
+
+// Move which created this board, checkers.Move
+func (node Board) Move() csa.Move {
+	return node.lastMove
+}
+
+func (node Board) ExtraTurn() bool {
+	return node.extraTurn
+}
+
+// The same board is always the same player to move, figures cannot return in odd number of plies
+func (node Board) RepetitionKey() uint64 {
+	b := &node.board
+	hash := uint64(14695981039346656037)
+	for _, bits := range []bitboard{b[pawns][white], b[pawns][black], b[kings][white], b[kings][black]} {
+		for _, word := range bits {
+			hash = (hash ^ word) * 1099511628211
+		}
+	}
+	return hash
+}
+
+func (node Board) Hash() uint64 {
+	return node.RepetitionKey()
+}
+
+func (node Board) IsTerminal() bool {
+	for color := range []int{white, black} {
+		if node.board[pawns][color].or(node.board[kings][color]) == (bitboard{}) {
+			// this color is no more => node is terminal
+			return true
+		}
+	}
+	return false
+}
+
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	var nodeQueue []Board
+	index := 0
+	return func(maximizing bool) csa.SearchNode {
+		if len(nodeQueue) == 0 {
+			// nodeQueue is empty, generate more moves if possible
+			// maximizing = black moves
+			// minimizing = white moves
+			var color, pawnDir int
+			if maximizing {
+				pawnDir = blackPawnDir
+				color = black
+			} else {
+				pawnDir = whitePawnDir
+				color = white
+			}
+			if node.extraTurn && index < node.squares() {
+				// jump chain continues only with the figure which jumped
+				nodeQueue = node.generateChainJumps(color, node.jumpIndex)
+				index = node.squares()
+			}
+			for ; index < node.squares(); index++ {
+				if node.placeOccupiedFigureColor(pawns, color, index) {
+					nodeQueue = node.generatePawnMoves(color, index, pawnDir)
+				} else if node.placeOccupiedFigureColor(kings, color, index) {
+					nodeQueue = node.generateKingMoves(color, index)
+				}
+				if len(nodeQueue) > 0 {
+					index++
+					break
+				}
+			}
+		}
+		for len(nodeQueue) > 0 {
+			searchNode := nodeQueue[0]
+			nodeQueue = nodeQueue[1:]
+			if !node.inNodeHistory(searchNode) {
+				return searchNode
+			}
+		}
+		return nil
+	}
+}
+
+func (node Board) String() string {
+	sb := strings.Builder{}
+	for i := 0; i < node.squares(); i++ {
+		if isBit(node.board[pawns][white], i) {
+			sb.WriteRune(whitePawn)
+		} else if isBit(node.board[kings][white], i) {
+			sb.WriteRune(whiteKing)
+		} else if isBit(node.board[pawns][black], i) {
+			sb.WriteRune(blackPawn)
+		} else if isBit(node.board[kings][black], i) {
+			sb.WriteRune(blackKing)
+		} else {
+			// space
+			sb.WriteRune('_')
+		}
+		if (i+1)%node.size == 0 {
+			// newline after the last column
+			sb.WriteByte('\n')
+		} else {
+			sb.WriteByte(' ')
+		}
+	}
+	return sb.String()
+}
+
+// Board without figures and with empty history, panics on invalid size
+func Empty(rules Rules) Board {
+	size := rules.Size
+	if size == 0 {
+		size = 8
+	}
+	if size < 4 || size > maxSize || size%2 != 0 {
+		panic(fmt.Sprintf("checkers: invalid board size %d", size))
+	}
+	return Board{
+		size:        size,
+		nodeHistory: make(nodeHistory),
+		multiJump:   rules.MultiJump,
+		flyingKings: rules.FlyingKings,
+	}
+}
+
+// Initial position, its history contains itself
+func New(rules Rules) Board {
+	node := Empty(rules)
+	// each color has (size-2)/2 rows, two middle rows are empty
+	// we start with black (since black has positive pawn direction), then white (negative pawn direction)
+	rows := (node.size - 2) / 2
+	for row := 0; row < node.size; row++ {
+		if row >= rows && row < node.size-rows {
+			continue
+		}
+		for i := 0; i < node.size/2; i++ {
+			index := row*node.size + i*2
+			if row%2 != 0 {
+				index++
+			}
+			color := black
+			if row >= rows {
+				color = white
+			}
+			node.board[pawns][color] = setBit(node.board[pawns][color], index)
+		}
+	}
+	// add self
+	node.addNodeHistory(node)
+	return node
+}
+
+func (node Board) Rules() Rules {
+	return Rules{Size: node.size, MultiJump: node.multiJump, FlyingKings: node.flyingKings}
+}
+
+// Figure and its color on the square, false if the square is empty
+func (node Board) At(index int) (Figure, Color, bool) {
+	for _, color := range []int{white, black} {
+		for _, figure := range []int{pawns, kings} {
+			if node.placeOccupiedFigureColor(figure, color, index) {
+				return Figure(figure), Color(color), true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Copy of the board with the figure placed on the square, any previous one is replaced
+func (node Board) Place(figure Figure, color Color, index int) Board {
+	node = node.Remove(index)
+	node.board[figure][color] = setBit(node.board[figure][color], index)
+	return node
+}
+
+// Copy of the board with the square emptied
+func (node Board) Remove(index int) Board {
+	for color := range node.board[pawns] {
+		node.board[pawns][color] = clearBit(node.board[pawns][color], index)
+		node.board[kings][color] = clearBit(node.board[kings][color], index)
+	}
+	return node
+}
+
+// Children of the board not repeating its history, the maximizing player plays black
+func (node Board) Moves(maximizing bool) []Board {
+	var moves []Board
+	for generator := node.SearchNodeGenerator(); ; {
+		child := generator(maximizing)
+		if child == nil {
+			return moves
+		}
+		moves = append(moves, child.(Board))
+	}
+}
+
+// Records the played board, the history is shared by all the boards derived from the same Empty or New one
+func (node Board) AddHistory(played Board) {
+	node.addNodeHistory(played)
+}
+
+// Whether the board was played, the boards in history are never generated again
+func (node Board) InHistory(board Board) bool {
+	return node.inNodeHistory(board)
+}
+
+func (node Board) cloneNode() Board {
+	// in case of eventually adding more attributes that wont be deep copied
+	return node
+}
+
+func (node Board) upgradeToKing(color, index int) Board {
+	if isBit(node.board[pawns][color], index) {
+		if node.promotionRow(color, index) {
+			// upgrade
+			clone := node.cloneNode()
+			clone.board[pawns][color] = clearBit(clone.board[pawns][color], index)
+			clone.board[kings][color] = setBit(clone.board[kings][color], index)
+			clone.scoreDelta += (kingScore - pawnScore) * colorCoef(color)
+			return clone
+		}
+	}
+	return node
+}
+
+func (node Board) figureMove(figure, color, index, offset int) (bool, Board) {
+	if index < 0 || index >= node.squares() || !node.offsetInBoard(index, offset) || node.placeOccupied(index+offset) {
+		return false, Board{}
+	}
+	clone := node.cloneNode()
+	clone.scoreDelta = 0
+	clone.extraTurn = false
+	clone.lastMove = Move{index, index + offset, false}
+	clone.board[figure][color] = clearBit(clone.board[figure][color], index)
+	clone.board[figure][color] = setBit(clone.board[figure][color], index+offset)
+	return true, clone.upgradeToKing(color, index+offset)
+}
+
+func (node Board) figureJump(figure, color, index, offset int) (bool, Board) {
+	if index < 0 || index >= node.squares() || !node.offsetInBoard(index, offset) || !node.offsetInBoard(index+offset, offset) {
+		return false, Board{}
+	}
+	if !node.placeOccupiedColor(enemyColor(color), index+offset) || node.placeOccupied(index+2*offset) {
+		return false, Board{}
+	}
+	return true, node.capture(figure, color, index, index+offset, index+2*offset)
+}
+
+// Figure jumps from the index over the captured enemy figure to the empty square
+func (node Board) capture(figure, color, index, captured, to int) Board {
+	clone := node.cloneNode()
+	clone.extraTurn = false
+	clone.lastMove = Move{index, to, true}
+	enemyCol := enemyColor(color)
+	if node.placeOccupiedFigureColor(pawns, enemyCol, captured) {
+		clone.scoreDelta = -pawnScore * colorCoef(enemyCol)
+	} else {
+		clone.scoreDelta = -kingScore * colorCoef(enemyCol)
+	}
+	clone.board[figure][color] = clearBit(clone.board[figure][color], index)
+	clone.board[pawns][enemyCol] = clearBit(clone.board[pawns][enemyCol], captured)
+	clone.board[kings][enemyCol] = clearBit(clone.board[kings][enemyCol], captured)
+	clone.board[figure][color] = setBit(clone.board[figure][color], to)
+	return clone.upgradeToKing(color, to)
+}
+
+// Generate both moves and jumps
+func (node Board) generateFigureMoves(figure, color, index, dir int) []Board {
+	moves := make([]Board, 0, 2)
+	for _, offset := range []int{node.size - 1, node.size + 1} {
+		ok, move := node.figureMove(figure, color, index, offset*dir)
+		if ok {
+			moves = append(moves, move)
+		}
+		ok, move = node.figureJump(figure, color, index, offset*dir)
+		if ok {
+			moves = append(moves, move.continueJump(figure, color, index+2*offset*dir))
+		}
+	}
+	return moves
+}
+
+// Generate both moves and jumps
+func (node Board) generatePawnMoves(color, index, dir int) []Board {
+	return node.generateFigureMoves(pawns, color, index, dir)
+}
+
+// Generate both moves and jumps
+func (node Board) generateKingMoves(color, index int) []Board {
+	if node.flyingKings {
+		return node.generateFlyingKingMoves(color, index)
+	}
+	moves := node.generateFigureMoves(kings, color, index, -1)
+	return append(moves, node.generateFigureMoves(kings, color, index, 1)...)
+}
+
+// Flying king moves to any empty square of the diagonals and jumps
+func (node Board) generateFlyingKingMoves(color, index int) []Board {
+	var moves []Board
+	for _, offset := range node.diagonals() {
+		for _, to := range node.diagonal(index, offset) {
+			if node.placeOccupied(to) {
+				break
+			}
+			clone := node.cloneNode()
+			clone.scoreDelta = 0
+			clone.extraTurn = false
+			clone.lastMove = Move{index, to, false}
+			clone.board[kings][color] = setBit(clearBit(clone.board[kings][color], index), to)
+			moves = append(moves, clone)
+		}
+	}
+	return append(moves, node.generateFlyingKingJumps(color, index)...)
+}
+
+// Flying king jumps over the first figure of the diagonal if it's the enemy one,
+// every empty square behind it up to the next figure is a separate jump
+func (node Board) generateFlyingKingJumps(color, index int) []Board {
+	var jumps []Board
+	for _, offset := range node.diagonals() {
+		captured := -1
+		for _, square := range node.diagonal(index, offset) {
+			if captured < 0 {
+				if node.placeOccupiedColor(color, square) {
+					break
+				}
+				if node.placeOccupiedColor(enemyColor(color), square) {
+					captured = square
+				}
+				continue
+			}
+			if node.placeOccupied(square) {
+				break
+			}
+			jump := node.capture(kings, color, index, captured, square)
+			jumps = append(jumps, jump.continueJump(kings, color, square))
+		}
+	}
+	return jumps
+}
+
+// With multiJump rules the figure which can jump again continues the turn
+func (node Board) continueJump(figure, color, index int) Board {
+	if !node.multiJump || !isBit(node.board[figure][color], index) {
+		// promoted pawn ends the turn
+		return node
+	}
+	if figure == kings && node.flyingKings {
+		if len(node.generateFlyingKingJumps(color, index)) > 0 {
+			node.extraTurn = true
+			node.jumpIndex = index
+		}
+		return node
+	}
+	for _, dir := range figureDirs(figure, color) {
+		for _, offset := range []int{node.size - 1, node.size + 1} {
+			if ok, _ := node.figureJump(figure, color, index, offset*dir); ok {
+				node.extraTurn = true
+				node.jumpIndex = index
+				return node
+			}
+		}
+	}
+	return node
+}
+
+func (node Board) generateChainJumps(color, index int) []Board {
+	figure := pawns
+	if node.placeOccupiedFigureColor(kings, color, index) {
+		figure = kings
+	}
+	if figure == kings && node.flyingKings {
+		return node.generateFlyingKingJumps(color, index)
+	}
+	var jumps []Board
+	for _, dir := range figureDirs(figure, color) {
+		for _, offset := range []int{node.size - 1, node.size + 1} {
+			if ok, jump := node.figureJump(figure, color, index, offset*dir); ok {
+				jumps = append(jumps, jump.continueJump(figure, color, index+2*offset*dir))
+			}
+		}
+	}
+	return jumps
+}
+
+func (node Board) placeOccupiedFigureColor(figure, color, index int) bool {
+	return isBit(node.board[figure][color], index)
+}
+
+func (node Board) placeOccupiedColor(color, index int) bool {
+	return isBit(node.board[pawns][color].or(node.board[kings][color]), index)
+}
+
+func (node Board) placeOccupied(index int) bool {
+	return node.placeOccupiedColor(white, index) || node.placeOccupiedColor(black, index)
+}
+
+func (node Board) inNodeHistory(searchNode Board) bool {
+	nodes, found := node.nodeHistory[searchNode.boardMask()]
+	if !found {
+		return false
+	}
+	// multiple boards can have same mask
+	for _, n := range nodes {
+		if n.board == searchNode.board {
+			return true
+		}
+	}
+	return false
+}
+
+func (node Board) addNodeHistory(newNode Board) {
+	mask := newNode.boardMask()
+	node.nodeHistory[mask] = append(node.nodeHistory[mask], newNode)
+}
+
+// Occupied squares folded to a single word
+func (node Board) boardMask() uint64 {
+	b := &node.board
+	mask := uint64(0)
+	for i, word := range b[pawns][black].or(b[kings][black]).or(b[pawns][white]).or(b[kings][white]) {
+		mask ^= word * (uint64(i)*2 + 1)
+	}
+	return mask
+}
+
+func (node Board) promotionRow(color, index int) bool {
+	return (color == black && index >= node.squares()-node.size) || (color == white && index < node.size)
+}
+
+func (node Board) squares() int {
+	return node.size * node.size
+}
+
+func (node Board) diagonals() []int {
+	return []int{-node.size - 1, -node.size + 1, node.size - 1, node.size + 1}
+}
+
+func (num bitboard) or(other bitboard) bitboard {
+	for i := range num {
+		num[i] |= other[i]
+	}
+	return num
+}
+
+func isBit(num bitboard, index int) bool {
+	return num[index/64]&(1<<(index%64)) != 0
+}
+
+func clearBit(num bitboard, index int) bitboard {
+	num[index/64] &^= 1 << (index % 64)
+	return num
+}
+
+func setBit(num bitboard, index int) bitboard {
+	num[index/64] |= 1 << (index % 64)
+	return num
+}
+
+func enemyColor(color int) int {
+	if color == white {
+		return black
+	}
+	return white
+}
+
+func figureDirs(figure, color int) []int {
+	if figure == kings {
+		return []int{-1, 1}
+	}
+	if color == white {
+		return []int{whitePawnDir}
+	}
+	return []int{blackPawnDir}
+}
+
+func colorCoef(color int) int {
+	if color == white {
+		return whiteCoef
+	}
+	return blackCoef
+}
+
+func abs(val int) int {
+	if val < 0 {
+		return -val
+	}
+	return val
+}
+
+// Whether the diagonal step from the index stays on the board
+func (node Board) offsetInBoard(index, offset int) bool {
+	to := index + offset
+	if to < 0 || to >= node.squares() {
+		return false
+	}
+	return abs(index/node.size-to/node.size)+abs(index%node.size-to%node.size) <= 2
+}
+
+// Squares from the index in the diagonal direction up to the board edge
+func (node Board) diagonal(index, offset int) []int {
+	var squares []int
+	for node.offsetInBoard(index, offset) {
+		index += offset
+		squares = append(squares, index)
+	}
+	return squares
+}
@@ -0,0 +1,815 @@
+package checkers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Bitboard of the squares
+func squares(indices ...int) bitboard {
+	var board bitboard
+	for _, index := range indices {
+		board = setBit(board, index)
+	}
+	return board
+}
+
+func TestString(t *testing.T) {
+	node := Empty(Rules{})
+	expected := strings.Repeat("_ _ _ _ _ _ _ _\n", 8)
+	if node.String() != expected {
+		t.Error("Invalid empty board")
+	}
+}
+
+func TestFullBoard(t *testing.T) {
+	node := New(Rules{})
+	expected := "♙ _ ♙ _ ♙ _ ♙ _\n_ ♙ _ ♙ _ ♙ _ ♙\n♙ _ ♙ _ ♙ _ ♙ _\n_ _ _ _ _ _ _ _" +
+		"\n_ _ _ _ _ _ _ _\n_ ♟ _ ♟ _ ♟ _ ♟\n♟ _ ♟ _ ♟ _ ♟ _\n_ ♟ _ ♟ _ ♟ _ ♟\n"
+	if node.String() != expected {
+		t.Error("Invalid full board")
+	}
+}
+
+func TestCustomBoard(t *testing.T) {
+	node := Empty(Rules{})
+	node.board[pawns][white] = setBit(node.board[pawns][white], 9)
+	node.board[kings][white] = setBit(node.board[kings][white], 4)
+	node.board[kings][black] = setBit(node.board[kings][black], 33)
+	node.board[pawns][black] = setBit(node.board[pawns][black], 35)
+
+	expected := "_ _ _ _ ♛ _ _ _\n_ ♟ _ _ _ _ _ _\n" + strings.Repeat("_ _ _ _ _ _ _ _\n", 2) +
+		"_ ♕ _ ♙ _ _ _ _\n" + strings.Repeat("_ _ _ _ _ _ _ _\n", 3)
+
+	if node.String() != expected {
+		t.Error("Invalid custom board")
+	}
+}
+
+func TestPawnMoves(t *testing.T) {
+	{
+		// out-of-board top
+		node := Empty(Rules{})
+		node.board[pawns][white] = squares(6)
+		moves := node.generatePawnMoves(white, 6, -1)
+		if len(moves) != 0 {
+			t.Error("Cannot go out of board top")
+		}
+	}
+	{
+		// out-of-board bottom
+		node := Empty(Rules{})
+		node.board[pawns][white] = squares(63)
+		moves := node.generatePawnMoves(white, 63, 1)
+		if len(moves) != 0 {
+			t.Error("Cannot go out of board bottom")
+		}
+	}
+	{
+		// out-of-board left
+		node := Empty(Rules{})
+		node.board[pawns][black] = squares(8)
+		moves := node.generatePawnMoves(black, 8, -1)
+		if len(moves) != 1 || !isBit(moves[0].board[pawns][black], 1) {
+			t.Error("Must be only one move to top-right")
+		}
+	}
+	{
+		// out-of-board right
+		node := Empty(Rules{})
+		node.board[pawns][black] = squares(15)
+		moves := node.generatePawnMoves(black, 15, -1)
+		if len(moves) != 1 || !isBit(moves[0].board[pawns][black], 6) {
+			t.Error("Must be only one move to top-left")
+		}
+	}
+	{
+		// full move
+		node := Empty(Rules{})
+		node.board[pawns][white] = squares(9)
+		moves := node.generatePawnMoves(white, 9, -1)
+		if len(moves) != 2 {
+			t.Error("Must be exactly two moves")
+		}
+		// pawn has been promoted to king
+		board := moves[0].board[kings][white].or(moves[1].board[kings][white])
+		if !isBit(board, 2) || !isBit(board, 0) {
+			t.Error(moves[1])
+		}
+	}
+	{
+		node := Empty(Rules{})
+		node.board[pawns][black] = squares(16)
+		moves := node.generatePawnMoves(black, 16, 1)
+		if len(moves) != 1 {
+			t.Error("Must be exactly one move")
+		}
+		board := moves[0].board[pawns][black]
+		if !isBit(board, 25) || isBit(board, 16) {
+			t.Error("Invalid moves")
+		}
+	}
+}
+
+func TestUpgradeToKing(t *testing.T) {
+	{
+		// white
+		node := Empty(Rules{})
+		node.board[pawns][white] = squares(8)
+		moves := node.generatePawnMoves(white, 8, -1)
+		if len(moves) != 1 {
+			t.Error("Must be exactly one move")
+		}
+		if !isBit(moves[0].board[kings][white], 1) {
+			t.Error("Invalid upgrade")
+		}
+	}
+	{
+		// black
+		node := Empty(Rules{})
+		node.board[pawns][black] = squares(48)
+		moves := node.generatePawnMoves(black, 48, 1)
+		if len(moves) != 1 {
+			t.Error("Must be exactly one move")
+		}
+		if !isBit(moves[0].board[kings][black], 57) {
+			t.Error("Invalid upgrade")
+		}
+	}
+	{
+		// jump
+		node := Empty(Rules{})
+		node.board[pawns][black] = squares(9)
+		node.board[pawns][white] = squares(16)
+		moves := node.generatePawnMoves(white, 16, -1)
+		if len(moves) != 1 {
+			t.Error("Must be exactly one move")
+		}
+		if !isBit(moves[0].board[kings][white], 2) {
+			t.Error("Invalid jump and upgrade")
+		}
+	}
+}
+
+func TestPawnJumps(t *testing.T) {
+	{
+		// out-of-board top
+		node := Empty(Rules{})
+		node.board[pawns][white] = squares(10)
+		node.board[pawns][black] = squares(1, 3)
+		moves := node.generatePawnMoves(white, 10, -1)
+		if len(moves) != 0 {
+			t.Error("Cannot go out of board top")
+		}
+	}
+	{
+		// out-of-board bottom
+		node := Empty(Rules{})
+		node.board[pawns][white] = squares(54)
+		node.board[pawns][black] = squares(63, 61)
+		moves := node.generatePawnMoves(white, 54, 1)
+		if len(moves) != 0 {
+			t.Error("Cannot go out of board bottom")
+		}
+	}
+	{
+		// out-of-board left
+		node := Empty(Rules{})
+		node.board[kings][black] = squares(8, 10)
+		node.board[pawns][white] = squares(17)
+		moves := node.generatePawnMoves(white, 17, -1)
+		if len(moves) != 1 {
+			t.Error("Must be only one jump to top-right")
+		}
+		// pawn has been promoted to king
+		if !isBit(moves[0].board[kings][black], 8) ||
+			isBit(moves[0].board[kings][black], 10) ||
+			!isBit(moves[0].board[kings][white], 3) ||
+			isBit(moves[0].board[pawns][white], 17) {
+			t.Error(moves[0])
+		}
+	}
+	{
+		// out-of-board right
+		node := Empty(Rules{})
+		node.board[kings][black] = squares(29, 31)
+		node.board[pawns][white] = squares(22)
+		moves := node.generatePawnMoves(white, 22, 1)
+		if len(moves) != 1 {
+			t.Error("Must be only one jump to top-left")
+		}
+		if !isBit(moves[0].board[kings][black], 31) ||
+			isBit(moves[0].board[kings][black], 29) ||
+			!isBit(moves[0].board[pawns][white], 36) ||
+			isBit(moves[0].board[pawns][white], 22) {
+			t.Error("Invalid jump")
+		}
+	}
+	{
+		// casual two jumps both directions
+		node := Empty(Rules{})
+		node.board[kings][black] = squares(10, 12)
+		node.board[pawns][white] = squares(19)
+		moves := node.generatePawnMoves(white, 19, -1)
+		if len(moves) != 2 {
+			t.Error("Expected two jumps")
+		}
+		// pawn has been promoted to king
+		if !isBit(moves[0].board[kings][black], 10) ||
+			!isBit(moves[0].board[kings][white], 5) ||
+			isBit(moves[0].board[pawns][white], 19) ||
+			isBit(moves[0].board[kings][black], 12) {
+			t.Error("Invalid move")
+		}
+		if !isBit(moves[1].board[kings][black], 12) ||
+			!isBit(moves[1].board[kings][white], 1) ||
+			isBit(moves[1].board[pawns][white], 19) ||
+			isBit(moves[1].board[kings][black], 10) {
+			t.Error("Invalid move")
+		}
+	}
+	{
+		// cannot jump through two figures
+		node := Empty(Rules{})
+		node.board[pawns][white] = squares(9, 18, 20, 13)
+		node.board[pawns][black] = squares(27)
+		moves := node.generatePawnMoves(black, 27, -1)
+		if len(moves) != 0 {
+			t.Error("Cannot jump over two figures in a row")
+		}
+	}
+	{
+		// cannot jump over own unit
+		node := Empty(Rules{})
+		node.board[pawns][white] = squares(17, 19, 26)
+		moves := node.generatePawnMoves(white, 26, -1)
+		if len(moves) != 0 {
+			t.Error("Cannot jump over own figure")
+		}
+	}
+}
+
+func TestKingMovesAndJumps(t *testing.T) {
+	{
+		node := Empty(Rules{})
+		node.board[pawns][white] = squares(1, 19)
+		node.board[kings][white] = squares(3, 17)
+		node.board[kings][black] = squares(10)
+		moves := node.generateKingMoves(black, 10)
+		if len(moves) != 2 {
+			t.Error("Expected two jumps")
+		}
+		whiteBoard := moves[0].board[pawns][white].or(moves[0].board[kings][white])
+		if !isBit(whiteBoard, 1) || !isBit(whiteBoard, 3) ||
+			isBit(whiteBoard, 17) || !isBit(whiteBoard, 19) ||
+			isBit(moves[0].board[kings][black], 10) {
+			t.Error("Invalid jump")
+		}
+		whiteBoard = moves[1].board[pawns][white].or(moves[1].board[kings][white])
+		if !isBit(whiteBoard, 1) || !isBit(whiteBoard, 3) ||
+			!isBit(whiteBoard, 17) || isBit(whiteBoard, 19) ||
+			isBit(moves[1].board[kings][black], 10) {
+			t.Error("Invalid jump")
+		}
+	}
+	{
+		node := Empty(Rules{})
+		node.board[kings][white] = squares(9)
+		moves := node.generateKingMoves(white, 9)
+		if len(moves) != 4 {
+			t.Error("Expected four moves")
+		}
+		var board bitboard
+		for i := 0; i < len(moves); i++ {
+			board = board.or(moves[i].board[kings][white])
+		}
+		if isBit(board, 9) || !isBit(board, 0) || !isBit(board, 2) ||
+			!isBit(board, 16) || !isBit(board, 18) {
+			t.Error("Invalid moves")
+		}
+	}
+}
+
+func TestNodeHistory(t *testing.T) {
+	node := Empty(Rules{})
+	if node.inNodeHistory(node) {
+		t.Error("Node itself cannot be in history")
+	}
+	node.addNodeHistory(node)
+	if !node.inNodeHistory(node) {
+		t.Error("Node itself must be in history")
+	}
+	node.addNodeHistory(New(Rules{}))
+	if !node.inNodeHistory(New(Rules{})) {
+		t.Error("Expected full board to be in history")
+	}
+	{
+		node1 := Empty(Rules{})
+		node1.board[pawns][white] = squares(33)
+		node2 := Empty(Rules{})
+		node2.board[kings][black] = squares(33)
+		if node1.boardMask() != node2.boardMask() {
+			t.Error("Expected same board mask")
+		}
+		// same board mask, yet cannot collide in node history
+		node.addNodeHistory(node1)
+		if !node.inNodeHistory(node1) {
+			t.Error("Node expected in hostory")
+		}
+		if node.inNodeHistory(node2) {
+			t.Error("Different node cannot be in history")
+		}
+	}
+}
+
+func TestCloneNode(t *testing.T) {
+	node := Empty(Rules{})
+	node.board[kings][white] = squares(33)
+	clone := node.cloneNode()
+	if node.boardMask() != clone.boardMask() {
+		t.Error("Expected same board mask")
+	}
+	if node.board != clone.board {
+		t.Error("Expected same board")
+	}
+	if reflect.ValueOf(node.nodeHistory).Pointer() != reflect.ValueOf(clone.nodeHistory).Pointer() {
+		t.Error("Nodes should share nodeHistory")
+	}
+}
+
+func TestSearchNodeGenerator(t *testing.T) {
+	if Empty(Rules{}).SearchNodeGenerator()(true) != nil || Empty(Rules{}).SearchNodeGenerator()(false) != nil {
+		t.Error("Impossible to generate nodes from empty board")
+	}
+	generator := New(Rules{}).SearchNodeGenerator()
+	for i := 0; i < 14; i++ {
+		if generator(i/7 == 0) == nil {
+			t.Error("Should generate more moves")
+		}
+	}
+	if generator(false) != nil || generator(true) != nil {
+		t.Error("Impossible to generate more moves")
+	}
+}
+
+func TestScoreDelta(t *testing.T) {
+	node := Empty(Rules{})
+	node.board[pawns][white] = squares(10, 49)
+	node.board[kings][white] = squares(28)
+	node.board[pawns][black] = squares(3, 19, 48)
+	node.board[kings][black] = squares(21)
+	for _, maximizing := range []bool{true, false} {
+		children := 0
+		for generator := node.SearchNodeGenerator(); ; children++ {
+			child := generator(maximizing)
+			if child == nil {
+				break
+			}
+			if node.Score()+child.(Board).ScoreDelta(node) != child.Score() {
+				t.Errorf("Invalid score delta %d for move\n%s", child.(Board).ScoreDelta(node), child)
+			}
+		}
+		if children == 0 {
+			t.Error("Expected some moves")
+		}
+	}
+	// full rescoring via evaluator must give the same results
+	fullScore := csa.Searcher{Evaluator: csa.EvaluatorFunc(func(node csa.SearchNode) int {
+		return node.Score()
+	})}
+	for _, maximizing := range []bool{true, false} {
+		_, score := csa.MinimaxAlphaBetaPrunning(New(Rules{}), 5, maximizing)
+		_, fullRescore := fullScore.MinimaxAlphaBetaPrunning(New(Rules{}), 5, maximizing)
+		if score != fullRescore {
+			t.Errorf("Incremental score %d differs from full score %d", score, fullRescore)
+		}
+	}
+}
+
+func TestMultiJump(t *testing.T) {
+	node := Empty(Rules{})
+	node.multiJump = true
+	node.board[pawns][white] = squares(44)
+	node.board[pawns][black] = squares(37, 21)
+	node.board[kings][black] = squares(7)
+	moves := node.generatePawnMoves(white, 44, whitePawnDir)
+	if len(moves) != 2 || !moves[0].extraTurn || moves[0].jumpIndex != 30 || moves[1].extraTurn {
+		t.Fatal("Expected jump with extra turn and single move")
+	}
+	// only the jumping figure continues
+	chain := moves[0].SearchNodeGenerator()
+	next := chain(false)
+	if next == nil || chain(false) != nil || next.(Board).extraTurn || !isBit(next.(Board).board[pawns][white], 12) {
+		t.Errorf("Expected one continuing jump %s", next)
+	}
+	// white captures both pawns within its turn
+	type minimax func(node csa.SearchNode, depth int, maximizing bool) (csa.SearchNode, int)
+	for _, minimaxFn := range []minimax{
+		csa.Minimax,
+		csa.MinimaxAlphaBetaPrunning,
+		func(node csa.SearchNode, depth int, maximizing bool) (csa.SearchNode, int) {
+			return csa.MinimaxConcurrent(node, depth, maximizing, 2)
+		},
+	} {
+		sn, score := minimaxFn(node, 2, false)
+		if sn == nil || !sn.(Board).extraTurn || score != kingScore-pawnScore {
+			t.Errorf("Expected double jump with score %d, got %d", kingScore-pawnScore, score)
+		}
+	}
+	// black moves twice, e.g. the king runs away, if the jumps are not chained
+	node.multiJump = false
+	if _, score := csa.MinimaxAlphaBetaPrunning(node, 2, false); score == kingScore-pawnScore {
+		t.Error("Black has to move in between the jumps")
+	}
+}
+
+func TestMove(t *testing.T) {
+	node := Empty(Rules{})
+	node.board[pawns][white] = squares(44)
+	node.board[pawns][black] = squares(37)
+	moves := []string{}
+	for generator := node.SearchNodeGenerator(); ; {
+		child := generator(false)
+		if child == nil {
+			break
+		}
+		moves = append(moves, fmt.Sprint(csa.MoveOf(child)))
+	}
+	if strings.Join(moves, " ") != "44x30 44-35" {
+		t.Errorf("Invalid moves %v", moves)
+	}
+	best, _ := csa.MinimaxAlphaBetaPrunning(node, 1, false)
+	if csa.MoveOf(best) != (Move{44, 30, true}) {
+		t.Errorf("Expected jump, got %v", csa.MoveOf(best))
+	}
+}
+
+func TestPlaceAndMoves(t *testing.T) {
+	node := Empty(Rules{MultiJump: true}).Place(Pawn, White, 44).Place(King, Black, 37).Place(Pawn, Black, 21)
+	if figure, color, ok := node.At(37); !ok || figure != King || color != Black {
+		t.Error("Expected black king")
+	}
+	if _, _, ok := node.Remove(37).At(37); ok {
+		t.Error("Expected empty square")
+	}
+	if !node.Rules().MultiJump || node.Score() != 3 {
+		t.Error("Invalid rules or score")
+	}
+	moves := node.Moves(false)
+	if len(moves) != 2 || moves[0].Move() != (Move{44, 30, true}) || !moves[0].ExtraTurn() {
+		t.Fatalf("Expected jump with extra turn and single move, got %v", moves)
+	}
+	node.AddHistory(moves[1])
+	if !node.InHistory(moves[1]) || len(node.Moves(false)) != 1 {
+		t.Error("Played board must not be generated again")
+	}
+}
+
+func TestGame(t *testing.T) {
+	engine := csa.NewEngine(csa.WithMaxDepth(4))
+	start := New(Rules{})
+	node, maximizing := start, true
+	for plies := 0; plies < 40 && !node.IsTerminal(); plies++ {
+		child, _ := engine.BestMove(node, maximizing)
+		if child == nil {
+			break
+		}
+		node = child.(Board)
+		node.AddHistory(node)
+		if !node.ExtraTurn() {
+			maximizing = !maximizing
+		}
+	}
+	if node.String() == start.String() {
+		t.Error("Expected played game")
+	}
+}
+
+func TestFlyingKings(t *testing.T) {
+	node := Empty(Rules{FlyingKings: true}).Place(King, Black, 0)
+	if moves := node.Moves(true); len(moves) != 7 {
+		t.Errorf("Expected moves along the whole diagonal, got %v", moves)
+	}
+	node = node.Place(Pawn, White, 27)
+	jumps := []string{}
+	for _, move := range node.Moves(true) {
+		jumps = append(jumps, fmt.Sprint(move.Move()))
+	}
+	if strings.Join(jumps, " ") != "0-9 0-18 0x36 0x45 0x54 0x63" {
+		t.Errorf("Expected jumps to any square behind the pawn, got %v", jumps)
+	}
+	if moves := node.Place(Pawn, White, 54).Moves(true); len(moves) != 4 {
+		t.Errorf("Expected landing squares up to the next figure, got %v", moves)
+	}
+	if moves := node.Place(Pawn, White, 36).Moves(true); len(moves) != 2 {
+		t.Errorf("Two figures cannot be jumped at once, got %v", moves)
+	}
+	// only the landing square with the next jump continues the chain
+	node = Empty(Rules{FlyingKings: true, MultiJump: true}).Place(King, Black, 0).Place(Pawn, White, 27).Place(Pawn, White, 38)
+	for _, move := range node.Moves(true) {
+		if move.ExtraTurn() != (move.Move() == Move{0, 45, true}) {
+			t.Errorf("Invalid extra turn of %v", move.Move())
+		}
+		if move.ExtraTurn() {
+			if chain := move.Moves(true); len(chain) != 1 || chain[0].Move() != (Move{45, 31, true}) {
+				t.Errorf("Expected continuing jump, got %v", chain)
+			}
+		}
+	}
+	if _, score := csa.MinimaxAlphaBetaPrunning(node, 2, true); score != 3 {
+		t.Errorf("Expected both pawns taken, got score %d", score)
+	}
+	// one square kings
+	if moves := Empty(Rules{}).Place(King, Black, 0).Moves(true); len(moves) != 1 {
+		t.Errorf("Expected a single step, got %v", moves)
+	}
+}
+
+func TestBoardSizes(t *testing.T) {
+	node := New(Rules{Size: 10})
+	if strings.Count(node.String(), string(blackPawn)) != 20 || strings.Count(node.String(), string(whitePawn)) != 20 {
+		t.Errorf("Expected four rows of pawns\n%s", node)
+	}
+	if !strings.HasPrefix(node.String(), "♙ _ ♙ _ ♙ _ ♙ _ ♙ _\n_ ♙") || strings.Count(node.String(), "\n") != 10 {
+		t.Errorf("Invalid board\n%s", node)
+	}
+	if len(node.Moves(true)) != 9 || len(node.Moves(false)) != 9 {
+		t.Error("Expected nine moves")
+	}
+	// promotion on the last row
+	if moves := Empty(Rules{Size: 10}).Place(Pawn, Black, 81).Moves(true); len(moves) != 2 || moves[0].Score() != kingScore {
+		t.Errorf("Expected promotion, got %v", moves)
+	}
+	if moves := Empty(Rules{Size: 10, FlyingKings: true}).Place(King, White, 99).Moves(false); len(moves) != 9 {
+		t.Errorf("Expected moves along the whole diagonal, got %d", len(moves))
+	}
+	// squares beyond the first word
+	node = Empty(Rules{Size: 16}).Place(Pawn, White, 200).Place(Pawn, Black, 183)
+	if figure, color, ok := node.At(200); !ok || figure != Pawn || color != White {
+		t.Error("Expected white pawn")
+	}
+	if moves := node.Moves(false); len(moves) != 2 || moves[1].Move() != (Move{200, 166, true}) || moves[1].Score() != -1 {
+		t.Errorf("Expected move and jump, got %v", moves)
+	}
+	if _, score := csa.MinimaxAlphaBetaPrunning(New(Rules{Size: 10}), 4, true); score != 0 {
+		t.Errorf("Expected no capture, got %d", score)
+	}
+	for _, size := range []int{2, 7, 18} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for size %d", size)
+				}
+			}()
+			Empty(Rules{Size: size})
+		}()
+	}
+}
+
+func TestNumericNotation(t *testing.T) {
+	for _, size := range []int{8, 10} {
+		node := Empty(Rules{Size: size})
+		for square := 1; square <= size*size/2; square++ {
+			if index := node.Index(square); node.Square(index) != square || (index/size+index%size)%2 != 0 {
+				t.Fatalf("Invalid square %d of index %d", square, index)
+			}
+		}
+		if node.Index(0) != -1 || node.Index(size*size/2+1) != -1 || node.Square(1) != 0 {
+			t.Error("Invalid squares")
+		}
+	}
+	node := New(Rules{})
+	if node.Square(6) != 1 || node.Square(0) != 4 || node.Square(63) != 29 {
+		t.Error("Invalid square numbers")
+	}
+	for _, square := range []int{1, 12, 21, 32} {
+		if _, color, _ := node.At(node.Index(square)); (color == Black) != (square <= 12) {
+			t.Errorf("Invalid color of square %d", square)
+		}
+	}
+	if notation := node.Notation(node.Moves(true)[0].Move().(Move)); notation != "12-16" {
+		t.Errorf("Invalid notation %s", notation)
+	}
+}
+
+const testPDN = `[Event "Test"]
+[White "Engine"]
+[Black "Human"]
+[Result "*"]
+
+1. 11-15 22-18 2. 15x22 {jump} 25x18 3. 8-11 (3. 9-14 18x9) 29-25! 4. 4-8 $1 25-22
+5. 12-16 24-20 *
+
+[Event "Second"]
+1. 9-14 23-19 1-0
+`
+
+func TestPDN(t *testing.T) {
+	games, err := ParsePDN(strings.NewReader(testPDN), Rules{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 2 || games[0].Tags["White"] != "Engine" || games[0].Result != "*" || games[1].Result != "2-0" {
+		t.Fatalf("Invalid games %v", games)
+	}
+	game := games[0]
+	if len(game.Boards) != 10 || len(game.Turns()) != 10 || game.Boards[9].Score() != 0 {
+		t.Errorf("Invalid moves %d", len(game.Boards))
+	}
+	if notation := game.Boards[2].Notation(game.Boards[2].Move().(Move)); notation != "15x22" {
+		t.Errorf("Expected jump, got %s", notation)
+	}
+	if !game.Start.InHistory(game.Boards[9]) {
+		t.Error("Played boards must be in the history")
+	}
+	var sb strings.Builder
+	if err := WritePDN(&sb, game); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), "[Black \"Human\"]\n[Result \"*\"]\n\n1. 11-15 22-18 2. 15x22 25x18 3. 8-11 29-25") {
+		t.Errorf("Invalid PDN\n%s", sb.String())
+	}
+	written, err := ParsePDN(strings.NewReader(sb.String()), Rules{})
+	if err != nil || len(written) != 1 || written[0].Boards[9].String() != game.Boards[9].String() {
+		t.Errorf("Written game differs %v", err)
+	}
+	if _, err := ParsePDN(strings.NewReader("1. 11-15 11-15"), Rules{}); err == nil {
+		t.Error("Expected illegal move")
+	}
+}
+
+func TestPDNJumpChain(t *testing.T) {
+	start, _, _ := ParsePosition("W:W22:B11,18,K3", Rules{MultiJump: true})
+	for _, notation := range []string{"22x15x8", "22x8"} {
+		game := Game{Start: start, Maximizing: false}
+		if err := game.play(notation); err != nil || len(game.Boards) != 2 || game.Boards[1].Score() != 2 {
+			t.Errorf("Expected double jump %s, %v", notation, err)
+		}
+		var sb strings.Builder
+		WritePDN(&sb, game)
+		if !strings.Contains(sb.String(), "1... 22x15x8 *") {
+			t.Errorf("Invalid PDN\n%s", sb.String())
+		}
+	}
+	game := Game{Start: start, Maximizing: false}
+	if err := game.play("22x15"); err == nil {
+		t.Error("Jump chain has to be finished")
+	}
+}
+
+func TestPosition(t *testing.T) {
+	node, maximizing, err := ParsePosition("W:W21-23,K30:BK1,2,12.", Rules{})
+	if err != nil || maximizing || node.Score() != -3-3+3+2 {
+		t.Fatalf("Invalid position %v\n%s", err, node)
+	}
+	if figure, color, _ := node.At(node.Index(30)); figure != King || color != White {
+		t.Error("Expected white king")
+	}
+	if fen := FormatPosition(node, true); fen != "B:W21,22,23,K30:BK1,2,12" {
+		t.Errorf("Invalid FEN %s", fen)
+	}
+	if !node.InHistory(node) {
+		t.Error("Position must be in its history")
+	}
+	if node, maximizing, _ := ParsePosition(FormatPosition(New(Rules{}), true), Rules{}); node.String() != New(Rules{}).String() || !maximizing {
+		t.Error("Initial position differs")
+	}
+	if node, _, err := ParsePosition("W:W31-50:B1-20", Rules{Size: 10}); err != nil || node.String() != New(Rules{Size: 10}).String() {
+		t.Errorf("Expected initial international position %v", err)
+	}
+	for _, fen := range []string{"", "X:W1", "B:W33", "B:W5-2", "B:Q1", "B:Wx"} {
+		if _, _, err := ParsePosition(fen, Rules{}); err == nil {
+			t.Errorf("Expected invalid FEN %q", fen)
+		}
+	}
+	// FEN of the PDN
+	games, err := ParsePDN(strings.NewReader(`[FEN "W:W22:B11,18,K3"]
+1. 22x15 *`), Rules{})
+	if err != nil || len(games) != 1 || games[0].Maximizing || games[0].Boards[0].Score() != 3 {
+		t.Fatalf("Invalid game %v", err)
+	}
+	var sb strings.Builder
+	WritePDN(&sb, games[0])
+	if !strings.Contains(sb.String(), `[FEN "W:W22:BK3,11,18"]`) || !strings.Contains(sb.String(), "1... 22x15 *") {
+		t.Errorf("Invalid PDN\n%s", sb.String())
+	}
+}
+
+func TestEndgame(t *testing.T) {
+	db := NewEndgame(Rules{}, 3)
+	node, _, _ := ParsePosition("B:WK14:BK1,K10", Rules{})
+	if node.PieceCount() != 3 {
+		t.Fatal("Expected three pieces")
+	}
+	// black king captures the last white piece
+	if result, ok := db.Lookup(node, true); !ok || result.WDL != 1 || result.DTM != 1 {
+		t.Errorf("Expected win in one ply, got %v", result)
+	}
+	if _, ok := NewEndgame(Rules{}, 2).Lookup(node, true); ok {
+		t.Error("Too many pieces")
+	}
+	// every position agrees with its children
+	pawns, _, _ := ParsePosition("B:W14,K30:B10", Rules{})
+	for _, m := range []material{node.material(), pawns.material()} {
+		testEndgameTable(t, db, m)
+	}
+	// search prefers the tablebase win
+	searcher := csa.Searcher{WinScore: 100, Tablebase: db, TablebasePieces: 3}
+	start, _, _ := ParsePosition("B:WK32:BK1,K10", Rules{})
+	result, _ := db.Lookup(start, true)
+	if _, score := searcher.MinimaxAlphaBetaPrunning(start, 2, true); (score > 100) != (result.WDL > 0) {
+		t.Errorf("Expected tablebase score, got %d of %v", score, result)
+	}
+}
+
+func testEndgameTable(t *testing.T, db *Endgame, m material) {
+	wins := 0
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.positions(m, func(node Board) {
+		for _, maximizing := range []bool{true, false} {
+			key, _ := node.endgameKey(maximizing)
+			entry := db.table(m)[key]
+			best := endgameEntry{wdl: -1}
+			moves := 0
+			node.turns(maximizing, nil, func(turn []Board) {
+				child, plies := turn[len(turn)-1], len(turn)
+				moves++
+				childEntry := endgameEntry{wdl: -1}
+				if !child.IsTerminal() {
+					key, _ := child.endgameKey(!maximizing)
+					childEntry = db.table(child.material())[key]
+				}
+				value := endgameEntry{wdl: -childEntry.wdl, dtm: childEntry.dtm + int16(plies)}
+				if value.wdl > best.wdl || (value.wdl == best.wdl && value.wdl > 0 && value.dtm < best.dtm) ||
+					(value.wdl == best.wdl && value.wdl < 0 && value.dtm > best.dtm) {
+					best = value
+				}
+			})
+			if best.wdl == 0 {
+				best.dtm = 0
+			}
+			if entry != best {
+				t.Fatalf("Entry %v differs from children %v of\n%s", entry, best, node)
+			}
+			if entry.wdl > 0 {
+				wins++
+			}
+		}
+	})
+	if wins == 0 {
+		t.Errorf("Expected wins of %v", m)
+	}
+}
+
+func TestHub(t *testing.T) {
+	rules := Rules{Size: 10, MultiJump: true, FlyingKings: true}
+	node, _, _ := ParsePosition("W:W32,33:B28,18,19", rules)
+	pos := formatHubPosition(node, false)
+	if start, maximizing, err := parseHubPosition(pos, rules); err != nil || maximizing || start.board != node.board {
+		t.Fatalf("Invalid position %s %v", pos, err)
+	}
+	// captured squares in any order
+	if turn := hubTurn(node, false, "33x13x28x18"); len(turn) != 2 || hubNotation(node, turn) != "33x13x18x28" {
+		t.Errorf("Expected double jump, got %d boards", len(turn))
+	}
+	if hubTurn(node, false, "33x13x18") != nil || hubTurn(node, false, "33-28") != nil {
+		t.Error("Expected illegal move")
+	}
+	if command, args := parseHubLine(`pos pos=` + pos + ` moves="33x13x18x28 19-24"`); command != "pos" ||
+		args["pos"] != pos || args["moves"] != "33x13x18x28 19-24" {
+		t.Errorf("Invalid command %s %v", command, args)
+	}
+
+	hub := &Hub{Name: "csa engine", Rules: rules}
+	var out strings.Builder
+	in := strings.Join([]string{
+		"hub", "init", "set-param name=variant value=normal", "new-game",
+		"level depth=2", "pos pos=" + pos + ` moves="33x13x18x28"`, "go think", "stop",
+		"pos moves=32-27", "pos moves=20-25", "ping", "quit", "ping",
+	}, "\n")
+	if err := hub.Run(strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	expected := []string{`id name="csa engine"`, "wait", "ready"}
+	if !reflect.DeepEqual(lines[:3], expected) || !strings.HasPrefix(lines[3], "info depth=1 ") {
+		t.Fatalf("Invalid output\n%s", out.String())
+	}
+	// black to move after the jump chain
+	done, last := lines[len(lines)-3], lines[len(lines)-2:]
+	if turn := hubTurn(hub.root, true, strings.TrimPrefix(done, "done move=")); turn == nil {
+		t.Errorf("Invalid move %s", done)
+	}
+	if !reflect.DeepEqual(last, []string{"error message=\"illegal move 20-25\"", "pong"}) {
+		t.Errorf("Invalid output\n%s", out.String())
+	}
+	// white moves first, the failed position keeps the previous one
+	if !hub.maximizing || hub.node.board == New(rules).board {
+		t.Error("Expected the position after 32-27")
+	}
+}
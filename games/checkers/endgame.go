@@ -0,0 +1,299 @@
+package checkers
+
+import (
+	"math/bits"
+	"sync"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Endgame database of the positions with few pieces built by retrograde analysis, implements csa.TablebaseProber
+// Tables of the materials are built on their first probe together with the tables they convert to,
+// four pieces on 8x8 board take seconds and hundreds of MB.
+// Positions are solved without the history, repetitions are draws, and the player who cannot move loses
+// as with csa.NoMoveLoss. Only the positions with all the pieces on the dark squares are in the database.
+type Endgame struct {
+	rules  Rules
+	pieces int
+
+	mutex  sync.RWMutex
+	tables map[material]endgameTable
+}
+
+// Counts of the pieces, [figure][color]
+type material [2][2]int
+
+// Win, draw or loss of the player to move and the plies to the end of the game
+type endgameEntry struct {
+	wdl int8
+	dtm int16
+}
+
+type endgameTable map[uint64]endgameEntry
+
+// Database of the positions with up to 6 pieces, 2-4 are practical
+func NewEndgame(rules Rules, pieces int) *Endgame {
+	return &Endgame{rules: Empty(rules).Rules(), pieces: min(pieces, 6), tables: make(map[material]endgameTable)}
+}
+
+func (node Board) PieceCount() int {
+	m := node.material()
+	return m[pawns][white] + m[pawns][black] + m[kings][white] + m[kings][black]
+}
+
+func (node Board) material() material {
+	var m material
+	for figure := range node.board {
+		for color, bitboard := range node.board[figure] {
+			for _, word := range bitboard {
+				m[figure][color] += bits.OnesCount64(word)
+			}
+		}
+	}
+	return m
+}
+
+// Player to move in the generated board, the one which made the last move continues its jump chain
+func (node Board) toMove() (int, bool) {
+	_, color, ok := node.At(node.lastMove.To)
+	if !ok || node.lastMove.From == node.lastMove.To {
+		return 0, false
+	}
+	if node.extraTurn {
+		return int(color), true
+	}
+	return enemyColor(int(color)), true
+}
+
+// Result of the board generated by the search, the player to move is the opponent of the last move's player
+func (db *Endgame) Probe(node csa.SearchNode) (csa.TablebaseResult, bool) {
+	board, ok := node.(Board)
+	if !ok {
+		return csa.TablebaseResult{}, false
+	}
+	color, ok := board.toMove()
+	if !ok {
+		return csa.TablebaseResult{}, false
+	}
+	return db.Lookup(board, color == black)
+}
+
+// Result of the board with the given player to move for the maximizing player (black)
+func (db *Endgame) Lookup(node Board, maximizing bool) (csa.TablebaseResult, bool) {
+	if node.extraTurn || node.Rules() != db.rules || node.PieceCount() > db.pieces || node.IsTerminal() {
+		return csa.TablebaseResult{}, false
+	}
+	key, ok := node.endgameKey(maximizing)
+	if !ok {
+		return csa.TablebaseResult{}, false
+	}
+	m := node.material()
+	db.mutex.RLock()
+	table, built := db.tables[m]
+	db.mutex.RUnlock()
+	if !built {
+		db.mutex.Lock()
+		table = db.table(m)
+		db.mutex.Unlock()
+	}
+	// draws are not stored
+	entry := table[key]
+	result := csa.TablebaseResult{WDL: int(entry.wdl), DTM: int(entry.dtm)}
+	if !maximizing {
+		result.WDL = -result.WDL
+	}
+	return result, true
+}
+
+// Exact key of the position, 9 bits per piece, false if a piece is on a light square
+func (node Board) endgameKey(maximizing bool) (uint64, bool) {
+	key := uint64(0)
+	if maximizing {
+		key = 1
+	}
+	b := &node.board
+	for i, word := range b[pawns][black].or(b[kings][black]).or(b[pawns][white]).or(b[kings][white]) {
+		for ; word != 0; word &= word - 1 {
+			index := i*64 + bits.TrailingZeros64(word)
+			square := node.Square(index)
+			if square == 0 {
+				return 0, false
+			}
+			figure, color, _ := node.At(index)
+			key = key<<9 | uint64(square)<<2 | uint64(figure)<<1 | uint64(color)
+		}
+	}
+	return key, true
+}
+
+// Table of the material, built if missing, the write lock has to be held
+func (db *Endgame) table(m material) endgameTable {
+	if table, ok := db.tables[m]; ok {
+		return table
+	}
+	table := db.build(m)
+	db.tables[m] = table
+	return table
+}
+
+// Calls visit with every position of the material, pawns are never on their promotion rows
+func (db *Endgame) positions(m material, visit func(node Board)) {
+	type group struct{ figure, color, count int }
+	var groups []group
+	for figure := range m {
+		for color, count := range m[figure] {
+			if count > 0 {
+				groups = append(groups, group{figure, color, count})
+			}
+		}
+	}
+	empty := Empty(db.rules)
+	empty.nodeHistory = nil
+	var place func(node Board, g, placed, from int)
+	place = func(node Board, g, placed, from int) {
+		if g == len(groups) {
+			visit(node)
+			return
+		}
+		if placed == groups[g].count {
+			place(node, g+1, 0, 1)
+			return
+		}
+		figure, color := groups[g].figure, groups[g].color
+		for square := from; square <= node.squares()/2; square++ {
+			index := node.Index(square)
+			if node.placeOccupied(index) || (figure == pawns && node.promotionRow(color, index)) {
+				continue
+			}
+			place(node.Place(Figure(figure), Color(color), index), g, placed+1, square+1)
+		}
+	}
+	place(empty, 0, 0, 1)
+}
+
+// Calls visit with the boards of the player's turns, the jump chains are followed to their ends
+func (node Board) turns(maximizing bool, turn []Board, visit func(turn []Board)) {
+	for generator := node.SearchNodeGenerator(); ; {
+		child := generator(maximizing)
+		if child == nil {
+			return
+		}
+		board := child.(Board)
+		if turn := append(turn[:len(turn):len(turn)], board); board.extraTurn {
+			board.turns(maximizing, turn, visit)
+		} else {
+			visit(turn)
+		}
+	}
+}
+
+// Solves the positions of the material by retrograde analysis, the captures and promotions lead to the other tables
+// which are built first, the write lock has to be held
+func (db *Endgame) build(m material) endgameTable {
+	index := make(map[uint64]int32)
+	var keys []uint64
+	db.positions(m, func(node Board) {
+		for _, maximizing := range []bool{true, false} {
+			key, _ := node.endgameKey(maximizing)
+			index[key] = int32(len(keys))
+			keys = append(keys, key)
+		}
+	})
+	type state struct {
+		unresolved int  // children in this table not resolved yet
+		longest    int  // plies of the longest loss among the resolved children
+		winning    bool // a child out of this table is a loss of the opponent
+		draw       bool // a child out of this table is a draw
+		resolved   bool
+	}
+	type resolution struct {
+		position int32
+		win      bool
+	}
+	states := make([]state, len(keys))
+	parents := make([][]int32, len(keys))
+	var buckets [][]resolution // by plies to the end of the game
+	push := func(plies int, position int32, win bool) {
+		for len(buckets) <= plies {
+			buckets = append(buckets, nil)
+		}
+		buckets[plies] = append(buckets[plies], resolution{position, win})
+	}
+	position := int32(0)
+	db.positions(m, func(node Board) {
+		for _, maximizing := range []bool{true, false} {
+			s := &states[position]
+			shortest, moves := -1, 0
+			node.turns(maximizing, nil, func(turn []Board) {
+				child, plies := turn[len(turn)-1], len(turn)
+				moves++
+				if child.IsTerminal() {
+					// the opponent has no pieces
+					if shortest < 0 || plies < shortest {
+						shortest = plies
+					}
+					return
+				}
+				if child.material() == m {
+					key, _ := child.endgameKey(!maximizing)
+					parents[index[key]] = append(parents[index[key]], position)
+					s.unresolved++
+					return
+				}
+				key, _ := child.endgameKey(!maximizing)
+				entry := db.table(child.material())[key]
+				plies += int(entry.dtm)
+				switch {
+				case entry.wdl < 0 && (shortest < 0 || plies < shortest):
+					shortest = plies
+				case entry.wdl > 0:
+					s.longest = max(s.longest, plies)
+				case entry.wdl == 0:
+					s.draw = true
+				}
+			})
+			switch {
+			case moves == 0:
+				// the player cannot move
+				push(0, position, false)
+			case shortest >= 0:
+				s.winning = true
+				push(shortest, position, true)
+			case s.unresolved == 0 && !s.draw:
+				push(s.longest, position, false)
+			}
+			position++
+		}
+	})
+	table := make(endgameTable)
+	for plies := 0; plies < len(buckets); plies++ {
+		for i := 0; i < len(buckets[plies]); i++ {
+			resolution := buckets[plies][i]
+			if states[resolution.position].resolved {
+				continue
+			}
+			states[resolution.position].resolved = true
+			entry := endgameEntry{wdl: -1, dtm: int16(plies)}
+			if resolution.win {
+				entry.wdl = 1
+			}
+			table[keys[resolution.position]] = entry
+			for _, parent := range parents[resolution.position] {
+				s := &states[parent]
+				if s.resolved {
+					continue
+				}
+				if !resolution.win {
+					push(plies+1, parent, true)
+					continue
+				}
+				s.unresolved--
+				s.longest = max(s.longest, plies+1)
+				if s.unresolved == 0 && !s.draw && !s.winning {
+					push(s.longest, parent, false)
+				}
+			}
+		}
+	}
+	return table
+}
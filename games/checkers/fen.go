@@ -0,0 +1,73 @@
+package checkers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parses the position of the draughts FEN, e.g. B:W21,22,K30:B1-4,K12, with the squares in the numeric notation
+// Returns the board with its own history and whether black (the maximizing player) is to move.
+func ParsePosition(fen string, rules Rules) (Board, bool, error) {
+	node := Empty(rules)
+	fields := strings.Split(strings.TrimSuffix(strings.TrimSpace(fen), "."), ":")
+	if len(fields) == 0 || (fields[0] != "W" && fields[0] != "B") {
+		return Board{}, false, fmt.Errorf("checkers: invalid FEN %q", fen)
+	}
+	colors := map[byte]int{'W': white, 'B': black}
+	for _, field := range fields[1:] {
+		if field == "" {
+			continue
+		}
+		color, ok := colors[field[0]]
+		if !ok {
+			return Board{}, false, fmt.Errorf("checkers: invalid FEN %q", fen)
+		}
+		for _, piece := range strings.Split(field[1:], ",") {
+			if piece == "" {
+				continue
+			}
+			figure := pawns
+			if piece[0] == 'K' {
+				figure, piece = kings, piece[1:]
+			}
+			first, last, isRange := strings.Cut(piece, "-")
+			if !isRange {
+				last = first
+			}
+			from, fromErr := strconv.Atoi(first)
+			to, toErr := strconv.Atoi(last)
+			if fromErr != nil || toErr != nil || from > to || node.Index(from) < 0 || node.Index(to) < 0 {
+				return Board{}, false, fmt.Errorf("checkers: invalid square %q of FEN %q", piece, fen)
+			}
+			for square := from; square <= to; square++ {
+				node = node.Place(Figure(figure), Color(color), node.Index(square))
+			}
+		}
+	}
+	node.addNodeHistory(node)
+	return node, fields[0] == "B", nil
+}
+
+// Position of the board in the draughts FEN
+func FormatPosition(node Board, maximizing bool) string {
+	var sb strings.Builder
+	if maximizing {
+		sb.WriteString("B")
+	} else {
+		sb.WriteString("W")
+	}
+	for _, color := range []int{white, black} {
+		sb.WriteString([]string{":W", ":B"}[color])
+		var pieces []string
+		for square := 1; square <= node.squares()/2; square++ {
+			if node.placeOccupiedFigureColor(pawns, color, node.Index(square)) {
+				pieces = append(pieces, strconv.Itoa(square))
+			} else if node.placeOccupiedFigureColor(kings, color, node.Index(square)) {
+				pieces = append(pieces, "K"+strconv.Itoa(square))
+			}
+		}
+		sb.WriteString(strings.Join(pieces, ","))
+	}
+	return sb.String()
+}
@@ -0,0 +1,322 @@
+package checkers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Engine speaking the Hub protocol of the draughts GUIs, e.g. over stdin and stdout
+// Positions are the side to move followed by the squares in the numeric notation, e.g. "W" + 20*"b" + 10*"e" + 20*"w",
+// moves are 32-28 or the captures 28x19x23 with the captured squares after the destination.
+// Hub is made for international draughts, the moves played by the GUI have to be legal by the Rules too,
+// e.g. the jumps are never compulsory here. White moves first in the initial position.
+// Pondering is not supported, "go ponder" searches until "ponder-hit".
+type Hub struct {
+	Name    string
+	Rules   Rules              // e.g. Size 10, FlyingKings and MultiJump
+	Options []csa.EngineOption // of the engine, the depth and time limits are set by the level command
+
+	writer     *bufio.Writer
+	mutex      sync.Mutex // of the writer
+	node       Board
+	maximizing bool
+	root       Board // of the running search, the jump chains are searched jump by jump
+	depth      int
+	clock      csa.Clock
+	infinite   bool
+	engine     *csa.Engine
+	cancel     context.CancelFunc
+	done       chan struct{} // closed by the finished search
+}
+
+// Serves the commands until quit or the end of the input
+func (hub *Hub) Run(r io.Reader, w io.Writer) error {
+	hub.writer = bufio.NewWriter(w)
+	hub.node, hub.maximizing = New(hub.Rules), false
+	defer hub.stop()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		command, args := parseHubLine(scanner.Text())
+		switch command {
+		case "hub":
+			hub.send("id name=%s", hubValue(hub.Name))
+			hub.send("wait")
+		case "init":
+			hub.send("ready")
+		case "ping":
+			hub.send("pong")
+		case "stop", "ponder-hit":
+			hub.stop()
+		case "quit":
+			return nil
+		case "new-game", "pos":
+			hub.stop()
+			if err := hub.position(args); err != nil {
+				hub.send("error message=%s", hubValue(err.Error()))
+			}
+		case "level":
+			hub.stop()
+			hub.level(args)
+		case "go":
+			hub.stop()
+			_, ponder := args["ponder"]
+			_, analyze := args["analyze"]
+			hub.think(ponder || analyze)
+		case "", "set-param":
+		default:
+			hub.send("error message=%s", hubValue("unknown command "+command))
+		}
+	}
+	return scanner.Err()
+}
+
+func (hub *Hub) send(format string, args ...any) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	fmt.Fprintf(hub.writer, format+"\n", args...)
+	hub.writer.Flush()
+}
+
+// Command and its arguments, name=value pairs with optionally quoted values or single names
+func parseHubLine(line string) (string, map[string]string) {
+	args := make(map[string]string)
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", args
+	}
+	rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), fields[0]))
+	for rest != "" {
+		end := strings.IndexAny(rest, " =")
+		if end < 0 {
+			args[rest] = ""
+			break
+		}
+		name := rest[:end]
+		if rest[end] == ' ' {
+			args[name] = ""
+			rest = strings.TrimSpace(rest[end:])
+			continue
+		}
+		rest = rest[end+1:]
+		if strings.HasPrefix(rest, `"`) {
+			closing := strings.Index(rest[1:], `"`)
+			if closing < 0 {
+				closing = len(rest) - 1
+			}
+			args[name] = rest[1 : closing+1]
+			rest = strings.TrimSpace(rest[min(closing+2, len(rest)):])
+			continue
+		}
+		value, next, _ := strings.Cut(rest, " ")
+		args[name] = value
+		rest = strings.TrimSpace(next)
+	}
+	return fields[0], args
+}
+
+// Value quoted if it contains spaces
+func hubValue(value string) string {
+	if strings.ContainsAny(value, " \"") {
+		return `"` + strings.ReplaceAll(value, `"`, "'") + `"`
+	}
+	return value
+}
+
+// Sets the position of the pos and new-game commands, the played moves are added to the history
+func (hub *Hub) position(args map[string]string) error {
+	node, maximizing := New(hub.Rules), false
+	if pos, ok := args["pos"]; ok {
+		var err error
+		if node, maximizing, err = parseHubPosition(pos, hub.Rules); err != nil {
+			return err
+		}
+	}
+	for _, move := range strings.Fields(args["moves"]) {
+		turn := hubTurn(node, maximizing, move)
+		if turn == nil {
+			return fmt.Errorf("illegal move %s", move)
+		}
+		for _, board := range turn {
+			node.AddHistory(board)
+		}
+		node, maximizing = turn[len(turn)-1], !maximizing
+	}
+	hub.node, hub.maximizing = node, maximizing
+	return nil
+}
+
+func parseHubPosition(pos string, rules Rules) (Board, bool, error) {
+	node := Empty(rules)
+	if len(pos) != node.squares()/2+1 || (pos[0] != 'W' && pos[0] != 'B') {
+		return Board{}, false, fmt.Errorf("invalid position %s", pos)
+	}
+	figures := map[byte][2]int{'w': {pawns, white}, 'b': {pawns, black}, 'W': {kings, white}, 'B': {kings, black}}
+	for i := 1; i < len(pos); i++ {
+		if figure, ok := figures[pos[i]]; ok {
+			node = node.Place(Figure(figure[0]), Color(figure[1]), node.Index(i))
+		} else if pos[i] != 'e' {
+			return Board{}, false, fmt.Errorf("invalid position %s", pos)
+		}
+	}
+	node.addNodeHistory(node)
+	return node, pos[0] == 'B', nil
+}
+
+func formatHubPosition(node Board, maximizing bool) string {
+	var sb strings.Builder
+	if maximizing {
+		sb.WriteByte('B')
+	} else {
+		sb.WriteByte('W')
+	}
+	for square := 1; square <= node.squares()/2; square++ {
+		figure, color, ok := node.At(node.Index(square))
+		switch {
+		case !ok:
+			sb.WriteByte('e')
+		case figure == Pawn:
+			sb.WriteByte("wb"[color])
+		default:
+			sb.WriteByte("WB"[color])
+		}
+	}
+	return sb.String()
+}
+
+// Turn of the player matching the Hub move, nil if there is none
+func hubTurn(node Board, maximizing bool, move string) []Board {
+	var found []Board
+	node.turns(maximizing, nil, func(turn []Board) {
+		if found == nil && hubNotation(node, turn) == hubNormalize(move) {
+			found = turn
+		}
+	})
+	return found
+}
+
+// Move with the captured squares sorted, e.g. 28x19x23
+func hubNotation(node Board, turn []Board) string {
+	first, last := turn[0].lastMove, turn[len(turn)-1].lastMove
+	if !first.Jump {
+		return fmt.Sprintf("%d-%d", node.Square(first.From), node.Square(last.To))
+	}
+	var captured []int
+	for index := 0; index < node.squares(); index++ {
+		if node.placeOccupied(index) && !turn[len(turn)-1].placeOccupied(index) && index != first.From {
+			captured = append(captured, node.Square(index))
+		}
+	}
+	sort.Ints(captured)
+	notation := fmt.Sprintf("%dx%d", node.Square(first.From), node.Square(last.To))
+	for _, square := range captured {
+		notation += "x" + strconv.Itoa(square)
+	}
+	return notation
+}
+
+func hubNormalize(move string) string {
+	fields := strings.Split(move, "x")
+	if len(fields) < 3 {
+		return move
+	}
+	captured := make([]int, 0, len(fields)-2)
+	for _, field := range fields[2:] {
+		square, err := strconv.Atoi(field)
+		if err != nil {
+			return move
+		}
+		captured = append(captured, square)
+	}
+	sort.Ints(captured)
+	for i, square := range captured {
+		fields[i+2] = strconv.Itoa(square)
+	}
+	return strings.Join(fields, "x")
+}
+
+// Applies the level command, the engine is recreated
+// Without the depth and the time the search runs until stopped.
+func (hub *Hub) level(args map[string]string) {
+	seconds := func(name string) time.Duration {
+		value, _ := strconv.ParseFloat(args[name], 64)
+		return time.Duration(value * float64(time.Second))
+	}
+	hub.depth, _ = strconv.Atoi(args["depth"])
+	moves, _ := strconv.Atoi(args["moves"])
+	hub.clock = csa.Clock{MoveTime: seconds("move-time"), Remaining: seconds("time"), Increment: seconds("inc"), MovesToGo: moves}
+	_, hub.infinite = args["infinite"]
+	if hub.engine != nil {
+		hub.engine.Close()
+		hub.engine = nil
+	}
+}
+
+// Starts the search of the current position, infinite one runs until stopped
+func (hub *Hub) think(infinite bool) {
+	if hub.engine == nil {
+		options := append([]csa.EngineOption{}, hub.Options...)
+		hub.engine = csa.NewEngine(append(options, csa.WithMaxDepth(hub.depth), csa.WithInfo(hub.info))...)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if soft, _ := (csa.TimeManager{}).Allocate(hub.clock); soft > 0 && !infinite && !hub.infinite {
+		ctx, cancel = context.WithTimeout(context.Background(), soft)
+	}
+	hub.cancel, hub.done = cancel, make(chan struct{})
+	node, maximizing, done := hub.node, hub.maximizing, hub.done
+	go func() {
+		defer close(done)
+		hub.root = node
+		child, _ := hub.engine.BestMoveContext(ctx, node, maximizing)
+		if child == nil {
+			hub.send("done")
+			return
+		}
+		// the engine moves a single jump of the chain, the rest of it is searched again
+		turn := []Board{child.(Board)}
+		for last := turn[0]; last.extraTurn; last = turn[len(turn)-1] {
+			hub.root = last
+			next, _ := hub.engine.BestMoveContext(ctx, last, maximizing)
+			turn = append(turn, next.(Board))
+		}
+		hub.send("done move=%s", hubNotation(node, turn))
+	}()
+}
+
+func (hub *Hub) info(info csa.SearchInfo) {
+	score := info.Score
+	if !hub.maximizing {
+		score = -score
+	}
+	var pv []string
+	node, turn := hub.root, []Board(nil)
+	for _, child := range info.PV {
+		board, ok := child.(Board)
+		if !ok {
+			break
+		}
+		if turn = append(turn, board); !board.extraTurn {
+			pv = append(pv, hubNotation(node, turn))
+			node, turn = board, nil
+		}
+	}
+	hub.send("info depth=%d score=%d nodes=%d time=%.3f nps=%d pv=%s", info.Depth, score, info.Nodes,
+		info.Time.Seconds(), info.NPS, `"`+strings.Join(pv, " ")+`"`)
+}
+
+// Stops the running search and waits for its move
+func (hub *Hub) stop() {
+	if hub.cancel != nil {
+		hub.cancel()
+		<-hub.done
+		hub.cancel = nil
+	}
+}
@@ -0,0 +1,325 @@
+package checkers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Numeric notation numbers the dark squares from 1 by rows from the top right corner of the board's String,
+// the standard diagrams are its mirror image with the first square in the top left corner.
+// Black starts on the squares 1-12 of the 8x8 board as in English checkers, 1-20 of the 10x10 one.
+
+// Number of the dark square in the numeric notation, zero for the light squares
+func (node Board) Square(index int) int {
+	row, col := index/node.size, index%node.size
+	if index < 0 || index >= node.squares() || (row+col)%2 != 0 {
+		return 0
+	}
+	return row*node.size/2 + (node.size-1-col)/2 + 1
+}
+
+// Index of the square in the numeric notation, -1 if there is no such square
+func (node Board) Index(square int) int {
+	if square < 1 || square > node.squares()/2 {
+		return -1
+	}
+	row, mirrored := (square-1)/(node.size/2), 2*((square-1)%(node.size/2))+1-(square-1)/(node.size/2)%2
+	return row*node.size + node.size - 1 - mirrored
+}
+
+// Move in the numeric notation, e.g. 11-15 or 15x22
+func (node Board) Notation(move Move) string {
+	separator := "-"
+	if move.Jump {
+		separator = "x"
+	}
+	return fmt.Sprintf("%d%s%d", node.Square(move.From), separator, node.Square(move.To))
+}
+
+// Game of the Portable Draughts Notation
+type Game struct {
+	Tags       map[string]string // e.g. Event, White, Black or GameType
+	Start      Board
+	Maximizing bool    // player to move in the start, black except for the GameType 20 of international draughts
+	Boards     []Board // played ones, the jump chains have a board per jump
+	Result     string  // 2-0, 0-2, 1-1 or * of the unfinished game, PDN's 1-0, 0-1 and 1/2-1/2 are accepted too
+}
+
+// Players' turns of the game, a single jump chain is a turn
+func (game Game) Turns() [][]Board {
+	var turns [][]Board
+	var turn []Board
+	for _, board := range game.Boards {
+		turn = append(turn, board)
+		if !board.extraTurn {
+			turns = append(turns, turn)
+			turn = nil
+		}
+	}
+	if len(turn) > 0 {
+		turns = append(turns, turn)
+	}
+	return turns
+}
+
+// Turn in the numeric notation with all the squares of the jump chain, e.g. 9x18x27
+func turnNotation(turn []Board) string {
+	notation := turn[0].Notation(turn[0].lastMove)
+	for _, board := range turn[1:] {
+		notation += fmt.Sprintf("x%d", board.Square(board.lastMove.To))
+	}
+	return notation
+}
+
+var pdnResults = map[string]string{"2-0": "2-0", "0-2": "0-2", "1-1": "1-1", "1-0": "2-0", "0-1": "0-2", "1/2-1/2": "1-1", "*": "*"}
+
+// Reads the games of the PDN, the ones without the FEN tag start from New(rules)
+// Comments, variations and annotations are skipped. Jump chains may omit the intermediate squares if unambiguous.
+func ParsePDN(r io.Reader, rules Rules) ([]Game, error) {
+	reader := bufio.NewReader(r)
+	var games []Game
+	var game *Game
+	start := func() {
+		if game == nil {
+			game = &Game{Tags: make(map[string]string), Start: New(rules), Maximizing: true}
+		}
+	}
+	for {
+		token, err := pdnToken(reader)
+		if err == io.EOF {
+			if game != nil {
+				games = append(games, *game)
+			}
+			return games, nil
+		}
+		if err != nil {
+			return games, err
+		}
+		switch {
+		case strings.HasPrefix(token, "["):
+			if game != nil && (len(game.Boards) > 0 || game.Result != "") {
+				// tags of the next game without the result of the previous one
+				games = append(games, *game)
+				game = nil
+			}
+			start()
+			name, value, err := parsePDNTag(token)
+			if err != nil {
+				return games, err
+			}
+			game.Tags[name] = value
+			switch name {
+			case "GameType":
+				if game.Tags["FEN"] == "" {
+					game.Maximizing = !strings.HasPrefix(value, "20")
+				}
+			case "FEN":
+				if game.Start, game.Maximizing, err = ParsePosition(value, rules); err != nil {
+					return games, err
+				}
+			}
+		case pdnResults[token] != "":
+			start()
+			game.Result = pdnResults[token]
+			games = append(games, *game)
+			game = nil
+		case strings.TrimRight(token, "0123456789.") == "":
+			// move number
+		default:
+			start()
+			if err := game.play(token); err != nil {
+				return games, fmt.Errorf("checkers: game %d: %w", len(games)+1, err)
+			}
+		}
+	}
+}
+
+// Next tag, move or result, the comments, variations, annotations and NAGs are skipped
+func pdnToken(reader *bufio.Reader) (string, error) {
+	depth := 0
+	var sb strings.Builder
+	for {
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			if sb.Len() > 0 {
+				return sb.String(), nil
+			}
+			return "", err
+		}
+		switch {
+		case r == '{':
+			// comment
+			if _, err := reader.ReadString('}'); err != nil {
+				return "", err
+			}
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth > 0:
+		case r == '[':
+			tag, err := reader.ReadString(']')
+			if err != nil {
+				return "", err
+			}
+			return "[" + tag, nil
+		case unicode.IsSpace(r) || r == '!' || r == '?':
+			if sb.Len() > 0 {
+				return sb.String(), nil
+			}
+		case r == '$':
+			// numeric annotation glyph
+			for r, _, err = reader.ReadRune(); err == nil && unicode.IsDigit(r); r, _, err = reader.ReadRune() {
+			}
+			reader.UnreadRune()
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+func parsePDNTag(token string) (string, string, error) {
+	name, value, ok := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(token, "["), "]"), " ")
+	value, err := strconv.Unquote(strings.TrimSpace(value))
+	if !ok || err != nil {
+		return "", "", fmt.Errorf("checkers: invalid PDN tag %s", token)
+	}
+	return name, value, nil
+}
+
+// Plays the move in the numeric notation
+func (game *Game) play(notation string) error {
+	jump := strings.Contains(notation, "x")
+	var squares []int
+	for _, field := range strings.FieldsFunc(notation, func(r rune) bool { return r == '-' || r == 'x' || r == ':' }) {
+		square, err := strconv.Atoi(field)
+		index := game.Start.Index(square)
+		if err != nil || index < 0 {
+			return fmt.Errorf("invalid move %s", notation)
+		}
+		squares = append(squares, index)
+	}
+	node, maximizing := game.Start, game.Maximizing
+	if len(game.Boards) > 0 {
+		node = game.Boards[len(game.Boards)-1]
+		maximizing = game.maximizing(len(game.Boards))
+	}
+	if len(squares) < 2 {
+		return fmt.Errorf("invalid move %s", notation)
+	}
+	turn := node.resolve(squares[0], squares[1:], jump, maximizing)
+	if turn == nil {
+		return fmt.Errorf("illegal move %s", notation)
+	}
+	for _, board := range turn {
+		game.Start.AddHistory(board)
+	}
+	game.Boards = append(game.Boards, turn...)
+	return nil
+}
+
+// Player to move after the given number of played boards
+func (game Game) maximizing(boards int) bool {
+	maximizing := game.Maximizing
+	for _, board := range game.Boards[:boards] {
+		if !board.extraTurn {
+			maximizing = !maximizing
+		}
+	}
+	return maximizing
+}
+
+// Boards of the turn from the square visiting the squares in order, nil if there is no such turn
+// Repetitions of the history are allowed.
+func (node Board) resolve(from int, squares []int, jump bool, maximizing bool) []Board {
+	history := node.nodeHistory
+	node.nodeHistory = make(nodeHistory)
+	for _, child := range node.Moves(maximizing) {
+		child.nodeHistory = history
+		if child.lastMove.From != from || child.lastMove.Jump != jump {
+			continue
+		}
+		rest := squares
+		if child.lastMove.To == rest[0] {
+			rest = rest[1:]
+		}
+		if !child.extraTurn {
+			if len(rest) == 0 {
+				return []Board{child}
+			}
+			continue
+		}
+		if len(rest) > 0 {
+			if chain := child.resolve(child.lastMove.To, rest, jump, maximizing); chain != nil {
+				return append([]Board{child}, chain...)
+			}
+		}
+	}
+	return nil
+}
+
+// Seven tag roster of the PDN, the other tags are written after it in alphabetical order
+var pdnRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// Writes the game in the PDN, moves in the numeric notation
+// The start is written as the FEN tag unless it's New with black to move.
+func WritePDN(w io.Writer, game Game) error {
+	result := game.Result
+	if result == "" {
+		result = "*"
+	}
+	tags := map[string]string{"Event": "?", "Site": "?", "Date": "????.??.??", "Round": "?", "White": "?", "Black": "?"}
+	var names []string
+	for name, value := range game.Tags {
+		if _, ok := tags[name]; !ok && name != "Result" {
+			names = append(names, name)
+		}
+		tags[name] = value
+	}
+	tags["Result"] = result
+	if game.Start.board != New(game.Start.Rules()).board || !game.Maximizing {
+		if _, ok := tags["FEN"]; !ok {
+			names = append(names, "FEN")
+		}
+		tags["FEN"] = FormatPosition(game.Start, game.Maximizing)
+	}
+	sort.Strings(names)
+	writer := bufio.NewWriter(w)
+	for _, name := range append(append([]string(nil), pdnRoster...), names...) {
+		fmt.Fprintf(writer, "[%s %q]\n", name, tags[name])
+	}
+	writer.WriteByte('\n')
+	line := 0
+	write := func(token string) {
+		if line > 0 && line+1+len(token) > 79 {
+			writer.WriteByte('\n')
+			line = 0
+		} else if line > 0 {
+			writer.WriteByte(' ')
+			line++
+		}
+		writer.WriteString(token)
+		line += len(token)
+	}
+	maximizing, number := game.Maximizing, 1
+	for i, turn := range game.Turns() {
+		if maximizing {
+			write(strconv.Itoa(number) + ".")
+		} else if i == 0 {
+			write(strconv.Itoa(number) + "...")
+		}
+		write(turnNotation(turn))
+		if !maximizing {
+			number++
+		}
+		maximizing = !maximizing
+	}
+	write(result)
+	writer.WriteString("\n\n")
+	return writer.Flush()
+}
@@ -0,0 +1,540 @@
+// Package chess is the game of chess of the csa package on bitboards with the legal move generation
+//
+// White is the maximizing player. Board knows its player to move (csa.PlayerNode), the checkmate and stalemate
+// are terminal as are the fifty-move rule and the insufficient material. Repetitions are detected by the search
+// from RepetitionKey, pass the keys of the played positions as csa.Searcher's History.
+//
+// Squares are indexed 0 (a1) to 63 (h8) by ranks, moves are in the UCI notation, e.g. e2e4 or e7e8q.
+package chess
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+type Color int
+
+const (
+	White Color = iota
+	Black
+)
+
+type Piece int
+
+const (
+	Pawn Piece = iota
+	Knight
+	Bishop
+	Rook
+	Queen
+	King
+)
+
+// Letters of the pieces, the white ones upper case
+const pieceLetters = "pnbrqk"
+
+// Castling rights
+const (
+	whiteKingside = 1 << iota
+	whiteQueenside
+	blackKingside
+	blackQueenside
+)
+
+// Bit per square, a1 is the lowest one
+type bitboard uint64
+
+func (b bitboard) count() int {
+	return bits.OnesCount64(uint64(b))
+}
+
+// Lowest square of the bitboard
+func (b bitboard) first() int {
+	return bits.TrailingZeros64(uint64(b))
+}
+
+// Move in the UCI notation, Promotion is Pawn (zero) unless the pawn promotes
+type Move struct {
+	From, To  int
+	Promotion Piece
+}
+
+func (move Move) String() string {
+	notation := SquareName(move.From) + SquareName(move.To)
+	if move.Promotion != Pawn {
+		notation += string(pieceLetters[move.Promotion])
+	}
+	return notation
+}
+
+// Move of the UCI notation, e.g. e2e4 or e7e8q, it does not have to be legal
+func ParseMove(notation string) (Move, error) {
+	if len(notation) != 4 && len(notation) != 5 {
+		return Move{}, fmt.Errorf("chess: invalid move %q", notation)
+	}
+	move := Move{From: Square(notation[:2]), To: Square(notation[2:4])}
+	if len(notation) == 5 {
+		promotion := strings.IndexByte(pieceLetters, notation[4])
+		if promotion <= int(Pawn) || promotion >= int(King) {
+			return Move{}, fmt.Errorf("chess: invalid promotion of %q", notation)
+		}
+		move.Promotion = Piece(promotion)
+	}
+	if move.From < 0 || move.To < 0 {
+		return Move{}, fmt.Errorf("chess: invalid move %q", notation)
+	}
+	return move, nil
+}
+
+// Square of the name, e.g. e4, -1 if invalid
+func Square(name string) int {
+	if len(name) != 2 || name[0] < 'a' || name[0] > 'h' || name[1] < '1' || name[1] > '8' {
+		return -1
+	}
+	return int(name[1]-'1')*8 + int(name[0]-'a')
+}
+
+// Name of the square, e.g. e4
+func SquareName(square int) string {
+	return fmt.Sprintf("%c%d", 'a'+square%8, square/8+1)
+}
+
+// Board with the player to move and the state of the castling, en passant and fifty-move rule,
+// implements csa.SearchNode. Intentionally passed by value everywhere.
+type Board struct {
+	pieces    [2][6]bitboard // pieces[color][piece]
+	occupied  [2]bitboard    // by color
+	side      Color          // to move
+	castling  int            // rights
+	enPassant int            // square behind the pawn which moved by two squares, -1 if none
+	halfmove  int            // plies since the last capture or pawn move
+	fullmove  int
+	hash      uint64 // Zobrist hash
+	lastMove  Move   // move which created the board, From == To for the initial board
+}
+
+// Initial position
+func New() Board {
+	node, _ := ParseFEN(StartFEN)
+	return node
+}
+
+func (node Board) SideToMove() Color {
+	return node.side
+}
+
+func (node Board) PlayerToMove() csa.Player {
+	if node.side == White {
+		return csa.MaximizingPlayer
+	}
+	return csa.MinimizingPlayer
+}
+
+// Piece on the square, false if empty
+func (node Board) At(square int) (Piece, Color, bool) {
+	bit := bitboard(1) << square
+	for color := White; color <= Black; color++ {
+		if node.occupied[color]&bit == 0 {
+			continue
+		}
+		for piece := Pawn; piece <= King; piece++ {
+			if node.pieces[color][piece]&bit != 0 {
+				return piece, color, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func (node *Board) put(color Color, piece Piece, square int) {
+	node.pieces[color][piece] |= 1 << square
+	node.occupied[color] |= 1 << square
+	node.hash ^= zobristPieces[color][piece][square]
+}
+
+func (node *Board) remove(color Color, piece Piece, square int) {
+	node.pieces[color][piece] &^= 1 << square
+	node.occupied[color] &^= 1 << square
+	node.hash ^= zobristPieces[color][piece][square]
+}
+
+func (node Board) all() bitboard {
+	return node.occupied[White] | node.occupied[Black]
+}
+
+// Whether the square is attacked by the color's pieces
+func (node Board) attacked(square int, by Color) bool {
+	p := &node.pieces[by]
+	occupied := node.all()
+	return pawnAttacks[1-by][square]&p[Pawn] != 0 ||
+		knightAttacks[square]&p[Knight] != 0 ||
+		kingAttacks[square]&p[King] != 0 ||
+		slidingAttacks(square, occupied, bishopDirections)&(p[Bishop]|p[Queen]) != 0 ||
+		slidingAttacks(square, occupied, rookDirections)&(p[Rook]|p[Queen]) != 0
+}
+
+// Whether the king of the player to move is attacked
+func (node Board) InCheck() bool {
+	return node.attacked(node.pieces[node.side][King].first(), 1-node.side)
+}
+
+// Moves of the player to move which may leave its king attacked
+func (node Board) pseudoLegalMoves(moves []Move) []Move {
+	us, them := node.side, 1-node.side
+	own, enemy, occupied := node.occupied[us], node.occupied[them], node.all()
+	add := func(from int, targets bitboard) {
+		for ; targets != 0; targets &= targets - 1 {
+			moves = append(moves, Move{From: from, To: targets.first()})
+		}
+	}
+	forward, startRank, lastRank := 8, 1, 7
+	if us == Black {
+		forward, startRank, lastRank = -8, 6, 0
+	}
+	for pawns := node.pieces[us][Pawn]; pawns != 0; pawns &= pawns - 1 {
+		from := pawns.first()
+		targets := pawnAttacks[us][from] & enemy
+		if node.enPassant >= 0 {
+			targets |= pawnAttacks[us][from] & (1 << node.enPassant)
+		}
+		if to := from + forward; occupied&(1<<to) == 0 {
+			targets |= 1 << to
+			if to2 := to + forward; from/8 == startRank && occupied&(1<<to2) == 0 {
+				targets |= 1 << to2
+			}
+		}
+		for ; targets != 0; targets &= targets - 1 {
+			to := targets.first()
+			if to/8 != lastRank {
+				moves = append(moves, Move{From: from, To: to})
+				continue
+			}
+			for _, promotion := range []Piece{Queen, Knight, Rook, Bishop} {
+				moves = append(moves, Move{From: from, To: to, Promotion: promotion})
+			}
+		}
+	}
+	for knights := node.pieces[us][Knight]; knights != 0; knights &= knights - 1 {
+		from := knights.first()
+		add(from, knightAttacks[from]&^own)
+	}
+	for sliders := node.pieces[us][Bishop] | node.pieces[us][Queen]; sliders != 0; sliders &= sliders - 1 {
+		from := sliders.first()
+		add(from, slidingAttacks(from, occupied, bishopDirections)&^own)
+	}
+	for sliders := node.pieces[us][Rook] | node.pieces[us][Queen]; sliders != 0; sliders &= sliders - 1 {
+		from := sliders.first()
+		add(from, slidingAttacks(from, occupied, rookDirections)&^own)
+	}
+	king := node.pieces[us][King].first()
+	add(king, kingAttacks[king]&^own)
+	// castling, the king may not be in check nor pass an attacked square
+	for _, c := range castlings[us] {
+		if node.castling&c.right != 0 && occupied&c.empty == 0 && !node.attacked(king, them) &&
+			!node.attacked((king+c.kingTo)/2, them) {
+			moves = append(moves, Move{From: king, To: c.kingTo})
+		}
+	}
+	return moves
+}
+
+type castlingMove struct {
+	right            int
+	empty            bitboard // squares between the king and the rook
+	kingTo           int
+	rookFrom, rookTo int
+}
+
+var castlings = [2][]castlingMove{
+	{{whiteKingside, 1<<5 | 1<<6, 6, 7, 5}, {whiteQueenside, 1<<1 | 1<<2 | 1<<3, 2, 0, 3}},
+	{{blackKingside, 1<<61 | 1<<62, 62, 63, 61}, {blackQueenside, 1<<57 | 1<<58 | 1<<59, 58, 56, 59}},
+}
+
+// Castling rights kept after a move from or to the square
+var castlingMasks = func() [64]int {
+	var masks [64]int
+	for square := range masks {
+		masks[square] = whiteKingside | whiteQueenside | blackKingside | blackQueenside
+	}
+	masks[4] &^= whiteKingside | whiteQueenside
+	masks[7] &^= whiteKingside
+	masks[0] &^= whiteQueenside
+	masks[60] &^= blackKingside | blackQueenside
+	masks[63] &^= blackKingside
+	masks[56] &^= blackQueenside
+	return masks
+}()
+
+// Board after the pseudo-legal move
+func (node Board) play(move Move) Board {
+	us, them := node.side, 1-node.side
+	piece, _, _ := node.At(move.From)
+	node.hash ^= zobristCastling[node.castling] ^ node.enPassantHash()
+	node.halfmove++
+	if captured, color, ok := node.At(move.To); ok && color == them {
+		node.remove(them, captured, move.To)
+		node.halfmove = 0
+	}
+	node.remove(us, piece, move.From)
+	node.put(us, piece, move.To)
+	enPassant := -1
+	switch {
+	case piece == Pawn:
+		node.halfmove = 0
+		if move.To == node.enPassant {
+			node.remove(them, Pawn, move.To+(move.From/8-move.To/8)*8)
+		}
+		if move.To-move.From == 16 || move.From-move.To == 16 {
+			enPassant = (move.From + move.To) / 2
+		}
+		if move.Promotion != Pawn {
+			node.remove(us, Pawn, move.To)
+			node.put(us, move.Promotion, move.To)
+		}
+	case piece == King && (move.To-move.From == 2 || move.From-move.To == 2):
+		for _, c := range castlings[us] {
+			if c.kingTo == move.To {
+				node.remove(us, Rook, c.rookFrom)
+				node.put(us, Rook, c.rookTo)
+			}
+		}
+	}
+	node.enPassant = enPassant
+	node.castling &= castlingMasks[move.From] & castlingMasks[move.To]
+	if us == Black {
+		node.fullmove++
+	}
+	node.side = them
+	node.hash ^= zobristSide ^ zobristCastling[node.castling] ^ node.enPassantHash()
+	node.lastMove = move
+	return node
+}
+
+// En passant is hashed only if a pawn of the player to move can capture, so the same positions share the hash
+func (node Board) enPassantHash() uint64 {
+	if node.enPassant < 0 || pawnAttacks[1-node.side][node.enPassant]&node.pieces[node.side][Pawn] == 0 {
+		return 0
+	}
+	return zobristEnPassant[node.enPassant%8]
+}
+
+// Whether the player which just moved did not leave its king attacked
+func (node Board) legal() bool {
+	return !node.attacked(node.pieces[1-node.side][King].first(), node.side)
+}
+
+// Legal moves of the player to move
+func (node Board) Moves() []Move {
+	var moves []Move
+	for _, move := range node.pseudoLegalMoves(make([]Move, 0, 64)) {
+		if node.play(move).legal() {
+			moves = append(moves, move)
+		}
+	}
+	return moves
+}
+
+func (node Board) hasLegalMove() bool {
+	for _, move := range node.pseudoLegalMoves(make([]Move, 0, 64)) {
+		if node.play(move).legal() {
+			return true
+		}
+	}
+	return false
+}
+
+// Board after the legal move, false if the move is illegal
+func (node Board) Play(move Move) (Board, bool) {
+	for _, legal := range node.Moves() {
+		if legal == move {
+			return node.play(move), true
+		}
+	}
+	return Board{}, false
+}
+
+// Whether neither player can checkmate, e.g. kings with a single minor piece
+func (node Board) insufficientMaterial() bool {
+	for color := White; color <= Black; color++ {
+		p := &node.pieces[color]
+		if p[Pawn]|p[Rook]|p[Queen] != 0 {
+			return false
+		}
+	}
+	minors := 0
+	for color := White; color <= Black; color++ {
+		minors += (node.pieces[color][Knight] | node.pieces[color][Bishop]).count()
+	}
+	return minors <= 1
+}
+
+func (node Board) IsTerminal() bool {
+	return node.halfmove >= 100 || node.insufficientMaterial() || !node.hasLegalMove()
+}
+
+// Stalemate, fifty-move rule or insufficient material, the repetitions are detected by the search
+func (node Board) IsDraw() bool {
+	return node.halfmove >= 100 || node.insufficientMaterial() || (!node.hasLegalMove() && !node.InCheck())
+}
+
+func (node Board) PieceCount() int {
+	return node.all().count()
+}
+
+// Children ordered by the promotions and captures, the most valuable victim first
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	var children []Board
+	started := false
+	return func(maximizing bool) csa.SearchNode {
+		if !started {
+			started = true
+			children = node.children()
+		}
+		if len(children) == 0 {
+			return nil
+		}
+		child := children[0]
+		children = children[1:]
+		return child
+	}
+}
+
+func (node Board) children() []Board {
+	type ordered struct {
+		board Board
+		order int
+	}
+	var children []ordered
+	for _, move := range node.pseudoLegalMoves(make([]Move, 0, 64)) {
+		child := node.play(move)
+		if !child.legal() {
+			continue
+		}
+		order := pieceValues[move.Promotion] - pieceValues[Pawn]
+		if victim, _, ok := node.At(move.To); ok {
+			attacker, _, _ := node.At(move.From)
+			order += 10*pieceValues[victim] - pieceValues[attacker]
+		}
+		children = append(children, ordered{child, order})
+	}
+	sort.SliceStable(children, func(i, j int) bool { return children[i].order > children[j].order })
+	boards := make([]Board, len(children))
+	for i, child := range children {
+		boards[i] = child.board
+	}
+	return boards
+}
+
+func (node Board) Hash() uint64 {
+	return node.hash
+}
+
+func (node Board) RepetitionKey() uint64 {
+	return node.hash
+}
+
+// Move which created this board, chess.Move, nil for the initial board
+func (node Board) Move() csa.Move {
+	if node.lastMove.From == node.lastMove.To {
+		return nil
+	}
+	return node.lastMove
+}
+
+func (node Board) String() string {
+	sb := strings.Builder{}
+	for rank := 7; rank >= 0; rank-- {
+		fmt.Fprintf(&sb, "%d", rank+1)
+		for file := 0; file < 8; file++ {
+			piece, color, ok := node.At(rank*8 + file)
+			switch {
+			case !ok:
+				sb.WriteString(" _")
+			case color == White:
+				sb.WriteString(" " + strings.ToUpper(string(pieceLetters[piece])))
+			default:
+				sb.WriteString(" " + string(pieceLetters[piece]))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("  a b c d e f g h\n")
+	return sb.String()
+}
+
+var (
+	bishopDirections = [][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+	rookDirections   = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+)
+
+// Squares attacked by the slider from the square, the blockers are included
+func slidingAttacks(square int, occupied bitboard, directions [][2]int) bitboard {
+	var attacks bitboard
+	for _, dir := range directions {
+		file, rank := square%8+dir[0], square/8+dir[1]
+		for ; file >= 0 && file < 8 && rank >= 0 && rank < 8; file, rank = file+dir[0], rank+dir[1] {
+			bit := bitboard(1) << (rank*8 + file)
+			attacks |= bit
+			if occupied&bit != 0 {
+				break
+			}
+		}
+	}
+	return attacks
+}
+
+// Squares reachable by the single steps from the square
+func stepAttacks(square int, steps [][2]int) bitboard {
+	var attacks bitboard
+	for _, step := range steps {
+		if file, rank := square%8+step[0], square/8+step[1]; file >= 0 && file < 8 && rank >= 0 && rank < 8 {
+			attacks |= 1 << (rank*8 + file)
+		}
+	}
+	return attacks
+}
+
+var (
+	knightAttacks, kingAttacks [64]bitboard
+	pawnAttacks                [2][64]bitboard // by the color's pawn on the square
+
+	zobristPieces    [2][6][64]uint64
+	zobristCastling  [16]uint64
+	zobristEnPassant [8]uint64
+	zobristSide      uint64
+)
+
+func init() {
+	for square := 0; square < 64; square++ {
+		knightAttacks[square] = stepAttacks(square, [][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}})
+		kingAttacks[square] = stepAttacks(square, [][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}})
+		pawnAttacks[White][square] = stepAttacks(square, [][2]int{{-1, 1}, {1, 1}})
+		pawnAttacks[Black][square] = stepAttacks(square, [][2]int{{-1, -1}, {1, -1}})
+	}
+	// fixed seed, the hashes are the same in every run
+	state := uint64(0x9e3779b97f4a7c15)
+	random := func() uint64 {
+		state ^= state << 13
+		state ^= state >> 7
+		state ^= state << 17
+		return state
+	}
+	for color := range zobristPieces {
+		for piece := range zobristPieces[color] {
+			for square := range zobristPieces[color][piece] {
+				zobristPieces[color][piece][square] = random()
+			}
+		}
+	}
+	for i := range zobristCastling {
+		zobristCastling[i] = random()
+	}
+	for i := range zobristEnPassant {
+		zobristEnPassant[i] = random()
+	}
+	zobristSide = random()
+}
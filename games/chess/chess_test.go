@@ -0,0 +1,157 @@
+package chess
+
+import (
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Board after the moves in the UCI notation
+func play(t *testing.T, fen string, moves ...string) Board {
+	node, err := ParseFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, notation := range moves {
+		move, err := ParseMove(notation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		child, ok := node.Play(move)
+		if !ok {
+			t.Fatalf("Illegal move %s\n%s", notation, node)
+		}
+		node = child
+	}
+	return node
+}
+
+func TestFEN(t *testing.T) {
+	for _, fen := range []string{
+		StartFEN,
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2",
+	} {
+		node, err := ParseFEN(fen)
+		if err != nil || node.FEN() != fen {
+			t.Errorf("Expected %s, got %s %v", fen, node.FEN(), err)
+		}
+	}
+	for _, fen := range []string{"", "8/8/8/8/8/8/8/8 w - -", "rnbqkbnr/ppppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -",
+		"4k3/8/8/8/8/8/8/4K2R x - -", "4k3/4R3/8/8/8/8/8/4K3 w - -"} {
+		if _, err := ParseFEN(fen); err == nil {
+			t.Errorf("Expected invalid FEN %q", fen)
+		}
+	}
+	expected := "8 r n b q k b n r\n7 p p p p p p p p\n" + "6 _ _ _ _ _ _ _ _\n5 _ _ _ _ _ _ _ _\n" +
+		"4 _ _ _ _ _ _ _ _\n3 _ _ _ _ _ _ _ _\n" + "2 P P P P P P P P\n1 R N B Q K B N R\n  a b c d e f g h\n"
+	if New().String() != expected {
+		t.Errorf("Invalid board\n%s", New())
+	}
+}
+
+func TestPerft(t *testing.T) {
+	for fen, counts := range map[string][]uint64{
+		StartFEN: {20, 400, 8902},
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1": {48, 2039, 97862},
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1":                            {14, 191, 2812, 43238},
+		"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1":     {6, 264, 9467},
+		"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8":            {44, 1486, 62379},
+	} {
+		node, _ := ParseFEN(fen)
+		for depth, expected := range counts {
+			if count := csa.Perft(node, depth+1, true); count != expected {
+				t.Errorf("Perft %d of %s is %d, expected %d", depth+1, fen, count, expected)
+			}
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	// transposition with the en passant square which cannot be captured
+	a := play(t, StartFEN, "e2e4", "g8f6", "g1f3")
+	b := play(t, StartFEN, "g1f3", "g8f6", "e2e4")
+	if a.Hash() != b.Hash() || a.RepetitionKey() != b.RepetitionKey() {
+		t.Error("Transpositions differ")
+	}
+	fresh, _ := ParseFEN(a.FEN())
+	if fresh.Hash() != a.Hash() {
+		t.Error("Incremental hash differs from the parsed one")
+	}
+	// the en passant which can be captured
+	c := play(t, StartFEN, "e2e4", "a7a6", "e4e5", "d7d5")
+	d := play(t, StartFEN, "e2e4", "d7d5", "e4e5", "a7a6")
+	if c.Hash() == d.Hash() {
+		t.Error("En passant capture makes the positions different")
+	}
+	if child := play(t, c.FEN(), "e5d6"); child.PieceCount() != 31 || child.FEN() != "rnbqkbnr/1pp1pppp/p2P4/8/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3" {
+		t.Errorf("Invalid en passant capture %s", child.FEN())
+	}
+}
+
+func TestCastling(t *testing.T) {
+	node := play(t, "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1g1")
+	if node.FEN() != "r3k2r/8/8/8/8/8/8/R4RK1 b kq - 1 1" {
+		t.Errorf("Invalid castling %s", node.FEN())
+	}
+	// through the attacked square
+	node, _ = ParseFEN("r3k2r/8/8/8/8/8/8/R3K1rR w KQkq - 0 1")
+	if _, ok := node.Play(Move{From: Square("e1"), To: Square("c1")}); ok {
+		t.Error("King cannot castle out of check")
+	}
+	node, _ = ParseFEN("r3k2r/8/8/8/8/8/5r2/R3K2R w KQkq - 0 1")
+	if _, ok := node.Play(Move{From: Square("e1"), To: Square("g1")}); ok {
+		t.Error("King cannot castle through the attacked square")
+	}
+	// capture of the rook removes the right
+	node = play(t, "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "h1h8")
+	if node.FEN() != "r3k2R/8/8/8/8/8/8/R3K3 b Qq - 0 1" {
+		t.Errorf("Invalid rights %s", node.FEN())
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	// fool's mate
+	node := play(t, StartFEN, "f2f3", "e7e5", "g2g4", "d8h4")
+	if !node.IsTerminal() || node.IsDraw() || node.Score() != -WinScore || !node.InCheck() {
+		t.Errorf("Expected checkmate\n%s", node)
+	}
+	for _, fen := range []string{
+		"7k/5Q2/6K1/8/8/8/8/8 b - - 0 1", // stalemate
+		"8/8/4k3/8/8/3BK3/8/8 w - - 0 1", // insufficient material
+		"4k3/8/8/8/8/8/4P3/4K3 w - - 100 80",
+	} {
+		if node, _ := ParseFEN(fen); !node.IsTerminal() || !node.IsDraw() || node.Score() != 0 {
+			t.Errorf("Expected draw of %s", fen)
+		}
+	}
+	if node := play(t, StartFEN, "e2e4"); node.IsTerminal() || node.Score() <= 0 || node.Move() != (Move{From: 12, To: 28}) {
+		t.Error("Central pawn is better")
+	}
+	if _, err := ParseMove("e7e8k"); err == nil {
+		t.Error("Expected invalid promotion")
+	}
+	if move, _ := ParseMove("e7e8q"); move.String() != "e7e8q" || move.Promotion != Queen {
+		t.Error("Invalid promotion")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	searcher := csa.Searcher{WinScore: WinScore}
+	// back rank mate
+	node, _ := ParseFEN("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	child, score := searcher.MinimaxAlphaBetaPrunning(node, 3, true)
+	if child.(Board).Move() != (Move{From: Square("a1"), To: Square("a8")}) || score != WinScore-1 {
+		t.Errorf("Expected mate in one, got %v %d", child.(Board).Move(), score)
+	}
+	// the hanging queen is taken
+	node, _ = ParseFEN("4k3/8/8/3q4/8/8/3R4/4K3 w - - 0 1")
+	if child, _ := searcher.MinimaxAlphaBetaPrunning(node, 2, true); child.(Board).Move().(Move).String() != "d2d5" {
+		t.Errorf("Expected capture of the queen, got %v", child.(Board).Move())
+	}
+	// knights back in the initial position
+	if node := play(t, StartFEN, "g1f3", "g8f6", "f3g1", "f6g8"); node.RepetitionKey() != New().RepetitionKey() {
+		t.Error("Expected repetition of the initial position")
+	}
+}
@@ -0,0 +1,132 @@
+package chess
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FEN of the initial position
+const StartFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+// Board of the Forsyth-Edwards Notation, the move counters may be omitted
+func ParseFEN(fen string) (Board, error) {
+	fields := strings.Fields(fen)
+	if len(fields) < 4 {
+		return Board{}, fmt.Errorf("chess: invalid FEN %q", fen)
+	}
+	node := Board{enPassant: -1, fullmove: 1}
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return Board{}, fmt.Errorf("chess: invalid FEN %q", fen)
+	}
+	for i, rank := range ranks {
+		file := 0
+		for _, r := range rank {
+			if r >= '1' && r <= '8' {
+				file += int(r - '0')
+				continue
+			}
+			piece := strings.IndexRune(pieceLetters, r|0x20)
+			if piece < 0 || file >= 8 {
+				return Board{}, fmt.Errorf("chess: invalid FEN %q", fen)
+			}
+			color := Black
+			if r < 'a' {
+				color = White
+			}
+			node.put(color, Piece(piece), (7-i)*8+file)
+			file++
+		}
+		if file != 8 {
+			return Board{}, fmt.Errorf("chess: invalid rank %q of FEN %q", rank, fen)
+		}
+	}
+	if node.pieces[White][King].count() != 1 || node.pieces[Black][King].count() != 1 {
+		return Board{}, fmt.Errorf("chess: FEN %q needs a king of each color", fen)
+	}
+	switch fields[1] {
+	case "w":
+	case "b":
+		node.side = Black
+		node.hash ^= zobristSide
+	default:
+		return Board{}, fmt.Errorf("chess: invalid player to move of FEN %q", fen)
+	}
+	if fields[2] != "-" {
+		for _, r := range fields[2] {
+			right := strings.IndexRune("KQkq", r)
+			if right < 0 {
+				return Board{}, fmt.Errorf("chess: invalid castling of FEN %q", fen)
+			}
+			node.castling |= 1 << right
+		}
+	}
+	node.hash ^= zobristCastling[node.castling]
+	if fields[3] != "-" {
+		if node.enPassant = Square(fields[3]); node.enPassant < 0 {
+			return Board{}, fmt.Errorf("chess: invalid en passant of FEN %q", fen)
+		}
+		node.hash ^= node.enPassantHash()
+	}
+	var err error
+	if len(fields) > 4 {
+		node.halfmove, err = strconv.Atoi(fields[4])
+	}
+	if len(fields) > 5 && err == nil {
+		node.fullmove, err = strconv.Atoi(fields[5])
+	}
+	if err != nil {
+		return Board{}, fmt.Errorf("chess: invalid move counters of FEN %q", fen)
+	}
+	if !node.legal() {
+		return Board{}, fmt.Errorf("chess: king of the player not to move is in check in FEN %q", fen)
+	}
+	return node, nil
+}
+
+// Position in the Forsyth-Edwards Notation
+func (node Board) FEN() string {
+	sb := strings.Builder{}
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			piece, color, ok := node.At(rank*8 + file)
+			if !ok {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			letter := pieceLetters[piece]
+			if color == White {
+				letter -= 0x20
+			}
+			sb.WriteByte(letter)
+		}
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+		if rank > 0 {
+			sb.WriteByte('/')
+		}
+	}
+	sb.WriteString([]string{" w ", " b "}[node.side])
+	castling := ""
+	for i, letter := range "KQkq" {
+		if node.castling&(1<<i) != 0 {
+			castling += string(letter)
+		}
+	}
+	if castling == "" {
+		castling = "-"
+	}
+	enPassant := "-"
+	if node.enPassant >= 0 {
+		enPassant = SquareName(node.enPassant)
+	}
+	fmt.Fprintf(&sb, "%s %s %d %d", castling, enPassant, node.halfmove, node.fullmove)
+	return sb.String()
+}
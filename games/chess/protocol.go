@@ -0,0 +1,51 @@
+package chess
+
+import (
+	"fmt"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Chess for the protocol adapters, e.g. xboard.Engine, the positions are FENs and the moves are in the UCI notation
+type Protocol struct{}
+
+func (Protocol) Start() (csa.SearchNode, bool) {
+	return New(), true
+}
+
+func (Protocol) Position(fen string) (csa.SearchNode, bool, error) {
+	node, err := ParseFEN(fen)
+	if err != nil {
+		return nil, false, err
+	}
+	return node, node.side == White, nil
+}
+
+func (Protocol) Play(node csa.SearchNode, maximizing bool, notation string) (csa.SearchNode, error) {
+	move, err := ParseMove(notation)
+	if err != nil {
+		return nil, err
+	}
+	child, ok := node.(Board).Play(move)
+	if !ok {
+		return nil, fmt.Errorf("chess: illegal move %s", notation)
+	}
+	return child, nil
+}
+
+func (Protocol) Notation(node, child csa.SearchNode) string {
+	return child.(Board).lastMove.String()
+}
+
+func (Protocol) Result(node csa.SearchNode, maximizing bool) string {
+	board := node.(Board)
+	switch {
+	case !board.IsDraw():
+		return [2]string{"0-1 {Black mates}", "1-0 {White mates}"}[board.side]
+	case board.halfmove >= 100:
+		return "1/2-1/2 {Draw by fifty-move rule}"
+	case board.insufficientMaterial():
+		return "1/2-1/2 {Insufficient material}"
+	}
+	return "1/2-1/2 {Stalemate}"
+}
@@ -0,0 +1,301 @@
+// Package connect4 is the Connect Four game of the csa package on bitboards, 7 columns and 6 rows
+//
+// Red is the maximizing player and drops the first disc. The player who connects four discs of its color
+// in a row, a column or a diagonal wins, the full board without such a line is a draw.
+//
+// Board implements csa.SymmetryNode, the mirrored boards share the canonical hash so the search
+// generates only one of the symmetric children, e.g. 4 out of the 7 first moves.
+package connect4
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+const (
+	Width  = 7
+	Height = 6
+
+	// Score of the won board is WinScore plus the number of empty squares, so the faster wins are preferred,
+	// the heuristic scores of the other boards stay below it. Use it as csa.Searcher's WinScore.
+	WinScore = 10000
+
+	// bits per column, the one above the top row stays empty
+	columnBits = Height + 1
+
+	// color indices
+	red    = 0
+	yellow = 1
+
+	// runes
+	redDisc    = 'X'
+	yellowDisc = 'O'
+)
+
+type Color int
+
+const (
+	Red    Color = red
+	Yellow Color = yellow
+)
+
+// Bit per square, column by column from the left, bottom to top within the column
+type bitboard uint64
+
+var (
+	bottomRow = func() bitboard {
+		var b bitboard
+		for column := 0; column < Width; column++ {
+			b |= 1 << (column * columnBits)
+		}
+		return b
+	}()
+	fullBoard = bottomRow * (1<<Height - 1)
+
+	// columns in the order of generation, the central ones are usually better
+	columnOrder = [Width]int{3, 2, 4, 1, 5, 0, 6}
+
+	// all the lines of four squares, scored by the evaluation
+	windows = func() []bitboard {
+		var windows []bitboard
+		for column := 0; column < Width; column++ {
+			for row := 0; row < Height; row++ {
+				for _, dir := range [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}} {
+					var w bitboard
+					for i := 0; i < 4; i++ {
+						c, r := column+i*dir[0], row+i*dir[1]
+						if c >= Width || r < 0 || r >= Height {
+							w = 0
+							break
+						}
+						w |= squareBit(c, r)
+					}
+					if w != 0 {
+						windows = append(windows, w)
+					}
+				}
+			}
+		}
+		return windows
+	}()
+)
+
+// Scores of a window with 1, 2 or 3 discs of a single color and the rest empty
+var windowScores = [4]int{0, 1, 10, 50}
+
+// Bonus per disc in the central column
+const centerScore = 3
+
+func squareBit(column, row int) bitboard {
+	return 1 << (column*columnBits + row)
+}
+
+// Board with the discs of both players, implements csa.SearchNode
+// Intentionally passed by value everywhere
+type Board struct {
+	discs    [2]bitboard // discs[color]
+	lastMove int         // column of the last dropped disc, -1 for the empty board
+}
+
+// Empty board, red to move
+func New() Board {
+	return Board{lastMove: -1}
+}
+
+// Board after the moves, e.g. "4453" with the columns numbered from 1
+func Parse(moves string) (Board, error) {
+	node := New()
+	for i, r := range moves {
+		column := int(r - '1')
+		if column < 0 || column >= Width || !node.CanPlay(column) || node.IsTerminal() {
+			return Board{}, fmt.Errorf("connect4: invalid move %q at %d", r, i+1)
+		}
+		node = node.Play(column)
+	}
+	return node, nil
+}
+
+func (node Board) occupied() bitboard {
+	return node.discs[red] | node.discs[yellow]
+}
+
+// Number of the dropped discs
+func (node Board) Discs() int {
+	return bits.OnesCount64(uint64(node.occupied()))
+}
+
+// Player to move, red after an even number of discs
+func (node Board) ToMove() Color {
+	return Color(node.Discs() % 2)
+}
+
+func (node Board) PlayerToMove() csa.Player {
+	if node.ToMove() == Red {
+		return csa.MaximizingPlayer
+	}
+	return csa.MinimizingPlayer
+}
+
+// Color of the disc in the column and row counted from the bottom, false if the square is empty
+func (node Board) At(column, row int) (Color, bool) {
+	bit := squareBit(column, row)
+	switch {
+	case node.discs[red]&bit != 0:
+		return Red, true
+	case node.discs[yellow]&bit != 0:
+		return Yellow, true
+	}
+	return 0, false
+}
+
+// Whether the column (from 0) has an empty square
+func (node Board) CanPlay(column int) bool {
+	return node.occupied()&squareBit(column, Height-1) == 0
+}
+
+// Board after the player to move drops a disc to the column (from 0), the column has to have an empty square
+func (node Board) Play(column int) Board {
+	return node.drop(column, int(node.ToMove()))
+}
+
+func (node Board) drop(column, color int) Board {
+	top := node.occupied() + bottomRow
+	node.discs[color] |= top & columnMask(column)
+	node.lastMove = column
+	return node
+}
+
+func columnMask(column int) bitboard {
+	return (1<<Height - 1) << (column * columnBits)
+}
+
+// Whether the discs connect four
+func isWin(discs bitboard) bool {
+	// vertical, horizontal and both diagonals
+	for _, shift := range []int{1, columnBits, columnBits - 1, columnBits + 1} {
+		pairs := discs & (discs >> shift)
+		if pairs&(pairs>>(2*shift)) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Winner of the board, false if nobody connected four
+func (node Board) Winner() (Color, bool) {
+	if isWin(node.discs[red]) {
+		return Red, true
+	}
+	if isWin(node.discs[yellow]) {
+		return Yellow, true
+	}
+	return 0, false
+}
+
+func (node Board) Score() int {
+	empty := Width*Height - node.Discs()
+	if winner, ok := node.Winner(); ok {
+		if winner == Red {
+			return WinScore + empty
+		}
+		return -WinScore - empty
+	}
+	score := 0
+	for _, w := range windows {
+		reds, yellows := bits.OnesCount64(uint64(node.discs[red]&w)), bits.OnesCount64(uint64(node.discs[yellow]&w))
+		if yellows == 0 {
+			score += windowScores[reds]
+		} else if reds == 0 {
+			score -= windowScores[yellows]
+		}
+	}
+	center := columnMask(Width / 2)
+	score += centerScore * (bits.OnesCount64(uint64(node.discs[red]&center)) - bits.OnesCount64(uint64(node.discs[yellow]&center)))
+	return score
+}
+
+func (node Board) IsTerminal() bool {
+	_, won := node.Winner()
+	return won || node.occupied() == fullBoard
+}
+
+func (node Board) IsDraw() bool {
+	_, won := node.Winner()
+	return !won && node.occupied() == fullBoard
+}
+
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	index := 0
+	return func(maximizing bool) csa.SearchNode {
+		color := yellow
+		if maximizing {
+			color = red
+		}
+		for ; index < Width; index++ {
+			if column := columnOrder[index]; node.CanPlay(column) {
+				index++
+				return node.drop(column, color)
+			}
+		}
+		return nil
+	}
+}
+
+// Unique key of the position, the heights of the columns are encoded by the bit above the top disc
+func (node Board) Hash() uint64 {
+	return uint64(node.occupied() + bottomRow + node.discs[red])
+}
+
+// Smaller Hash of the board and its mirror image
+func (node Board) CanonicalHash() uint64 {
+	return min(node.Hash(), node.Mirror().Hash())
+}
+
+// Board mirrored by the central column
+func (node Board) Mirror() Board {
+	mirror := Board{lastMove: -1}
+	if node.lastMove >= 0 {
+		mirror.lastMove = Width - 1 - node.lastMove
+	}
+	for color, discs := range node.discs {
+		for column := 0; column < Width; column++ {
+			stack := discs & columnMask(column) >> (column * columnBits)
+			mirror.discs[color] |= stack << ((Width - 1 - column) * columnBits)
+		}
+	}
+	return mirror
+}
+
+// Column (from 1) of the last dropped disc, nil for the empty board
+func (node Board) Move() csa.Move {
+	if node.lastMove < 0 {
+		return nil
+	}
+	return node.lastMove + 1
+}
+
+func (node Board) String() string {
+	sb := strings.Builder{}
+	for row := Height - 1; row >= 0; row-- {
+		for column := 0; column < Width; column++ {
+			if column > 0 {
+				sb.WriteString(" ")
+			}
+			color, ok := node.At(column, row)
+			switch {
+			case !ok:
+				sb.WriteString("_")
+			case color == Red:
+				sb.WriteRune(redDisc)
+			default:
+				sb.WriteRune(yellowDisc)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("1 2 3 4 5 6 7\n")
+	return sb.String()
+}
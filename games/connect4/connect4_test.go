@@ -0,0 +1,126 @@
+package connect4
+
+import (
+	"strings"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+func TestString(t *testing.T) {
+	node, err := Parse("4453")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Repeat("_ _ _ _ _ _ _\n", 4) + "_ _ _ O _ _ _\n_ _ O X X _ _\n1 2 3 4 5 6 7\n"
+	if node.String() != expected {
+		t.Errorf("Invalid board\n%s", node)
+	}
+	if node.ToMove() != Red || node.Discs() != 4 || node.Move() != 3 {
+		t.Error("Invalid player to move or last move")
+	}
+	if color, ok := node.At(3, 1); !ok || color != Yellow {
+		t.Error("Expected yellow disc")
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, moves := range []string{"0", "8", "a", "4444444", "12121212"} {
+		if _, err := Parse(moves); err == nil {
+			t.Errorf("Expected invalid moves %s", moves)
+		}
+	}
+}
+
+func TestWinner(t *testing.T) {
+	for moves, winner := range map[string]Color{
+		"1212121":     Red,    // vertical
+		"1122334":     Red,    // horizontal
+		"71122334":    Yellow, // horizontal
+		"12233434464": Red,    // diagonal
+		"76655454424": Red,    // anti-diagonal
+	} {
+		node, err := Parse(moves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if color, ok := node.Winner(); !ok || color != winner || !node.IsTerminal() || node.IsDraw() {
+			t.Errorf("Expected winner %d of %s\n%s", winner, moves, node)
+		}
+		if score := node.Score(); (score > WinScore) != (winner == Red) || (score < -WinScore) != (winner == Yellow) {
+			t.Errorf("Invalid score %d of %s", score, moves)
+		}
+	}
+	// lines do not continue over the edges
+	node, _ := Parse("1713172")
+	if _, ok := node.Winner(); ok {
+		t.Errorf("Unexpected winner\n%s", node)
+	}
+}
+
+func TestDraw(t *testing.T) {
+	node, _ := Parse("121212" + "343434" + "565656" + "212121" + "434343" + "656565" + "777777")
+	if !node.IsTerminal() || !node.IsDraw() || node.Discs() != Width*Height {
+		t.Errorf("Expected draw\n%s", node)
+	}
+	if node, _ := Parse("1212121"); node.IsDraw() {
+		t.Error("Win is not a draw")
+	}
+}
+
+func TestHash(t *testing.T) {
+	seen := make(map[uint64]string)
+	var visit func(node Board, moves string, depth int)
+	visit = func(node Board, moves string, depth int) {
+		if other, ok := seen[node.Hash()]; ok {
+			if a, _ := Parse(other); a.discs != node.discs {
+				t.Fatalf("Hash collision of %s and %s", other, moves)
+			}
+		}
+		seen[node.Hash()] = moves
+		if depth == 0 || node.IsTerminal() {
+			return
+		}
+		for column := 0; column < Width; column++ {
+			if node.CanPlay(column) {
+				visit(node.Play(column), moves+string(rune('1'+column)), depth-1)
+			}
+		}
+	}
+	visit(New(), "", 5)
+	node, _ := Parse("1123")
+	mirror, _ := Parse("7765")
+	if node.Mirror().discs != mirror.discs || node.CanonicalHash() != mirror.CanonicalHash() || node.Hash() == mirror.Hash() {
+		t.Error("Invalid mirror")
+	}
+}
+
+func TestSymmetry(t *testing.T) {
+	children := 0
+	generator := New().SearchNodeGenerator()
+	seen := make(map[uint64]bool)
+	for child := generator(true); child != nil; child = generator(true) {
+		seen[child.(Board).CanonicalHash()] = true
+		children++
+	}
+	if children != Width || len(seen) != 4 {
+		t.Errorf("Expected 4 of 7 first moves, got %d of %d", len(seen), children)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	searcher := csa.Searcher{WinScore: WinScore}
+	node, _ := Parse("11223")
+	if child, score := searcher.MinimaxAlphaBetaPrunning(node, 4, false); child.(Board).Move() != 4 || score <= -WinScore {
+		t.Errorf("Yellow has to block, got %v %d", child.(Board).Move(), score)
+	}
+	// red wins at once
+	node, _ = Parse("112237")
+	if child, score := searcher.MinimaxAlphaBetaPrunning(node, 4, true); score < WinScore {
+		t.Errorf("Expected red win, got %v %d", child.(Board).Move(), score)
+	}
+	// the evaluation prefers the center
+	if child, _ := searcher.MinimaxAlphaBetaPrunning(New(), 2, true); child.(Board).Move() != 4 {
+		t.Errorf("Expected central column, got %v", child.(Board).Move())
+	}
+}
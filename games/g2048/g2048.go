@@ -0,0 +1,404 @@
+// Package g2048 is the 2048 sliding tile puzzle of the csa package, searched by expectimax
+//
+// The single player slides all the tiles of the 4x4 board in one direction, two equal tiles hitting each other merge
+// into their sum. Then a tile is spawned on a random empty square, 2 with probability 0.9 and 4 otherwise,
+// so the board after the slide is a csa.ChanceNode with the outcome per empty square and tile. The game ends
+// when no slide changes the board. The player is always the maximizing one.
+//
+// Squares are indexed 0 to 15 by rows from the top left corner.
+package g2048
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+const (
+	Size = 4
+
+	// probability of spawning 2, 4 is spawned otherwise
+	twoProbability = 0.9
+
+	// exponent of the largest tile, 32768, which does not merge any more
+	maxExponent = 15
+)
+
+type Direction int
+
+const (
+	Up Direction = iota
+	Down
+	Left
+	Right
+)
+
+var directionNames = [4]string{"up", "down", "left", "right"}
+
+func (direction Direction) String() string {
+	if direction >= 0 && int(direction) < len(directionNames) {
+		return directionNames[direction]
+	}
+	return "unknown"
+}
+
+// Heuristic weights of a row or column, the common ones of the 2048 expectimax players
+const (
+	lostPenalty        = 200000
+	emptyWeight        = 270
+	mergesWeight       = 700
+	monotonicityPower  = 4
+	monotonicityWeight = 47
+	sumPower           = 3.5
+	sumWeight          = 11
+	rows               = 1 << 16
+	rowMask            = rows - 1
+)
+
+var (
+	// row after the slide to the left, the points of its merges and its heuristic score, indexed by the row
+	leftRows   [rows]uint16
+	leftPoints [rows]int
+	rowScores  [rows]int
+
+	// bounds of the scores, four rows and four columns are scored
+	MinScore, MaxScore int
+)
+
+func init() {
+	minRow, maxRow := math.MaxInt, math.MinInt
+	for row := 0; row < rows; row++ {
+		var line [Size]int
+		for i := range line {
+			line[i] = row >> (4 * i) & 0xf
+		}
+		rowScores[row] = lineScore(line)
+		minRow, maxRow = min(minRow, rowScores[row]), max(maxRow, rowScores[row])
+		leftRows[row], leftPoints[row] = slideLine(line)
+	}
+	MinScore, MaxScore = 2*Size*minRow, 2*Size*maxRow
+}
+
+// Tiles of the line slid to its start and the points of the merges, every tile merges at most once
+func slideLine(line [Size]int) (uint16, int) {
+	var slid [Size]int
+	n, points, merged := 0, 0, false
+	for _, exponent := range line {
+		if exponent == 0 {
+			continue
+		}
+		if n > 0 && slid[n-1] == exponent && exponent < maxExponent && !merged {
+			slid[n-1]++
+			points += 1 << slid[n-1]
+			merged = true
+			continue
+		}
+		slid[n] = exponent
+		n++
+		merged = false
+	}
+	var row uint16
+	for i, exponent := range slid {
+		row |= uint16(exponent) << (4 * i)
+	}
+	return row, points
+}
+
+// Empty squares, merges, monotonicity and the sum of the tiles
+func lineScore(line [Size]int) int {
+	sum := 0.0
+	empty, merges, previous, counter := 0, 0, 0, 0
+	for _, exponent := range line {
+		sum += math.Pow(float64(exponent), sumPower)
+		if exponent == 0 {
+			empty++
+			continue
+		}
+		if previous == exponent {
+			counter++
+		} else if counter > 0 {
+			merges += 1 + counter
+			counter = 0
+		}
+		previous = exponent
+	}
+	if counter > 0 {
+		merges += 1 + counter
+	}
+	left, right := 0.0, 0.0
+	for i := 1; i < Size; i++ {
+		a := math.Pow(float64(line[i-1]), monotonicityPower)
+		b := math.Pow(float64(line[i]), monotonicityPower)
+		if line[i-1] > line[i] {
+			left += a - b
+		} else {
+			right += b - a
+		}
+	}
+	return int(lostPenalty + emptyWeight*float64(empty) + mergesWeight*float64(merges) -
+		monotonicityWeight*math.Min(left, right) - sumWeight*sum)
+}
+
+// Board with the tile exponents, implements csa.SearchNode and csa.ChanceNode
+// Intentionally passed by value everywhere
+type Board struct {
+	tiles    uint64 // exponent per 4 bits, square 0 in the lowest ones
+	points   int    // of the merges so far
+	chance   bool   // the tile is spawned next
+	lastMove Direction
+}
+
+// Board with two random tiles
+func New(rng *rand.Rand) Board {
+	node := Board{lastMove: -1}
+	return node.spawn(rng).spawn(rng)
+}
+
+// Board of the tiles by rows, e.g. {{2, 0, 0, 2}, ...} with zeros on the empty squares
+func FromTiles(tiles [Size][Size]int) (Board, error) {
+	node := Board{lastMove: -1}
+	for row := range tiles {
+		for column, tile := range tiles[row] {
+			exponent := bits.TrailingZeros(uint(tile))
+			if tile == 0 {
+				continue
+			}
+			if tile < 2 || tile&(tile-1) != 0 || exponent > maxExponent {
+				return Board{}, fmt.Errorf("g2048: invalid tile %d", tile)
+			}
+			node.tiles |= uint64(exponent) << (4 * (row*Size + column))
+		}
+	}
+	return node, nil
+}
+
+// Tile on the square, zero if it is empty
+func (node Board) Tile(row, column int) int {
+	exponent := node.tiles >> (4 * (row*Size + column)) & 0xf
+	if exponent == 0 {
+		return 0
+	}
+	return 1 << exponent
+}
+
+// The largest tile
+func (node Board) MaxTile() int {
+	exponent := uint64(0)
+	for tiles := node.tiles; tiles != 0; tiles >>= 4 {
+		exponent = max(exponent, tiles&0xf)
+	}
+	if exponent == 0 {
+		return 0
+	}
+	return 1 << exponent
+}
+
+// Points of the merges, the score of the game
+func (node Board) Points() int {
+	return node.points
+}
+
+func (node Board) emptySquares() []int {
+	var squares []int
+	for square := 0; square < Size*Size; square++ {
+		if node.tiles>>(4*square)&0xf == 0 {
+			squares = append(squares, square)
+		}
+	}
+	return squares
+}
+
+// Rows become the columns
+func transpose(x uint64) uint64 {
+	a1 := x & 0xf0f00f0ff0f00f0f
+	a2 := x & 0x0000f0f00000f0f0
+	a3 := x & 0x0f0f00000f0f0000
+	a := a1 | a2<<12 | a3>>12
+	b1 := a & 0xff00ff0000ff00ff
+	b2 := a & 0x00ff00ff00000000
+	b3 := a & 0x00000000ff00ff00
+	return b1 | b2>>24 | b3<<24
+}
+
+func reverseRow(row uint64) uint64 {
+	return row>>12 | row>>4&0x00f0 | row<<4&0x0f00 | row<<12&0xf000
+}
+
+// Tiles slid to the start of the rows and the points of the merges
+func slideRows(tiles uint64, reverse bool) (uint64, int) {
+	var slid uint64
+	points := 0
+	for i := 0; i < Size; i++ {
+		row := tiles >> (16 * i) & rowMask
+		if reverse {
+			row = reverseRow(row)
+		}
+		result := uint64(leftRows[row])
+		points += leftPoints[row]
+		if reverse {
+			result = reverseRow(result)
+		}
+		slid |= result << (16 * i)
+	}
+	return slid, points
+}
+
+// Chance node after the slide, false if the slide does not change the board
+func (node Board) Slide(direction Direction) (Board, bool) {
+	if node.chance {
+		return Board{}, false
+	}
+	var tiles uint64
+	var points int
+	switch direction {
+	case Left, Right:
+		tiles, points = slideRows(node.tiles, direction == Right)
+	case Up, Down:
+		tiles, points = slideRows(transpose(node.tiles), direction == Down)
+		tiles = transpose(tiles)
+	default:
+		return Board{}, false
+	}
+	if tiles == node.tiles {
+		return Board{}, false
+	}
+	return Board{tiles: tiles, points: node.points + points, chance: true, lastMove: direction}, true
+}
+
+// Board with the tile spawned on the empty square
+func (node Board) Spawn(square, tile int) (Board, bool) {
+	if square < 0 || square >= Size*Size || node.tiles>>(4*square)&0xf != 0 || (tile != 2 && tile != 4) {
+		return Board{}, false
+	}
+	node.tiles |= uint64(bits.TrailingZeros(uint(tile))) << (4 * square)
+	node.chance = false
+	return node, true
+}
+
+// Board with a random tile spawned on a random empty square
+func (node Board) spawn(rng *rand.Rand) Board {
+	squares := node.emptySquares()
+	tile := 2
+	if rng.Float64() >= twoProbability {
+		tile = 4
+	}
+	child, _ := node.Spawn(squares[rng.Intn(len(squares))], tile)
+	return child
+}
+
+// Board after the random spawn of the chance node, the board itself otherwise
+func (node Board) Roll(rng *rand.Rand) Board {
+	if !node.chance {
+		return node
+	}
+	return node.spawn(rng)
+}
+
+func (node Board) IsChance() bool {
+	return node.chance
+}
+
+// Spawns of 2 and 4 on every empty square
+func (node Board) Outcomes() []csa.ChanceOutcome {
+	squares := node.emptySquares()
+	outcomes := make([]csa.ChanceOutcome, 0, 2*len(squares))
+	for _, square := range squares {
+		two, _ := node.Spawn(square, 2)
+		four, _ := node.Spawn(square, 4)
+		outcomes = append(outcomes,
+			csa.ChanceOutcome{Node: two, Probability: twoProbability / float64(len(squares))},
+			csa.ChanceOutcome{Node: four, Probability: (1 - twoProbability) / float64(len(squares))})
+	}
+	return outcomes
+}
+
+func (node Board) PlayerToMove() csa.Player {
+	return csa.MaximizingPlayer
+}
+
+// Heuristic score of the rows and the columns, MinScore if the game is over
+func (node Board) Score() int {
+	if node.IsTerminal() {
+		return MinScore
+	}
+	score := 0
+	transposed := transpose(node.tiles)
+	for i := 0; i < Size; i++ {
+		score += rowScores[node.tiles>>(16*i)&rowMask] + rowScores[transposed>>(16*i)&rowMask]
+	}
+	return score
+}
+
+// No slide changes the board
+func (node Board) IsTerminal() bool {
+	if node.chance {
+		return false
+	}
+	for direction := Up; direction <= Right; direction++ {
+		if _, ok := node.Slide(direction); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Slides in the order of the directions
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	direction := Up
+	return func(maximizing bool) csa.SearchNode {
+		for ; direction <= Right; direction++ {
+			if child, ok := node.Slide(direction); ok {
+				direction++
+				return child
+			}
+		}
+		return nil
+	}
+}
+
+// Direction of the slide which created this board, nil for the initial board
+func (node Board) Move() csa.Move {
+	if node.lastMove < 0 {
+		return nil
+	}
+	return node.lastMove
+}
+
+func (node Board) Hash() uint64 {
+	if node.chance {
+		return ^node.tiles
+	}
+	return node.tiles
+}
+
+func (node Board) String() string {
+	sb := strings.Builder{}
+	for row := 0; row < Size; row++ {
+		for column := 0; column < Size; column++ {
+			if column > 0 {
+				sb.WriteByte(' ')
+			}
+			if tile := node.Tile(row, column); tile == 0 {
+				sb.WriteString("    .")
+			} else {
+				fmt.Fprintf(&sb, "%5d", tile)
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Best slide found by the expectimax search of depth plies with the Star1 prunning, both slides and spawns are plies
+// Returns false if the game is over.
+func BestMove(s csa.Searcher, node Board, depth int) (Direction, bool) {
+	child, _ := s.Star1(node, depth, true, MinScore, MaxScore)
+	if child == nil {
+		return 0, false
+	}
+	return child.(Board).lastMove, true
+}
@@ -0,0 +1,108 @@
+package g2048
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+func board(t *testing.T, tiles [Size][Size]int) Board {
+	node, err := FromTiles(tiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
+
+func TestSlide(t *testing.T) {
+	node := board(t, [Size][Size]int{
+		{2, 2, 2, 2},
+		{4, 0, 4, 8},
+		{0, 0, 0, 0},
+		{2, 4, 8, 16},
+	})
+	for direction, expected := range map[Direction][Size][Size]int{
+		Left:  {{4, 4, 0, 0}, {8, 8, 0, 0}, {0, 0, 0, 0}, {2, 4, 8, 16}},
+		Right: {{0, 0, 4, 4}, {0, 0, 8, 8}, {0, 0, 0, 0}, {2, 4, 8, 16}},
+		Up:    {{2, 2, 2, 2}, {4, 4, 4, 8}, {2, 0, 8, 16}, {0, 0, 0, 0}},
+		Down:  {{0, 0, 0, 0}, {2, 0, 2, 2}, {4, 2, 4, 8}, {2, 4, 8, 16}},
+	} {
+		child, ok := node.Slide(direction)
+		if !ok || !child.IsChance() || child.Move() != direction || child.tiles != board(t, expected).tiles {
+			t.Errorf("Invalid slide %s\n%s", direction, child)
+		}
+	}
+	if child, _ := node.Slide(Left); child.Points() != 4+4+8 {
+		t.Errorf("Expected 16 points, got %d", child.Points())
+	}
+	if _, ok := board(t, [Size][Size]int{{2, 4, 8, 16}}).Slide(Left); ok {
+		t.Error("Expected no change")
+	}
+	if _, err := FromTiles([Size][Size]int{{3}}); err == nil {
+		t.Error("Expected invalid tile")
+	}
+}
+
+func TestChance(t *testing.T) {
+	node, _ := board(t, [Size][Size]int{{2, 2}}).Slide(Right)
+	outcomes := node.Outcomes()
+	sum := 0.0
+	for _, outcome := range outcomes {
+		sum += outcome.Probability
+		if outcome.Node.(Board).IsChance() || outcome.Node.(Board).tiles&node.tiles != node.tiles {
+			t.Errorf("Invalid outcome\n%s", outcome.Node)
+		}
+	}
+	if len(outcomes) != 30 || math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Expected 30 outcomes with the total probability 1, got %d %f", len(outcomes), sum)
+	}
+	full := board(t, [Size][Size]int{{2, 4, 2, 4}, {4, 2, 4, 2}, {2, 4, 2, 4}, {4, 2, 4, 2}})
+	if !full.IsTerminal() || full.Score() != MinScore {
+		t.Error("Expected the end of the game")
+	}
+	// the empty squares and the monotonic rows score better
+	good := board(t, [Size][Size]int{{2, 4, 8, 16}})
+	bad := board(t, [Size][Size]int{{16, 2, 8, 4}, {2, 8, 2, 2}})
+	if good.Score() <= bad.Score() || good.Score() > MaxScore || bad.Score() < MinScore {
+		t.Error("Invalid heuristic")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	node := New(rng)
+	for i := 0; i < 5; i++ {
+		expectedNode, expected := csa.Expectiminimax(node, 3, true)
+		starNode, score := csa.Star1(node, 3, true, MinScore, MaxScore)
+		if score != expected || starNode != expectedNode {
+			t.Errorf("Star1 scored %d, expectiminimax %d\n%s", score, expected, node)
+		}
+		node = expectedNode.(Board).Roll(rng)
+	}
+	// the chance root is scored without the best child
+	chance, _ := node.Slide(Left)
+	if child, _ := csa.Expectiminimax(chance, 2, true); child != nil {
+		t.Error("Expected no child of the chance root")
+	}
+}
+
+func TestPlay(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	node := New(rng)
+	for moves := 0; moves < 300; moves++ {
+		direction, ok := BestMove(csa.Searcher{}, node, 2)
+		if !ok {
+			break
+		}
+		child, ok := node.Slide(direction)
+		if !ok {
+			t.Fatalf("Invalid move %s", direction)
+		}
+		node = child.Roll(rng)
+	}
+	if node.MaxTile() < 256 {
+		t.Errorf("Expected at least 256, got\n%s", node)
+	}
+}
@@ -0,0 +1,461 @@
+// Package goban is the game of Go of the csa package on the small boards, 9x9 by default
+//
+// Black is the maximizing player and moves first. The players alternately place a stone on an empty point or pass,
+// the opponent's groups without liberties are captured. Suicide is illegal and so is the immediate recapture
+// of a single stone (simple ko). The game ends after two passes in a row and it is scored by the Tromp-Taylor
+// area scoring: the stones and the empty regions reaching only the stones of one color, white gets the komi.
+//
+// The search does not generate the moves filling the player's own eyes, so the random playouts of MCTS end
+// with both players passing. Board implements csa.PlayoutNode. The points are in the notation of GTP,
+// e.g. D4 with the columns A-T without I and the row 1 at the bottom.
+package goban
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+const (
+	MaxSize = 19
+
+	// Compensation of white for moving second on 9x9 and larger boards
+	DefaultKomi = 7.5
+
+	// Score of the finished game is WinScore plus the rounded margin for the winner. Use it as csa.Searcher's WinScore.
+	WinScore = 1000
+
+	points = MaxSize * MaxSize
+
+	// columns of the notation
+	columnLetters = "ABCDEFGHJKLMNOPQRST"
+
+	// runes
+	blackStone = 'X'
+	whiteStone = 'O'
+)
+
+type Color int8
+
+const (
+	Empty Color = iota
+	Black
+	White
+)
+
+func (color Color) opponent() Color {
+	return Black + White - color
+}
+
+// Board size, 9x9 if zero, and the komi, e.g. DefaultKomi
+type Rules struct {
+	Size int
+	Komi float64
+	// Plies after which the game ends even without the passes, 3*Size*Size if zero
+	MaxMoves int
+}
+
+func (rules Rules) withDefaults() Rules {
+	if rules.Size == 0 {
+		rules.Size = 9
+	}
+	if rules.MaxMoves == 0 {
+		rules.MaxMoves = 3 * rules.Size * rules.Size
+	}
+	return rules
+}
+
+// Point of the move with the row 0 at the bottom, Pass is not on the board
+type Point struct {
+	Column, Row int
+}
+
+var Pass = Point{-1, -1}
+
+// GTP vertex, e.g. D4 or pass
+func (point Point) String() string {
+	if point == Pass {
+		return "pass"
+	}
+	return fmt.Sprintf("%c%d", columnLetters[point.Column], point.Row+1)
+}
+
+// Parses the GTP vertex, e.g. D4, d4 or pass
+func ParsePoint(vertex string) (Point, error) {
+	vertex = strings.ToUpper(vertex)
+	if vertex == "PASS" {
+		return Pass, nil
+	}
+	var row int
+	if len(vertex) < 2 {
+		return Point{}, fmt.Errorf("goban: invalid vertex %q", vertex)
+	}
+	column := strings.IndexByte(columnLetters, vertex[0])
+	if _, err := fmt.Sscanf(vertex[1:], "%d", &row); err != nil || column < 0 || row < 1 || row > MaxSize {
+		return Point{}, fmt.Errorf("goban: invalid vertex %q", vertex)
+	}
+	return Point{column, row - 1}, nil
+}
+
+var (
+	neighbours = [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	diagonals  = [4][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+
+	// hash of a stone of the color on the point, indexed by row*MaxSize+column
+	zobrist = func() [2][points]uint64 {
+		var keys [2][points]uint64
+		rng := rand.New(rand.NewSource(1))
+		for color := range keys {
+			for i := range keys[color] {
+				keys[color][i] = rng.Uint64()
+			}
+		}
+		return keys
+	}()
+	zobristWhite = zobrist[1][0] ^ zobrist[0][points-1]
+)
+
+// Board with the stones, implements csa.SearchNode
+// Intentionally passed by value everywhere
+type Board struct {
+	stones   [points]Color
+	size     int8
+	komi     float64
+	maxMoves int16
+	side     Color // to move
+	ko       int16 // point which cannot be played now, -1 if there is none
+	passes   int8  // in a row
+	moves    int16
+	hash     uint64
+	lastMove Point
+	started  bool // false for the initial board
+}
+
+// Empty board of the rules, black to move; panics if the rules are invalid
+func New(rules Rules) Board {
+	rules = rules.withDefaults()
+	if rules.Size < 2 || rules.Size > MaxSize || rules.MaxMoves < 1 || rules.MaxMoves > math.MaxInt16 {
+		panic(fmt.Sprintf("goban: invalid rules %+v", rules))
+	}
+	return Board{size: int8(rules.Size), komi: rules.Komi, maxMoves: int16(rules.MaxMoves), side: Black, ko: -1}
+}
+
+func (node Board) Size() int {
+	return int(node.size)
+}
+
+func (node Board) Komi() float64 {
+	return node.komi
+}
+
+func (node Board) onBoard(column, row int) bool {
+	return column >= 0 && row >= 0 && column < int(node.size) && row < int(node.size)
+}
+
+// Color of the stone on the point, Empty if there is none
+func (node Board) At(point Point) Color {
+	if !node.onBoard(point.Column, point.Row) {
+		return Empty
+	}
+	return node.stones[point.Row*MaxSize+point.Column]
+}
+
+func (node Board) SideToMove() Color {
+	return node.side
+}
+
+// Board with the other player to move, e.g. for the consecutive moves of one color in GTP
+func (node Board) WithSideToMove(color Color) Board {
+	if node.side != color && (color == Black || color == White) {
+		node.side = color
+		node.hash ^= zobristWhite
+		node.ko = -1
+	}
+	return node
+}
+
+func (node Board) PlayerToMove() csa.Player {
+	if node.side == Black {
+		return csa.MaximizingPlayer
+	}
+	return csa.MinimizingPlayer
+}
+
+// Stones of the group on the index and whether it has any liberty
+func (node *Board) group(index int, stones []int) ([]int, bool) {
+	color := node.stones[index]
+	var visited [points]bool
+	visited[index] = true
+	stones = append(stones[:0], index)
+	free := false
+	for i := 0; i < len(stones); i++ {
+		column, row := stones[i]%MaxSize, stones[i]/MaxSize
+		for _, offset := range neighbours {
+			c, r := column+offset[0], row+offset[1]
+			if !node.onBoard(c, r) || visited[r*MaxSize+c] {
+				continue
+			}
+			switch node.stones[r*MaxSize+c] {
+			case Empty:
+				free = true
+			case color:
+				visited[r*MaxSize+c] = true
+				stones = append(stones, r*MaxSize+c)
+			}
+		}
+	}
+	return stones, free
+}
+
+func (node *Board) remove(stones []int) {
+	for _, index := range stones {
+		node.hash ^= zobrist[node.stones[index]-1][index]
+		node.stones[index] = Empty
+	}
+}
+
+// Board after the move of the player to move, false if it is illegal or the game is over
+func (node Board) Play(point Point) (Board, bool) {
+	if node.IsTerminal() {
+		return Board{}, false
+	}
+	node.started, node.lastMove, node.moves = true, point, node.moves+1
+	if point == Pass {
+		node.passes++
+		node.ko = -1
+		node.side = node.side.opponent()
+		node.hash ^= zobristWhite
+		return node, true
+	}
+	index := point.Row*MaxSize + point.Column
+	if !node.onBoard(point.Column, point.Row) || node.stones[index] != Empty || int(node.ko) == index {
+		return Board{}, false
+	}
+	color := node.side
+	node.stones[index] = color
+	node.hash ^= zobrist[color-1][index]
+	captured, capturedAt := 0, -1
+	var stones [points]int
+	for _, offset := range neighbours {
+		c, r := point.Column+offset[0], point.Row+offset[1]
+		if !node.onBoard(c, r) || node.stones[r*MaxSize+c] != color.opponent() {
+			continue
+		}
+		if group, free := node.group(r*MaxSize+c, stones[:0]); !free {
+			captured += len(group)
+			capturedAt = group[0]
+			node.remove(group)
+		}
+	}
+	group, free := node.group(index, stones[:0])
+	if !free {
+		// suicide
+		return Board{}, false
+	}
+	node.ko = -1
+	if captured == 1 && len(group) == 1 && node.liberties(index) == 1 {
+		node.ko = int16(capturedAt)
+	}
+	node.passes = 0
+	node.side = color.opponent()
+	node.hash ^= zobristWhite
+	return node, true
+}
+
+// Empty neighbours of the point
+func (node Board) liberties(index int) int {
+	count := 0
+	column, row := index%MaxSize, index/MaxSize
+	for _, offset := range neighbours {
+		c, r := column+offset[0], row+offset[1]
+		if node.onBoard(c, r) && node.stones[r*MaxSize+c] == Empty {
+			count++
+		}
+	}
+	return count
+}
+
+// Empty point surrounded by the stones of the color, with at most one diagonal of the opponent
+// and none on the edge, so that it cannot become a false eye
+func (node Board) isEye(index int, color Color) bool {
+	if node.stones[index] != Empty {
+		return false
+	}
+	column, row := index%MaxSize, index/MaxSize
+	for _, offset := range neighbours {
+		c, r := column+offset[0], row+offset[1]
+		if node.onBoard(c, r) && node.stones[r*MaxSize+c] != color {
+			return false
+		}
+	}
+	opponents, edge := 0, false
+	for _, offset := range diagonals {
+		c, r := column+offset[0], row+offset[1]
+		if !node.onBoard(c, r) {
+			edge = true
+		} else if node.stones[r*MaxSize+c] == color.opponent() {
+			opponents++
+		}
+	}
+	return opponents == 0 || (opponents == 1 && !edge)
+}
+
+// Both players passed or the game reached the maximum number of moves
+func (node Board) IsTerminal() bool {
+	return node.passes >= 2 || node.moves >= node.maxMoves
+}
+
+// Tromp-Taylor score, black's area minus white's area and the komi
+func (node Board) Area() float64 {
+	size := int(node.size)
+	area := 0
+	var visited [points]bool
+	for row := 0; row < size; row++ {
+		for column := 0; column < size; column++ {
+			index := row*MaxSize + column
+			switch node.stones[index] {
+			case Black:
+				area++
+				continue
+			case White:
+				area--
+				continue
+			}
+			if visited[index] {
+				continue
+			}
+			// the empty region and the colors it reaches
+			region, reaches := []int{index}, [3]bool{}
+			visited[index] = true
+			for i := 0; i < len(region); i++ {
+				c0, r0 := region[i]%MaxSize, region[i]/MaxSize
+				for _, offset := range neighbours {
+					c, r := c0+offset[0], r0+offset[1]
+					if !node.onBoard(c, r) {
+						continue
+					}
+					next := r*MaxSize + c
+					if stone := node.stones[next]; stone != Empty {
+						reaches[stone] = true
+					} else if !visited[next] {
+						visited[next] = true
+						region = append(region, next)
+					}
+				}
+			}
+			switch {
+			case reaches[Black] && !reaches[White]:
+				area += len(region)
+			case reaches[White] && !reaches[Black]:
+				area -= len(region)
+			}
+		}
+	}
+	return float64(area) - node.komi
+}
+
+// Tromp-Taylor score rounded, WinScore plus the margin for the winner of the finished game
+func (node Board) Score() int {
+	margin := int(math.Round(node.Area()))
+	if !node.IsTerminal() {
+		return margin
+	}
+	if node.Area() > 0 {
+		return WinScore + max(margin, 0)
+	}
+	return -WinScore + min(margin, 0)
+}
+
+// Legal moves not filling the own eyes, the pass last
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	size := int(node.size)
+	index := 0
+	return func(maximizing bool) csa.SearchNode {
+		for ; index < size*size; index++ {
+			point := Point{index % size, index / size}
+			if node.isEye(point.Row*MaxSize+point.Column, node.side) {
+				continue
+			}
+			if child, ok := node.Play(point); ok {
+				index++
+				return child
+			}
+		}
+		if index == size*size {
+			index++
+			if child, ok := node.Play(Pass); ok {
+				return child
+			}
+		}
+		return nil
+	}
+}
+
+// Random legal moves not filling the own eyes, the player passes if it has none
+func (node Board) RandomPlayout(maximizing bool, rng *rand.Rand) csa.SearchNode {
+	size := int(node.size)
+	candidates := make([]Point, 0, size*size)
+	for !node.IsTerminal() {
+		candidates = candidates[:0]
+		for row := 0; row < size; row++ {
+			for column := 0; column < size; column++ {
+				if index := row*MaxSize + column; node.stones[index] == Empty && !node.isEye(index, node.side) {
+					candidates = append(candidates, Point{column, row})
+				}
+			}
+		}
+		child, ok := Board{}, false
+		for len(candidates) > 0 && !ok {
+			i := rng.Intn(len(candidates))
+			child, ok = node.Play(candidates[i])
+			candidates[i] = candidates[len(candidates)-1]
+			candidates = candidates[:len(candidates)-1]
+		}
+		if !ok {
+			child, _ = node.Play(Pass)
+		}
+		node = child
+	}
+	return node
+}
+
+// Point of the move which created this board or Pass, nil for the initial board
+func (node Board) Move() csa.Move {
+	if !node.started {
+		return nil
+	}
+	return node.lastMove
+}
+
+func (node Board) Hash() uint64 {
+	return node.hash ^ uint64(node.ko+1)<<48 ^ uint64(node.passes)<<60
+}
+
+// Rows from the top with the letters of the columns, X for black and O for white
+func (node Board) String() string {
+	size := int(node.size)
+	sb := strings.Builder{}
+	for row := size - 1; row >= 0; row-- {
+		fmt.Fprintf(&sb, "%2d", row+1)
+		for column := 0; column < size; column++ {
+			r := '.'
+			switch node.stones[row*MaxSize+column] {
+			case Black:
+				r = blackStone
+			case White:
+				r = whiteStone
+			}
+			fmt.Fprintf(&sb, " %c", r)
+		}
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("  ")
+	for column := 0; column < size; column++ {
+		fmt.Fprintf(&sb, " %c", columnLetters[column])
+	}
+	sb.WriteByte('\n')
+	return sb.String()
+}
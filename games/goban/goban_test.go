@@ -0,0 +1,139 @@
+package goban
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Board after the vertices in the notation
+func play(t *testing.T, rules Rules, vertices ...string) Board {
+	node := New(rules)
+	for _, vertex := range vertices {
+		point, err := ParsePoint(vertex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		child, ok := node.Play(point)
+		if !ok {
+			t.Fatalf("Illegal move %s\n%s", vertex, node)
+		}
+		node = child
+	}
+	return node
+}
+
+func TestCapture(t *testing.T) {
+	// white A1 surrounded by B1 and A2
+	node := play(t, Rules{Size: 5}, "B1", "A1", "A2")
+	if node.At(Point{0, 0}) != Empty || node.At(Point{1, 0}) != Black || node.Move() != (Point{0, 1}) {
+		t.Errorf("Expected the capture\n%s", node)
+	}
+	// white A1 would have no liberty
+	if _, ok := node.Play(Point{0, 0}); ok {
+		t.Errorf("Expected the suicide to be illegal\n%s", node)
+	}
+	expected := " 5 . . . . .\n 4 . . . . .\n 3 . . . . .\n 2 X . . . .\n 1 . X . . .\n   A B C D E\n"
+	if node.String() != expected {
+		t.Errorf("Invalid board\n%s", node)
+	}
+	if _, err := ParsePoint("I3"); err == nil {
+		t.Error("Expected invalid vertex")
+	}
+}
+
+func TestKo(t *testing.T) {
+	// black C2 captures white B2, white cannot recapture at C2 immediately
+	node := play(t, Rules{Size: 5}, "B1", "C1", "A2", "D2", "B3", "C3", "E5", "B2", "C2")
+	if node.At(Point{1, 1}) != Empty {
+		t.Fatalf("Expected the capture\n%s", node)
+	}
+	if _, ok := node.Play(Point{1, 1}); ok {
+		t.Errorf("Expected the ko\n%s", node)
+	}
+	// after the threat elsewhere the recapture is legal
+	node = play(t, Rules{Size: 5}, "B1", "C1", "A2", "D2", "B3", "C3", "E5", "B2", "C2", "E1", "E4")
+	if _, ok := node.Play(Point{1, 1}); !ok {
+		t.Errorf("Expected the recapture\n%s", node)
+	}
+}
+
+func TestScore(t *testing.T) {
+	// black owns the columns A-B, white D-E, C is shared by nobody
+	node := play(t, Rules{Size: 5, Komi: 0.5}, "B1", "D1", "B2", "D2", "B3", "D3", "B4", "D4", "B5", "D5")
+	if node.Area() != -0.5 || node.Score() != -1 || node.IsTerminal() {
+		t.Errorf("Expected area -0.5, got %v\n%s", node.Area(), node)
+	}
+	node = play(t, Rules{Size: 5, Komi: 0.5}, "B1", "D1", "B2", "D2", "B3", "D3", "B4", "D4", "B5", "D5", "C3",
+		"pass", "pass")
+	if !node.IsTerminal() || node.Area() != 0.5 || node.Score() != WinScore+1 {
+		t.Errorf("Expected black to win by 0.5, got %v\n%s", node.Area(), node)
+	}
+	if _, ok := node.Play(Point{2, 0}); ok {
+		t.Error("Expected no move after the end")
+	}
+	// A1 is black's eye
+	node = play(t, Rules{Size: 5}, "B1", "E5", "A2", "E4", "B2")
+	if !node.isEye(0, Black) || node.isEye(0, White) {
+		t.Errorf("Expected the eye of black\n%s", node)
+	}
+}
+
+func TestPlayout(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		end := New(Rules{Komi: DefaultKomi}).RandomPlayout(true, rng).(Board)
+		if !end.IsTerminal() || end.passes < 2 {
+			t.Fatalf("Expected both players to pass\n%s", end)
+		}
+	}
+	// black captures the two white stones in atari
+	node := play(t, Rules{Size: 5}, "B1", "A1", "C2", "A2", "B3", "B2")
+	mcts := csa.MCTS{Iterations: 3000, Exploration: WinScore, Rand: rand.New(rand.NewSource(1))}
+	child, _ := mcts.Search(node, true)
+	if move := child.(Board).Move(); move != (Point{0, 2}) {
+		t.Errorf("Expected the capture at A3, got %v", move)
+	}
+}
+
+func TestGTP(t *testing.T) {
+	input := strings.Join([]string{
+		"1 protocol_version",
+		"boardsize 5",
+		"komi 0.5",
+		"play b B1",
+		"play w I1",
+		"play w B1",
+		"2 genmove w",
+		"known_command genmove",
+		"undo",
+		"undo",
+		"final_score",
+		"foo",
+		"quit",
+		"showboard",
+	}, "\n")
+	gtp := &GTP{Name: "csa", MCTS: csa.MCTS{Iterations: 100, Exploration: WinScore}}
+	output := &strings.Builder{}
+	if err := gtp.Run(strings.NewReader(input), output); err != nil {
+		t.Fatal(err)
+	}
+	responses := strings.Split(strings.TrimSuffix(output.String(), "\n\n"), "\n\n")
+	expected := []string{"=1 2", "= ", "= ", "= ", "? syntax error", "? illegal move", "", "= true", "= ", "= ",
+		"= W+0.5", "? unknown command", "= "}
+	if len(responses) != len(expected) {
+		t.Fatalf("Expected %d responses, got %q", len(expected), responses)
+	}
+	for i, response := range responses {
+		if i == 6 {
+			point, err := ParsePoint(strings.TrimPrefix(response, "=2 "))
+			if !strings.HasPrefix(response, "=2 ") || err != nil || point == (Point{1, 0}) {
+				t.Errorf("Expected a legal move, got %q", response)
+			}
+		} else if response != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], response)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package goban
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Engine speaking the Go Text Protocol version 2, e.g. over stdin and stdout against GnuGo by gogui-twogtp
+// The moves of genmove are searched by MCTS with the given iterations, the random playouts do not fill the own eyes.
+// The board size and the komi are set by the controller, the time controls are accepted and ignored.
+// Consecutive moves of one color are allowed as GTP requires, the simple ko is forgotten then.
+type GTP struct {
+	Name    string
+	Version string
+	Rules   Rules // initial size and komi, e.g. DefaultKomi
+	MCTS    csa.MCTS
+
+	writer  *bufio.Writer
+	history []Board // the last one is the current board
+}
+
+var gtpCommands = []string{
+	"protocol_version", "name", "version", "known_command", "list_commands", "quit", "boardsize", "clear_board",
+	"komi", "play", "genmove", "undo", "showboard", "final_score", "time_settings", "time_left",
+}
+
+// Serves the commands until quit or the end of the input
+func (gtp *GTP) Run(r io.Reader, w io.Writer) error {
+	gtp.writer = bufio.NewWriter(w)
+	gtp.history = []Board{New(gtp.Rules)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(strings.ToLower(strings.SplitN(scanner.Text(), "#", 2)[0]))
+		if len(fields) == 0 {
+			continue
+		}
+		id := ""
+		if _, err := strconv.Atoi(fields[0]); err == nil {
+			id, fields = fields[0], fields[1:]
+			if len(fields) == 0 {
+				continue
+			}
+		}
+		response, err := gtp.command(fields[0], fields[1:])
+		if err != nil {
+			fmt.Fprintf(gtp.writer, "?%s %s\n\n", id, err)
+		} else {
+			fmt.Fprintf(gtp.writer, "=%s %s\n\n", id, response)
+		}
+		if err := gtp.writer.Flush(); err != nil {
+			return err
+		}
+		if fields[0] == "quit" {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+func (gtp *GTP) node() Board {
+	return gtp.history[len(gtp.history)-1]
+}
+
+// Current board with the color to move, the controller may continue after both players passed
+func (gtp *GTP) current(color Color) Board {
+	node := gtp.node().WithSideToMove(color)
+	node.passes = min(node.passes, 1)
+	return node
+}
+
+func (gtp *GTP) command(command string, args []string) (string, error) {
+	switch command {
+	case "protocol_version":
+		return "2", nil
+	case "name":
+		return gtp.Name, nil
+	case "version":
+		return gtp.Version, nil
+	case "known_command":
+		if len(args) != 1 {
+			return "", fmt.Errorf("syntax error")
+		}
+		for _, known := range gtpCommands {
+			if known == args[0] {
+				return "true", nil
+			}
+		}
+		return "false", nil
+	case "list_commands":
+		return strings.Join(gtpCommands, "\n"), nil
+	case "quit", "time_settings", "time_left":
+		return "", nil
+	case "boardsize":
+		size, err := gtp.intArg(args)
+		if err != nil || size < 2 || size > MaxSize {
+			return "", fmt.Errorf("unacceptable size")
+		}
+		gtp.Rules.Size = size
+		gtp.history = []Board{New(gtp.Rules)}
+		return "", nil
+	case "clear_board":
+		gtp.history = []Board{New(gtp.Rules)}
+		return "", nil
+	case "komi":
+		if len(args) != 1 {
+			return "", fmt.Errorf("syntax error")
+		}
+		komi, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return "", fmt.Errorf("syntax error")
+		}
+		gtp.Rules.Komi = komi
+		for i := range gtp.history {
+			gtp.history[i].komi = komi
+		}
+		return "", nil
+	case "play":
+		if len(args) != 2 {
+			return "", fmt.Errorf("syntax error")
+		}
+		color, err := parseColor(args[0])
+		if err != nil {
+			return "", err
+		}
+		point, err := ParsePoint(args[1])
+		if err != nil {
+			return "", fmt.Errorf("syntax error")
+		}
+		child, ok := gtp.current(color).Play(point)
+		if !ok {
+			return "", fmt.Errorf("illegal move")
+		}
+		gtp.history = append(gtp.history, child)
+		return "", nil
+	case "genmove":
+		if len(args) != 1 {
+			return "", fmt.Errorf("syntax error")
+		}
+		color, err := parseColor(args[0])
+		if err != nil {
+			return "", err
+		}
+		return gtp.genmove(color), nil
+	case "undo":
+		if len(gtp.history) == 1 {
+			return "", fmt.Errorf("cannot undo")
+		}
+		gtp.history = gtp.history[:len(gtp.history)-1]
+		return "", nil
+	case "showboard":
+		return "\n" + strings.TrimSuffix(gtp.node().String(), "\n"), nil
+	case "final_score":
+		switch area := gtp.node().Area(); {
+		case area > 0:
+			return "B+" + strconv.FormatFloat(area, 'f', -1, 64), nil
+		case area < 0:
+			return "W+" + strconv.FormatFloat(-area, 'f', -1, 64), nil
+		}
+		return "0", nil
+	}
+	return "", fmt.Errorf("unknown command")
+}
+
+// Searches and plays the move of the color, pass if the game is over
+func (gtp *GTP) genmove(color Color) string {
+	node := gtp.current(color)
+	if node.IsTerminal() {
+		// the board stays for undo
+		gtp.history = append(gtp.history, node)
+		return "PASS"
+	}
+	child, _ := gtp.MCTS.Search(node, color == Black)
+	if child == nil {
+		child, _ = node.Play(Pass)
+	}
+	board := child.(Board)
+	gtp.history = append(gtp.history, board)
+	return strings.ToUpper(board.lastMove.String())
+}
+
+func (gtp *GTP) intArg(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("syntax error")
+	}
+	return strconv.Atoi(args[0])
+}
+
+func parseColor(color string) (Color, error) {
+	switch color {
+	case "b", "black":
+		return Black, nil
+	case "w", "white":
+		return White, nil
+	}
+	return Empty, fmt.Errorf("syntax error")
+}
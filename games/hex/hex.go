@@ -0,0 +1,398 @@
+// Package hex is the game of Hex of the csa package on the rhombic boards up to 19x19
+//
+// Black is the maximizing player and moves first. The players alternately place a stone of their color on any empty
+// cell, black connects the top and the bottom edge while white connects the left and the right edge. The game cannot
+// end in a draw, the full board always has exactly one winner. The connected groups are kept in a union-find
+// together with the virtual cells of the four edges, so the winner is known after every move.
+//
+// Board implements csa.PlayoutNode, the playout fills the empty cells in a random order and finds the winner once,
+// and csa.SymmetryNode, the board rotated by 180 degrees is equivalent. Cells are a1 (top left) to s19,
+// the columns are letters and the rows numbers.
+package hex
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+const (
+	MaxSize = 19
+
+	// Score of the won board, the heuristic scores of the other boards stay below it. Use it as csa.Searcher's WinScore.
+	WinScore = 1000
+
+	cells = MaxSize * MaxSize
+
+	// virtual cells of the edges in the union-find
+	top    = cells
+	bottom = cells + 1
+	left   = cells + 2
+	right  = cells + 3
+
+	// runes
+	blackStone = 'X'
+	whiteStone = 'O'
+)
+
+type Color int8
+
+const (
+	Empty Color = iota
+	Black
+	White
+)
+
+// Cell of the move, numbered from 0
+type Cell struct {
+	Column, Row int
+}
+
+// Notation, e.g. c4
+func (cell Cell) String() string {
+	return fmt.Sprintf("%c%d", 'a'+cell.Column, cell.Row+1)
+}
+
+// Parses the notation, e.g. c4
+func ParseCell(notation string) (Cell, error) {
+	var column rune
+	var row int
+	if _, err := fmt.Sscanf(notation, "%c%d", &column, &row); err != nil || column < 'a' || column >= 'a'+MaxSize ||
+		row < 1 || row > MaxSize {
+		return Cell{}, fmt.Errorf("hex: invalid cell %q", notation)
+	}
+	return Cell{int(column - 'a'), row - 1}, nil
+}
+
+var (
+	// offsets of the six neighbours in the rhombus
+	neighbours = [6][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, -1}, {-1, 1}}
+
+	// hash of a stone of the color on the cell, indexed by row*MaxSize+column
+	zobrist = func() [2][cells]uint64 {
+		var keys [2][cells]uint64
+		rng := rand.New(rand.NewSource(1))
+		for color := range keys {
+			for i := range keys[color] {
+				keys[color][i] = rng.Uint64()
+			}
+		}
+		return keys
+	}()
+)
+
+// Board with the stones and their connected groups, implements csa.SearchNode
+// Intentionally passed by value everywhere
+type Board struct {
+	stones      [cells]Color
+	parents     [cells + 4]int16 // union-find of the groups, the roots point to themselves
+	size        int8
+	empty       int16
+	side        Color // to move
+	hash        uint64
+	rotatedHash uint64 // of the board rotated by 180 degrees
+	lastMove    int16  // index of the cell, -1 for the empty board
+}
+
+// Empty board of the size, black to move; panics if the size is invalid
+func New(size int) Board {
+	if size < 1 || size > MaxSize {
+		panic(fmt.Sprintf("hex: invalid size %d", size))
+	}
+	node := Board{size: int8(size), empty: int16(size * size), side: Black, lastMove: -1}
+	for i := range node.parents {
+		node.parents[i] = int16(i)
+	}
+	return node
+}
+
+func (node Board) Size() int {
+	return int(node.size)
+}
+
+func (node Board) index(cell Cell) int {
+	return cell.Row*MaxSize + cell.Column
+}
+
+func (node Board) onBoard(column, row int) bool {
+	return column >= 0 && row >= 0 && column < int(node.size) && row < int(node.size)
+}
+
+// Color of the stone on the cell, Empty if there is none
+func (node Board) At(cell Cell) Color {
+	if !node.onBoard(cell.Column, cell.Row) {
+		return Empty
+	}
+	return node.stones[node.index(cell)]
+}
+
+func (node Board) SideToMove() Color {
+	return node.side
+}
+
+func (node Board) PlayerToMove() csa.Player {
+	if node.side == Black {
+		return csa.MaximizingPlayer
+	}
+	return csa.MinimizingPlayer
+}
+
+func (node *Board) find(i int) int {
+	for int(node.parents[i]) != i {
+		node.parents[i] = node.parents[node.parents[i]]
+		i = int(node.parents[i])
+	}
+	return i
+}
+
+func (node *Board) union(a, b int) {
+	if a, b = node.find(a), node.find(b); a != b {
+		node.parents[a] = int16(b)
+	}
+}
+
+// Places the stone and joins it with the neighbouring stones and edges of its color
+func (node *Board) place(cell Cell, color Color) {
+	i, size := node.index(cell), int(node.size)
+	node.stones[i] = color
+	node.empty--
+	rotated := (size-1-cell.Row)*MaxSize + size - 1 - cell.Column
+	node.hash ^= zobrist[color-1][i]
+	node.rotatedHash ^= zobrist[color-1][rotated]
+	for _, offset := range neighbours {
+		column, row := cell.Column+offset[0], cell.Row+offset[1]
+		if node.onBoard(column, row) && node.stones[row*MaxSize+column] == color {
+			node.union(i, row*MaxSize+column)
+		}
+	}
+	switch {
+	case color == Black && cell.Row == 0:
+		node.union(i, top)
+	case color == White && cell.Column == 0:
+		node.union(i, left)
+	}
+	switch {
+	case color == Black && cell.Row == size-1:
+		node.union(i, bottom)
+	case color == White && cell.Column == size-1:
+		node.union(i, right)
+	}
+}
+
+// Board after the stone of the player to move placed on the cell, false if it is occupied or the game is over
+func (node Board) Play(cell Cell) (Board, bool) {
+	if !node.onBoard(cell.Column, cell.Row) || node.stones[node.index(cell)] != Empty || node.IsTerminal() {
+		return Board{}, false
+	}
+	node.place(cell, node.side)
+	node.side = Black + White - node.side
+	node.lastMove = int16(node.index(cell))
+	return node, true
+}
+
+// Color which connected its edges, Empty if the game is not over
+func (node Board) Winner() Color {
+	if node.find(top) == node.find(bottom) {
+		return Black
+	}
+	if node.find(left) == node.find(right) {
+		return White
+	}
+	return Empty
+}
+
+func (node Board) IsTerminal() bool {
+	return node.Winner() != Empty
+}
+
+// Cells needed to connect the edges of the color, the stones of the color are free, zero if connected
+func (node Board) distance(color Color) int {
+	size := int(node.size)
+	const unreachable = cells + 1
+	var distances [cells]int
+	for i := range distances {
+		distances[i] = unreachable
+	}
+	cost := func(column, row int) int {
+		switch node.stones[row*MaxSize+column] {
+		case color:
+			return 0
+		case Empty:
+			return 1
+		}
+		return unreachable
+	}
+	// 0-1 breadth-first search from the first edge, level by level of the distance
+	var current, next []Cell
+	for k := 0; k < size; k++ {
+		cell := Cell{k, 0}
+		if color == White {
+			cell = Cell{0, k}
+		}
+		if c := cost(cell.Column, cell.Row); c == 0 {
+			distances[node.index(cell)] = 0
+			current = append(current, cell)
+		} else if c == 1 {
+			distances[node.index(cell)] = 1
+			next = append(next, cell)
+		}
+	}
+	for level := 0; len(current) > 0 || len(next) > 0; level++ {
+		for len(current) > 0 {
+			cell := current[len(current)-1]
+			current = current[:len(current)-1]
+			if distances[node.index(cell)] < level {
+				continue
+			}
+			if (color == Black && cell.Row == size-1) || (color == White && cell.Column == size-1) {
+				return level
+			}
+			for _, offset := range neighbours {
+				column, row := cell.Column+offset[0], cell.Row+offset[1]
+				if !node.onBoard(column, row) {
+					continue
+				}
+				c := cost(column, row)
+				if c == unreachable || level+c >= distances[row*MaxSize+column] {
+					continue
+				}
+				distances[row*MaxSize+column] = level + c
+				if c == 0 {
+					current = append(current, Cell{column, row})
+				} else {
+					next = append(next, Cell{column, row})
+				}
+			}
+		}
+		current, next = next, current[:0]
+	}
+	return unreachable
+}
+
+// Difference of the shortest connections of white and black, WinScore plus the empty cells for the winner
+func (node Board) Score() int {
+	switch node.Winner() {
+	case Black:
+		return WinScore + int(node.empty)
+	case White:
+		return -WinScore - int(node.empty)
+	}
+	return node.distance(White) - node.distance(Black)
+}
+
+// Empty cells from the center of the board outwards
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	order := centerOrder(int(node.size))
+	i := 0
+	return func(maximizing bool) csa.SearchNode {
+		for ; i < len(order); i++ {
+			if child, ok := node.Play(order[i]); ok {
+				i++
+				return child
+			}
+		}
+		return nil
+	}
+}
+
+var centerOrders [MaxSize + 1][]Cell
+
+func init() {
+	for size := 1; size <= MaxSize; size++ {
+		var order []Cell
+		for row := 0; row < size; row++ {
+			for column := 0; column < size; column++ {
+				order = append(order, Cell{column, row})
+			}
+		}
+		// distance from the center doubled to stay integral
+		distance := func(cell Cell) int {
+			return abs(2*cell.Column-size+1) + abs(2*cell.Row-size+1)
+		}
+		for i := 1; i < len(order); i++ {
+			for j := i; j > 0 && distance(order[j]) < distance(order[j-1]); j-- {
+				order[j], order[j-1] = order[j-1], order[j]
+			}
+		}
+		centerOrders[size] = order
+	}
+}
+
+func centerOrder(size int) []Cell {
+	return centerOrders[size]
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Fills the empty cells in a random order alternating the colors, the full board has a single winner
+func (node Board) RandomPlayout(maximizing bool, rng *rand.Rand) csa.SearchNode {
+	size := int(node.size)
+	empty := make([]Cell, 0, node.empty)
+	for row := 0; row < size; row++ {
+		for column := 0; column < size; column++ {
+			if node.stones[row*MaxSize+column] == Empty {
+				empty = append(empty, Cell{column, row})
+			}
+		}
+	}
+	rng.Shuffle(len(empty), func(i, j int) { empty[i], empty[j] = empty[j], empty[i] })
+	color := White
+	if maximizing {
+		color = Black
+	}
+	for _, cell := range empty {
+		node.place(cell, color)
+		color = Black + White - color
+	}
+	node.side = color
+	return node
+}
+
+// Cell of the move which created this board, nil for the empty board
+func (node Board) Move() csa.Move {
+	if node.lastMove < 0 {
+		return nil
+	}
+	return Cell{int(node.lastMove) % MaxSize, int(node.lastMove) / MaxSize}
+}
+
+func (node Board) Hash() uint64 {
+	return node.hash
+}
+
+func (node Board) CanonicalHash() uint64 {
+	return min(node.hash, node.rotatedHash)
+}
+
+// Rows shifted to the right by the rhombus, X for black and O for white
+func (node Board) String() string {
+	size := int(node.size)
+	sb := strings.Builder{}
+	sb.WriteString("  ")
+	for column := 0; column < size; column++ {
+		fmt.Fprintf(&sb, " %c", 'a'+column)
+	}
+	sb.WriteByte('\n')
+	for row := 0; row < size; row++ {
+		fmt.Fprintf(&sb, "%s%2d", strings.Repeat(" ", row), row+1)
+		for column := 0; column < size; column++ {
+			r := '.'
+			switch node.stones[row*MaxSize+column] {
+			case Black:
+				r = blackStone
+			case White:
+				r = whiteStone
+			}
+			fmt.Fprintf(&sb, " %c", r)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
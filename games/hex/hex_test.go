@@ -0,0 +1,99 @@
+package hex
+
+import (
+	"math/rand"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Board after the cells in the notation
+func play(t *testing.T, size int, cells ...string) Board {
+	node := New(size)
+	for _, notation := range cells {
+		cell, err := ParseCell(notation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		child, ok := node.Play(cell)
+		if !ok {
+			t.Fatalf("Illegal cell %s\n%s", notation, node)
+		}
+		node = child
+	}
+	return node
+}
+
+func TestWinner(t *testing.T) {
+	// black column b1-b3 against white a1, a2
+	node := play(t, 3, "b1", "a1", "b2", "a2", "b3")
+	if node.Winner() != Black || !node.IsTerminal() || node.Score() != WinScore+4 {
+		t.Errorf("Expected black to win\n%s", node)
+	}
+	if _, ok := node.Play(Cell{2, 2}); ok {
+		t.Error("Expected no move after the end")
+	}
+	// white connects a3, b2 and c1 along the diagonal
+	node = play(t, 3, "a1", "a3", "b1", "b2", "c3", "c1")
+	if node.Winner() != White || node.Score() != -WinScore-3 {
+		t.Errorf("Expected white to win\n%s", node)
+	}
+	if node.Move() != (Cell{2, 0}) || node.At(Cell{1, 1}) != White {
+		t.Errorf("Invalid move %v", node.Move())
+	}
+	expected := "   a b c\n 1 X X O\n  2 . O .\n   3 O . X\n"
+	if node.String() != expected {
+		t.Errorf("Invalid board\n%s", node)
+	}
+	if _, err := ParseCell("z1"); err == nil {
+		t.Error("Expected invalid cell")
+	}
+}
+
+func TestScore(t *testing.T) {
+	node := New(5)
+	if node.distance(Black) != 5 || node.distance(White) != 5 || node.Score() != 0 {
+		t.Errorf("Expected distance 5, got %d %d", node.distance(Black), node.distance(White))
+	}
+	node = play(t, 5, "c3", "c2")
+	if node.distance(Black) != 4 || node.distance(White) != 4 {
+		t.Errorf("Expected distance 4, got %d %d", node.distance(Black), node.distance(White))
+	}
+	// the rotation by 180 degrees
+	if a, b := play(t, 5, "a1"), play(t, 5, "e5"); a.Hash() == b.Hash() || a.CanonicalHash() != b.CanonicalHash() {
+		t.Error("Expected equivalent boards")
+	}
+}
+
+func TestPlayout(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	wins := 0
+	for i := 0; i < 200; i++ {
+		end := New(5).RandomPlayout(true, rng).(Board)
+		if end.empty != 0 || !end.IsTerminal() {
+			t.Fatalf("Expected the full board\n%s", end)
+		}
+		if end.Winner() == Black {
+			wins++
+		}
+	}
+	// the first player has an advantage
+	if wins < 100 || wins > 160 {
+		t.Errorf("Black won %d out of 200 random games", wins)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	// the center of 3x3 wins
+	child, score := csa.Searcher{WinScore: WinScore}.MinimaxAlphaBetaPrunning(New(3), 9, true)
+	if child.(Board).Move() != (Cell{1, 1}) || score <= WinScore/2 {
+		t.Errorf("Expected the center to win, got %v %d", child.(Board).Move(), score)
+	}
+	// black reaches the bottom edge from b3
+	node := play(t, 4, "b1", "d1", "b2", "d2", "b3", "d3")
+	mcts := csa.MCTS{Iterations: 2000, Exploration: WinScore, Rand: rand.New(rand.NewSource(1))}
+	child, _ = mcts.Search(node, true)
+	if move := child.(Board).Move(); move != (Cell{0, 3}) && move != (Cell{1, 3}) {
+		t.Errorf("Expected the winning move, got %v", move)
+	}
+}
@@ -0,0 +1,233 @@
+// Package kalah is the Kalah (Mancala) game of the csa package with configurable pits and seeds
+//
+// South is the maximizing player and moves first. The player picks up all the seeds of one of its pits and sows them
+// one by one counterclockwise into the following pits and its own store, skipping the opponent's store.
+// If the last seed lands in the player's store, the player moves again, the child is a csa.ExtraTurnNode.
+// If it lands in an empty pit of the player and the opposite pit has seeds, both are captured into the store.
+// The game ends once either player has no seeds in its pits, the remaining seeds go to their owner's store
+// and the player with more seeds in the store wins.
+//
+// Pits are numbered 1 to Pits from the player's left, the seeds move from the pit 1 towards the store.
+package kalah
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+const (
+	MaxPits  = 8
+	MaxSeeds = 12
+)
+
+type Side int
+
+const (
+	South Side = iota
+	North
+)
+
+// Kalah(Pits, Seeds), Kalah(6, 4) if zero
+type Rules struct {
+	Pits  int
+	Seeds int // per pit initially
+	// The last seed in an empty pit of the player is captured even if the opposite pit is empty
+	EmptyCapture bool
+}
+
+func (rules Rules) withDefaults() Rules {
+	if rules.Pits == 0 {
+		rules.Pits = 6
+	}
+	if rules.Seeds == 0 {
+		rules.Seeds = 4
+	}
+	return rules
+}
+
+// Board with the seeds of both players, implements csa.SearchNode
+// Intentionally passed by value everywhere
+type Board struct {
+	pits         [2][MaxPits]uint8 // pits[side][pit], pit 0 is the first one sown by the player
+	stores       [2]uint8
+	pitCount     uint8
+	emptyCapture bool
+	side         Side
+	extraTurn    bool // the player who moved moves again
+	lastMove     int  // pit number, 0 for the initial board
+}
+
+// Initial board of the rules, south to move; panics if the rules are invalid
+func New(rules Rules) Board {
+	rules = rules.withDefaults()
+	if rules.Pits < 1 || rules.Pits > MaxPits || rules.Seeds < 1 || rules.Seeds > MaxSeeds {
+		panic(fmt.Sprintf("kalah: invalid rules %+v", rules))
+	}
+	node := Board{pitCount: uint8(rules.Pits), emptyCapture: rules.EmptyCapture}
+	for side := range node.pits {
+		for pit := 0; pit < rules.Pits; pit++ {
+			node.pits[side][pit] = uint8(rules.Seeds)
+		}
+	}
+	return node
+}
+
+// Seeds in the pit of the side, numbered from 1
+func (node Board) Seeds(side Side, pit int) int {
+	return int(node.pits[side][pit-1])
+}
+
+// Seeds in the store of the side
+func (node Board) Store(side Side) int {
+	return int(node.stores[side])
+}
+
+func (node Board) SideToMove() Side {
+	return node.side
+}
+
+func (node Board) PlayerToMove() csa.Player {
+	if node.side == South {
+		return csa.MaximizingPlayer
+	}
+	return csa.MinimizingPlayer
+}
+
+func (node Board) ExtraTurn() bool {
+	return node.extraTurn
+}
+
+// Board after sowing the seeds of the pit of the player to move, false if the pit is empty or the game is over
+func (node Board) Play(pit int) (Board, bool) {
+	pits := int(node.pitCount)
+	if pit < 1 || pit > pits || node.pits[node.side][pit-1] == 0 || node.IsTerminal() {
+		return Board{}, false
+	}
+	player := node.side
+	seeds := int(node.pits[player][pit-1])
+	node.pits[player][pit-1] = 0
+	// positions are the player's pits, its store and the opponent's pits
+	position := pit - 1
+	for ; seeds > 0; seeds-- {
+		position = (position + 1) % (2*pits + 1)
+		switch {
+		case position < pits:
+			node.pits[player][position]++
+		case position == pits:
+			node.stores[player]++
+		default:
+			node.pits[1-player][position-pits-1]++
+		}
+	}
+	node.lastMove, node.extraTurn = pit, position == pits
+	if position < pits && node.pits[player][position] == 1 {
+		opposite := &node.pits[1-player][pits-1-position]
+		if *opposite > 0 || node.emptyCapture {
+			node.stores[player] += *opposite + 1
+			node.pits[player][position], *opposite = 0, 0
+		}
+	}
+	if !node.extraTurn {
+		node.side = 1 - player
+	}
+	if node.sideEmpty(South) || node.sideEmpty(North) {
+		// the remaining seeds go to their owner
+		for side := range node.pits {
+			for i := 0; i < pits; i++ {
+				node.stores[side] += node.pits[side][i]
+				node.pits[side][i] = 0
+			}
+		}
+		node.extraTurn = false
+	}
+	return node, true
+}
+
+func (node Board) sideEmpty(side Side) bool {
+	for _, seeds := range node.pits[side][:node.pitCount] {
+		if seeds > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (node Board) IsTerminal() bool {
+	return node.sideEmpty(South) && node.sideEmpty(North)
+}
+
+// Difference of the stores from south's point of view, the final one at the end of the game
+func (node Board) Score() int {
+	return int(node.stores[South]) - int(node.stores[North])
+}
+
+// Pits from the store's side first, the extra turns are the most promising moves
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	pit := int(node.pitCount)
+	return func(maximizing bool) csa.SearchNode {
+		for ; pit >= 1; pit-- {
+			if child, ok := node.Play(pit); ok {
+				pit--
+				return child
+			}
+		}
+		return nil
+	}
+}
+
+// Pit number of the move which created this board, nil for the initial board
+func (node Board) Move() csa.Move {
+	if node.lastMove == 0 {
+		return nil
+	}
+	return node.lastMove
+}
+
+func (node Board) Hash() uint64 {
+	h := uint64(14695981039346656037)
+	for side := range node.pits {
+		for _, seeds := range node.pits[side][:node.pitCount] {
+			h = (h ^ uint64(seeds)) * 1099511628211
+		}
+		h = (h ^ uint64(node.stores[side])) * 1099511628211
+	}
+	return h ^ uint64(node.side)
+}
+
+// North's pits from its last one on the top line, the stores and south's pits from its first one, e.g.
+//
+//	   4  4  4  4  4  4
+//	0                    0
+//	   4  4  4  4  4  4
+func (node Board) String() string {
+	pits := int(node.pitCount)
+	north, south := make([]string, pits), make([]string, pits)
+	for i := 0; i < pits; i++ {
+		north[i] = fmt.Sprintf("%3d", node.pits[North][pits-1-i])
+		south[i] = fmt.Sprintf("%3d", node.pits[South][i])
+	}
+	gap := strings.Repeat(" ", 3*pits+1)
+	return fmt.Sprintf("  %s\n%-2d%s%d\n  %s\n", strings.Join(north, ""), node.stores[North], gap,
+		node.stores[South], strings.Join(south, ""))
+}
+
+// Exact solution of the board by alpha-beta searching to the end of the game, practical for the small rules
+// Returns the best child and the final store difference from south's point of view with the best play.
+func Solve(s csa.Searcher, node Board) (csa.SearchNode, int) {
+	s.WinScore = 0
+	return s.MinimaxAlphaBetaPrunning(node, math.MaxInt32, node.side == South)
+}
+
+// Pit numbers of the legal moves of the player to move
+func (node Board) Moves() []int {
+	var moves []int
+	for pit := 1; pit <= int(node.pitCount); pit++ {
+		if _, ok := node.Play(pit); ok {
+			moves = append(moves, pit)
+		}
+	}
+	return moves
+}
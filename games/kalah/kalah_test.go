@@ -0,0 +1,96 @@
+package kalah
+
+import (
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Board after the pits played
+func play(t *testing.T, rules Rules, pits ...int) Board {
+	node := New(rules)
+	for _, pit := range pits {
+		child, ok := node.Play(pit)
+		if !ok {
+			t.Fatalf("Illegal pit %d\n%s", pit, node)
+		}
+		node = child
+	}
+	return node
+}
+
+func TestSowing(t *testing.T) {
+	// the last seed in the store
+	node := play(t, Rules{}, 3)
+	if !node.ExtraTurn() || node.SideToMove() != South || node.Store(South) != 1 || node.Seeds(South, 6) != 5 {
+		t.Errorf("Expected extra turn\n%s", node)
+	}
+	node = play(t, Rules{}, 3, 6)
+	if node.ExtraTurn() || node.SideToMove() != North || node.Seeds(North, 4) != 5 || node.Move() != 6 {
+		t.Errorf("Expected north to move\n%s", node)
+	}
+	if _, ok := node.Play(7); ok {
+		t.Error("Expected invalid pit")
+	}
+	// the opponent's store is skipped
+	node = play(t, Rules{Pits: 2, Seeds: 6}, 1)
+	if node.Store(North) != 0 || node.Seeds(South, 1) != 1 || node.Seeds(South, 2) != 8 || node.Store(South) != 1 {
+		t.Errorf("Invalid sowing\n%s", node)
+	}
+	expected := "    4  4  4  4  4  4\n0                    0\n    4  4  4  4  4  4\n"
+	if New(Rules{}).String() != expected {
+		t.Errorf("Invalid board\n%s", New(Rules{}))
+	}
+}
+
+func TestCapture(t *testing.T) {
+	node := New(Rules{Pits: 3, Seeds: 1})
+	node.pits[South] = [MaxPits]uint8{1, 0, 1}
+	node.pits[North] = [MaxPits]uint8{2, 3, 0}
+	// the last seed in the empty pit 2 captures the opposite seeds of north's pit 2
+	if child, _ := node.Play(1); child.Store(South) != 4 || child.Seeds(South, 2) != 0 || child.Seeds(North, 2) != 0 {
+		t.Errorf("Expected capture\n%s", child)
+	}
+	node.pits[North] = [MaxPits]uint8{2, 0, 3}
+	if child, _ := node.Play(1); child.Store(South) != 0 || child.Seeds(South, 2) != 1 {
+		t.Errorf("Expected no capture of the empty pit\n%s", child)
+	}
+	node.emptyCapture = true
+	if child, _ := node.Play(1); child.Store(South) != 1 || child.Seeds(South, 2) != 0 {
+		t.Errorf("Expected capture of the last seed\n%s", child)
+	}
+	// south's side is emptied, north gets its remaining seeds
+	node = New(Rules{Pits: 2, Seeds: 1})
+	node.pits[South] = [MaxPits]uint8{0, 1}
+	child, _ := node.Play(2)
+	if !child.IsTerminal() || child.Store(North) != 2 || child.Score() != -1 || child.ExtraTurn() {
+		t.Errorf("Expected the end of the game\n%s", child)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	// results of the perfect play of Kalah(pits, seeds) for the first player
+	results := map[Rules]string{
+		{Pits: 1, Seeds: 1}: "draw", {Pits: 1, Seeds: 2}: "loss", {Pits: 1, Seeds: 3}: "win", {Pits: 1, Seeds: 4}: "loss",
+		{Pits: 2, Seeds: 1}: "win", {Pits: 2, Seeds: 2}: "loss", {Pits: 2, Seeds: 3}: "loss", {Pits: 2, Seeds: 4}: "loss",
+		{Pits: 3, Seeds: 1}: "draw", {Pits: 3, Seeds: 2}: "win", {Pits: 3, Seeds: 3}: "win", {Pits: 3, Seeds: 4}: "win",
+		{Pits: 4, Seeds: 1}: "win", {Pits: 4, Seeds: 2}: "win", {Pits: 4, Seeds: 3}: "win",
+	}
+	for rules, expected := range results {
+		child, score := Solve(csa.Searcher{}, New(rules))
+		result := "draw"
+		if score > 0 {
+			result = "win"
+		} else if score < 0 {
+			result = "loss"
+		}
+		if result != expected || child == nil {
+			t.Errorf("Kalah(%d, %d) is a %s, got %d", rules.Pits, rules.Seeds, expected, score)
+		}
+	}
+	// the extra turns keep the player, the first two moves are by south
+	_, score := Solve(csa.Searcher{}, play(t, Rules{Pits: 3, Seeds: 3}, 1))
+	if _, expected := csa.Minimax(play(t, Rules{Pits: 3, Seeds: 3}, 1), 100, true); score != expected {
+		t.Errorf("Alpha-beta %d differs from minimax %d", score, expected)
+	}
+}
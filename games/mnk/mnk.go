@@ -0,0 +1,377 @@
+// Package mnk is the m,n,k-game of the csa package, tic-tac-toe (3,3,3), gomoku (15,15,5) and the others
+//
+// Players alternately place their stones on the empty squares of the m x n board, the first one to get k stones
+// in a row, a column or a diagonal wins. The full board without such a line is a draw.
+// X is the maximizing player and places the first stone.
+//
+// Children are generated in the order of the threats they make, the winning squares first, then the ones blocking
+// the opponent's win and then by the lines through the square. With Rules.Radius only the squares near the stones
+// are generated which keeps the branching factor of gomoku low.
+package mnk
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+const (
+	// Score of the won board is WinScore plus the number of empty squares, so the faster wins are preferred,
+	// the heuristic scores of the other boards stay below it. Use it as csa.Searcher's WinScore.
+	WinScore = 1 << 20
+
+	// color indices
+	cross  = 0
+	nought = 1
+
+	// largest board, e.g. 19x19
+	maxSize    = 19
+	maxSquares = 384
+
+	// last move of the empty board
+	noMove = -1
+)
+
+type Color int
+
+const (
+	X Color = cross
+	O Color = nought
+)
+
+// Size of the board and the length of the winning line
+type Rules struct {
+	M, N, K int // columns, rows and the winning line, 3 by default
+	Radius  int // children only on the empty squares at most Radius squares from a stone, all empty squares if zero
+}
+
+// Bit per square indexed by rows
+type bitboard [maxSquares / 64]uint64
+
+func (b bitboard) isBit(index int) bool {
+	return b[index/64]&(1<<(index%64)) != 0
+}
+
+func (b bitboard) setBit(index int) bitboard {
+	b[index/64] |= 1 << (index % 64)
+	return b
+}
+
+func (b bitboard) and(other bitboard) bitboard {
+	for i := range b {
+		b[i] &= other[i]
+	}
+	return b
+}
+
+func (b bitboard) count() int {
+	count := 0
+	for _, word := range b {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// Lines of the rules shared by their boards
+type geometry struct {
+	rules   Rules
+	windows []bitboard // all the lines of K squares, scored by the evaluation
+}
+
+// Directions of the lines, (dx, dy)
+var directions = [4][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+
+func newGeometry(rules Rules) *geometry {
+	g := &geometry{rules: rules}
+	for y := 0; y < rules.N; y++ {
+		for x := 0; x < rules.M; x++ {
+			for _, dir := range directions {
+				var window bitboard
+				for i := 0; i < rules.K; i++ {
+					wx, wy := x+i*dir[0], y+i*dir[1]
+					if !g.inBoard(wx, wy) {
+						window = bitboard{}
+						break
+					}
+					window = window.setBit(wy*rules.M + wx)
+				}
+				if window != (bitboard{}) {
+					g.windows = append(g.windows, window)
+				}
+			}
+		}
+	}
+	return g
+}
+
+func (g *geometry) inBoard(x, y int) bool {
+	return x >= 0 && x < g.rules.M && y >= 0 && y < g.rules.N
+}
+
+// Board with the stones of both players, implements csa.SearchNode
+// Intentionally passed by value everywhere
+type Board struct {
+	geometry *geometry
+	stones   [2]bitboard // stones[color]
+	count    int         // of the stones
+	lastMove int         // square of the last stone, noMove for the empty board
+	winner   int         // color which made the line, -1 if none
+}
+
+// Empty board of the rules, panics if the board is larger than 19x19 or the line does not fit in it
+func New(rules Rules) Board {
+	for _, value := range []*int{&rules.M, &rules.N, &rules.K} {
+		if *value == 0 {
+			*value = 3
+		}
+	}
+	if rules.M < 1 || rules.N < 1 || rules.M > maxSize || rules.N > maxSize || rules.K < 1 || rules.K > max(rules.M, rules.N) {
+		panic(fmt.Sprintf("mnk: invalid rules %+v", rules))
+	}
+	return Board{geometry: newGeometry(rules), lastMove: noMove, winner: -1}
+}
+
+func (node Board) Rules() Rules {
+	return node.geometry.rules
+}
+
+func (node Board) squares() int {
+	return node.geometry.rules.M * node.geometry.rules.N
+}
+
+// Player to move, X after an even number of stones
+func (node Board) ToMove() Color {
+	return Color(node.count % 2)
+}
+
+// Color of the stone on the square, false if empty
+func (node Board) At(square int) (Color, bool) {
+	switch {
+	case node.stones[cross].isBit(square):
+		return X, true
+	case node.stones[nought].isBit(square):
+		return O, true
+	}
+	return 0, false
+}
+
+// Winner of the board, false if nobody made the line
+func (node Board) Winner() (Color, bool) {
+	return Color(node.winner), node.winner >= 0
+}
+
+// Board after the player to move places its stone on the square, false if the square is not empty or the game ended
+func (node Board) Play(square int) (Board, bool) {
+	if square < 0 || square >= node.squares() || node.IsTerminal() {
+		return Board{}, false
+	}
+	if _, ok := node.At(square); ok {
+		return Board{}, false
+	}
+	return node.place(int(node.ToMove()), square), true
+}
+
+func (node Board) place(color, square int) Board {
+	node.stones[color] = node.stones[color].setBit(square)
+	node.count++
+	node.lastMove = square
+	for _, dir := range directions {
+		if node.line(color, square, dir)+1 >= node.geometry.rules.K {
+			node.winner = color
+		}
+	}
+	return node
+}
+
+// Stones of the color next to the square in both ways of the direction
+func (node Board) line(color, square int, dir [2]int) int {
+	m := node.geometry.rules.M
+	length := 0
+	for _, sign := range []int{1, -1} {
+		x, y := square%m+sign*dir[0], square/m+sign*dir[1]
+		for ; node.geometry.inBoard(x, y) && node.stones[color].isBit(y*m+x); x, y = x+sign*dir[0], y+sign*dir[1] {
+			length++
+		}
+	}
+	return length
+}
+
+func (node Board) IsTerminal() bool {
+	return node.winner >= 0 || node.count == node.squares()
+}
+
+func (node Board) IsDraw() bool {
+	return node.winner < 0 && node.count == node.squares()
+}
+
+// Sum over the lines of K squares with the stones of a single color, 4^stones each
+func (node Board) Score() int {
+	if node.winner >= 0 {
+		score := WinScore + node.squares() - node.count
+		if node.winner == nought {
+			return -score
+		}
+		return score
+	}
+	score := 0
+	for _, window := range node.geometry.windows {
+		xs, os := node.stones[cross].and(window).count(), node.stones[nought].and(window).count()
+		if os == 0 && xs > 0 {
+			score += 1 << (2 * (xs - 1))
+		} else if xs == 0 && os > 0 {
+			score -= 1 << (2 * (os - 1))
+		}
+	}
+	return score
+}
+
+// Threat the player makes by the stone on the square, the win and the block of the opponent's win are the highest
+func (node Board) threat(color, square int) int {
+	k := node.geometry.rules.K
+	threat := 0
+	for _, dir := range directions {
+		own, opponent := node.line(color, square, dir), node.line(1-color, square, dir)
+		switch {
+		case own+1 >= k:
+			threat += 1 << 24
+		case opponent+1 >= k:
+			threat += 1 << 20
+		default:
+			threat += 1<<(2*own) + 1<<(2*opponent) - 2
+		}
+	}
+	return threat
+}
+
+// Empty squares of the children, all of them or the ones near the stones with Radius
+func (node Board) candidates() []int {
+	var candidates []int
+	radius, m := node.geometry.rules.Radius, node.geometry.rules.M
+	if radius <= 0 || node.count == 0 {
+		for square := 0; square < node.squares(); square++ {
+			if _, ok := node.At(square); !ok {
+				candidates = append(candidates, square)
+			}
+		}
+		return candidates
+	}
+	var near bitboard
+	for square := 0; square < node.squares(); square++ {
+		if _, ok := node.At(square); !ok {
+			continue
+		}
+		for y := square/m - radius; y <= square/m+radius; y++ {
+			for x := square%m - radius; x <= square%m+radius; x++ {
+				if node.geometry.inBoard(x, y) {
+					near = near.setBit(y*m + x)
+				}
+			}
+		}
+	}
+	for square := 0; square < node.squares(); square++ {
+		if _, ok := node.At(square); !ok && near.isBit(square) {
+			candidates = append(candidates, square)
+		}
+	}
+	return candidates
+}
+
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	var candidates []int
+	started := false
+	color := 0
+	return func(maximizing bool) csa.SearchNode {
+		if !started {
+			started = true
+			if node.IsTerminal() {
+				return nil
+			}
+			color = nought
+			if maximizing {
+				color = cross
+			}
+			candidates = node.candidates()
+			threats := make(map[int]int, len(candidates))
+			for _, square := range candidates {
+				threats[square] = node.threat(color, square)
+			}
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return threats[candidates[i]] > threats[candidates[j]]
+			})
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+		square := candidates[0]
+		candidates = candidates[1:]
+		return node.place(color, square)
+	}
+}
+
+func (node Board) Hash() uint64 {
+	hash := uint64(14695981039346656037)
+	for _, stones := range node.stones {
+		for _, word := range stones {
+			hash = (hash ^ word) * 1099511628211
+		}
+	}
+	return hash
+}
+
+// Square of the notation, e.g. b2 for the center of tic-tac-toe, -1 if invalid
+// Columns are letters from a, rows are numbered from 1 at the bottom.
+func (node Board) Square(notation string) int {
+	if len(notation) < 2 {
+		return -1
+	}
+	col := int(notation[0] - 'a')
+	row, err := strconv.Atoi(notation[1:])
+	if err != nil || !node.geometry.inBoard(col, node.geometry.rules.N-row) {
+		return -1
+	}
+	return (node.geometry.rules.N-row)*node.geometry.rules.M + col
+}
+
+// Square in the notation, e.g. b2
+func (node Board) Notation(square int) string {
+	m, n := node.geometry.rules.M, node.geometry.rules.N
+	return fmt.Sprintf("%c%d", 'a'+square%m, n-square/m)
+}
+
+// Square of the last stone in the notation, nil for the empty board
+func (node Board) Move() csa.Move {
+	if node.lastMove == noMove {
+		return nil
+	}
+	return node.Notation(node.lastMove)
+}
+
+func (node Board) String() string {
+	m, n := node.geometry.rules.M, node.geometry.rules.N
+	sb := strings.Builder{}
+	for y := 0; y < n; y++ {
+		fmt.Fprintf(&sb, "%2d", n-y)
+		for col := 0; col < m; col++ {
+			color, ok := node.At(y*m + col)
+			switch {
+			case !ok:
+				sb.WriteString(" _")
+			case color == X:
+				sb.WriteString(" X")
+			default:
+				sb.WriteString(" O")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("  ")
+	for col := 0; col < m; col++ {
+		fmt.Fprintf(&sb, " %c", 'a'+col)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package mnk
+
+import (
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Board after the moves in the notation
+func play(t *testing.T, rules Rules, moves ...string) Board {
+	node := New(rules)
+	for _, move := range moves {
+		child, ok := node.Play(node.Square(move))
+		if !ok {
+			t.Fatalf("Illegal move %s\n%s", move, node)
+		}
+		node = child
+	}
+	return node
+}
+
+func TestString(t *testing.T) {
+	node := play(t, Rules{}, "b2", "a3")
+	expected := " 3 O _ _\n 2 _ X _\n 1 _ _ _\n   a b c\n"
+	if node.String() != expected {
+		t.Errorf("Invalid board\n%s", node)
+	}
+	if node.ToMove() != X || node.Move() != "a3" || node.Square("d1") != -1 || node.Square("a4") != -1 {
+		t.Error("Invalid move or notation")
+	}
+	if _, ok := node.Play(node.Square("b2")); ok {
+		t.Error("Square is occupied")
+	}
+}
+
+func TestRules(t *testing.T) {
+	if rules := New(Rules{}).Rules(); rules != (Rules{M: 3, N: 3, K: 3}) {
+		t.Errorf("Expected tic-tac-toe, got %+v", rules)
+	}
+	for _, rules := range []Rules{{M: 20}, {M: 3, N: 3, K: 4}, {M: -1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected invalid rules %+v", rules)
+				}
+			}()
+			New(rules)
+		}()
+	}
+}
+
+func TestWinner(t *testing.T) {
+	gomoku := Rules{M: 15, N: 15, K: 5}
+	for _, moves := range [][]string{
+		{"a1", "a2", "b1", "b2", "c1"}, // row
+		{"a1", "b1", "a2", "b2", "a3"}, // column
+		{"a1", "b1", "b2", "c1", "c3"}, // diagonal
+		{"a3", "a1", "b2", "b1", "c1"}, // anti-diagonal
+	} {
+		node := play(t, Rules{}, moves...)
+		if winner, ok := node.Winner(); !ok || winner != X || !node.IsTerminal() || node.Score() != WinScore+4 {
+			t.Errorf("Expected X win\n%s", node)
+		}
+	}
+	node := play(t, gomoku, "h8", "a1", "i8", "a2", "j8", "a3", "k8", "a4")
+	if _, ok := node.Winner(); ok {
+		t.Error("Four stones do not win")
+	}
+	if node, _ := node.Play(node.Square("l8")); !node.IsTerminal() {
+		t.Error("Five stones win")
+	}
+	// lines do not wrap around the edges
+	node = play(t, Rules{M: 4, N: 4, K: 3}, "d4", "a1", "a3", "b1")
+	if _, ok := node.Winner(); ok {
+		t.Errorf("Unexpected winner\n%s", node)
+	}
+	draw := play(t, Rules{}, "a1", "b2", "c3", "a2", "c2", "c1", "a3", "b3", "b1")
+	if !draw.IsDraw() || !draw.IsTerminal() || draw.SearchNodeGenerator()(true) != nil {
+		t.Errorf("Expected draw\n%s", draw)
+	}
+}
+
+func TestThreatOrdering(t *testing.T) {
+	// X wins at c1, O threatens a3
+	node := play(t, Rules{}, "a1", "b2", "b1", "a2")
+	generator := node.SearchNodeGenerator()
+	if first := generator(true).(Board); first.Move() != "c1" {
+		t.Errorf("Expected winning move first, got %v", first.Move())
+	}
+	if second := generator(true).(Board); second.Move() != "c2" {
+		t.Errorf("Expected block second, got %v", second.Move())
+	}
+	// radius limits the children to the neighbourhood of the stones
+	node = play(t, Rules{M: 15, N: 15, K: 5, Radius: 1}, "h8")
+	children := 0
+	generator = node.SearchNodeGenerator()
+	for child := generator(false); child != nil; child = generator(false) {
+		children++
+	}
+	if children != 8 {
+		t.Errorf("Expected 8 children, got %d", children)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	// tic-tac-toe is a draw
+	if _, score := csa.MinimaxAlphaBetaPrunning(New(Rules{}), 9, true); score != 0 {
+		t.Errorf("Expected draw, got %d", score)
+	}
+	// O has to block the open three of gomoku
+	node := play(t, Rules{M: 9, N: 9, K: 5, Radius: 2}, "e5", "a1", "f5", "a2", "g5")
+	child, _ := csa.Searcher{WinScore: WinScore}.MinimaxAlphaBetaPrunning(node, 2, false)
+	if move := child.(Board).Move(); move != "d5" && move != "h5" {
+		t.Errorf("Expected block of the three, got %v", move)
+	}
+}
@@ -0,0 +1,185 @@
+// Package nim is the game of Nim of the csa package, the smallest example of a SearchNode
+//
+// Players alternately take any positive number of stones from a single pile. In the normal play the player
+// who takes the last stone wins, in the misère play it loses. The maximizing player moves first.
+//
+// Nim is solved, Winning and Optimal play it perfectly by the nim-sum, so the search can be checked against them.
+package nim
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Stones taken from the pile, numbered from 1 in the notation
+type Move struct {
+	Pile, Take int
+}
+
+// Pile and the taken stones, e.g. 2:3
+func (move Move) String() string {
+	return fmt.Sprintf("%d:%d", move.Pile+1, move.Take)
+}
+
+// Board with the piles of stones, implements csa.SearchNode
+// Intentionally passed by value everywhere, the piles are never modified.
+type Board struct {
+	piles      []int
+	misere     bool
+	maximizing bool // player to move
+	lastMove   *Move
+}
+
+// Board of the piles with the maximizing player to move, misère play if set
+func New(misere bool, piles ...int) Board {
+	for _, pile := range piles {
+		if pile < 0 {
+			panic(fmt.Sprintf("nim: negative pile %d", pile))
+		}
+	}
+	return Board{piles: slices.Clone(piles), misere: misere, maximizing: true}
+}
+
+func (node Board) Piles() []int {
+	return slices.Clone(node.piles)
+}
+
+func (node Board) Misere() bool {
+	return node.misere
+}
+
+func (node Board) PlayerToMove() csa.Player {
+	if node.maximizing {
+		return csa.MaximizingPlayer
+	}
+	return csa.MinimizingPlayer
+}
+
+// Board after the move of the player to move, false if the move is illegal
+func (node Board) Play(move Move) (Board, bool) {
+	if move.Pile < 0 || move.Pile >= len(node.piles) || move.Take < 1 || move.Take > node.piles[move.Pile] {
+		return Board{}, false
+	}
+	node.piles = slices.Clone(node.piles)
+	node.piles[move.Pile] -= move.Take
+	node.maximizing = !node.maximizing
+	node.lastMove = &move
+	return node, true
+}
+
+func (node Board) IsTerminal() bool {
+	for _, pile := range node.piles {
+		if pile > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// 1 if the maximizing player won, -1 if it lost and 0 for the unfinished game
+func (node Board) Score() int {
+	if !node.IsTerminal() {
+		return 0
+	}
+	// the player to move did not take the last stone
+	if node.maximizing != node.misere {
+		return -1
+	}
+	return 1
+}
+
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	pile, take := 0, 1
+	return func(maximizing bool) csa.SearchNode {
+		for ; pile < len(node.piles); pile, take = pile+1, 1 {
+			if take <= node.piles[pile] {
+				child, _ := node.Play(Move{pile, take})
+				take++
+				return child
+			}
+		}
+		return nil
+	}
+}
+
+func (node Board) Hash() uint64 {
+	hash := uint64(14695981039346656037)
+	if node.maximizing {
+		hash ^= 1
+	}
+	for _, pile := range node.piles {
+		hash = (hash ^ uint64(pile)) * 1099511628211
+	}
+	return hash
+}
+
+// Hash of the sorted piles, the order of the piles does not matter
+func (node Board) CanonicalHash() uint64 {
+	sorted := node
+	sorted.piles = slices.Clone(node.piles)
+	slices.Sort(sorted.piles)
+	return sorted.Hash()
+}
+
+// Move which created this board, nim.Move, nil for the initial board
+func (node Board) Move() csa.Move {
+	if node.lastMove == nil {
+		return nil
+	}
+	return *node.lastMove
+}
+
+func (node Board) String() string {
+	piles := make([]string, len(node.piles))
+	for i, pile := range node.piles {
+		piles[i] = strconv.Itoa(pile)
+	}
+	if node.misere {
+		return strings.Join(piles, " ") + " (misère)"
+	}
+	return strings.Join(piles, " ")
+}
+
+func (node Board) nimSum() int {
+	sum := 0
+	for _, pile := range node.piles {
+		sum ^= pile
+	}
+	return sum
+}
+
+// Whether the player to move wins with the perfect play
+// The nim-sum decides, except for the misère play with no pile larger than one, where the even number of piles wins.
+func (node Board) Winning() bool {
+	large, ones := 0, 0
+	for _, pile := range node.piles {
+		if pile > 1 {
+			large++
+		} else if pile == 1 {
+			ones++
+		}
+	}
+	if node.misere && large == 0 {
+		return ones%2 == 0
+	}
+	return node.nimSum() != 0
+}
+
+// Move of the perfect play, false if the player to move loses anyway or the game ended
+func (node Board) Optimal() (Move, bool) {
+	if node.IsTerminal() || !node.Winning() {
+		return Move{}, false
+	}
+	for pile, stones := range node.piles {
+		for take := 1; take <= stones; take++ {
+			if child, _ := node.Play(Move{pile, take}); !child.Winning() {
+				return Move{pile, take}, true
+			}
+		}
+	}
+	return Move{}, false
+}
@@ -0,0 +1,67 @@
+package nim
+
+import (
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+func TestPlay(t *testing.T) {
+	node := New(false, 3, 4, 5)
+	child, ok := node.Play(Move{Pile: 1, Take: 4})
+	if !ok || child.String() != "3 0 5" || child.Move().(Move).String() != "2:4" || child.PlayerToMove() != csa.MinimizingPlayer {
+		t.Errorf("Invalid board %s", child)
+	}
+	if node.String() != "3 4 5" {
+		t.Error("Parent was modified")
+	}
+	for _, move := range []Move{{-1, 1}, {3, 1}, {0, 0}, {0, 4}} {
+		if _, ok := node.Play(move); ok {
+			t.Errorf("Expected illegal move %v", move)
+		}
+	}
+	if New(true, 1).String() != "1 (misère)" {
+		t.Error("Invalid misère board")
+	}
+}
+
+func TestScore(t *testing.T) {
+	// the maximizing player took the last stone
+	node, _ := New(false, 1).Play(Move{0, 1})
+	if !node.IsTerminal() || node.Score() != 1 {
+		t.Error("Expected win of the last player")
+	}
+	node, _ = New(true, 1).Play(Move{0, 1})
+	if node.Score() != -1 {
+		t.Error("Expected misère loss of the last player")
+	}
+	if New(false, 3, 0, 5).CanonicalHash() != New(false, 5, 3, 0).CanonicalHash() {
+		t.Error("Order of the piles does not matter")
+	}
+}
+
+func TestPerfectPlay(t *testing.T) {
+	for _, misere := range []bool{false, true} {
+		for a := 0; a <= 4; a++ {
+			for b := 0; b <= 3; b++ {
+				for c := 0; c <= 3; c++ {
+					node := New(misere, a, b, c)
+					if node.IsTerminal() {
+						continue
+					}
+					_, score := csa.MinimaxAlphaBetaPrunning(node, a+b+c, true)
+					if (score > 0) != node.Winning() {
+						t.Errorf("Search scored %d, theory wins %v of %s", score, node.Winning(), node)
+					}
+					move, ok := node.Optimal()
+					if ok != node.Winning() {
+						t.Errorf("Expected optimal move of %s", node)
+					}
+					if child, _ := node.Play(move); ok && child.Winning() {
+						t.Errorf("Optimal move %v of %s leaves a win to the opponent", move, node)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Package npuzzle is the sliding puzzle of the csa package, e.g. the 8-puzzle or the 15-puzzle, a StateNode of AStar
+//
+// Numbered tiles slide into the blank square of the square board, the goal has the tiles in the order by rows
+// with the blank in the bottom right corner. Every slide costs one, the Manhattan distance of the tiles
+// to their goal squares is the admissible and consistent heuristic.
+package npuzzle
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Max size of the board, the hash of the 15-puzzle is exact
+const MaxSize = 4
+
+// Number of the tile slid into the blank
+type Move int
+
+func (move Move) String() string {
+	return strconv.Itoa(int(move))
+}
+
+// Board of the tiles, zero is the blank, implements csa.StateNode, csa.HeuristicNode and csa.HashNode
+// Intentionally passed by value everywhere.
+type Board struct {
+	tiles    [MaxSize * MaxSize]int8
+	size     int
+	blank    int // index of the blank
+	lastMove Move
+}
+
+// Solved board of given size
+func New(size int) Board {
+	if size < 2 || size > MaxSize {
+		panic(fmt.Sprintf("npuzzle: invalid size %d", size))
+	}
+	node := Board{size: size, blank: size*size - 1}
+	for i := 0; i < size*size-1; i++ {
+		node.tiles[i] = int8(i + 1)
+	}
+	return node
+}
+
+// Board of the tiles by rows separated by spaces, e.g. "1 2 3 4 5 6 0 7 8" of the 8-puzzle
+func Parse(tiles string) (Board, error) {
+	fields := strings.Fields(tiles)
+	size := 2
+	for size*size < len(fields) {
+		size++
+	}
+	if size*size != len(fields) || size > MaxSize {
+		return Board{}, fmt.Errorf("npuzzle: invalid number of tiles %d", len(fields))
+	}
+	node := Board{size: size}
+	seen := make([]bool, len(fields))
+	for i, field := range fields {
+		tile, err := strconv.Atoi(field)
+		if err != nil || tile < 0 || tile >= len(fields) || seen[tile] {
+			return Board{}, fmt.Errorf("npuzzle: invalid tile %q", field)
+		}
+		seen[tile] = true
+		node.tiles[i] = int8(tile)
+		if tile == 0 {
+			node.blank = i
+		}
+	}
+	return node, nil
+}
+
+// Board after sliding the tile into the blank, false if the tile is not next to it
+func (node Board) Play(move Move) (Board, bool) {
+	for _, index := range node.around() {
+		if int(node.tiles[index]) == int(move) {
+			node.tiles[node.blank], node.tiles[index] = node.tiles[index], 0
+			node.blank, node.lastMove = index, move
+			return node, true
+		}
+	}
+	return node, false
+}
+
+// Indices of the squares next to the blank
+func (node Board) around() []int {
+	row, column := node.blank/node.size, node.blank%node.size
+	indices := make([]int, 0, 4)
+	if row > 0 {
+		indices = append(indices, node.blank-node.size)
+	}
+	if row < node.size-1 {
+		indices = append(indices, node.blank+node.size)
+	}
+	if column > 0 {
+		indices = append(indices, node.blank-1)
+	}
+	if column < node.size-1 {
+		indices = append(indices, node.blank+1)
+	}
+	return indices
+}
+
+func (node Board) Neighbors() []csa.Neighbor {
+	indices := node.around()
+	neighbors := make([]csa.Neighbor, 0, len(indices))
+	for _, index := range indices {
+		if child, _ := node.Play(Move(node.tiles[index])); child.lastMove != node.lastMove {
+			neighbors = append(neighbors, csa.Neighbor{Node: child, Cost: 1})
+		}
+	}
+	return neighbors
+}
+
+func (node Board) IsGoal() bool {
+	for i := 0; i < node.size*node.size-1; i++ {
+		if int(node.tiles[i]) != i+1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Manhattan distance of the tiles to their goal squares
+func (node Board) Heuristic() int {
+	distance := 0
+	for i := 0; i < node.size*node.size; i++ {
+		if tile := int(node.tiles[i]); tile != 0 {
+			goal := tile - 1
+			distance += abs(i/node.size-goal/node.size) + abs(i%node.size-goal%node.size)
+		}
+	}
+	return distance
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Tiles packed by four bits, the same for the boards of the same tiles
+func (node Board) Hash() uint64 {
+	var hash uint64
+	for i := 0; i < node.size*node.size; i++ {
+		hash = hash<<4 | uint64(node.tiles[i])
+	}
+	return hash
+}
+
+// Last slid tile, zero of the parsed and new boards
+func (node Board) Move() csa.Move {
+	return node.lastMove
+}
+
+func (node Board) Size() int {
+	return node.size
+}
+
+// Whether the goal can be reached, only half of the boards can
+func (node Board) Solvable() bool {
+	inversions := 0
+	for i := 0; i < node.size*node.size; i++ {
+		for j := i + 1; j < node.size*node.size; j++ {
+			if node.tiles[i] != 0 && node.tiles[j] != 0 && node.tiles[i] > node.tiles[j] {
+				inversions++
+			}
+		}
+	}
+	if node.size%2 == 1 {
+		return inversions%2 == 0
+	}
+	// the rows of the blank from the bottom change the parity on the even boards
+	return (inversions+node.size-1-node.blank/node.size)%2 == 0
+}
+
+// Board after the random slides, always solvable
+func (node Board) Shuffle(slides int, rnd *rand.Rand) Board {
+	for i := 0; i < slides; i++ {
+		indices := node.around()
+		node, _ = node.Play(Move(node.tiles[indices[rnd.Intn(len(indices))]]))
+	}
+	node.lastMove = 0
+	return node
+}
+
+func (node Board) String() string {
+	width := len(strconv.Itoa(node.size*node.size - 1))
+	sb := strings.Builder{}
+	for i := 0; i < node.size*node.size; i++ {
+		if i > 0 && i%node.size == 0 {
+			sb.WriteString("\n")
+		} else if i > 0 {
+			sb.WriteString(" ")
+		}
+		if node.tiles[i] == 0 {
+			sb.WriteString(strings.Repeat(".", width))
+		} else {
+			fmt.Fprintf(&sb, "%*d", width, node.tiles[i])
+		}
+	}
+	return sb.String()
+}
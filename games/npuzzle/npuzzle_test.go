@@ -0,0 +1,99 @@
+package npuzzle
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+func TestPlay(t *testing.T) {
+	node, err := Parse("1 2 3 4 5 6 0 7 8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.IsGoal() || node.Heuristic() != 2 || !node.Solvable() || len(node.Neighbors()) != 2 {
+		t.Errorf("Unexpected board\n%s", node)
+	}
+	child, ok := node.Play(7)
+	if !ok || child.String() != "1 2 3\n4 5 6\n7 . 8" || child.Move() != Move(7) || len(child.Neighbors()) != 2 {
+		t.Errorf("Unexpected child\n%s", child)
+	}
+	if _, ok := node.Play(5); ok {
+		t.Error("Expected the illegal slide of a tile not next to the blank")
+	}
+	if goal, _ := child.Play(8); !goal.IsGoal() || goal.Hash() != New(3).Hash() || goal.Heuristic() != 0 {
+		t.Errorf("Expected the goal\n%s", goal)
+	}
+	for _, tiles := range []string{"1 2 3", "1 2 3 4 5 6 7 8 8", "1 2 x 0", "0 1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24"} {
+		if _, err := Parse(tiles); err == nil {
+			t.Errorf("Expected the invalid board %s", tiles)
+		}
+	}
+	// swapped tiles
+	for _, tiles := range []string{"2 1 3 4 5 6 7 8 0", "1 2 3 4 5 6 7 8 9 10 11 12 13 15 14 0"} {
+		if node, _ := Parse(tiles); node.Solvable() {
+			t.Errorf("Expected the unsolvable board\n%s", node)
+		}
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		if node := New(4).Shuffle(50, rnd); !node.Solvable() {
+			t.Fatalf("Expected the solvable shuffled board\n%s", node)
+		}
+	}
+}
+
+func TestAStar(t *testing.T) {
+	// one of the hardest 8-puzzles
+	node, _ := Parse("8 6 7 2 5 4 3 0 1")
+	path, cost := csa.AStar{}.Search(node)
+	if cost != 31 || len(path) != 32 || !path[31].IsGoal() {
+		t.Fatalf("Expected the solution of 31 slides, got %d", cost)
+	}
+	// replaying the moves
+	for _, step := range path[1:] {
+		var ok bool
+		if node, ok = node.Play(step.(Board).Move().(Move)); !ok {
+			t.Fatalf("Invalid slide %v", step.(Board).Move())
+		}
+	}
+	if !node.IsGoal() {
+		t.Errorf("Expected the goal\n%s", node)
+	}
+	rnd := rand.New(rand.NewSource(2))
+	shuffled := New(4).Shuffle(30, rnd)
+	if path, cost := (csa.AStar{}).Search(shuffled); path == nil || cost > 30 || cost < shuffled.Heuristic() {
+		t.Errorf("Expected the solution of at most 30 slides, got %d\n%s", cost, shuffled)
+	}
+}
+
+func TestAnytime(t *testing.T) {
+	node, _ := Parse("8 6 7 2 5 4 3 0 1")
+	_, _, stats := csa.AStar{}.SearchStats(node)
+	path, cost, weightedStats := csa.AStar{Weight: 2}.SearchStats(node)
+	if path == nil || cost > 62 || weightedStats.Expanded >= stats.Expanded {
+		t.Errorf("Expected the faster weighted solution, got %d slides with %d of %d expansions",
+			cost, weightedStats.Expanded, stats.Expanded)
+	}
+	var first csa.AStarSolution
+	solution := csa.AStar{Weight: 2}.SearchAnytime(context.Background(), node, func(solution csa.AStarSolution) {
+		if first.Path == nil {
+			first = solution
+		}
+	})
+	if first.Cost != cost || first.Bound < 1 || solution.Cost != 31 || solution.Bound != 1 {
+		t.Errorf("Expected the first solution of %d slides and the optimal one, got %+v and %+v", cost, first, solution)
+	}
+	// the 15-puzzle is too hard for the plain A* within the deadline
+	rnd := rand.New(rand.NewSource(3))
+	shuffled := New(4).Shuffle(200, rnd)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	solution = csa.AStar{Weight: 3}.SearchAnytime(ctx, shuffled, nil)
+	if solution.Path == nil || solution.Bound < 1 || solution.Cost < shuffled.Heuristic() {
+		t.Errorf("Expected a bounded solution, got %d slides with bound %f", solution.Cost, solution.Bound)
+	}
+}
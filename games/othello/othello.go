@@ -0,0 +1,305 @@
+// Package othello is the Othello (Reversi) game of the csa package on bitboards
+//
+// Black is the maximizing player and moves first. A disc has to be placed so that it flanks a line of the opponent's
+// discs which are flipped. The player without such a move passes, the pass is a child of the board. The game ends
+// when neither player can move and the player with more discs wins.
+//
+// Squares are indexed 0 (a1) to 63 (h8) by rows from the top left corner, the columns are a-h and the rows 1-8.
+package othello
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+const (
+	Size = 8
+
+	// Score of the finished game is WinScore plus the disc difference for the winner, the heuristic scores
+	// of the other boards stay below it. Use it as csa.Searcher's WinScore.
+	WinScore = 10000
+
+	// color indices
+	black = 0
+	white = 1
+
+	// square of the pass, the move of the player without any other
+	Pass = -1
+
+	// last move of the initial board
+	noMove = -2
+
+	// runes
+	blackDisc = 'X'
+	whiteDisc = 'O'
+)
+
+type Color int
+
+const (
+	Black Color = black
+	White Color = white
+)
+
+// Bit per square indexed by rows
+type bitboard uint64
+
+const (
+	notColumnA bitboard = 0xfefefefefefefefe
+	notColumnH bitboard = 0x7f7f7f7f7f7f7f7f
+	corners    bitboard = 1<<0 | 1<<7 | 1<<56 | 1<<63
+)
+
+// Shifts to the neighbouring squares
+var directions = [8]int{1, -1, Size, -Size, Size + 1, Size - 1, -Size + 1, -Size - 1}
+
+// Evaluation weights
+const (
+	cornerScore   = 25 // per corner disc
+	xSquareScore  = 8  // penalty per disc diagonally next to an empty corner
+	mobilityScore = 3  // per legal move
+)
+
+// Squares diagonally next to the corners, by the corner
+var xSquares = map[bitboard]bitboard{1 << 0: 1 << 9, 1 << 7: 1 << 14, 1 << 56: 1 << 49, 1 << 63: 1 << 54}
+
+// Neighbours of the squares in the direction, the ones wrapping around the board are removed
+func shift(b bitboard, direction int) bitboard {
+	if direction > 0 {
+		b <<= direction
+	} else {
+		b >>= -direction
+	}
+	switch direction {
+	case 1, Size + 1, -Size + 1:
+		// to the right
+		return b & notColumnA
+	case -1, Size - 1, -Size - 1:
+		return b & notColumnH
+	}
+	return b
+}
+
+// Empty squares where the player flanks the opponent's discs
+func moves(player, opponent bitboard) bitboard {
+	empty := ^(player | opponent)
+	var moves bitboard
+	for _, direction := range directions {
+		line := shift(player, direction) & opponent
+		for i := 0; i < Size-3; i++ {
+			line |= shift(line, direction) & opponent
+		}
+		moves |= shift(line, direction) & empty
+	}
+	return moves
+}
+
+// Opponent's discs flipped by the player's disc on the square
+func flips(player, opponent, square bitboard) bitboard {
+	var flipped bitboard
+	for _, direction := range directions {
+		var line bitboard
+		next := shift(square, direction)
+		for ; next&opponent != 0; next = shift(next, direction) {
+			line |= next
+		}
+		if next&player != 0 {
+			flipped |= line
+		}
+	}
+	return flipped
+}
+
+// Board with the discs of both players, implements csa.SearchNode
+// Intentionally passed by value everywhere
+type Board struct {
+	discs    [2]bitboard // discs[color]
+	lastMove int         // square of the last disc, Pass or noMove
+}
+
+// Initial board with the four central discs
+func New() Board {
+	node := Board{lastMove: noMove}
+	node.discs[white] = 1<<27 | 1<<36
+	node.discs[black] = 1<<28 | 1<<35
+	return node
+}
+
+func color(maximizing bool) int {
+	if maximizing {
+		return black
+	}
+	return white
+}
+
+// Square of the notation, e.g. d3, -1 if invalid
+func Square(notation string) int {
+	if len(notation) != 2 || notation[0] < 'a' || notation[0] > 'h' || notation[1] < '1' || notation[1] > '8' {
+		return -1
+	}
+	return int(notation[1]-'1')*Size + int(notation[0]-'a')
+}
+
+// Square in the notation, e.g. d3
+func Notation(square int) string {
+	return fmt.Sprintf("%c%d", 'a'+square%Size, square/Size+1)
+}
+
+// Color of the disc on the square, false if empty
+func (node Board) At(square int) (Color, bool) {
+	switch bit := bitboard(1) << square; {
+	case node.discs[black]&bit != 0:
+		return Black, true
+	case node.discs[white]&bit != 0:
+		return White, true
+	}
+	return 0, false
+}
+
+// Number of the discs of the color
+func (node Board) Count(color Color) int {
+	return bits.OnesCount64(uint64(node.discs[color]))
+}
+
+// Squares where the player can place its disc
+func (node Board) Moves(maximizing bool) []int {
+	player := color(maximizing)
+	var squares []int
+	for b := moves(node.discs[player], node.discs[1-player]); b != 0; b &= b - 1 {
+		squares = append(squares, bits.TrailingZeros64(uint64(b)))
+	}
+	return squares
+}
+
+// Board after the player's disc on the square or its Pass, false if the move is illegal
+func (node Board) Play(square int, maximizing bool) (Board, bool) {
+	player := color(maximizing)
+	legal := moves(node.discs[player], node.discs[1-player])
+	if square == Pass {
+		node.lastMove = Pass
+		return node, legal == 0 && !node.IsTerminal()
+	}
+	if square < 0 || square >= Size*Size || legal&(1<<square) == 0 {
+		return Board{}, false
+	}
+	return node.place(player, square), true
+}
+
+func (node Board) place(player, square int) Board {
+	bit := bitboard(1) << square
+	flipped := flips(node.discs[player], node.discs[1-player], bit)
+	node.discs[player] |= bit | flipped
+	node.discs[1-player] &^= flipped
+	node.lastMove = square
+	return node
+}
+
+func (node Board) IsTerminal() bool {
+	return moves(node.discs[black], node.discs[white]) == 0 && moves(node.discs[white], node.discs[black]) == 0
+}
+
+func (node Board) IsDraw() bool {
+	return node.IsTerminal() && node.Count(Black) == node.Count(White)
+}
+
+// Disc difference adjusted by WinScore for the finished game, otherwise mobility, corners and X-squares
+func (node Board) Score() int {
+	difference := node.Count(Black) - node.Count(White)
+	if node.IsTerminal() {
+		switch {
+		case difference > 0:
+			return WinScore + difference
+		case difference < 0:
+			return -WinScore + difference
+		}
+		return 0
+	}
+	b, w := node.discs[black], node.discs[white]
+	score := mobilityScore * (bits.OnesCount64(uint64(moves(b, w))) - bits.OnesCount64(uint64(moves(w, b))))
+	score += cornerScore * (bits.OnesCount64(uint64(b&corners)) - bits.OnesCount64(uint64(w&corners)))
+	for corner, xSquare := range xSquares {
+		if (b|w)&corner == 0 {
+			score -= xSquareScore * (bits.OnesCount64(uint64(b&xSquare)) - bits.OnesCount64(uint64(w&xSquare)))
+		}
+	}
+	return score
+}
+
+func (node Board) SearchNodeGenerator() csa.SearchNodeGenerator {
+	var remaining bitboard
+	started := false
+	return func(maximizing bool) csa.SearchNode {
+		player := color(maximizing)
+		if !started {
+			started = true
+			remaining = moves(node.discs[player], node.discs[1-player])
+			if remaining == 0 && !node.IsTerminal() {
+				child := node
+				child.lastMove = Pass
+				return child
+			}
+		}
+		if remaining == 0 {
+			return nil
+		}
+		square := bits.TrailingZeros64(uint64(remaining))
+		remaining &= remaining - 1
+		return node.place(player, square)
+	}
+}
+
+func (node Board) Hash() uint64 {
+	return uint64(node.discs[black])*0x9e3779b97f4a7c15 ^ uint64(node.discs[white])
+}
+
+// Square of the last disc in the notation, e.g. d3, or pass, nil for the initial board
+func (node Board) Move() csa.Move {
+	switch node.lastMove {
+	case noMove:
+		return nil
+	case Pass:
+		return "pass"
+	}
+	return Notation(node.lastMove)
+}
+
+func (node Board) String() string {
+	sb := strings.Builder{}
+	sb.WriteString("  a b c d e f g h\n")
+	for row := 0; row < Size; row++ {
+		fmt.Fprintf(&sb, "%d", row+1)
+		for col := 0; col < Size; col++ {
+			color, ok := node.At(row*Size + col)
+			switch {
+			case !ok:
+				sb.WriteString(" _")
+			case color == Black:
+				sb.WriteString(" " + string(blackDisc))
+			default:
+				sb.WriteString(" " + string(whiteDisc))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Exact solution of the board by alpha-beta searching to the end of the game, practical up to about 14 empty squares
+// Returns the best child and the final disc difference from black's point of view with the best play,
+// searcher's WinScore is ignored and its DrawScore should be zero.
+func Solve(s csa.Searcher, node Board, maximizing bool) (csa.SearchNode, int) {
+	s.WinScore = 0
+	empty := Size*Size - node.Count(Black) - node.Count(White)
+	// no two passes in a row before the end
+	child, score := s.MinimaxAlphaBetaPrunning(node, 2*empty+1, maximizing)
+	switch {
+	case score >= WinScore/2:
+		score -= WinScore
+	case score <= -WinScore/2:
+		score += WinScore
+	}
+	return child, score
+}
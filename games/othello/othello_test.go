@@ -0,0 +1,127 @@
+package othello
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Board of the rows of the diagram, X is black and O is white
+func diagram(rows ...string) Board {
+	node := Board{lastMove: noMove}
+	for row, line := range rows {
+		for col, r := range strings.ReplaceAll(line, " ", "") {
+			switch r {
+			case blackDisc:
+				node.discs[black] |= 1 << (row*Size + col)
+			case whiteDisc:
+				node.discs[white] |= 1 << (row*Size + col)
+			}
+		}
+	}
+	return node
+}
+
+func TestString(t *testing.T) {
+	expected := "  a b c d e f g h\n1 _ _ _ _ _ _ _ _\n2 _ _ _ _ _ _ _ _\n3 _ _ _ _ _ _ _ _\n4 _ _ _ O X _ _ _\n" +
+		"5 _ _ _ X O _ _ _\n6 _ _ _ _ _ _ _ _\n7 _ _ _ _ _ _ _ _\n8 _ _ _ _ _ _ _ _\n"
+	if New().String() != expected {
+		t.Errorf("Invalid initial board\n%s", New())
+	}
+	if Square("d3") != 19 || Notation(19) != "d3" || Square("i1") != -1 || Square("a9") != -1 {
+		t.Error("Invalid notation")
+	}
+}
+
+func TestMoves(t *testing.T) {
+	node := New()
+	var moves []string
+	for _, square := range node.Moves(true) {
+		moves = append(moves, Notation(square))
+	}
+	if !reflect.DeepEqual(moves, []string{"d3", "c4", "f5", "e6"}) {
+		t.Errorf("Invalid moves %v", moves)
+	}
+	child, ok := node.Play(Square("d3"), true)
+	if !ok || child.Count(Black) != 4 || child.Count(White) != 1 || child.Move() != "d3" {
+		t.Errorf("Invalid board after d3\n%s", child)
+	}
+	if _, ok := node.Play(Square("a1"), true); ok {
+		t.Error("Expected illegal move")
+	}
+	if _, ok := node.Play(Pass, true); ok {
+		t.Error("Pass is legal only without any other move")
+	}
+	// lines do not wrap around the edges
+	node = diagram(
+		"O X X _ X X X X",
+		"O _ _ _ _ _ _ _",
+	)
+	child, _ = node.Play(Square("d1"), false)
+	if child.Count(White) != 5 || child.Count(Black) != 4 {
+		t.Errorf("Expected two flipped discs\n%s", child)
+	}
+}
+
+func TestPass(t *testing.T) {
+	node := diagram("X O")
+	if node.IsTerminal() {
+		t.Fatal("Black can move")
+	}
+	generator := node.SearchNodeGenerator()
+	child := generator(false)
+	if child == nil || child.(Board).Move() != "pass" || generator(false) != nil {
+		t.Error("Expected the single pass of white")
+	}
+	if _, ok := node.Play(Pass, false); !ok {
+		t.Error("Expected legal pass")
+	}
+	// nobody can move
+	node = diagram("X _ O")
+	if !node.IsTerminal() || node.Score() != 0 || !node.IsDraw() || node.SearchNodeGenerator()(true) != nil {
+		t.Error("Expected finished draw")
+	}
+	if node := diagram("X X"); !node.IsTerminal() || node.Score() != WinScore+2 {
+		t.Error("Expected black win")
+	}
+}
+
+func TestEvaluation(t *testing.T) {
+	corner := diagram(
+		"X O",
+		"O O",
+	)
+	xSquare := diagram(
+		"_ O",
+		"O X",
+	)
+	if corner.Score() <= xSquare.Score() {
+		t.Errorf("Corner is better than X-square, %d <= %d", corner.Score(), xSquare.Score())
+	}
+}
+
+func TestSolve(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for game := 0; game < 3; game++ {
+		node, maximizing := New(), true
+		for node.Count(Black)+node.Count(White) < Size*Size-7 && !node.IsTerminal() {
+			if moves := node.Moves(maximizing); len(moves) > 0 {
+				node, _ = node.Play(moves[r.Intn(len(moves))], maximizing)
+			}
+			maximizing = !maximizing
+		}
+		child, score := Solve(csa.Searcher{}, node, maximizing)
+		_, minimax := csa.Searcher{}.Minimax(node, 2*(Size*Size-node.Count(Black)-node.Count(White))+1, maximizing)
+		if minimax >= WinScore {
+			minimax -= WinScore
+		} else if minimax <= -WinScore {
+			minimax += WinScore
+		}
+		if child == nil || score != minimax {
+			t.Errorf("Solved score %d differs from minimax %d\n%s", score, minimax, node)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package ggp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Constant, variable (?x) or compound term (f a b), the literals and rules are terms too
+// Immutable after creation, shared by the concurrent searches.
+type term struct {
+	name string
+	args []*term
+	key  string // text of the term, e.g. (cell 1 1 b)
+}
+
+func newTerm(name string, args []*term) *term {
+	t := &term{name: name, args: args, key: name}
+	if len(args) > 0 {
+		keys := make([]string, 0, len(args)+1)
+		keys = append(keys, name)
+		for _, arg := range args {
+			keys = append(keys, arg.key)
+		}
+		t.key = "(" + strings.Join(keys, " ") + ")"
+	}
+	return t
+}
+
+func (t *term) String() string {
+	return t.key
+}
+
+func (t *term) isVariable() bool {
+	return len(t.args) == 0 && strings.HasPrefix(t.name, "?")
+}
+
+func (t *term) variables(variables map[string]bool) {
+	if t.isVariable() {
+		variables[t.name] = true
+	}
+	for _, arg := range t.args {
+		arg.variables(variables)
+	}
+}
+
+// Parses the terms of the KIF text, the comments start with a semicolon
+func parseTerms(text string) ([]*term, error) {
+	var lines []string
+	for _, line := range strings.Split(strings.ToLower(text), "\n") {
+		lines = append(lines, strings.SplitN(line, ";", 2)[0])
+	}
+	text = strings.Join(lines, "\n")
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(text))
+	var terms []*term
+	for len(tokens) > 0 {
+		t, rest, err := parseTerm(tokens)
+		if err != nil {
+			return nil, err
+		}
+		terms, tokens = append(terms, t), rest
+	}
+	return terms, nil
+}
+
+func parseTerm(tokens []string) (*term, []string, error) {
+	switch token := tokens[0]; token {
+	case ")":
+		return nil, nil, fmt.Errorf("ggp: unexpected )")
+	case "(":
+		tokens = tokens[1:]
+		if len(tokens) == 0 || tokens[0] == "(" || tokens[0] == ")" {
+			return nil, nil, fmt.Errorf("ggp: expected the name after (")
+		}
+		name := tokens[0]
+		var args []*term
+		for tokens = tokens[1:]; len(tokens) > 0 && tokens[0] != ")"; {
+			arg, rest, err := parseTerm(tokens)
+			if err != nil {
+				return nil, nil, err
+			}
+			args, tokens = append(args, arg), rest
+		}
+		if len(tokens) == 0 {
+			return nil, nil, fmt.Errorf("ggp: missing )")
+		}
+		return newTerm(name, args), tokens[1:], nil
+	default:
+		return newTerm(token, nil), tokens[1:], nil
+	}
+}
+
+// Rule with the body of the literals without or, the positive literals first
+type rule struct {
+	head *term
+	body []*term
+}
+
+// Rules of the term (<= head literals...) with the disjunctions expanded into separate rules
+func newRules(t *term) ([]rule, error) {
+	if t.name != "<=" {
+		r := rule{head: t}
+		return []rule{r}, r.check()
+	}
+	if len(t.args) == 0 {
+		return nil, fmt.Errorf("ggp: rule without head")
+	}
+	bodies := [][]*term{nil}
+	for _, literal := range t.args[1:] {
+		options := []*term{literal}
+		if literal.name == "or" {
+			options = literal.args
+		}
+		var expanded [][]*term
+		for _, body := range bodies {
+			for _, option := range options {
+				expanded = append(expanded, append(append([]*term{}, body...), option))
+			}
+		}
+		bodies = expanded
+	}
+	rules := make([]rule, 0, len(bodies))
+	for _, body := range bodies {
+		r := rule{head: t.args[0]}
+		for _, literal := range body {
+			if !isFilter(literal) {
+				r.body = append(r.body, literal)
+			}
+		}
+		for _, literal := range body {
+			if isFilter(literal) {
+				r.body = append(r.body, literal)
+			}
+		}
+		if err := r.check(); err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// Negation or distinct, evaluated after the positive literals bound their variables
+func isFilter(literal *term) bool {
+	return literal.name == "not" || literal.name == "distinct"
+}
+
+// Every variable has to appear in a positive literal of the body
+func (r rule) check() error {
+	bound, used := map[string]bool{}, map[string]bool{}
+	r.head.variables(used)
+	for _, literal := range r.body {
+		switch {
+		case literal.name == "or":
+			return fmt.Errorf("ggp: nested or in %s", r.head)
+		case literal.name == "not" && len(literal.args) != 1, literal.name == "distinct" && len(literal.args) != 2:
+			return fmt.Errorf("ggp: invalid %s in %s", literal, r.head)
+		case isFilter(literal):
+			literal.variables(used)
+		default:
+			literal.variables(bound)
+		}
+	}
+	for variable := range used {
+		if !bound[variable] {
+			return fmt.Errorf("ggp: unsafe variable %s in %s", variable, r.head)
+		}
+	}
+	return nil
+}
+
+// Relation of the literal, of the negated one for not
+func relation(literal *term) string {
+	if literal.name == "not" {
+		return relation(literal.args[0])
+	}
+	return literal.name
+}
+
+// Rules grouped by the strata of the negation, evaluated in order
+type program [][]rule
+
+// Strata of the relations, the relations negated by a rule are complete before it is evaluated
+func stratify(rules []rule) (map[string]int, error) {
+	strata := map[string]int{}
+	for changed := true; changed; {
+		changed = false
+		for _, r := range rules {
+			for _, literal := range r.body {
+				if literal.name == "distinct" {
+					continue
+				}
+				stratum := strata[relation(literal)]
+				if literal.name == "not" {
+					stratum++
+				}
+				if stratum > strata[r.head.name] {
+					if stratum > len(rules) {
+						return nil, fmt.Errorf("ggp: recursion through negation in %s", r.head)
+					}
+					strata[r.head.name], changed = stratum, true
+				}
+			}
+		}
+	}
+	return strata, nil
+}
+
+// Program of the rules with the heads of the relations
+func newProgram(rules []rule, strata map[string]int, relations func(name string) bool) program {
+	var p program
+	for _, r := range rules {
+		if !relations(r.head.name) {
+			continue
+		}
+		stratum := strata[r.head.name]
+		for len(p) <= stratum {
+			p = append(p, nil)
+		}
+		p[stratum] = append(p[stratum], r)
+	}
+	return p
+}
+
+// Facts by their relations, layered over the parent's facts, e.g. of the state over the static ones
+type model struct {
+	parent *model
+	facts  map[string][]*term
+	keys   map[string]bool
+}
+
+func newModel(parent *model) *model {
+	return &model{parent: parent, facts: map[string][]*term{}, keys: map[string]bool{}}
+}
+
+func (m *model) has(key string) bool {
+	for ; m != nil; m = m.parent {
+		if m.keys[key] {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *model) add(fact *term) bool {
+	if m.has(fact.key) {
+		return false
+	}
+	m.keys[fact.key] = true
+	m.facts[fact.name] = append(m.facts[fact.name], fact)
+	return true
+}
+
+// Facts of the relation, the parent's first
+func (m *model) relation(name string) []*term {
+	if m.parent == nil {
+		return m.facts[name]
+	}
+	parent := m.parent.relation(name)
+	if len(m.facts[name]) == 0 {
+		return parent
+	}
+	return append(append([]*term{}, parent...), m.facts[name]...)
+}
+
+// Derives all the facts of the program's rules, stratum by stratum until no new fact appears
+func (m *model) derive(p program) {
+	for _, rules := range p {
+		for changed := true; changed; {
+			changed = false
+			for _, r := range rules {
+				var derived []*term
+				m.match(r.body, map[string]*term{}, func(bindings map[string]*term) {
+					derived = append(derived, substitute(r.head, bindings))
+				})
+				for _, fact := range derived {
+					if m.add(fact) {
+						changed = true
+					}
+				}
+			}
+		}
+	}
+}
+
+// Calls found with every bindings satisfying the literals
+func (m *model) match(body []*term, bindings map[string]*term, found func(bindings map[string]*term)) {
+	if len(body) == 0 {
+		found(bindings)
+		return
+	}
+	literal := body[0]
+	switch literal.name {
+	case "not":
+		if !m.has(substitute(literal.args[0], bindings).key) {
+			m.match(body[1:], bindings, found)
+		}
+	case "distinct":
+		if substitute(literal.args[0], bindings).key != substitute(literal.args[1], bindings).key {
+			m.match(body[1:], bindings, found)
+		}
+	default:
+		var bound []string
+		for _, fact := range m.relation(literal.name) {
+			if unify(literal, fact, bindings, &bound) {
+				m.match(body[1:], bindings, found)
+			}
+			for _, variable := range bound {
+				delete(bindings, variable)
+			}
+			bound = bound[:0]
+		}
+	}
+}
+
+// Binds the variables of the pattern to match the ground fact, the new variables are appended to bound
+func unify(pattern, fact *term, bindings map[string]*term, bound *[]string) bool {
+	if pattern.isVariable() {
+		if value, ok := bindings[pattern.name]; ok {
+			return value.key == fact.key
+		}
+		bindings[pattern.name] = fact
+		*bound = append(*bound, pattern.name)
+		return true
+	}
+	if pattern.name != fact.name || len(pattern.args) != len(fact.args) {
+		return false
+	}
+	for i, arg := range pattern.args {
+		if !unify(arg, fact.args[i], bindings, bound) {
+			return false
+		}
+	}
+	return true
+}
+
+func substitute(t *term, bindings map[string]*term) *term {
+	if t.isVariable() {
+		return bindings[t.name]
+	}
+	if len(t.args) == 0 {
+		return t
+	}
+	args := make([]*term, len(t.args))
+	for i, arg := range t.args {
+		args[i] = substitute(arg, bindings)
+	}
+	return newTerm(t.name, args)
+}
+
+// Relations depending on any of the given ones, directly or through the other relations
+func dependents(rules []rule, names ...string) map[string]bool {
+	depends := map[string]bool{}
+	for _, name := range names {
+		depends[name] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, r := range rules {
+			if depends[r.head.name] {
+				continue
+			}
+			for _, literal := range r.body {
+				if literal.name != "distinct" && depends[relation(literal)] {
+					depends[r.head.name], changed = true, true
+					break
+				}
+			}
+		}
+	}
+	return depends
+}
+
+// Facts sorted by their keys
+func sortTerms(terms []*term) {
+	sort.Slice(terms, func(i, j int) bool { return terms[i].key < terms[j].key })
+}
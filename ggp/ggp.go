@@ -0,0 +1,282 @@
+// Package ggp plays the games described in the Game Description Language (GDL) with the csa package,
+// any two-player game of the description is searchable without writing its Go code.
+//
+// The description is the KIF text of the facts and rules (<= head body...) with the relations role, init, true,
+// does, next, legal, goal and terminal of GDL, the literals not, distinct and or, and the variables ?x.
+// The interpreter derives all the facts of every state bottom-up, the rules have to be safe and stratified as GDL
+// requires. The relations independent of the state are derived once by Parse.
+//
+// The first role is the maximizing player. In the turn-based games the role without a choice plays its only move,
+// e.g. noop, while the other one chooses. When both roles have a choice, the first role chooses first
+// and the second one knows its move, the simultaneous games are searched pessimistically for the first role.
+// The score of the terminal states is the difference of the goals of the roles, zero before the end.
+package ggp
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Score of the won terminal state, goal 100 against 0. Use it as csa.Searcher's WinScore.
+const WinScore = 100
+
+// Interpreted game description, shared by all its states
+type Game struct {
+	roles  [2]*term
+	static *model  // facts independent of the states
+	state  program // rules of the relations depending on true
+	move   program // rules of the relations depending on does, e.g. next
+}
+
+// Parses the description, fails if it is not valid or has not two roles
+func Parse(description string) (*Game, error) {
+	terms, err := parseTerms(description)
+	if err != nil {
+		return nil, err
+	}
+	var rules []rule
+	for _, t := range terms {
+		r, err := newRules(t)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r...)
+	}
+	strata, err := stratify(rules)
+	if err != nil {
+		return nil, err
+	}
+	dynamic, moves := dependents(rules, "true", "does"), dependents(rules, "does")
+	game := &Game{
+		static: newModel(nil),
+		state: newProgram(rules, strata, func(name string) bool {
+			return dynamic[name] && !moves[name]
+		}),
+		move: newProgram(rules, strata, func(name string) bool { return moves[name] }),
+	}
+	game.static.derive(newProgram(rules, strata, func(name string) bool { return !dynamic[name] }))
+	roles := game.static.relation("role")
+	if len(roles) != 2 {
+		return nil, fmt.Errorf("ggp: %d roles, only the two-player games are supported", len(roles))
+	}
+	for i, role := range roles {
+		if len(role.args) != 1 {
+			return nil, fmt.Errorf("ggp: invalid %s", role)
+		}
+		game.roles[i] = role.args[0]
+	}
+	return game, nil
+}
+
+// Names of the maximizing and the minimizing role
+func (game *Game) Roles() [2]string {
+	return [2]string{game.roles[0].key, game.roles[1].key}
+}
+
+// Initial state of the init facts
+func (game *Game) Start() State {
+	var facts []*term
+	for _, fact := range game.static.relation("init") {
+		if len(fact.args) == 1 {
+			facts = append(facts, fact.args[0])
+		}
+	}
+	return game.newState(facts, -1, nil)
+}
+
+// State of the game, implements csa.SearchNode
+// Intentionally passed by value everywhere, the derived facts are shared.
+type State struct {
+	game     *Game
+	facts    []*term // sorted true facts
+	model    *model  // derived facts of the state
+	legal    [2][]*term
+	goals    [2]int
+	terminal bool
+	pending  *term // move of the first role, the second one chooses now
+	mover    int   // role which chose the move creating the state, -1 for the initial state
+	move     *term
+	hash     uint64
+}
+
+func (game *Game) newState(facts []*term, mover int, move *term) State {
+	sortTerms(facts)
+	state := State{game: game, facts: facts, model: newModel(game.static), mover: mover, move: move}
+	h := fnv.New64a()
+	for _, fact := range facts {
+		state.model.add(newTerm("true", []*term{fact}))
+		h.Write([]byte(fact.key))
+	}
+	state.hash = h.Sum64()
+	state.model.derive(game.state)
+	for _, legal := range state.model.relation("legal") {
+		if i := game.role(legal, 2); i >= 0 {
+			state.legal[i] = append(state.legal[i], legal.args[1])
+		}
+	}
+	for _, goal := range state.model.relation("goal") {
+		if i := game.role(goal, 2); i >= 0 {
+			if value, err := strconv.Atoi(goal.args[1].key); err == nil {
+				state.goals[i] = max(state.goals[i], value)
+			}
+		}
+	}
+	state.terminal = state.model.has("terminal")
+	return state
+}
+
+// Index of the role of the fact's first argument, -1 if it is none or the fact has not the arity
+func (game *Game) role(fact *term, arity int) int {
+	if len(fact.args) != arity {
+		return -1
+	}
+	for i, role := range game.roles {
+		if fact.args[0].key == role.key {
+			return i
+		}
+	}
+	return -1
+}
+
+// State after the moves of both roles
+func (state State) next(moves [2]*term, mover int) State {
+	m := newModel(state.model)
+	for i, move := range moves {
+		m.add(newTerm("does", []*term{state.game.roles[i], move}))
+	}
+	m.derive(state.game.move)
+	var facts []*term
+	for _, fact := range m.relation("next") {
+		if len(fact.args) == 1 {
+			facts = append(facts, fact.args[0])
+		}
+	}
+	return state.game.newState(facts, mover, moves[mover])
+}
+
+// Role choosing the move now
+func (state State) chooser() int {
+	if state.pending != nil || (len(state.legal[0]) == 1 && len(state.legal[1]) > 1) {
+		return 1
+	}
+	return 0
+}
+
+func (state State) PlayerToMove() csa.Player {
+	if state.chooser() == 0 {
+		return csa.MaximizingPlayer
+	}
+	return csa.MinimizingPlayer
+}
+
+// The role which chose the move chooses again
+func (state State) ExtraTurn() bool {
+	return !state.terminal && state.chooser() == state.mover
+}
+
+func (state State) IsTerminal() bool {
+	return state.terminal
+}
+
+// Goal of the role in the state, 0 if the description has none
+func (state State) Goal(role int) int {
+	return state.goals[role]
+}
+
+// Difference of the goals of the first and the second role at the end of the game, zero before
+func (state State) Score() int {
+	if !state.terminal {
+		return 0
+	}
+	return state.goals[0] - state.goals[1]
+}
+
+// Legal moves of the choosing role in the order of the description
+func (state State) SearchNodeGenerator() csa.SearchNodeGenerator {
+	i := 0
+	return func(maximizing bool) csa.SearchNode {
+		if state.terminal {
+			return nil
+		}
+		switch {
+		case state.pending != nil:
+			if i < len(state.legal[1]) {
+				i++
+				return state.next([2]*term{state.pending, state.legal[1][i-1]}, 1)
+			}
+		case state.chooser() == 1:
+			if i < len(state.legal[1]) {
+				i++
+				return state.next([2]*term{state.legal[0][0], state.legal[1][i-1]}, 1)
+			}
+		case len(state.legal[1]) == 1:
+			if i < len(state.legal[0]) {
+				i++
+				return state.next([2]*term{state.legal[0][i-1], state.legal[1][0]}, 0)
+			}
+		case len(state.legal[1]) > 1:
+			if i < len(state.legal[0]) {
+				i++
+				child := state
+				child.pending, child.mover, child.move = state.legal[0][i-1], 0, state.legal[0][i-1]
+				return child
+			}
+		}
+		return nil
+	}
+}
+
+// Legal moves of the choosing role, e.g. (mark 1 1)
+func (state State) Moves() []string {
+	var moves []string
+	generator := state.SearchNodeGenerator()
+	for child := generator(true); child != nil; child = generator(true) {
+		moves = append(moves, child.(State).move.key)
+	}
+	return moves
+}
+
+// State after the move of the choosing role, e.g. (mark 1 1)
+func (state State) Play(move string) (State, error) {
+	terms, err := parseTerms(move)
+	if err != nil || len(terms) != 1 {
+		return State{}, fmt.Errorf("ggp: invalid move %q", move)
+	}
+	generator := state.SearchNodeGenerator()
+	for child := generator(true); child != nil; child = generator(true) {
+		if child.(State).move.key == terms[0].key {
+			return child.(State), nil
+		}
+	}
+	return State{}, fmt.Errorf("ggp: illegal move %s", terms[0])
+}
+
+// Move of the role which created this state, nil for the initial state
+func (state State) Move() csa.Move {
+	if state.move == nil {
+		return nil
+	}
+	return state.move.key
+}
+
+func (state State) Hash() uint64 {
+	if state.pending == nil {
+		return state.hash
+	}
+	h := fnv.New64a()
+	h.Write([]byte(state.pending.key))
+	return state.hash ^ h.Sum64()
+}
+
+// True facts of the state, one per line
+func (state State) String() string {
+	lines := make([]string, len(state.facts))
+	for i, fact := range state.facts {
+		lines[i] = fact.key
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,115 @@
+package ggp
+
+import (
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Rock-paper-scissors, both roles choose at once
+const rockPaperScissors = `
+(role left) (role right)
+(shape rock) (shape paper) (shape scissors)
+(beats rock scissors) (beats paper rock) (beats scissors paper)
+(init start)
+(<= (legal ?r ?s) (role ?r) (shape ?s) (true start))
+(<= (next (played ?r ?s)) (does ?r ?s))
+(<= (won ?r) (true (played ?r ?a)) (true (played ?o ?b)) (beats ?a ?b))
+(<= (goal ?r 100) (role ?r) (won ?r))
+(<= (goal ?r 50) (role ?r) (not (won left)) (not (won right)) (not (true start)))
+(<= (goal ?r 0) (role ?r) (role ?o) (won ?o) (distinct ?r ?o))
+(<= terminal (not (true start)))
+`
+
+func play(t *testing.T, state State, moves ...string) State {
+	for _, move := range moves {
+		child, err := state.Play(move)
+		if err != nil {
+			t.Fatalf("%v\n%s", err, state)
+		}
+		state = child
+	}
+	return state
+}
+
+func TestTicTacToe(t *testing.T) {
+	game, err := Parse(TicTacToe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roles := game.Roles(); roles != [2]string{"xplayer", "oplayer"} {
+		t.Errorf("Invalid roles %v", roles)
+	}
+	start := game.Start()
+	if moves := start.Moves(); len(moves) != 9 || moves[0] != "(mark 1 1)" || start.PlayerToMove() != csa.MaximizingPlayer {
+		t.Errorf("Expected 9 moves of xplayer, got %v", moves)
+	}
+	// oplayer chooses while xplayer plays noop
+	node := play(t, start, "(mark 2 2)")
+	if node.PlayerToMove() != csa.MinimizingPlayer || len(node.Moves()) != 8 || node.ExtraTurn() {
+		t.Errorf("Expected 8 moves of oplayer\n%s", node)
+	}
+	if _, err := node.Play("(mark 2 2)"); err == nil {
+		t.Error("Expected the illegal move")
+	}
+	// the diagonal of xplayer
+	node = play(t, node, "(mark 1 2)", "(mark 1 1)", "(mark 2 1)", "(mark 3 3)")
+	if !node.IsTerminal() || node.Score() != WinScore || node.Goal(1) != 0 || node.Move() != "(mark 3 3)" {
+		t.Errorf("Expected xplayer to win, got %d\n%s", node.Score(), node)
+	}
+	// transposition
+	a, b := play(t, start, "(mark 1 1)", "(mark 2 2)", "(mark 3 3)"), play(t, start, "(mark 3 3)", "(mark 2 2)", "(mark 1 1)")
+	if a.Hash() != b.Hash() || a.String() != b.String() {
+		t.Errorf("Expected equal states\n%s\n\n%s", a, b)
+	}
+	// perfect play is a draw
+	engine := csa.NewEngine(csa.WithSearcher(csa.Searcher{WinScore: WinScore}), csa.WithMaxDepth(9), csa.WithTT(1<<16))
+	defer engine.Close()
+	if _, score := engine.BestMove(start, true); score != 0 {
+		t.Errorf("Expected the draw, got %d", score)
+	}
+	// oplayer has to block the row
+	node = play(t, start, "(mark 1 1)", "(mark 2 2)", "(mark 1 2)")
+	if child, _ := engine.BestMove(node, false); csa.MoveOf(child) != "(mark 1 3)" {
+		t.Errorf("Expected the block, got %v", csa.MoveOf(child))
+	}
+}
+
+func TestSimultaneous(t *testing.T) {
+	game, err := Parse(rockPaperScissors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := game.Start()
+	pending := play(t, start, "rock")
+	if pending.PlayerToMove() != csa.MinimizingPlayer || pending.IsTerminal() || len(pending.Moves()) != 3 {
+		t.Errorf("Expected the choice of right, got %v", pending.Moves())
+	}
+	if pending.Hash() == start.Hash() {
+		t.Error("Expected the pending move in the hash")
+	}
+	if end := play(t, pending, "paper"); !end.IsTerminal() || end.Score() != -WinScore {
+		t.Errorf("Expected right to win, got %d\n%s", end.Score(), end)
+	}
+	if end := play(t, pending, "rock"); end.Score() != 0 || end.Goal(0) != 50 {
+		t.Errorf("Expected the draw, got %d\n%s", end.Score(), end)
+	}
+	// the second role knows the first role's move
+	if _, score := csa.MinimaxAlphaBetaPrunning(start, 2, true); score != -WinScore {
+		t.Errorf("Expected the loss of the first role, got %d", score)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for _, description := range []string{
+		"(role a)",
+		"(role a) (role b) (<= (p ?x) (not (q ?x)))",
+		"(role a) (role b) (<= p (not q)) (<= q (not p))",
+		"(role a) (role b) (<= p (q ?x)",
+		"(role a) (role b) (p ?x)",
+	} {
+		if _, err := Parse(description); err == nil {
+			t.Errorf("Expected invalid description %s", description)
+		}
+	}
+}
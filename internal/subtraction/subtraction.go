@@ -0,0 +1,49 @@
+// Package subtraction is the subtraction game shared by the tests of the packages playing whole games
+//
+// Players alternately take one to three stones and the one taking the last stone wins. Positions with
+// a multiple of four stones are lost for the player to move.
+package subtraction
+
+import (
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Pile of stones, implements csa.SearchNode and csa.MoveNode
+type Node struct {
+	Stones     int
+	Taken      int  // by the last move, which is the number of the move
+	Maximizing bool // player who took the stones
+}
+
+// Pile of the given number of stones
+func New(stones int) Node {
+	return Node{Stones: stones}
+}
+
+func (node Node) Score() int {
+	if node.Stones > 0 {
+		return 0
+	}
+	if node.Maximizing {
+		return 1
+	}
+	return -1
+}
+
+func (node Node) IsTerminal() bool {
+	return node.Stones == 0
+}
+
+func (node Node) SearchNodeGenerator() csa.SearchNodeGenerator {
+	taken := 0
+	return func(maximizing bool) csa.SearchNode {
+		if taken++; taken > min(3, node.Stones) {
+			return nil
+		}
+		return Node{node.Stones - taken, taken, maximizing}
+	}
+}
+
+func (node Node) Move() csa.Move {
+	return node.Taken
+}
@@ -0,0 +1,26 @@
+package csa
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// Key of the runtime/pprof label with the search phase, e.g. "iteration", "root-split", "split", "helper",
+// "rollout", "tree" or "playout"
+const PhaseLabel = "csa.phase"
+
+// Runs fn with the goroutine labeled for CPU profiles if enabled, the labels of ctx are restored afterwards
+func labeled(ctx context.Context, enabled bool, fn func(), labels ...string) {
+	labeledContext(ctx, enabled, func(context.Context) {
+		fn()
+	}, labels...)
+}
+
+// Labeled fn gets the context with the labels, so its nested phases can restore them
+func labeledContext(ctx context.Context, enabled bool, fn func(ctx context.Context), labels ...string) {
+	if !enabled {
+		fn(ctx)
+		return
+	}
+	pprof.Do(ctx, pprof.Labels(labels...), fn)
+}
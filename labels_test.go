@@ -0,0 +1,46 @@
+package csa
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTTTProfileLabels(t *testing.T) {
+	// goroutine profile lists the labels of the goroutines evaluating the first nodes
+	var mutex sync.Mutex
+	var evaluated int
+	var profile bytes.Buffer
+	evaluator := EvaluatorFunc(func(node SearchNode) int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if evaluated++; evaluated <= 20 {
+			pprof.Lookup("goroutine").WriteTo(&profile, 1)
+		}
+		return node.Score()
+	})
+	for _, test := range []struct {
+		search func()
+		label  string
+	}{
+		{func() {
+			Searcher{Evaluator: evaluator, ProfileLabels: true}.MinimaxConcurrent(tttNode{}, 9, true, 2)
+		}, `"csa.phase":"root-split"`},
+		{func() {
+			engine := NewEngine(WithSearcher(Searcher{Evaluator: evaluator, ProfileLabels: true}), WithInfo(func(SearchInfo) {}))
+			engine.BestMove(tttNode{}, true)
+		}, `"csa.phase":"iteration"`},
+		{func() {
+			MCTS{Iterations: 10, Evaluator: evaluator, ProfileLabels: true}.Search(tttNode{}, true)
+		}, `"csa.phase":"playout"`},
+	} {
+		evaluated = 0
+		profile.Reset()
+		test.search()
+		if !strings.Contains(profile.String(), test.label) {
+			t.Errorf("Expected label %s in the profile", test.label)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package csa
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger of the engine's events: search started and finished, depth finished, transposition table created,
+// checkpoint resumed and worker panic re-raised
+// Depths are logged at debug level, worker panics at error level and the other events at info level.
+// The table is never resized, its size is fixed when it is created, so there is no resize event.
+func WithLogger(logger *slog.Logger) EngineOption {
+	return func(engine *Engine) {
+		engine.logger = logger
+	}
+}
+
+func (engine *Engine) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if engine.logger != nil {
+		engine.logger.Log(ctx, level, msg, args...)
+	}
+}
+
+// Logs the worker panic before it is re-raised to the caller of the search, has to be deferred directly
+func (engine *Engine) logPanic(ctx context.Context) {
+	if r := recover(); r != nil {
+		if err, ok := r.(*PanicError); ok {
+			engine.log(ctx, slog.LevelError, "worker panic re-raised", "error", err, "stack", string(err.Stack))
+		}
+		panic(r)
+	}
+}
+
+var algorithmNames = []string{"alpha-beta", "minimax", "concurrent", "rollout", "work-stealing", "ybwc", "lazy-smp"}
+
+func (algorithm Algorithm) String() string {
+	if int(algorithm) < len(algorithmNames) {
+		return algorithmNames[algorithm]
+	}
+	return "unknown"
+}
@@ -0,0 +1,40 @@
+package csa
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTTTEngineLogger(t *testing.T) {
+	var log bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	engine := NewEngine(WithAlgorithm(AlgorithmLazySMP), WithLogger(logger), WithWorkers(2), WithInfo(func(SearchInfo) {}))
+	engine.BestMove(tttNode{}, true)
+	engine.Close()
+	for _, event := range []string{
+		`msg="search started" algorithm=lazy-smp`,
+		`msg="transposition table created"`,
+		`msg="depth finished" depth=1`,
+		`msg="search finished" depth=9`,
+	} {
+		if !strings.Contains(log.String(), event) {
+			t.Errorf("Missing event %s in\n%s", event, log.String())
+		}
+	}
+	log.Reset()
+	engine = NewEngine(WithAlgorithm(AlgorithmConcurrent), WithLogger(logger), WithWorkers(2))
+	defer engine.Close()
+	func() {
+		defer func() {
+			if _, ok := recover().(*PanicError); !ok {
+				t.Error("Expected worker panic")
+			}
+		}()
+		engine.BestMove(tttPanicNode{}, true)
+	}()
+	if !strings.Contains(log.String(), `level=ERROR msg="worker panic re-raised" error="csa: search worker panicked: bad board"`) {
+		t.Errorf("Missing worker panic in\n%s", log.String())
+	}
+}
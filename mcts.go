@@ -0,0 +1,249 @@
+package csa
+
+import (
+	"context"
+	"math"
+	"math/rand"
+)
+
+// Monte Carlo tree search (UCT) with optional implicit minimax backups (Lanctot et al.)
+// Every tree node keeps both the average playout result and the minimax value of node scores in its subtree,
+// the selection uses their mix, (1-MinimaxWeight)*average + MinimaxWeight*minimax.
+// MinimaxWeight set to zero gives plain UCT.
+// If Model is set, playouts are replaced by model's value and the selection uses PUCT with model's policy priors.
+// All values are from the maximizing player's point of view in units of node's Score.
+type MCTS struct {
+	Iterations    int
+	Exploration   float64 // UCT exploration constant, should be scaled to the range of scores
+	MinimaxWeight float64 // 0 = pure playouts, 1 = pure implicit minimax
+	PlayoutDepth  int     // max plies of a single playout, 0 = until terminal node
+	Rand          *rand.Rand
+	Evaluator     Evaluator // overrides node's Score if set
+	Model         Model
+	ProfileLabels bool    // goroutine is labeled by the search phase (PhaseLabel) for runtime/pprof CPU profiles
+	RootNoise     float64 // weight of the Dirichlet noise mixed into the root priors for exploration, e.g. 0.25
+	NoiseAlpha    float64 // concentration of the Dirichlet noise, e.g. 0.3, smaller values for more moves
+}
+
+type mctsNode struct {
+	node       SearchNode
+	maximizing bool // player to move in this node
+	visits     int
+	total      float64 // sum of playout results
+	minimax    int     // implicit minimax value
+	prior      float64 // model's prior probability
+	children   []*mctsNode
+	expanded   bool
+}
+
+// Returns the most visited root child and its mixed value
+func (m MCTS) Search(node SearchNode, maximizing bool) (SearchNode, float64) {
+	root := m.search(node, maximizing)
+	var best *mctsNode
+	for _, child := range root.children {
+		if best == nil || child.visits > best.visits {
+			best = child
+		}
+	}
+	if best == nil {
+		return nil, float64(MinimaxInitScore(root.maximizing))
+	}
+	return best.node, m.value(best)
+}
+
+// Root children with their visit counts normalized to the probabilities and the mixed value of the root,
+// e.g. the policy and value targets of the self-play training
+func (m MCTS) SearchPolicy(node SearchNode, maximizing bool) ([]SearchNode, []float64, float64) {
+	root := m.search(node, maximizing)
+	children := make([]SearchNode, len(root.children))
+	policy := make([]float64, len(root.children))
+	visits := 0
+	for _, child := range root.children {
+		visits += child.visits
+	}
+	for i, child := range root.children {
+		children[i] = child.node
+		if visits > 0 {
+			policy[i] = float64(child.visits) / float64(visits)
+		}
+	}
+	return children, policy, m.value(root)
+}
+
+func (m MCTS) search(node SearchNode, maximizing bool) *mctsNode {
+	if m.Rand == nil {
+		m.Rand = rand.New(rand.NewSource(1))
+	}
+	root := m.newNode(node, maximizing)
+	labeledContext(context.Background(), m.ProfileLabels, func(ctx context.Context) {
+		for i := 0; i < m.Iterations; i++ {
+			m.iteration(ctx, root)
+			if i == 0 && m.RootNoise > 0 {
+				// the first iteration expanded the root
+				m.addNoise(root)
+			}
+		}
+	}, PhaseLabel, "tree")
+	return root
+}
+
+// Mixes Dirichlet noise into the priors of the root's children
+func (m MCTS) addNoise(root *mctsNode) {
+	noise := make([]float64, len(root.children))
+	sum := 0.0
+	for i := range noise {
+		noise[i] = gammaSample(m.Rand, m.NoiseAlpha)
+		sum += noise[i]
+	}
+	for i, child := range root.children {
+		if sum > 0 {
+			child.prior = (1-m.RootNoise)*child.prior + m.RootNoise*noise[i]/sum
+		}
+	}
+}
+
+// Gamma(alpha, 1) distributed sample by Marsaglia and Tsang's method
+func gammaSample(rnd *rand.Rand, alpha float64) float64 {
+	if alpha <= 0 {
+		return 0
+	}
+	if alpha < 1 {
+		return gammaSample(rnd, alpha+1) * math.Pow(rnd.Float64(), 1/alpha)
+	}
+	d := alpha - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rnd.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		if u := rnd.Float64(); math.Log(u) < x*x/2+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+func (m MCTS) newNode(node SearchNode, maximizing bool) *mctsNode {
+	return &mctsNode{
+		node:       node,
+		maximizing: playerToMove(node, maximizing),
+		minimax:    m.score(node),
+	}
+}
+
+func (m MCTS) score(node SearchNode) int {
+	s := Searcher{Evaluator: m.Evaluator}
+	return s.score(node)
+}
+
+// Context holds the labels of the tree phase
+func (m MCTS) iteration(ctx context.Context, root *mctsNode) {
+	path := []*mctsNode{root}
+	current := root
+	// selection
+	for current.expanded && len(current.children) > 0 {
+		current = m.selectChild(current)
+		path = append(path, current)
+	}
+	var result float64
+	if m.Model != nil {
+		// expansion and evaluation by model
+		if current.node.IsTerminal() {
+			result = float64(m.score(current.node))
+		} else {
+			m.expand(current)
+			result = m.predict(current)
+		}
+	} else {
+		// expansion
+		if !current.expanded && !current.node.IsTerminal() {
+			m.expand(current)
+			if len(current.children) > 0 {
+				current = current.children[m.Rand.Intn(len(current.children))]
+				path = append(path, current)
+			}
+		}
+		// simulation
+		labeled(ctx, m.ProfileLabels, func() {
+			result = float64(m.score(randomPlayout(current.node, current.maximizing, m.PlayoutDepth, m.Rand)))
+		}, PhaseLabel, "playout")
+	}
+	// backpropagation
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i].visits++
+		path[i].total += result
+		path[i].updateMinimax()
+	}
+}
+
+func (m MCTS) selectChild(node *mctsNode) *mctsNode {
+	var best *mctsNode
+	bestValue := math.Inf(-1)
+	logVisits := math.Log(float64(node.visits))
+	for _, child := range node.children {
+		if child.visits == 0 && m.Model == nil {
+			return child
+		}
+		value := m.value(child)
+		if !node.maximizing {
+			value = -value
+		}
+		if m.Model != nil {
+			// PUCT
+			value += m.Exploration * child.prior * math.Sqrt(float64(node.visits)) / float64(1+child.visits)
+		} else {
+			value += m.Exploration * math.Sqrt(logVisits/float64(child.visits))
+		}
+		if value > bestValue {
+			best = child
+			bestValue = value
+		}
+	}
+	return best
+}
+
+func (m MCTS) value(node *mctsNode) float64 {
+	average := float64(node.minimax)
+	if node.visits > 0 {
+		average = node.total / float64(node.visits)
+	}
+	return (1-m.MinimaxWeight)*average + m.MinimaxWeight*float64(node.minimax)
+}
+
+func (m MCTS) expand(node *mctsNode) {
+	for generator := nodeGenerator(node.node); ; {
+		childNode := generator(node.maximizing)
+		if childNode == nil {
+			break
+		}
+		node.children = append(node.children, m.newNode(childNode, nextPlayer(childNode, node.maximizing)))
+	}
+	node.expanded = true
+	node.updateMinimax()
+}
+
+func (m MCTS) predict(node *mctsNode) float64 {
+	children := make([]SearchNode, len(node.children))
+	for i, child := range node.children {
+		children[i] = child.node
+	}
+	value, policy := m.Model.Predict(node.node, children)
+	for i := 0; i < len(policy) && i < len(node.children); i++ {
+		node.children[i].prior = policy[i]
+	}
+	return value
+}
+
+func (node *mctsNode) updateMinimax() {
+	if len(node.children) == 0 {
+		return
+	}
+	node.minimax = node.children[0].minimax
+	for _, child := range node.children[1:] {
+		if (node.maximizing && child.minimax > node.minimax) || (!node.maximizing && child.minimax < node.minimax) {
+			node.minimax = child.minimax
+		}
+	}
+}
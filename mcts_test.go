@@ -0,0 +1,55 @@
+package csa
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTTTMCTS(t *testing.T) {
+	node := tttNode{}
+	node.board[0][0] = cross
+	node.board[0][1] = cross
+	node.board[1][0] = circle
+	node.board[1][1] = circle
+	for _, weight := range []float64{0, 0.5, 1} {
+		mcts := MCTS{
+			Iterations:    1000,
+			Exploration:   10,
+			MinimaxWeight: weight,
+			Rand:          rand.New(rand.NewSource(42)),
+		}
+		// cross wins immediately
+		sn, _ := mcts.Search(node, false)
+		if sn.(tttNode).board[0][2] != cross {
+			t.Errorf("Cross did not win with minimax weight %f %s", weight, sn)
+		}
+		// circle wins immediately
+		sn, _ = mcts.Search(node, true)
+		if sn.(tttNode).board[1][2] != circle {
+			t.Errorf("Circle did not win with minimax weight %f %s", weight, sn)
+		}
+	}
+}
+
+func TestTTTMCTSSearchPolicy(t *testing.T) {
+	mcts := MCTS{Iterations: 500, Exploration: 1, Rand: rand.New(rand.NewSource(1))}
+	children, policy, _ := mcts.SearchPolicy(tttNode{}, true)
+	sum := 0.0
+	for _, probability := range policy {
+		sum += probability
+	}
+	if len(children) != 9 || len(policy) != 9 || math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Invalid policy %v of %d children", policy, len(children))
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for _, alpha := range []float64{0.3, 2.5} {
+		mean := 0.0
+		for i := 0; i < 10000; i++ {
+			mean += gammaSample(rnd, alpha) / 10000
+		}
+		if math.Abs(mean-alpha) > 0.1 {
+			t.Errorf("Gamma(%f) sample mean %f", alpha, mean)
+		}
+	}
+}
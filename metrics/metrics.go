@@ -0,0 +1,115 @@
+// Package metrics exports statistics of csa engines in the Prometheus text exposition format
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Source of the exported statistics, implemented by *csa.Engine
+type StatsSource interface {
+	Stats() csa.EngineStats
+}
+
+// Registry of the engines served as Prometheus metrics labeled by the engine name
+// Serves the metrics over HTTP, e.g. http.Handle("/metrics", exporter).
+type Exporter struct {
+	mutex   sync.Mutex
+	sources map[string]StatsSource
+}
+
+func NewExporter() *Exporter {
+	return &Exporter{sources: make(map[string]StatsSource)}
+}
+
+// Exports the source's statistics under given name, replaces the previous source with the same name
+func (exporter *Exporter) Register(name string, source StatsSource) {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+	exporter.sources[name] = source
+}
+
+func (exporter *Exporter) Unregister(name string) {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+	delete(exporter.sources, name)
+}
+
+func (exporter *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	exporter.Write(w)
+}
+
+type metric struct {
+	name  string
+	kind  string
+	help  string
+	value func(stats csa.EngineStats) float64
+}
+
+var metrics = []metric{
+	{"csa_searches_total", "counter", "Finished searches.", func(stats csa.EngineStats) float64 {
+		return float64(stats.Searches)
+	}},
+	{"csa_nodes_total", "counter", "Searched nodes.", func(stats csa.EngineStats) float64 {
+		return float64(stats.Nodes)
+	}},
+	{"csa_nodes_per_second", "gauge", "Searched nodes per second of the last search.", func(stats csa.EngineStats) float64 {
+		return float64(stats.NPS)
+	}},
+	{"csa_search_depth", "gauge", "Last finished depth of the last search.", func(stats csa.EngineStats) float64 {
+		return float64(stats.Depth)
+	}},
+	{"csa_tt_probes_total", "counter", "Transposition table probes.", func(stats csa.EngineStats) float64 {
+		return float64(stats.TTProbes)
+	}},
+	{"csa_tt_hits_total", "counter", "Transposition table probes deciding the searched node.", func(stats csa.EngineStats) float64 {
+		return float64(stats.TTHits)
+	}},
+	{"csa_tt_hit_ratio", "gauge", "Ratio of the transposition table hits to the probes.", func(stats csa.EngineStats) float64 {
+		if stats.TTProbes == 0 {
+			return 0
+		}
+		return float64(stats.TTHits) / float64(stats.TTProbes)
+	}},
+	{"csa_workers", "gauge", "Workers of the parallel search.", func(stats csa.EngineStats) float64 {
+		return float64(stats.Workers)
+	}},
+	{"csa_worker_utilization", "gauge", "Ratio of the busy workers to all workers.", func(stats csa.EngineStats) float64 {
+		if stats.Workers == 0 {
+			return 0
+		}
+		return float64(stats.BusyWorkers) / float64(stats.Workers)
+	}},
+}
+
+// Writes the metrics of all registered sources sorted by their names
+func (exporter *Exporter) Write(w io.Writer) error {
+	exporter.mutex.Lock()
+	names := make([]string, 0, len(exporter.sources))
+	for name := range exporter.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	stats := make([]csa.EngineStats, len(names))
+	for i, name := range names {
+		stats[i] = exporter.sources[name].Stats()
+	}
+	exporter.mutex.Unlock()
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind); err != nil {
+			return err
+		}
+		for i, name := range names {
+			if _, err := fmt.Fprintf(w, "%s{engine=%q} %g\n", metric.name, name, metric.value(stats[i])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
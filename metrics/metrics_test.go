@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+type fixedStats csa.EngineStats
+
+func (stats fixedStats) Stats() csa.EngineStats {
+	return csa.EngineStats(stats)
+}
+
+func TestExporter(t *testing.T) {
+	exporter := NewExporter()
+	exporter.Register("main", fixedStats{Searches: 3, Nodes: 1500, TTProbes: 4, TTHits: 1, Workers: 4, BusyWorkers: 2})
+	exporter.Register("idle", fixedStats{})
+	recorder := httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	for _, line := range []string{
+		"# TYPE csa_nodes_total counter",
+		`csa_nodes_total{engine="main"} 1500`,
+		`csa_tt_hit_ratio{engine="main"} 0.25`,
+		`csa_worker_utilization{engine="main"} 0.5`,
+		`csa_tt_hit_ratio{engine="idle"} 0`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Missing %q in\n%s", line, body)
+		}
+	}
+	exporter.Unregister("idle")
+	recorder = httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if strings.Contains(recorder.Body.String(), "idle") {
+		t.Error("Unregistered engine exported")
+	}
+}
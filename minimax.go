@@ -2,6 +2,8 @@ package csa
 
 import (
 	"math"
+	"math/rand"
+	"sync"
 )
 
 type SearchNodeGenerator func(maximizing bool) SearchNode
@@ -12,63 +14,304 @@ type SearchNode interface {
 	SearchNodeGenerator() SearchNodeGenerator
 }
 
+// Optional interface for nodes generating all children at once
+// The search uses Children instead of SearchNodeGenerator, which can be implemented by ChildrenGenerator.
+type ChildrenNode interface {
+	Children(maximizing bool) []SearchNode
+}
+
+// Generator iterating over node's Children
+func ChildrenGenerator(node ChildrenNode) SearchNodeGenerator {
+	var children []SearchNode
+	generated := false
+	return func(maximizing bool) SearchNode {
+		if !generated {
+			children = node.Children(maximizing)
+			generated = true
+		}
+		if len(children) == 0 {
+			return nil
+		}
+		child := children[0]
+		children = children[1:]
+		return child
+	}
+}
+
+// Optional interface for nodes appending all children to the given slice, which is reused by the search,
+// so no generator closure nor slice is allocated per expanded node
+type AppendChildrenNode interface {
+	AppendChildren(children []SearchNode, maximizing bool) []SearchNode
+}
+
+// Optional interface for nodes able to compute their score incrementally from the parent's score
+// Child's score is then parent's score + ScoreDelta(parent)
+type IncrementalNode interface {
+	ScoreDelta(parent SearchNode) int
+}
+
+// Optional interface for nodes which are drawn, their score is replaced by searcher's DrawScore
+type DrawNode interface {
+	IsDraw() bool
+}
+
+type Player int
+
+const (
+	MinimizingPlayer Player = iota
+	MaximizingPlayer
+)
+
+// Optional interface for nodes knowing whose turn it is
+// The search derives maximizing/minimizing from the node, the maximizing flags passed by the caller
+// (or alternated for the children) are then ignored.
+type PlayerNode interface {
+	PlayerToMove() Player
+}
+
+// Move descriptor, any user type
+type Move interface{}
+
+// Optional interface for nodes describing the move which created them from their parent,
+// so search results can be reported as moves instead of resulting nodes
+type MoveNode interface {
+	Move() Move
+}
+
+// Move which created the node, nil if the node does not describe it
+func MoveOf(node SearchNode) Move {
+	if moveNode, ok := optionalNode(node).(MoveNode); ok {
+		return moveNode.Move()
+	}
+	return nil
+}
+
+// Optional interface for nodes which let the player who made the move play again,
+// e.g. multi-capture chains or games with double moves
+type ExtraTurnNode interface {
+	ExtraTurn() bool
+}
+
+// External evaluation function which overrides node's Score
+type Evaluator interface {
+	Evaluate(node SearchNode) int
+}
+
+type EvaluatorFunc func(node SearchNode) int
+
+func (fn EvaluatorFunc) Evaluate(node SearchNode) int {
+	return fn(node)
+}
+
+// Search configuration shared by all minimax variants
+// Zero value searches with node's own Score
+type Searcher struct {
+	Evaluator Evaluator
+	// Leaf scores with absolute value at least WinScore are wins and are moved towards zero by one per ply,
+	// so faster wins and slower losses are preferred. Zero disables the adjustment.
+	WinScore int
+	// Score of drawn positions from the maximizing player's point of view, e.g. negative value
+	// makes the maximizing player avoid draws (contempt) while positive makes it seek them
+	DrawScore int
+	// Which of the equally scored children is chosen, the first generated one by default
+	TieBreak TieBreak
+	// Optional custom comparator of equally scored children, returns true if a is preferred over b
+	// Children preferred by none of them are decided by TieBreak.
+	TieBreaker func(a, b SearchNode) bool
+	// If set, equally scored root children are chosen randomly (overriding the tie-breaking policy)
+	Rand *rand.Rand
+	// Occurrences of a RepetitionNode position which make it a draw, 2 (any repetition) if zero
+	Repetitions int
+	// Repetition keys of the positions played before the searched root, oldest first
+	History []uint64
+	// How non-terminal nodes without any children are scored, as a loss of the player to move by default
+	NoMove NoMovePolicy
+	// Optional hook scoring non-terminal nodes without any children, overrides NoMove policy
+	NoMoveScore func(node SearchNode, maximizing bool) int
+	// Plies expanded by MinimaxConcurrent before the subtrees are handed over to the workers, 1 (root children) if zero
+	// Deeper split balances the load when the root has few children, but the expanded plies are not prunned.
+	SplitDepth int
+	// MinimaxConcurrent returns the same scores and searches the same nodes regardless of the scheduling of the workers,
+	// which then do not share the best root score nor the transposition table
+	Reproducible bool
+	// Goroutines are labeled by the search phase (PhaseLabel) for runtime/pprof CPU profiles
+	ProfileLabels bool
+	// Optional observer of the search events, e.g. TreeRecorder
+	Observer SearchObserver
+	// Optional endgame tablebase, probed nodes are leaves with the exact score
+	Tablebase TablebaseProber
+	// Only PieceCountNode nodes with at most this number of pieces are probed, all nodes if zero
+	TablebasePieces int
+
+	*searchState
+}
+
+// State of a single search, shared by the searched nodes behind a pointer, so the Searcher is cheap to pass
+type searchState struct {
+	rootKeys *tieKeys
+	path     repetitionPath
+	tt       *transpositionTable
+	limits   *searchLimits
+	pv       *pvTable
+	rng      *rand.Rand // of the PlayoutEvaluator, one per worker
+}
+
 func Minimax(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
-	if depth == 0 || node.IsTerminal() {
-		return node, node.Score()
+	return Searcher{}.Minimax(node, depth, maximizing)
+}
+
+func MinimaxAlphaBetaPrunning(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	return Searcher{}.MinimaxAlphaBetaPrunning(node, depth, maximizing)
+}
+
+func (s Searcher) Minimax(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	s = s.newSearch()
+	return s.minimaxImpl(node, nil, 0, depth, 0, maximizing)
+}
+
+func (s *Searcher) minimaxImpl(node, parent SearchNode, parentScore, depth, ply int, maximizing bool) (SearchNode, int) {
+	if s.limits.stop() {
+		return nil, 0
 	}
+	s.pv.clear(ply)
+	s.observe(EventEnter, node, ply, math.MinInt, math.MaxInt, 0)
+	if leaf := s.probeLeaf(node, ply); depth == 0 || leaf.leaf {
+		s.markHorizon(leaf, depth)
+		score := s.leafScore(leaf, node, parent, parentScore, ply)
+		s.observe(EventLeaf, node, ply, math.MinInt, math.MaxInt, score)
+		return node, score
+	}
+	s.pushPath(node)
+	defer s.popPath(node)
+	maximizing = playerToMove(node, maximizing)
+	score := s.interiorScore(node, parent, parentScore)
 	// default minimizing player
 	var bestNode SearchNode
 	bestScore := MinimaxInitScore(maximizing)
-	for generator := node.SearchNodeGenerator(); ; {
-		childNode := generator(maximizing)
+	bestIndex := -1
+	children := newChildIterator(node)
+	defer children.release()
+	for index := 0; ; index++ {
+		childNode := children.next(maximizing)
+		if childNode == nil && index == 0 {
+			noMoveScore := s.noMoveScore(node, parent, parentScore, ply, maximizing, func() int {
+				_, passScore := s.minimaxImpl(node, parent, parentScore, depth-1, ply+1, !maximizing)
+				return passScore
+			})
+			s.observe(EventLeave, node, ply, math.MinInt, math.MaxInt, noMoveScore)
+			return nil, noMoveScore
+		}
 		if childNode == nil {
 			break
 		}
-		_, newScore := Minimax(childNode, depth-1, !maximizing)
-		if (maximizing && newScore >= bestScore) || (!maximizing && newScore <= bestScore) {
+		_, newScore := s.minimaxImpl(childNode, node, score, depth-1, ply+1, nextPlayer(childNode, maximizing))
+		if bestNode == nil || (maximizing && newScore > bestScore) || (!maximizing && newScore < bestScore) ||
+			(newScore == bestScore && s.preferTie(childNode, bestNode, index, bestIndex, ply)) {
+			childNode, bestNode = bestNode, childNode
 			bestScore = newScore
-			bestNode = childNode
+			bestIndex = index
+			s.pv.update(ply, bestNode)
+			s.observe(EventBest, bestNode, ply, math.MinInt, math.MaxInt, bestScore)
 		}
+		// the worse one of them
+		if childNode != nil {
+			s.recycle(childNode)
+		}
+	}
+	s.observe(EventLeave, node, ply, math.MinInt, math.MaxInt, bestScore)
+	if ply > 0 {
+		// only the root's best child is used by the caller
+		s.recycle(bestNode)
 	}
 	return bestNode, bestScore
 }
 
-func MinimaxAlphaBetaPrunning(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+func (s Searcher) MinimaxAlphaBetaPrunning(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
 	var alpha, beta int
 	alpha, beta = math.MinInt, math.MaxInt
-	return minimaxAlphaBetaPrunningImpl(node, depth, alpha, beta, maximizing)
+	s = s.newSearch()
+	return s.minimaxAlphaBetaPrunningImpl(node, nil, 0, depth, 0, alpha, beta, maximizing)
 }
 
-func minimaxAlphaBetaPrunningImpl(node SearchNode, depth, alpha, beta int, maximizing bool) (SearchNode, int) {
-	if depth <= 0 || node.IsTerminal() {
-		return node, node.Score()
+func (s *Searcher) minimaxAlphaBetaPrunningImpl(node, parent SearchNode, parentScore, depth, ply, alpha, beta int, maximizing bool) (SearchNode, int) {
+	if s.limits.stop() {
+		return nil, 0
+	}
+	s.pv.clear(ply)
+	s.observe(EventEnter, node, ply, alpha, beta, 0)
+	if leaf := s.probeLeaf(node, ply); depth <= 0 || leaf.leaf {
+		s.markHorizon(leaf, depth)
+		score := s.leafScore(leaf, node, parent, parentScore, ply)
+		s.observe(EventLeaf, node, ply, alpha, beta, score)
+		return node, score
 	}
+	maximizing = playerToMove(node, maximizing)
+	key, hashed := s.tt.key(node, maximizing)
+	if ttScore, found := s.probeTT(key, hashed, depth, ply, alpha, beta); found {
+		s.observe(EventLeaf, node, ply, alpha, beta, ttScore)
+		return nil, ttScore
+	}
+	horizon := s.limits.horizonCount()
+	s.pushPath(node)
+	defer s.popPath(node)
+	score := s.interiorScore(node, parent, parentScore)
+	alphaOrig, betaOrig := alpha, beta
 	// default minimizing player
 	var bestNode SearchNode
 	bestScore := MinimaxInitScore(maximizing)
-	for generator := node.SearchNodeGenerator(); ; {
-		childNode := generator(maximizing)
+	bestIndex := -1
+	children := newChildIterator(node)
+	defer children.release()
+	for index := 0; ; index++ {
+		childNode := children.next(maximizing)
+		if childNode == nil && index == 0 {
+			noMoveScore := s.noMoveScore(node, parent, parentScore, ply, maximizing, func() int {
+				_, passScore := s.minimaxAlphaBetaPrunningImpl(node, parent, parentScore, depth-1, ply+1, alpha, beta, !maximizing)
+				return passScore
+			})
+			s.observe(EventLeave, node, ply, alpha, beta, noMoveScore)
+			return nil, noMoveScore
+		}
 		if childNode == nil {
 			break
 		}
-		_, newScore := minimaxAlphaBetaPrunningImpl(childNode, depth-1, alpha, beta, !maximizing)
+		childAlpha, childBeta := s.tieWindow(alpha, beta, maximizing, bestNode != nil, ply)
+		_, newScore := s.minimaxAlphaBetaPrunningImpl(childNode, node, score, depth-1, ply+1, childAlpha, childBeta, nextPlayer(childNode, maximizing))
+		tie := bestNode != nil && newScore == bestScore && s.preferTie(childNode, bestNode, index, bestIndex, ply)
 		if maximizing {
-			if newScore > alpha {
+			if newScore > alpha || tie {
 				alpha = newScore
-				bestNode = childNode
+				childNode, bestNode = bestNode, childNode
 				bestScore = newScore
+				bestIndex = index
+				s.pv.update(ply, bestNode)
+				s.observe(EventBest, bestNode, ply, alpha, beta, bestScore)
 			}
 		} else {
-			if newScore < beta {
+			if newScore < beta || tie {
 				beta = newScore
-				bestNode = childNode
+				childNode, bestNode = bestNode, childNode
 				bestScore = newScore
+				bestIndex = index
+				s.pv.update(ply, bestNode)
+				s.observe(EventBest, bestNode, ply, alpha, beta, bestScore)
 			}
 		}
+		// the worse one of them
+		if childNode != nil {
+			s.recycle(childNode)
+		}
 		if alpha >= beta {
+			s.observe(EventCutoff, node, ply, alpha, beta, bestScore)
 			break
 		}
 	}
+	s.storeTT(key, hashed, horizon, depth, ply, alphaOrig, betaOrig, maximizing, bestNode != nil, bestScore)
+	s.observe(EventLeave, node, ply, alphaOrig, betaOrig, bestScore)
+	if ply > 0 {
+		// only the root's best child is used by the caller
+		s.recycle(bestNode)
+	}
 	return bestNode, bestScore
 }
 
@@ -78,3 +321,209 @@ func MinimaxInitScore(maximizing bool) int {
 	}
 	return math.MaxInt
 }
+
+// Copy of searcher with fresh per search state
+func (s Searcher) newSearch() Searcher {
+	s.searchState = &searchState{path: newRepetitionPath(s.History)}
+	if s.Rand != nil {
+		s.rootKeys = &tieKeys{rng: s.Rand}
+	}
+	return s
+}
+
+// Copy of the search state for a goroutine continuing the search from the same path
+func (state *searchState) fork() *searchState {
+	forked := *state
+	forked.path = state.path.clone()
+	forked.rng = nil
+	return &forked
+}
+
+func (s *Searcher) score(node SearchNode) int {
+	if playout, ok := s.Evaluator.(*playoutEvaluator); ok && s.searchState != nil {
+		if s.rng == nil {
+			s.rng = playout.workerRand()
+		}
+		return playout.evaluate(node, s.DrawScore, s.rng)
+	}
+	if s.Evaluator != nil {
+		return s.Evaluator.Evaluate(node)
+	}
+	return node.Score()
+}
+
+func (s *Searcher) isTerminal(node SearchNode) bool {
+	return node.IsTerminal() || isDraw(node)
+}
+
+// Why a node is not expanded, probed once per searched node and passed along to its scoring
+type leafProbe struct {
+	leaf       bool
+	draw       bool // drawn or repeated node
+	repetition bool // the draw depends on the searched path
+	tablebase  bool
+	score      int // of the tablebase
+}
+
+// Leaf status of the node in given ply from the root
+func (s *Searcher) probeLeaf(node SearchNode, ply int) leafProbe {
+	if isDraw(node) {
+		return leafProbe{leaf: true, draw: true}
+	}
+	if s.isRepetition(node, ply) {
+		return leafProbe{leaf: true, draw: true, repetition: true}
+	}
+	if score, ok := s.probeTablebase(node, ply); ok {
+		return leafProbe{leaf: true, tablebase: true, score: score}
+	}
+	return leafProbe{leaf: node.IsTerminal()}
+}
+
+// Node which is not expanded in given ply from the root
+func (s *Searcher) isLeaf(node SearchNode, ply int) bool {
+	return s.probeLeaf(node, ply).leaf
+}
+
+func isDraw(node SearchNode) bool {
+	drawNode, ok := optionalNode(node).(DrawNode)
+	return ok && drawNode.IsDraw()
+}
+
+// Final score of a leaf node in given ply from the root, or of a node cut by the depth
+func (s *Searcher) leafScore(leaf leafProbe, node, parent SearchNode, parentScore, ply int) int {
+	if leaf.draw {
+		return s.DrawScore
+	}
+	if leaf.tablebase {
+		return s.winDistanceScore(leaf.score, ply)
+	}
+	return s.winDistanceScore(s.incrementalScore(node, parent, parentScore), ply)
+}
+
+func (s *Searcher) winDistanceScore(score, ply int) int {
+	if s.WinScore <= 0 {
+		return score
+	}
+	if score >= s.WinScore {
+		return score - ply
+	}
+	if score <= -s.WinScore {
+		return score + ply
+	}
+	return score
+}
+
+// Node's score computed from the parent's score if the node supports it
+func (s *Searcher) incrementalScore(node, parent SearchNode, parentScore int) int {
+	if incNode, ok := node.(IncrementalNode); ok && parent != nil && s.Evaluator == nil {
+		return parentScore + incNode.ScoreDelta(parent)
+	}
+	return s.score(node)
+}
+
+// Score of inner node is needed only to pass it down to the incremental children
+func (s *Searcher) interiorScore(node, parent SearchNode, parentScore int) int {
+	if _, ok := node.(IncrementalNode); ok && s.Evaluator == nil {
+		return s.incrementalScore(node, parent, parentScore)
+	}
+	return 0
+}
+
+// Whether maximizing player is to move in the node, defaults to the given flag if the node does not know
+func playerToMove(node SearchNode, maximizing bool) bool {
+	if playerNode, ok := optionalNode(node).(PlayerNode); ok {
+		return playerNode.PlayerToMove() == MaximizingPlayer
+	}
+	return maximizing
+}
+
+// Whether maximizing player is to move in the child of a node where the given player moved
+func nextPlayer(child SearchNode, maximizing bool) bool {
+	if extraTurnNode, ok := optionalNode(child).(ExtraTurnNode); ok && extraTurnNode.ExtraTurn() {
+		return maximizing
+	}
+	return !maximizing
+}
+
+func nodeGenerator(node SearchNode) SearchNodeGenerator {
+	generator := allChildrenGenerator(node)
+	if _, ok := node.(SymmetryNode); ok {
+		return symmetryGenerator(generator)
+	}
+	return generator
+}
+
+// Generator of all the node's children including the symmetric ones
+func allChildrenGenerator(node SearchNode) SearchNodeGenerator {
+	if childrenNode, ok := node.(ChildrenNode); ok {
+		return ChildrenGenerator(childrenNode)
+	}
+	if appendNode, ok := node.(AppendChildrenNode); ok {
+		return appendChildrenGenerator(appendNode)
+	}
+	return node.SearchNodeGenerator()
+}
+
+// Generator of AppendChildrenNode's children for searches without pooled buffers
+func appendChildrenGenerator(node AppendChildrenNode) SearchNodeGenerator {
+	var children []SearchNode
+	generated := false
+	return func(maximizing bool) SearchNode {
+		if !generated {
+			children = node.AppendChildren(nil, maximizing)
+			generated = true
+		}
+		if len(children) == 0 {
+			return nil
+		}
+		child := children[0]
+		children = children[1:]
+		return child
+	}
+}
+
+// Children buffers of AppendChildrenNode nodes
+var childBuffers = sync.Pool{New: func() any { return new([]SearchNode) }}
+
+// Children of a node generated by AppendChildrenNode into a pooled buffer, or by node's generator
+// Has to be released once the node is searched.
+type childIterator struct {
+	node      SearchNode
+	generator SearchNodeGenerator
+	buffer    *[]SearchNode
+	index     int
+}
+
+func newChildIterator(node SearchNode) childIterator {
+	if _, ok := node.(AppendChildrenNode); !ok {
+		return childIterator{generator: nodeGenerator(node)}
+	}
+	if _, ok := node.(SymmetryNode); ok {
+		return childIterator{generator: nodeGenerator(node)}
+	}
+	return childIterator{node: node}
+}
+
+func (children *childIterator) next(maximizing bool) SearchNode {
+	if children.generator != nil {
+		return children.generator(maximizing)
+	}
+	if children.buffer == nil {
+		children.buffer = childBuffers.Get().(*[]SearchNode)
+		*children.buffer = children.node.(AppendChildrenNode).AppendChildren((*children.buffer)[:0], maximizing)
+	}
+	if children.index == len(*children.buffer) {
+		return nil
+	}
+	children.index++
+	return (*children.buffer)[children.index-1]
+}
+
+func (children *childIterator) release() {
+	if children.buffer != nil {
+		// the children are not kept alive by the pool
+		clear(*children.buffer)
+		childBuffers.Put(children.buffer)
+		children.buffer = nil
+	}
+}
@@ -0,0 +1,52 @@
+package csa
+
+import (
+	"math"
+)
+
+// All root children achieving the best score (in generator order) using alpha-beta prunning
+func MinimaxAllBest(node SearchNode, depth int, maximizing bool) ([]SearchNode, int) {
+	return Searcher{}.MinimaxAllBest(node, depth, maximizing)
+}
+
+func (s Searcher) MinimaxAllBest(node SearchNode, depth int, maximizing bool) ([]SearchNode, int) {
+	if leaf := s.probeLeaf(node, 0); depth <= 0 || leaf.leaf {
+		return nil, s.leafScore(leaf, node, nil, 0, 0)
+	}
+	maximizing = playerToMove(node, maximizing)
+	s = s.newSearch()
+	s.pushPath(node)
+	defer s.popPath(node)
+	score := s.interiorScore(node, nil, 0)
+	var bestNodes []SearchNode
+	bestScore := MinimaxInitScore(maximizing)
+	for generator := nodeGenerator(node); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			break
+		}
+		// window widened by one, so children equal to the best one get exact score
+		alpha, beta := math.MinInt, math.MaxInt
+		if len(bestNodes) > 0 {
+			if maximizing && bestScore != math.MinInt {
+				alpha = bestScore - 1
+			} else if !maximizing && bestScore != math.MaxInt {
+				beta = bestScore + 1
+			}
+		}
+		_, newScore := s.minimaxAlphaBetaPrunningImpl(childNode, node, score, depth-1, 1, alpha, beta, nextPlayer(childNode, maximizing))
+		if len(bestNodes) == 0 || (maximizing && newScore > bestScore) || (!maximizing && newScore < bestScore) {
+			bestNodes = []SearchNode{childNode}
+			bestScore = newScore
+		} else if newScore == bestScore {
+			bestNodes = append(bestNodes, childNode)
+		}
+	}
+	if len(bestNodes) == 0 {
+		return nil, s.noMoveScore(node, nil, 0, 0, maximizing, func() int {
+			_, passScore := s.minimaxAlphaBetaPrunningImpl(node, nil, 0, depth-1, 1, math.MinInt, math.MaxInt, !maximizing)
+			return passScore
+		})
+	}
+	return bestNodes, bestScore
+}
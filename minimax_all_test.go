@@ -0,0 +1,28 @@
+package csa
+
+import "testing"
+
+func TestTTTMinimaxAllBest(t *testing.T) {
+	nodes, score := MinimaxAllBest(tttNode{}, 9, true)
+	if len(nodes) != 9 || score != 0 {
+		t.Errorf("All moves on empty board are a draw, got %d moves with score %d", len(nodes), score)
+	}
+	node := tttNode{}
+	node.board[0][0] = cross
+	node.board[1][1] = circle
+	node.board[2][2] = cross
+	// only edges hold the draw for circle
+	nodes, score = MinimaxAllBest(node, 9, true)
+	if len(nodes) != 4 || score != 0 {
+		t.Errorf("Expected four drawing moves, got %d with score %d", len(nodes), score)
+	}
+	for _, n := range nodes {
+		b := n.(tttNode).board
+		if b[0][2] == circle || b[2][0] == circle {
+			t.Errorf("Corner move loses %s", n)
+		}
+		if _, s := Minimax(n, 9, false); s != score {
+			t.Errorf("Move is not optimal %s", n)
+		}
+	}
+}
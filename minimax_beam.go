@@ -0,0 +1,100 @@
+package csa
+
+import (
+	"math"
+	"sort"
+)
+
+// Static ordering hook used by beam search, higher value means better node for maximizing player
+type NodeOrdering func(node SearchNode) int
+
+// Minimax with alpha-beta prunning where only the best width children (according to ordering) of each node are searched.
+// If ordering is nil, node's Score (or searcher's Evaluator) is used instead.
+func MinimaxBeam(node SearchNode, depth int, maximizing bool, width int, ordering NodeOrdering) (SearchNode, int) {
+	return Searcher{}.MinimaxBeam(node, depth, maximizing, width, ordering)
+}
+
+func (s Searcher) MinimaxBeam(node SearchNode, depth int, maximizing bool, width int, ordering NodeOrdering) (SearchNode, int) {
+	if ordering == nil {
+		ordering = s.score
+	}
+	var alpha, beta int
+	alpha, beta = math.MinInt, math.MaxInt
+	s = s.newSearch()
+	return s.minimaxBeamImpl(node, nil, 0, depth, 0, alpha, beta, maximizing, width, ordering)
+}
+
+func (s *Searcher) minimaxBeamImpl(node, parent SearchNode, parentScore, depth, ply, alpha, beta int, maximizing bool, width int, ordering NodeOrdering) (SearchNode, int) {
+	if leaf := s.probeLeaf(node, ply); depth <= 0 || leaf.leaf {
+		return node, s.leafScore(leaf, node, parent, parentScore, ply)
+	}
+	s.pushPath(node)
+	defer s.popPath(node)
+	maximizing = playerToMove(node, maximizing)
+	score := s.interiorScore(node, parent, parentScore)
+	var bestNode SearchNode
+	bestScore := MinimaxInitScore(maximizing)
+	bestIndex := -1
+	children := beamChildren(node, maximizing, width, ordering)
+	if len(children) == 0 {
+		return nil, s.noMoveScore(node, parent, parentScore, ply, maximizing, func() int {
+			_, passScore := s.minimaxBeamImpl(node, parent, parentScore, depth-1, ply+1, alpha, beta, !maximizing, width, ordering)
+			return passScore
+		})
+	}
+	for index, childNode := range children {
+		childAlpha, childBeta := s.tieWindow(alpha, beta, maximizing, bestNode != nil, ply)
+		_, newScore := s.minimaxBeamImpl(childNode, node, score, depth-1, ply+1, childAlpha, childBeta, nextPlayer(childNode, maximizing), width, ordering)
+		tie := bestNode != nil && newScore == bestScore && s.preferTie(childNode, bestNode, index, bestIndex, ply)
+		if maximizing {
+			if newScore > alpha || tie {
+				alpha = newScore
+				bestNode = childNode
+				bestScore = newScore
+				bestIndex = index
+			}
+		} else {
+			if newScore < beta || tie {
+				beta = newScore
+				bestNode = childNode
+				bestScore = newScore
+				bestIndex = index
+			}
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return bestNode, bestScore
+}
+
+type beamChild struct {
+	node SearchNode
+	key  int
+}
+
+func beamChildren(node SearchNode, maximizing bool, width int, ordering NodeOrdering) []SearchNode {
+	var children []beamChild
+	for generator := nodeGenerator(node); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			break
+		}
+		children = append(children, beamChild{childNode, ordering(childNode)})
+	}
+	// stable to keep generator order among equal children
+	sort.SliceStable(children, func(i, j int) bool {
+		if maximizing {
+			return children[i].key > children[j].key
+		}
+		return children[i].key < children[j].key
+	})
+	if width > 0 && len(children) > width {
+		children = children[:width]
+	}
+	nodes := make([]SearchNode, len(children))
+	for i := range children {
+		nodes[i] = children[i].node
+	}
+	return nodes
+}
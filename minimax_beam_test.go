@@ -0,0 +1,30 @@
+package csa
+
+import "testing"
+
+func TestTTTMinimaxBeam(t *testing.T) {
+	var sn SearchNode = tttNode{}
+	maximizing := true
+	for i := 0; i < 9; i++ {
+		beamNode, beamScore := MinimaxBeam(sn, 9, maximizing, 9, nil)
+		abNode, abScore := MinimaxAlphaBetaPrunning(sn, 9, maximizing)
+		if beamScore != abScore || beamNode != abNode {
+			t.Errorf("Full width beam differs from alpha-beta %s", sn)
+		}
+		sn = beamNode
+		maximizing = !maximizing
+	}
+	if sn.Score() != empty {
+		t.Errorf("Score is not a draw %s", sn)
+	}
+	// narrow beam must search only the first children in the order
+	visited := 0
+	ordering := func(node SearchNode) int {
+		visited++
+		return 0
+	}
+	node, _ := MinimaxBeam(tttNode{}, 1, false, 2, ordering)
+	if visited != 9 || node.(tttNode).board[0][0] != cross {
+		t.Errorf("Unexpected beam node %s", node)
+	}
+}
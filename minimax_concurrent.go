@@ -1,27 +1,101 @@
 package csa
 
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Upper limit of the number of workers, more goroutines than that only add scheduling overhead
+const maxWorkers = 1024
+
 func MinimaxConcurrent(node SearchNode, depth int, maximizing bool, workers int) (SearchNode, int) {
-	if depth == 0 || node.IsTerminal() {
-		return node, node.Score()
-	}
-	// setup workers
-	jobs := make(chan workerJob, workers*5)
-	results := make(chan workerResult, workers*5)
+	return Searcher{}.MinimaxConcurrent(node, depth, maximizing, workers)
+}
+
+// MinimaxConcurrent with one worker per available CPU
+func MinimaxParallel(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	return Searcher{}.MinimaxParallel(node, depth, maximizing)
+}
+
+func (s Searcher) MinimaxParallel(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	return s.MinimaxConcurrent(node, depth, maximizing, 0)
+}
+
+// Root children are searched in parallel by given number of workers, which exist only during the call
+// Zero or negative number of workers means runtime.GOMAXPROCS(0). Engine keeps its workers between the searches instead.
+// Panic of a worker is re-raised as *PanicError after all the workers stopped.
+func (s Searcher) MinimaxConcurrent(node SearchNode, depth int, maximizing bool, workers int) (SearchNode, int) {
+	pool := newWorkerPool(workers)
+	defer pool.close()
+	s = s.newSearch()
+	return s.minimaxConcurrent(node, depth, maximizing, pool)
+}
+
+// Fixed number of goroutines running submitted tasks until closed
+type workerPool struct {
+	workers int
+	tasks   chan func()
+	wg      sync.WaitGroup
+	idle    atomic.Int64 // number of workers waiting for a task
+}
+
+func newWorkerPool(workers int) *workerPool {
+	return startWorkerPool(workerCount(workers))
+}
+
+// Pool of exactly given number of workers, the tasks are handed over directly to the waiting workers
+func startWorkerPool(workers int) *workerPool {
+	pool := &workerPool{workers: workers, tasks: make(chan func())}
 	for i := 0; i < workers; i++ {
-		go minimaxConcurrentWorker(jobs, results)
+		pool.wg.Add(1)
+		go func() {
+			defer pool.wg.Done()
+			for {
+				pool.idle.Add(1)
+				task, ok := <-pool.tasks
+				pool.idle.Add(-1)
+				if !ok {
+					return
+				}
+				task()
+			}
+		}()
 	}
-	// feed workers
-	totalJobs := make(chan int)
-	go minimaxConcurrentFeeder(node, depth, maximizing, jobs, totalJobs)
-	// consume results
-	return minimaxConcurrentConsumer(maximizing, jobs, results, totalJobs)
+	return pool
 }
 
-type workerJob struct {
-	id         int
-	node       SearchNode
-	depth      int
-	maximizing bool
+func workerCount(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return min(workers, maxWorkers)
+}
+
+func (pool *workerPool) submit(task func()) {
+	pool.tasks <- task
+}
+
+func (pool *workerPool) hasIdle() bool {
+	return pool.idle.Load() > 0
+}
+
+// Hands the task over to an idle worker, returns false if all workers are busy
+func (pool *workerPool) trySubmit(task func()) bool {
+	select {
+	case pool.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// Waits for the submitted tasks and stops the workers, the pool owns the channel so only it closes it
+func (pool *workerPool) close() {
+	close(pool.tasks)
+	pool.wg.Wait()
 }
 
 type workerResult struct {
@@ -30,49 +104,209 @@ type workerResult struct {
 	score int
 }
 
-func minimaxConcurrentWorker(jobs <-chan workerJob, results chan<- workerResult) {
-	for job := range jobs {
-		_, score := MinimaxAlphaBetaPrunning(job.node, job.depth, job.maximizing)
-		results <- workerResult{job.id, job.node, score}
+func (s *Searcher) minimaxConcurrent(node SearchNode, depth int, maximizing bool, pool *workerPool) (SearchNode, int) {
+	if leaf := s.probeLeaf(node, 0); depth == 0 || leaf.leaf {
+		s.markHorizon(leaf, depth)
+		return node, s.leafScore(leaf, node, nil, 0, 0)
 	}
-}
-
-func minimaxConcurrentFeeder(node SearchNode, depth int, maximizing bool, jobs chan<- workerJob, totalJobs chan<- int) {
-	counter := 0
-	for generator := node.SearchNodeGenerator(); ; {
+	if s.SplitDepth > 1 {
+		return s.minimaxConcurrentSplit(node, depth, maximizing, pool)
+	}
+	maximizing = playerToMove(node, maximizing)
+	// workers continue from the root
+	s.pushPath(node)
+	defer s.popPath(node)
+	score := s.interiorScore(node, nil, 0)
+	var children []SearchNode
+	for generator := nodeGenerator(node); ; {
 		childNode := generator(maximizing)
 		if childNode == nil {
-			totalJobs <- counter
-			return
+			break
 		}
-		jobs <- workerJob{counter, childNode, depth - 1, !maximizing}
-		counter++
+		children = append(children, childNode)
+	}
+	// buffered for all results, so the workers never wait for the consumer
+	results := make(chan workerResult, len(children))
+	bound := newRootBound(maximizing)
+	var trap panicTrap
+	for id, childNode := range children {
+		job := s.job()
+		pool.submit(func() {
+			// the result is sent even if the search panics, so the consumer does not wait forever
+			result := workerResult{id, childNode, MinimaxInitScore(maximizing)}
+			defer func() { results <- result }()
+			defer trap.catch()
+			if trap.failed() {
+				return
+			}
+			// root children are in the first ply
+			alpha, beta := math.MinInt, math.MaxInt
+			if !s.Reproducible {
+				alpha, beta = bound.window(maximizing)
+			}
+			labeled(context.Background(), s.ProfileLabels, func() {
+				_, result.score = job.minimaxAlphaBetaPrunningImpl(childNode, node, score, depth-1, 1, alpha, beta, nextPlayer(childNode, maximizing))
+			}, PhaseLabel, "root-split")
+			bound.update(result.score, maximizing)
+		})
+	}
+	bestNode, bestScore := s.minimaxConcurrentConsumer(maximizing, results, len(children))
+	trap.rethrow()
+	s.pv.clear(0)
+	s.pv.clear(1)
+	if bestNode != nil {
+		s.pv.update(0, bestNode)
+	}
+	if bestNode == nil {
+		return nil, s.noMoveScore(node, nil, 0, 0, maximizing, func() int {
+			_, passScore := s.minimaxAlphaBetaPrunningImpl(node, nil, 0, depth-1, 1, math.MinInt, math.MaxInt, !maximizing)
+			return passScore
+		})
 	}
+	return bestNode, bestScore
 }
 
-func minimaxConcurrentConsumer(maximizing bool, jobs chan workerJob, results chan workerResult, totalJobs chan int) (SearchNode, int) {
+// Searcher of a worker's job, each job has its own copy of the searched path and does not keep the lines
+func (s Searcher) job() Searcher {
+	job := s
+	job.searchState = s.fork()
+	job.pv = nil
+	if s.Reproducible {
+		job.tt = nil
+	}
+	return job
+}
+
+func (s *Searcher) minimaxConcurrentConsumer(maximizing bool, results <-chan workerResult, numJobs int) (SearchNode, int) {
 	best := workerResult{-1, nil, MinimaxInitScore(maximizing)}
-	numResults, numJobs := 0, -1
+	for i := 0; i < numJobs; i++ {
+		result := <-results
+		if best.node == nil || (maximizing && result.score > best.score) || (!maximizing && result.score < best.score) {
+			best = result
+		} else if result.score == best.score && s.preferTie(result.node, best.node, result.jobId, best.jobId, 0) {
+			// ties decided by jobId (generator index), otherwise we could get non-deterministic results
+			best = result
+		}
+	}
+	return best.node, best.score
+}
+
+// Best root score found so far by any of the workers, shared so the later root children are searched
+// only for scores at least as good
+type rootBound struct {
+	score atomic.Int64
+}
+
+func newRootBound(maximizing bool) *rootBound {
+	bound := &rootBound{}
+	bound.score.Store(int64(MinimaxInitScore(maximizing)))
+	return bound
+}
+
+// Window widened by one, so children equal to the best one get exact score for tie-breaking
+// and the worse ones fail strictly below it
+func (bound *rootBound) window(maximizing bool) (int, int) {
+	score := int(bound.score.Load())
+	if maximizing && score != math.MinInt {
+		return score - 1, math.MaxInt
+	}
+	if !maximizing && score != math.MaxInt {
+		return math.MinInt, score + 1
+	}
+	return math.MinInt, math.MaxInt
+}
+
+func (bound *rootBound) update(score int, maximizing bool) {
 	for {
-		select {
-		case result := <-results:
-			if (maximizing && result.score > best.score) || (!maximizing && result.score < best.score) {
-				best = result
-			}
-			if result.score == best.score && result.jobId < best.jobId {
-				// if not ordered by jobId, we could get non-deterministic results
-				best = result
+		current := bound.score.Load()
+		if (maximizing && int64(score) <= current) || (!maximizing && int64(score) >= current) ||
+			bound.score.CompareAndSwap(current, int64(score)) {
+			return
+		}
+	}
+}
+
+// Node of the tree expanded before the search, the leaves are searched by the workers
+type splitTask struct {
+	node       SearchNode
+	maximizing bool
+	children   []*splitTask
+	score      int // of the leaf, set by the worker
+}
+
+func (s *Searcher) minimaxConcurrentSplit(node SearchNode, depth int, maximizing bool, pool *workerPool) (SearchNode, int) {
+	var wg sync.WaitGroup
+	var trap panicTrap
+	root := s.splitTrees(node, depth, maximizing, pool, &wg, &trap)
+	trap.rethrow()
+	bestNode, bestScore := s.splitTreeScore(root, 0)
+	s.pv.clear(0)
+	s.pv.clear(1)
+	if root.children == nil {
+		// root without children searched by a worker
+		return nil, bestScore
+	}
+	s.pv.update(0, bestNode)
+	return bestNode, bestScore
+}
+
+// Expanded tree of the root whose leaves were searched, even if the expansion panics the submitted leaves are waited for
+func (s *Searcher) splitTrees(node SearchNode, depth int, maximizing bool, pool *workerPool, wg *sync.WaitGroup, trap *panicTrap) *splitTask {
+	defer wg.Wait()
+	return s.splitTree(node, nil, 0, depth, 0, maximizing, pool, wg, trap)
+}
+
+// Expands the node up to SplitDepth plies from the root and submits the leaves to the workers
+func (s *Searcher) splitTree(node, parent SearchNode, parentScore, depth, ply int, maximizing bool, pool *workerPool, wg *sync.WaitGroup, trap *panicTrap) *splitTask {
+	task := &splitTask{node: node}
+	if ply < s.SplitDepth && depth > 0 && !s.isLeaf(node, ply) {
+		task.maximizing = playerToMove(node, maximizing)
+		s.pushPath(node)
+		score := s.interiorScore(node, parent, parentScore)
+		for generator := nodeGenerator(node); ; {
+			childNode := generator(task.maximizing)
+			if childNode == nil {
+				break
 			}
-			numResults++
-		case numJobs = <-totalJobs:
-			// all jobs have been assigned
+			child := s.splitTree(childNode, node, score, depth-1, ply+1, nextPlayer(childNode, task.maximizing), pool, wg, trap)
+			task.children = append(task.children, child)
+		}
+		s.popPath(node)
+		if task.children != nil {
+			return task
 		}
-		if numJobs >= 0 && numResults == numJobs {
-			// all jobs have been assigned and also finished successfully
-			close(jobs)
-			close(results)
-			close(totalJobs)
-			return best.node, best.score
+	}
+	job := s.job()
+	wg.Add(1)
+	pool.submit(func() {
+		defer wg.Done()
+		defer trap.catch()
+		if trap.failed() {
+			return
+		}
+		labeled(context.Background(), s.ProfileLabels, func() {
+			_, task.score = job.minimaxAlphaBetaPrunningImpl(node, parent, parentScore, depth, ply, math.MinInt, math.MaxInt, maximizing)
+		}, PhaseLabel, "root-split")
+	})
+	return task
+}
+
+// Minimax of the expanded tree over the scores of the searched leaves
+func (s *Searcher) splitTreeScore(task *splitTask, ply int) (SearchNode, int) {
+	if task.children == nil {
+		return task.node, task.score
+	}
+	var bestNode SearchNode
+	bestScore := MinimaxInitScore(task.maximizing)
+	bestIndex := -1
+	for index, child := range task.children {
+		_, score := s.splitTreeScore(child, ply+1)
+		if bestNode == nil || (task.maximizing && score > bestScore) || (!task.maximizing && score < bestScore) ||
+			(score == bestScore && s.preferTie(child.node, bestNode, index, bestIndex, ply)) {
+			bestNode = child.node
+			bestScore = score
+			bestIndex = index
 		}
 	}
+	return bestNode, bestScore
 }
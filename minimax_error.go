@@ -0,0 +1,107 @@
+package csa
+
+type SearchNodeGeneratorE func(maximizing bool) (SearchNodeE, error)
+
+// Variant of SearchNode whose evaluation and move generation can fail, e.g. when querying an external database
+// The first error aborts the search and is returned by the E-suffixed minimax functions.
+type SearchNodeE interface {
+	Score() (int, error)
+	IsTerminal() bool
+	SearchNodeGenerator() SearchNodeGeneratorE
+}
+
+func MinimaxE(node SearchNodeE, depth int, maximizing bool) (SearchNodeE, int, error) {
+	return Searcher{}.MinimaxE(node, depth, maximizing)
+}
+
+func MinimaxAlphaBetaPrunningE(node SearchNodeE, depth int, maximizing bool) (SearchNodeE, int, error) {
+	return Searcher{}.MinimaxAlphaBetaPrunningE(node, depth, maximizing)
+}
+
+func MinimaxConcurrentE(node SearchNodeE, depth int, maximizing bool, workers int) (SearchNodeE, int, error) {
+	return Searcher{}.MinimaxConcurrentE(node, depth, maximizing, workers)
+}
+
+func (s Searcher) MinimaxE(node SearchNodeE, depth int, maximizing bool) (SearchNodeE, int, error) {
+	return searchE(s.Minimax, node, depth, maximizing)
+}
+
+func (s Searcher) MinimaxAlphaBetaPrunningE(node SearchNodeE, depth int, maximizing bool) (SearchNodeE, int, error) {
+	return searchE(s.MinimaxAlphaBetaPrunning, node, depth, maximizing)
+}
+
+// Besides the node errors, panic of any worker is returned as *PanicError
+func (s Searcher) MinimaxConcurrentE(node SearchNodeE, depth int, maximizing bool, workers int) (SearchNodeE, int, error) {
+	return searchE(func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+		return s.MinimaxConcurrent(node, depth, maximizing, workers)
+	}, node, depth, maximizing)
+}
+
+// Error raised from within the search, caught by searchE
+type searchError struct {
+	err error
+}
+
+// Adapter of SearchNodeE to SearchNode, errors are propagated by panicking
+type errorNode struct {
+	node SearchNodeE
+}
+
+func (node errorNode) Score() int {
+	score, err := node.node.Score()
+	if err != nil {
+		panic(searchError{err})
+	}
+	return score
+}
+
+func (node errorNode) IsTerminal() bool {
+	return node.node.IsTerminal()
+}
+
+func (node errorNode) SearchNodeGenerator() SearchNodeGenerator {
+	generator := node.node.SearchNodeGenerator()
+	return func(maximizing bool) SearchNode {
+		childNode, err := generator(maximizing)
+		if err != nil {
+			panic(searchError{err})
+		}
+		if childNode == nil {
+			return nil
+		}
+		return errorNode{childNode}
+	}
+}
+
+// Node implementing the optional interfaces, the SearchNodeE wrapped by the E-suffixed searches
+func optionalNode(node SearchNode) any {
+	if wrapped, ok := node.(errorNode); ok {
+		return wrapped.node
+	}
+	return node
+}
+
+func searchE(minimax func(SearchNode, int, bool) (SearchNode, int), node SearchNodeE, depth int, maximizing bool) (bestNode SearchNodeE, bestScore int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if panicErr, ok := r.(*PanicError); ok {
+				// raised by a worker, the node error is unwrapped
+				r = panicErr.Value
+				err = panicErr
+			}
+			if searchErr, ok := r.(searchError); ok {
+				err = searchErr.err
+			}
+			if err == nil {
+				// not ours
+				panic(r)
+			}
+			bestNode, bestScore = nil, 0
+		}
+	}()
+	resultNode, score := minimax(errorNode{node}, depth, maximizing)
+	if resultNode == nil {
+		return nil, score, nil
+	}
+	return resultNode.(errorNode).node, score, nil
+}
@@ -0,0 +1,85 @@
+package csa
+
+import (
+	"errors"
+	"testing"
+)
+
+// Node with evaluation which can fail
+type tttErrorNode struct {
+	tttNode
+	failAt int // fail when evaluating board with this number of empty squares
+}
+
+func (node tttErrorNode) Score() (int, error) {
+	if node.numberEmptySquares() == node.failAt {
+		return 0, errors.New("evaluation failed")
+	}
+	return node.tttNode.Score(), nil
+}
+
+func (node tttErrorNode) SearchNodeGenerator() SearchNodeGeneratorE {
+	generator := node.tttNode.SearchNodeGenerator()
+	return func(maximizing bool) (SearchNodeE, error) {
+		child := generator(maximizing)
+		if child == nil {
+			return nil, nil
+		}
+		return tttErrorNode{child.(tttNode), node.failAt}, nil
+	}
+}
+
+func TestTTTMinimaxE(t *testing.T) {
+	concurrent := func(node SearchNodeE, depth int, maximizing bool) (SearchNodeE, int, error) {
+		return MinimaxConcurrentE(node, depth, maximizing, 3)
+	}
+	for _, minimaxFn := range []func(SearchNodeE, int, bool) (SearchNodeE, int, error){MinimaxE, MinimaxAlphaBetaPrunningE, concurrent} {
+		node, score, err := minimaxFn(tttErrorNode{failAt: -1}, 9, true)
+		_, expectedScore := Minimax(tttNode{}, 9, true)
+		if err != nil || node == nil || score != expectedScore {
+			t.Errorf("Unexpected result %v %d %v", node, score, err)
+		}
+		if _, ok := node.(tttErrorNode); !ok {
+			t.Error("Result must be the original node type")
+		}
+		node, _, err = minimaxFn(tttErrorNode{failAt: 4}, 9, true)
+		if err == nil || err.Error() != "evaluation failed" || node != nil {
+			t.Errorf("Expected evaluation error, got %v", err)
+		}
+	}
+}
+
+// Node which knows whose turn it is and never fails
+type tttPlayerErrorNode struct {
+	tttPlayerNode
+}
+
+func (node tttPlayerErrorNode) Score() (int, error) {
+	return node.tttPlayerNode.Score(), nil
+}
+
+func (node tttPlayerErrorNode) SearchNodeGenerator() SearchNodeGeneratorE {
+	generator := node.tttPlayerNode.SearchNodeGenerator()
+	return func(maximizing bool) (SearchNodeE, error) {
+		child := generator(maximizing)
+		if child == nil {
+			return nil, nil
+		}
+		return tttPlayerErrorNode{child.(tttPlayerNode)}, nil
+	}
+}
+
+func TestTTTMinimaxEOptionalInterfaces(t *testing.T) {
+	node := tttPlayerNode{player: MinimizingPlayer}
+	node.board[0][0] = circle
+	node.board[1][1] = cross
+	node.board[0][1] = circle
+	// the passed flag is wrong, the player to move is taken from the wrapped node
+	expectedNode, expectedScore := MinimaxAlphaBetaPrunning(node, 9, true)
+	for _, minimaxFn := range []func(SearchNodeE, int, bool) (SearchNodeE, int, error){MinimaxE, MinimaxAlphaBetaPrunningE} {
+		bestNode, score, err := minimaxFn(tttPlayerErrorNode{node}, 9, true)
+		if err != nil || score != expectedScore || bestNode.(tttPlayerErrorNode).tttPlayerNode != expectedNode {
+			t.Errorf("Expected %s %d, got %v %d %v", expectedNode, expectedScore, bestNode, score, err)
+		}
+	}
+}
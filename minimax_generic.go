@@ -0,0 +1,73 @@
+package csa
+
+import (
+	"math"
+)
+
+// Generic counterpart of SearchNode, generator returns false once there are no more children
+// Children are passed by their concrete type, so there is no interface boxing nor type assertions on results.
+// The generic searches have no Searcher as methods cannot have type parameters, they score by node's Score
+// and keep the first generated of equally scored children like the zero Searcher. No other option applies.
+type Node[T any] interface {
+	Score() int
+	IsTerminal() bool
+	Generator() func(maximizing bool) (T, bool)
+}
+
+func MinimaxOf[T Node[T]](node T, depth int, maximizing bool) (T, int) {
+	if depth == 0 || node.IsTerminal() {
+		return node, node.Score()
+	}
+	var bestNode T
+	bestScore := MinimaxInitScore(maximizing)
+	found := false
+	for generator := node.Generator(); ; {
+		childNode, ok := generator(maximizing)
+		if !ok {
+			break
+		}
+		_, newScore := MinimaxOf(childNode, depth-1, !maximizing)
+		if !found || (maximizing && newScore > bestScore) || (!maximizing && newScore < bestScore) {
+			bestScore = newScore
+			bestNode = childNode
+			found = true
+		}
+	}
+	return bestNode, bestScore
+}
+
+func MinimaxAlphaBetaPrunningOf[T Node[T]](node T, depth int, maximizing bool) (T, int) {
+	return minimaxAlphaBetaPrunningOfImpl(node, depth, math.MinInt, math.MaxInt, maximizing)
+}
+
+func minimaxAlphaBetaPrunningOfImpl[T Node[T]](node T, depth, alpha, beta int, maximizing bool) (T, int) {
+	if depth <= 0 || node.IsTerminal() {
+		return node, node.Score()
+	}
+	var bestNode T
+	bestScore := MinimaxInitScore(maximizing)
+	for generator := node.Generator(); ; {
+		childNode, ok := generator(maximizing)
+		if !ok {
+			break
+		}
+		_, newScore := minimaxAlphaBetaPrunningOfImpl(childNode, depth-1, alpha, beta, !maximizing)
+		if maximizing {
+			if newScore > alpha {
+				alpha = newScore
+				bestNode = childNode
+				bestScore = newScore
+			}
+		} else {
+			if newScore < beta {
+				beta = newScore
+				bestNode = childNode
+				bestScore = newScore
+			}
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return bestNode, bestScore
+}
@@ -0,0 +1,25 @@
+package csa
+
+import "testing"
+
+func TestTTTMinimaxGeneric(t *testing.T) {
+	for _, minimaxFns := range []struct {
+		generic func(tttNode, int, bool) (tttNode, int)
+		boxed   func(SearchNode, int, bool) (SearchNode, int)
+	}{
+		{MinimaxOf[tttNode], Minimax},
+		{MinimaxAlphaBetaPrunningOf[tttNode], MinimaxAlphaBetaPrunning},
+	} {
+		node := tttNode{}
+		maximizing := false
+		for !node.IsTerminal() {
+			newNode, score := minimaxFns.generic(node, 9, maximizing)
+			expectedNode, expectedScore := minimaxFns.boxed(node, 9, maximizing)
+			if newNode != expectedNode || score != expectedScore {
+				t.Errorf("Generic variant differs %s%s", newNode, expectedNode)
+			}
+			node = newNode
+			maximizing = !maximizing
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package csa
+
+import (
+	"math"
+)
+
+func MinimaxAlphaBetaIterative(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	return Searcher{}.MinimaxAlphaBetaIterative(node, depth, maximizing)
+}
+
+// Alpha-beta prunning with an explicit stack instead of recursion, so the search depth is limited only by memory
+// Results are the same as of MinimaxAlphaBetaPrunning.
+func (s Searcher) MinimaxAlphaBetaIterative(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	s = s.newSearch()
+	return s.alphaBetaIterativeImpl(node, nil, 0, depth, 0, math.MinInt, math.MaxInt, maximizing)
+}
+
+// Node being searched, the frame of its parent is below it in the stack
+type abFrame struct {
+	node        SearchNode
+	parent      SearchNode
+	parentScore int
+	score       int
+	depth       int
+	alpha       int
+	beta        int
+	maximizing  bool
+	children    childIterator
+	index       int        // of the next generated child
+	child       SearchNode // being searched
+	bestNode    SearchNode
+	bestScore   int
+	bestIndex   int
+}
+
+func (s *Searcher) alphaBetaIterativeImpl(node, parent SearchNode, parentScore, depth, rootPly, alpha, beta int, maximizing bool) (SearchNode, int) {
+	var stack []abFrame
+	// pushes the node's frame, or returns its score if it is a leaf
+	enter := func(node, parent SearchNode, parentScore, depth, alpha, beta int, maximizing bool) (int, bool) {
+		ply := rootPly + len(stack)
+		if leaf := s.probeLeaf(node, ply); depth <= 0 || leaf.leaf {
+			return s.leafScore(leaf, node, parent, parentScore, ply), true
+		}
+		s.pushPath(node)
+		maximizing = playerToMove(node, maximizing)
+		stack = append(stack, abFrame{
+			node:        node,
+			parent:      parent,
+			parentScore: parentScore,
+			score:       s.interiorScore(node, parent, parentScore),
+			depth:       depth,
+			alpha:       alpha,
+			beta:        beta,
+			maximizing:  maximizing,
+			children:    newChildIterator(node),
+			bestScore:   MinimaxInitScore(maximizing),
+			bestIndex:   -1,
+		})
+		return 0, false
+	}
+	if score, leaf := enter(node, parent, parentScore, depth, alpha, beta, maximizing); leaf {
+		return node, score
+	}
+	var bestNode SearchNode
+	var childScore int
+	returned := false
+	for len(stack) > 0 {
+		frame := &stack[len(stack)-1]
+		ply := rootPly + len(stack) - 1
+		if returned {
+			returned = false
+			tie := frame.bestNode != nil && childScore == frame.bestScore &&
+				s.preferTie(frame.child, frame.bestNode, frame.index-1, frame.bestIndex, ply)
+			if frame.maximizing && (childScore > frame.alpha || tie) {
+				frame.alpha = childScore
+				frame.bestNode, frame.bestScore, frame.bestIndex = frame.child, childScore, frame.index-1
+			} else if !frame.maximizing && (childScore < frame.beta || tie) {
+				frame.beta = childScore
+				frame.bestNode, frame.bestScore, frame.bestIndex = frame.child, childScore, frame.index-1
+			}
+		}
+		var childNode SearchNode
+		if frame.index == 0 || frame.alpha < frame.beta {
+			childNode = frame.children.next(frame.maximizing)
+		}
+		if childNode == nil {
+			// node finished, its score is returned to the parent
+			bestNode, childScore = frame.bestNode, frame.bestScore
+			if frame.index == 0 {
+				bestNode, childScore = nil, s.noMoveScore(frame.node, frame.parent, frame.parentScore, ply, frame.maximizing, func() int {
+					_, passScore := s.alphaBetaIterativeImpl(frame.node, frame.parent, frame.parentScore, frame.depth-1, ply+1, frame.alpha, frame.beta, !frame.maximizing)
+					return passScore
+				})
+			}
+			frame.children.release()
+			s.popPath(frame.node)
+			stack = stack[:len(stack)-1]
+			returned = true
+			continue
+		}
+		frame.child = childNode
+		frame.index++
+		childAlpha, childBeta := s.tieWindow(frame.alpha, frame.beta, frame.maximizing, frame.bestNode != nil, ply)
+		// the frame pointer is invalidated by the push
+		score, depth := frame.score, frame.depth
+		if leafScore, leaf := enter(childNode, frame.node, score, depth-1, childAlpha, childBeta, nextPlayer(childNode, frame.maximizing)); leaf {
+			childScore = leafScore
+			returned = true
+		}
+	}
+	return bestNode, childScore
+}
@@ -0,0 +1,32 @@
+package csa
+
+import "testing"
+
+// Line of forced moves ending with a win
+type chainNode int
+
+func (node chainNode) Score() int {
+	return -int(node)
+}
+
+func (node chainNode) IsTerminal() bool {
+	return node == 0
+}
+
+func (node chainNode) SearchNodeGenerator() SearchNodeGenerator {
+	generated := false
+	return func(bool) SearchNode {
+		if generated {
+			return nil
+		}
+		generated = true
+		return node - 1
+	}
+}
+
+func TestMinimaxAlphaBetaIterativeDeep(t *testing.T) {
+	best, score := MinimaxAlphaBetaIterative(chainNode(100000), 200000, true)
+	if best != chainNode(99999) || score != 0 {
+		t.Errorf("Unexpected result %v %d", best, score)
+	}
+}
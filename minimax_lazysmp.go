@@ -0,0 +1,83 @@
+package csa
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+// Transposition table entries created for AlgorithmLazySMP if the engine has no table
+const lazySMPTableSize = 1 << 20
+
+// Starts the helpers of Lazy SMP search, which iteratively deepen the same root over the shared transposition
+// table, so the main search finds more of its nodes already searched
+// Helpers search every other depth one ply deeper and the root children in a different order, so they do not
+// all search the same nodes at the same time. Their results are used only through the table.
+// Returned function stops the helpers and waits for them, they have no limits of their own.
+// A panic of a helper stops the main search and is re-raised by the returned function as *PanicError.
+func (engine *Engine) startHelpers(s Searcher, node SearchNode, maximizing bool) func() {
+	pool := engine.workerPool(func() *workerPool {
+		// the searching goroutine is the main thread
+		return startWorkerPool(workerCount(engine.workers) - 1)
+	})
+	limits := &searchLimits{}
+	var wg sync.WaitGroup
+	var trap panicTrap
+	for id := 1; id < workerCount(engine.workers); id++ {
+		helper := s
+		helper.searchState = &searchState{path: s.path.clone(), tt: s.tt, limits: limits}
+		wg.Add(1)
+		pool.submit(func() {
+			defer wg.Done()
+			defer func() {
+				// the result of the main search is discarded by the re-raised panic
+				if trap.failed() {
+					s.limits.stopped.Store(true)
+				}
+			}()
+			defer trap.catch()
+			labeled(context.Background(), s.ProfileLabels, func() {
+				for depth := 1 + id%2; depth <= engine.depth() && !limits.isStopped(); depth++ {
+					helper.lazySMPHelper(node, depth, maximizing, id)
+				}
+			}, PhaseLabel, "helper")
+		})
+	}
+	return func() {
+		limits.stopped.Store(true)
+		wg.Wait()
+		trap.rethrow()
+	}
+}
+
+// Alpha-beta search of the root children rotated by the helper's id
+func (s *Searcher) lazySMPHelper(node SearchNode, depth int, maximizing bool, id int) {
+	if s.isTerminal(node) {
+		return
+	}
+	maximizing = playerToMove(node, maximizing)
+	s.pushPath(node)
+	defer s.popPath(node)
+	score := s.interiorScore(node, nil, 0)
+	var children []SearchNode
+	for generator := nodeGenerator(node); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			break
+		}
+		children = append(children, childNode)
+	}
+	alpha, beta := math.MinInt, math.MaxInt
+	for i := range children {
+		childNode := children[(i+id)%len(children)]
+		_, childScore := s.minimaxAlphaBetaPrunningImpl(childNode, node, score, depth-1, 1, alpha, beta, nextPlayer(childNode, maximizing))
+		if s.limits.isStopped() {
+			return
+		}
+		if maximizing {
+			alpha = max(alpha, childScore)
+		} else {
+			beta = min(beta, childScore)
+		}
+	}
+}
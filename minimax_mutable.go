@@ -0,0 +1,104 @@
+package csa
+
+import (
+	"math"
+)
+
+// Node modified in place, Apply plays the move and Undo takes back the last applied move
+// Useful for large states where copying the node for every child dominates the search.
+type MutableNode interface {
+	Score() int
+	IsTerminal() bool
+	Moves(maximizing bool) []Move
+	Apply(move Move)
+	Undo()
+}
+
+// Minimax on in place modified node, returns the best move (nil if there is none)
+// The node is in its original state once the search finishes.
+func MinimaxMutable(node MutableNode, depth int, maximizing bool) (Move, int) {
+	return Searcher{}.MinimaxMutable(node, depth, maximizing)
+}
+
+func MinimaxAlphaBetaPrunningMutable(node MutableNode, depth int, maximizing bool) (Move, int) {
+	return Searcher{}.MinimaxAlphaBetaPrunningMutable(node, depth, maximizing)
+}
+
+// Evaluator, WinScore, DrawScore of the DrawNode nodes, TieBreak and Rand apply as in Minimax, the Evaluator
+// is called with the node in the searched state if it implements SearchNode. The other options need the child
+// nodes, which the mutable searches do not have, and are ignored.
+func (s Searcher) MinimaxMutable(node MutableNode, depth int, maximizing bool) (Move, int) {
+	s = s.newSearch()
+	return s.minimaxMutableImpl(node, depth, 0, maximizing)
+}
+
+func (s Searcher) MinimaxAlphaBetaPrunningMutable(node MutableNode, depth int, maximizing bool) (Move, int) {
+	s = s.newSearch()
+	return s.minimaxAlphaBetaPrunningMutableImpl(node, depth, 0, math.MinInt, math.MaxInt, maximizing)
+}
+
+func (s *Searcher) minimaxMutableImpl(node MutableNode, depth, ply int, maximizing bool) (Move, int) {
+	if draw := isDrawMutable(node); depth == 0 || draw || node.IsTerminal() {
+		return nil, s.mutableScore(node, draw, ply)
+	}
+	var bestMove Move
+	bestScore := MinimaxInitScore(maximizing)
+	bestIndex := -1
+	for index, move := range node.Moves(maximizing) {
+		node.Apply(move)
+		_, newScore := s.minimaxMutableImpl(node, depth-1, ply+1, !maximizing)
+		node.Undo()
+		if bestIndex < 0 || (maximizing && newScore > bestScore) || (!maximizing && newScore < bestScore) ||
+			(newScore == bestScore && s.preferTieIndex(index, bestIndex, ply)) {
+			bestMove, bestScore, bestIndex = move, newScore, index
+		}
+	}
+	return bestMove, bestScore
+}
+
+func (s *Searcher) minimaxAlphaBetaPrunningMutableImpl(node MutableNode, depth, ply, alpha, beta int, maximizing bool) (Move, int) {
+	if draw := isDrawMutable(node); depth <= 0 || draw || node.IsTerminal() {
+		return nil, s.mutableScore(node, draw, ply)
+	}
+	var bestMove Move
+	bestScore := MinimaxInitScore(maximizing)
+	bestIndex := -1
+	for index, move := range node.Moves(maximizing) {
+		childAlpha, childBeta := s.tieWindow(alpha, beta, maximizing, bestIndex >= 0, ply)
+		node.Apply(move)
+		_, newScore := s.minimaxAlphaBetaPrunningMutableImpl(node, depth-1, ply+1, childAlpha, childBeta, !maximizing)
+		node.Undo()
+		tie := bestIndex >= 0 && newScore == bestScore && s.preferTieIndex(index, bestIndex, ply)
+		if maximizing {
+			if newScore > alpha || tie {
+				alpha = newScore
+				bestMove, bestScore, bestIndex = move, newScore, index
+			}
+		} else {
+			if newScore < beta || tie {
+				beta = newScore
+				bestMove, bestScore, bestIndex = move, newScore, index
+			}
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return bestMove, bestScore
+}
+
+func isDrawMutable(node MutableNode) bool {
+	drawNode, ok := node.(DrawNode)
+	return ok && drawNode.IsDraw()
+}
+
+// Leaf score of the node in its current state
+func (s *Searcher) mutableScore(node MutableNode, draw bool, ply int) int {
+	if draw {
+		return s.DrawScore
+	}
+	if searchNode, ok := node.(SearchNode); ok {
+		return s.winDistanceScore(s.score(searchNode), ply)
+	}
+	return s.winDistanceScore(node.Score(), ply)
+}
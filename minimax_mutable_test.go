@@ -0,0 +1,103 @@
+package csa
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Node modified in place, moves are square indices
+type tttMutableNode struct {
+	tttNode
+	symbol int // symbol to move
+	played []int
+}
+
+func (node *tttMutableNode) Moves(maximizing bool) []Move {
+	var moves []Move
+	for i := 0; i < 9; i++ {
+		if node.board[i/3][i%3] == empty {
+			moves = append(moves, i)
+		}
+	}
+	return moves
+}
+
+func (node *tttMutableNode) Apply(move Move) {
+	square := move.(int)
+	node.board[square/3][square%3] = node.symbol
+	node.symbol = -node.symbol
+	node.played = append(node.played, square)
+}
+
+func (node *tttMutableNode) Undo() {
+	square := node.played[len(node.played)-1]
+	node.board[square/3][square%3] = empty
+	node.symbol = -node.symbol
+	node.played = node.played[:len(node.played)-1]
+}
+
+func TestTTTMinimaxMutable(t *testing.T) {
+	for _, minimaxFns := range []struct {
+		mutable func(MutableNode, int, bool) (Move, int)
+		boxed   func(SearchNode, int, bool) (SearchNode, int)
+	}{
+		{MinimaxMutable, Minimax},
+		{MinimaxAlphaBetaPrunningMutable, MinimaxAlphaBetaPrunning},
+	} {
+		node := &tttMutableNode{symbol: cross}
+		for !node.IsTerminal() {
+			before := node.tttNode
+			maximizing := node.symbol == circle
+			move, score := minimaxFns.mutable(node, 9, maximizing)
+			if node.tttNode != before {
+				t.Fatal("Node must be restored after the search")
+			}
+			expected, expectedScore := minimaxFns.boxed(node.tttNode, 9, maximizing)
+			node.Apply(move)
+			if score != expectedScore || expected.(tttNode) != node.tttNode {
+				t.Errorf("Mutable variant differs %s%s", node, expected)
+			}
+		}
+	}
+}
+
+func TestTTTMinimaxMutableSearcher(t *testing.T) {
+	// centre and corners are worth more, many children are scored equally
+	evaluator := EvaluatorFunc(func(node SearchNode) int {
+		var board [3][3]int
+		switch node := node.(type) {
+		case tttNode:
+			board = node.board
+		case *tttMutableNode:
+			board = node.board
+		}
+		return 10*node.Score() + 2*board[1][1] + board[0][0] + board[2][2]
+	})
+	searchers := []func() Searcher{
+		func() Searcher { return Searcher{Evaluator: evaluator} },
+		func() Searcher { return Searcher{Evaluator: evaluator, TieBreak: TieBreakLast, WinScore: 10} },
+		func() Searcher { return Searcher{TieBreak: TieBreakLast, DrawScore: 3} },
+		func() Searcher { return Searcher{Evaluator: evaluator, Rand: rand.New(rand.NewSource(7))} },
+	}
+	for i, searcher := range searchers {
+		for _, minimaxFns := range []struct {
+			mutable func(Searcher, MutableNode, int, bool) (Move, int)
+			boxed   func(Searcher, SearchNode, int, bool) (SearchNode, int)
+		}{
+			{Searcher.MinimaxMutable, Searcher.Minimax},
+			{Searcher.MinimaxAlphaBetaPrunningMutable, Searcher.MinimaxAlphaBetaPrunning},
+		} {
+			node := &tttMutableNode{symbol: cross}
+			for !node.IsTerminal() {
+				maximizing := node.symbol == circle
+				move, score := minimaxFns.mutable(searcher(), node, 3, maximizing)
+				expected, expectedScore := minimaxFns.boxed(searcher(), node.tttNode, 3, maximizing)
+				node.Apply(move)
+				if score != expectedScore || expected.(tttNode) != node.tttNode {
+					t.Errorf("Mutable variant of searcher %d differs %s%s", i, node, expected)
+					break
+				}
+			}
+		}
+	}
+}
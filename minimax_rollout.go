@@ -0,0 +1,194 @@
+package csa
+
+import (
+	"math"
+)
+
+// Rollout based alpha-beta (Huang, Pruning Game Tree by Rollouts)
+// Each rollout goes from the root to a single leaf and tightens the bounds of the nodes on its path.
+// The search is anytime - it can be interrupted between rollouts - and exact once the root bounds meet.
+// With the engine's transposition table, the solved nodes are stored and the stored ones are not expanded.
+type RolloutSearch struct {
+	searcher   Searcher
+	root       *rolloutNode
+	depth      int
+	maximizing bool
+	rollouts   int
+}
+
+type rolloutNode struct {
+	node     SearchNode
+	lower    int
+	upper    int
+	children []*rolloutNode
+	expanded bool
+	pass     bool // no moves, the only child is the node itself with the other player to move
+}
+
+func NewRolloutSearch(node SearchNode, depth int, maximizing bool) *RolloutSearch {
+	return Searcher{}.NewRolloutSearch(node, depth, maximizing)
+}
+
+func (s Searcher) NewRolloutSearch(node SearchNode, depth int, maximizing bool) *RolloutSearch {
+	return s.newSearch().rolloutSearch(node, depth, maximizing)
+}
+
+// Rollout search continuing the search state, e.g. with the engine's transposition table
+func (s Searcher) rolloutSearch(node SearchNode, depth int, maximizing bool) *RolloutSearch {
+	return &RolloutSearch{
+		searcher:   s,
+		root:       newRolloutNode(node),
+		depth:      depth,
+		maximizing: playerToMove(node, maximizing),
+	}
+}
+
+func MinimaxRollout(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	return Searcher{}.MinimaxRollout(node, depth, maximizing)
+}
+
+func (s Searcher) MinimaxRollout(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+	search := s.NewRolloutSearch(node, depth, maximizing)
+	for !search.Solved() {
+		search.Rollout()
+	}
+	return search.Best()
+}
+
+// Run single rollout, returns true if the root value is known afterwards
+func (search *RolloutSearch) Rollout() bool {
+	if !search.Solved() {
+		search.rollouts++
+		search.root.rollout(search.searcher, search.depth, 0, math.MinInt, math.MaxInt, search.maximizing)
+	}
+	return search.Solved()
+}
+
+func (search *RolloutSearch) Solved() bool {
+	return search.root.lower >= search.root.upper
+}
+
+func (search *RolloutSearch) Rollouts() int {
+	return search.rollouts
+}
+
+// Current bounds of the root value
+func (search *RolloutSearch) Bounds() (int, int) {
+	return search.root.lower, search.root.upper
+}
+
+// Best root child according to the current bounds
+// Once solved it returns the exact minimax value, otherwise the pessimistic bound of the chosen child
+func (search *RolloutSearch) Best() (SearchNode, int) {
+	root := search.root
+	if search.depth <= 0 || search.searcher.isTerminal(root.node) {
+		return root.node, root.lower
+	}
+	var best *rolloutNode
+	bestIndex := -1
+	for index, child := range root.children {
+		if best == nil ||
+			(search.maximizing && child.lower > best.lower) ||
+			(!search.maximizing && child.upper < best.upper) ||
+			(search.maximizing && child.lower == best.lower && search.searcher.preferTie(child.node, best.node, index, bestIndex, 0)) ||
+			(!search.maximizing && child.upper == best.upper && search.searcher.preferTie(child.node, best.node, index, bestIndex, 0)) {
+			best = child
+			bestIndex = index
+		}
+	}
+	if best == nil || root.pass {
+		// no moves, the value comes from the no-move policy
+		return nil, root.lower
+	}
+	if search.maximizing {
+		return best.node, best.lower
+	}
+	return best.node, best.upper
+}
+
+func newRolloutNode(node SearchNode) *rolloutNode {
+	return &rolloutNode{
+		node:  node,
+		lower: math.MinInt,
+		upper: math.MaxInt,
+	}
+}
+
+func (rn *rolloutNode) rollout(s Searcher, depth, ply, alpha, beta int, maximizing bool) {
+	if leaf := s.probeLeaf(rn.node, ply); depth <= 0 || leaf.leaf {
+		rn.lower = s.leafScore(leaf, rn.node, nil, 0, ply)
+		rn.upper = rn.lower
+		return
+	}
+	maximizing = playerToMove(rn.node, maximizing)
+	key, hashed := s.tt.key(rn.node, maximizing)
+	if !rn.expanded {
+		// the full window, only the solved nodes are stored
+		if score, found := s.probeTT(key, hashed, depth, ply, math.MinInt, math.MaxInt); found {
+			rn.lower, rn.upper = score, score
+			return
+		}
+		rn.expand(maximizing)
+	}
+	s.pushPath(rn.node)
+	defer s.popPath(rn.node)
+	if len(rn.children) == 0 {
+		rn.lower = s.noMoveScore(rn.node, nil, 0, ply, maximizing, func() int {
+			rn.pass = true
+			rn.children = append(rn.children, newRolloutNode(rn.node))
+			rn.children[0].rollout(s, depth-1, ply+1, alpha, beta, !maximizing)
+			return rn.children[0].lower
+		})
+		if len(rn.children) == 0 {
+			rn.upper = rn.lower
+			return
+		}
+	}
+	if rn.pass {
+		// passing is not a repetition of the node
+		s.popPath(rn.node)
+		defer s.pushPath(rn.node)
+	}
+	alpha = max(alpha, rn.lower)
+	beta = min(beta, rn.upper)
+	// leftmost child with non-empty window, which makes the rollouts equivalent to alpha-beta
+	for _, child := range rn.children {
+		childAlpha, childBeta := max(alpha, child.lower), min(beta, child.upper)
+		if childAlpha < childBeta {
+			childMaximizing := nextPlayer(child.node, maximizing)
+			if rn.pass {
+				childMaximizing = !maximizing
+			}
+			child.rollout(s, depth-1, ply+1, childAlpha, childBeta, childMaximizing)
+			break
+		}
+	}
+	rn.updateBounds(maximizing)
+	if hashed && rn.lower >= rn.upper {
+		s.tt.store(key, depth, s.ttScoreIn(rn.lower, ply), ttExact)
+	}
+}
+
+func (rn *rolloutNode) expand(maximizing bool) {
+	for generator := nodeGenerator(rn.node); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			break
+		}
+		rn.children = append(rn.children, newRolloutNode(childNode))
+	}
+	rn.expanded = true
+}
+
+func (rn *rolloutNode) updateBounds(maximizing bool) {
+	rn.lower, rn.upper = rn.children[0].lower, rn.children[0].upper
+	for _, child := range rn.children[1:] {
+		if maximizing {
+			rn.lower = max(rn.lower, child.lower)
+			rn.upper = max(rn.upper, child.upper)
+		} else {
+			rn.lower = min(rn.lower, child.lower)
+			rn.upper = min(rn.upper, child.upper)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package csa
+
+import "testing"
+
+func TestTTTMinimaxRollout(t *testing.T) {
+	var sn SearchNode = tttNode{}
+	maximizing := false
+	for i := 0; i < 9; i++ {
+		rolloutNode, rolloutScore := MinimaxRollout(sn, 9, maximizing)
+		_, score := Minimax(sn, 9, maximizing)
+		if rolloutScore != score {
+			t.Errorf("Rollout score %d differs from minimax score %d %s", rolloutScore, score, sn)
+		}
+		sn = rolloutNode
+		maximizing = !maximizing
+	}
+	if sn.Score() != empty {
+		t.Errorf("Score is not a draw %s", sn)
+	}
+	// anytime search has to keep valid bounds
+	search := NewRolloutSearch(tttNode{}, 9, true)
+	for i := 0; i < 10; i++ {
+		search.Rollout()
+	}
+	if lower, upper := search.Bounds(); lower > 0 || upper < 0 {
+		t.Errorf("Invalid bounds [%d, %d]", lower, upper)
+	}
+	for !search.Rollout() {
+	}
+	if _, score := search.Best(); score != 0 || search.Rollouts() <= 10 {
+		t.Errorf("Invalid solved search with score %d", score)
+	}
+}
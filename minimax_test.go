@@ -0,0 +1,256 @@
+package csa
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+// Alpha-beta of the baseline commit without any Searcher configuration, the reference of the overhead
+// of the configurable search on the same nodes
+func baselineAlphaBeta(node SearchNode, depth, alpha, beta int, maximizing bool) (SearchNode, int) {
+	if depth <= 0 || node.IsTerminal() {
+		return node, node.Score()
+	}
+	var bestNode SearchNode
+	bestScore := MinimaxInitScore(maximizing)
+	for generator := node.SearchNodeGenerator(); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			break
+		}
+		_, newScore := baselineAlphaBeta(childNode, depth-1, alpha, beta, !maximizing)
+		if maximizing {
+			if newScore > alpha {
+				alpha = newScore
+				bestNode = childNode
+				bestScore = newScore
+			}
+		} else {
+			if newScore < beta {
+				beta = newScore
+				bestNode = childNode
+				bestScore = newScore
+			}
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return bestNode, bestScore
+}
+
+func TestBaselineAlphaBeta(t *testing.T) {
+	_, expected := MinimaxAlphaBetaPrunning(tttNode{}, 9, true)
+	if _, score := baselineAlphaBeta(tttNode{}, 9, math.MinInt, math.MaxInt, true); score != expected {
+		t.Errorf("Expected score %d of the baseline, got %d", expected, score)
+	}
+}
+
+func BenchmarkTTTAlphaBetaBaseline(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		baselineAlphaBeta(tttNode{}, 9, math.MinInt, math.MaxInt, true)
+	}
+}
+
+func BenchmarkCheckersAlphaBetaBaseline(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		baselineAlphaBeta(cNodeFullBoard(), 6, math.MinInt, math.MaxInt, true)
+	}
+}
+
+func TestTTTEvaluator(t *testing.T) {
+	// called concurrently by the workers of MinimaxConcurrent
+	var calls atomic.Int64
+	searcher := Searcher{
+		Evaluator: EvaluatorFunc(func(node SearchNode) int {
+			calls.Add(1)
+			return node.Score() + 100
+		}),
+		// draws are not evaluated
+		DrawScore: 100,
+	}
+	for _, minimax := range []minimaxFn{
+		searcher.Minimax,
+		searcher.MinimaxAlphaBetaPrunning,
+		searcher.MinimaxRollout,
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			return searcher.MinimaxBeam(node, depth, maximizing, 9, nil)
+		},
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			return searcher.MinimaxConcurrent(node, depth, maximizing, 2)
+		},
+	} {
+		calls.Store(0)
+		_, score := minimax(tttNode{}, 9, true)
+		if score != 100 || calls.Load() == 0 {
+			t.Errorf("Evaluator was not used, score %d", score)
+		}
+	}
+}
+
+func TestTTTWinScore(t *testing.T) {
+	// only win or loss matters, number of empty squares is ignored
+	searcher := Searcher{
+		Evaluator: EvaluatorFunc(func(node SearchNode) int {
+			_, symbol := node.(tttNode).anyFullRow()
+			return symbol * 100
+		}),
+		WinScore: 100,
+	}
+	for _, minimax := range []minimaxFn{
+		searcher.Minimax,
+		searcher.MinimaxAlphaBetaPrunning,
+		searcher.MinimaxRollout,
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			return searcher.MinimaxConcurrent(node, depth, maximizing, 2)
+		},
+	} {
+		node := tttNode{}
+		node.board[0][0] = cross
+		node.board[0][1] = cross
+		node.board[1][1] = cross
+		node.board[1][0] = circle
+		node.board[2][2] = circle
+		sn, score := minimax(node, 9, false)
+		if !sn.IsTerminal() || score != -99 {
+			t.Errorf("Cross did not win immediately, score %d %s", score, sn)
+		}
+		// circle loses anyway, but should delay it
+		_, score = minimax(node, 9, true)
+		if score != -98 {
+			t.Errorf("Circle did not delay the loss, score %d", score)
+		}
+	}
+}
+
+func TestTTTDrawScore(t *testing.T) {
+	node := tttNode{board: [3][3]int{
+		{cross, circle, cross},
+		{cross, circle, circle},
+		{circle, cross, empty},
+	}}
+	if node.IsDraw() {
+		t.Error("Unfinished game cannot be a draw")
+	}
+	searcher := Searcher{DrawScore: 5}
+	for _, minimax := range []minimaxFn{
+		searcher.Minimax,
+		searcher.MinimaxAlphaBetaPrunning,
+		searcher.MinimaxRollout,
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			return searcher.MinimaxConcurrent(node, depth, maximizing, 2)
+		},
+	} {
+		sn, score := minimax(node, 9, false)
+		if !sn.(tttNode).IsDraw() || score != 5 {
+			t.Errorf("Expected draw score, got %d %s", score, sn)
+		}
+	}
+}
+
+// Node which knows whose turn it is
+type tttPlayerNode struct {
+	tttNode
+	player Player
+}
+
+func (node tttPlayerNode) PlayerToMove() Player {
+	return node.player
+}
+
+func (node tttPlayerNode) SearchNodeGenerator() SearchNodeGenerator {
+	generator := node.tttNode.SearchNodeGenerator()
+	return func(maximizing bool) SearchNode {
+		child := generator(node.player == MaximizingPlayer)
+		if child == nil {
+			return nil
+		}
+		return tttPlayerNode{child.(tttNode), MaximizingPlayer - node.player}
+	}
+}
+
+func TestTTTPlayerToMove(t *testing.T) {
+	for _, minimax := range []minimaxFn{
+		Minimax,
+		MinimaxAlphaBetaPrunning,
+		MinimaxRollout,
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			return MinimaxConcurrent(node, depth, maximizing, 2)
+		},
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			return MinimaxBeam(node, depth, maximizing, 9, nil)
+		},
+	} {
+		var sn SearchNode = tttPlayerNode{player: MinimizingPlayer}
+		// wrong flag on purpose, has to be ignored
+		for i := 0; i < 4; i++ {
+			sn, _ = minimax(sn, 2, true)
+		}
+		node := sn.(tttPlayerNode)
+		if node.player != MinimizingPlayer || node.board[0][0] != cross {
+			t.Errorf("Players did not alternate %s", sn)
+		}
+		// cross wins immediately
+		node.board = [3][3]int{{cross, cross, empty}, {circle, circle, empty}}
+		if sn, score := minimax(node, 9, true); score >= 0 || sn.(tttPlayerNode).board[0][2] != cross {
+			t.Errorf("Cross did not win %s", sn)
+		}
+	}
+}
+
+// Node generating its children without the generator closure
+type tttAppendNode struct {
+	tttNode
+}
+
+func (node tttAppendNode) AppendChildren(children []SearchNode, maximizing bool) []SearchNode {
+	for i := 0; i < 9; i++ {
+		if node.board[i/3][i%3] == empty {
+			child := node
+			child.board[i/3][i%3] = cross
+			if maximizing {
+				child.board[i/3][i%3] = circle
+			}
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func TestTTTAppendChildren(t *testing.T) {
+	node := tttNode{}
+	node.board[0][1] = cross
+	for _, minimax := range []minimaxFn{Minimax, MinimaxAlphaBetaPrunning, MinimaxAlphaBetaIterative} {
+		expected, expectedScore := minimax(node, 8, false)
+		best, score := minimax(tttAppendNode{node}, 8, false)
+		if score != expectedScore || best.(tttAppendNode).tttNode != expected {
+			t.Errorf("AppendChildren search differs %d %d%s%s", score, expectedScore, best, expected)
+		}
+	}
+}
+
+func BenchmarkTTTAlphaBetaAppendChildren(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		MinimaxAlphaBetaPrunning(tttAppendNode{}, 9, true)
+	}
+}
+
+func BenchmarkTTTAlphaBeta(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		MinimaxAlphaBetaPrunning(tttNode{}, 9, true)
+	}
+}
+
+func BenchmarkTTTMinimax(b *testing.B) {
+	b.ReportAllocs()
+	node := tttNode{}
+	node.board[1][1] = cross
+	for i := 0; i < b.N; i++ {
+		Minimax(node, 8, false)
+	}
+}
@@ -0,0 +1,167 @@
+package csa
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+// Children of nodes closer to the leaves are not worth handing over to other workers
+const minSplitDepth = 2
+
+func MinimaxWorkStealing(node SearchNode, depth int, maximizing bool, workers int) (SearchNode, int) {
+	return Searcher{}.MinimaxWorkStealing(node, depth, maximizing, workers)
+}
+
+// Parallel alpha-beta which splits the work at any depth whenever a worker is idle
+// Every node searches its children itself unless an idle worker takes the child over, so with few root
+// children the workers still get work from deeper nodes. The calling goroutine is one of the workers.
+// Zero or negative number of workers means runtime.GOMAXPROCS(0).
+func (s Searcher) MinimaxWorkStealing(node SearchNode, depth int, maximizing bool, workers int) (SearchNode, int) {
+	pool := startWorkerPool(workerCount(workers) - 1)
+	defer pool.close()
+	s = s.newSearch()
+	return s.minimaxWorkStealing(node, depth, maximizing, pool, false)
+}
+
+func MinimaxYBWC(node SearchNode, depth int, maximizing bool, workers int) (SearchNode, int) {
+	return Searcher{}.MinimaxYBWC(node, depth, maximizing, workers)
+}
+
+// Young Brothers Wait parallel alpha-beta
+// The eldest child of every node is searched first by the node itself to establish the bound, only then
+// its younger brothers can be taken over by idle workers. Searches fewer nodes than MinimaxWorkStealing,
+// which splits without waiting, but keeps the workers idle more often.
+// Zero or negative number of workers means runtime.GOMAXPROCS(0).
+func (s Searcher) MinimaxYBWC(node SearchNode, depth int, maximizing bool, workers int) (SearchNode, int) {
+	pool := startWorkerPool(workerCount(workers) - 1)
+	defer pool.close()
+	s = s.newSearch()
+	return s.minimaxWorkStealing(node, depth, maximizing, pool, true)
+}
+
+func (s *Searcher) minimaxWorkStealing(node SearchNode, depth int, maximizing bool, pool *workerPool, eldestFirst bool) (SearchNode, int) {
+	// lines are not kept, the children can be searched by other workers
+	search, state := *s, *s.searchState
+	state.pv = nil
+	search.searchState = &state
+	bestNode, bestScore := search.workStealingImpl(node, nil, 0, depth, 0, math.MinInt, math.MaxInt, maximizing, pool, eldestFirst)
+	s.pv.clear(0)
+	s.pv.clear(1)
+	if bestNode != nil {
+		s.pv.update(0, bestNode)
+	}
+	return bestNode, bestScore
+}
+
+// Alpha-beta state of a node whose children are searched by several workers
+type splitNode struct {
+	mutex      sync.Mutex
+	wg         sync.WaitGroup
+	maximizing bool
+	ply        int
+	alpha      int
+	beta       int
+	bestNode   SearchNode
+	bestScore  int
+	bestIndex  int
+	trap       panicTrap // of the tasks taken over by other workers
+}
+
+// With eldestFirst the eldest child is never handed over, the others wait for its result (YBWC)
+func (s *Searcher) workStealingImpl(node, parent SearchNode, parentScore, depth, ply, alpha, beta int, maximizing bool, pool *workerPool, eldestFirst bool) (SearchNode, int) {
+	if depth < minSplitDepth {
+		return s.minimaxAlphaBetaPrunningImpl(node, parent, parentScore, depth, ply, alpha, beta, maximizing)
+	}
+	if s.limits.stop() {
+		return nil, 0
+	}
+	if leaf := s.probeLeaf(node, ply); leaf.leaf {
+		s.markHorizon(leaf, depth)
+		return node, s.leafScore(leaf, node, parent, parentScore, ply)
+	}
+	maximizing = playerToMove(node, maximizing)
+	key, hashed := s.tt.key(node, maximizing)
+	if ttScore, found := s.probeTT(key, hashed, depth, ply, alpha, beta); found {
+		return nil, ttScore
+	}
+	horizon := s.limits.horizonCount()
+	s.pushPath(node)
+	defer s.popPath(node)
+	score := s.interiorScore(node, parent, parentScore)
+	split := &splitNode{
+		maximizing: maximizing,
+		ply:        ply,
+		alpha:      alpha,
+		beta:       beta,
+		bestScore:  MinimaxInitScore(maximizing),
+		bestIndex:  -1,
+	}
+	for generator, index := nodeGenerator(node), 0; !split.cutoff(); index++ {
+		childNode := generator(maximizing)
+		if childNode == nil && index == 0 {
+			return nil, s.noMoveScore(node, parent, parentScore, ply, maximizing, func() int {
+				_, passScore := s.workStealingImpl(node, parent, parentScore, depth-1, ply+1, alpha, beta, !maximizing, pool, eldestFirst)
+				return passScore
+			})
+		}
+		if childNode == nil {
+			break
+		}
+		childAlpha, childBeta := split.window(s)
+		search := func(child *Searcher) {
+			_, newScore := child.workStealingImpl(childNode, node, score, depth-1, ply+1, childAlpha, childBeta, nextPlayer(childNode, maximizing), pool, eldestFirst)
+			split.update(s, childNode, index, newScore)
+		}
+		if (index > 0 || !eldestFirst) && pool.hasIdle() {
+			// idle worker continues with its own copy of the searched path
+			worker := *s
+			worker.searchState = s.fork()
+			split.wg.Add(1)
+			if pool.trySubmit(func() {
+				defer split.wg.Done()
+				defer split.trap.catch()
+				labeled(context.Background(), s.ProfileLabels, func() {
+					search(&worker)
+				}, PhaseLabel, "split")
+			}) {
+				continue
+			}
+			split.wg.Done()
+		}
+		search(s)
+	}
+	split.wg.Wait()
+	split.trap.rethrow()
+	s.storeTT(key, hashed, horizon, depth, ply, alpha, beta, maximizing, split.bestNode != nil, split.bestScore)
+	return split.bestNode, split.bestScore
+}
+
+func (split *splitNode) cutoff() bool {
+	split.mutex.Lock()
+	defer split.mutex.Unlock()
+	return split.alpha >= split.beta || split.trap.failed()
+}
+
+// Window of the next child according to the results of the finished children
+func (split *splitNode) window(s *Searcher) (int, int) {
+	split.mutex.Lock()
+	defer split.mutex.Unlock()
+	return s.tieWindow(split.alpha, split.beta, split.maximizing, split.bestNode != nil, split.ply)
+}
+
+func (split *splitNode) update(s *Searcher, childNode SearchNode, index, newScore int) {
+	split.mutex.Lock()
+	defer split.mutex.Unlock()
+	tie := split.bestNode != nil && newScore == split.bestScore && s.preferTie(childNode, split.bestNode, index, split.bestIndex, split.ply)
+	if (split.maximizing && (newScore > split.alpha || tie)) || (!split.maximizing && (newScore < split.beta || tie)) {
+		if split.maximizing {
+			split.alpha = newScore
+		} else {
+			split.beta = newScore
+		}
+		split.bestNode = childNode
+		split.bestScore = newScore
+		split.bestIndex = index
+	}
+}
@@ -0,0 +1,60 @@
+package csa
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+)
+
+// Externally trained value/policy model (e.g. neural network served by an inference runtime)
+// Value is from the maximizing player's point of view in units of node's Score,
+// policy holds the prior probability of every given child (in the same order).
+// Children may be nil if only the value is needed.
+type Model interface {
+	Predict(node SearchNode, children []SearchNode) (value float64, policy []float64)
+}
+
+type modelEvaluator struct {
+	model Model
+}
+
+// Use model's value as an Evaluator for the minimax variants
+func ModelEvaluator(model Model) Evaluator {
+	return modelEvaluator{model}
+}
+
+func (e modelEvaluator) Evaluate(node SearchNode) int {
+	value, _ := e.model.Predict(node, nil)
+	return int(math.Round(value))
+}
+
+// Example backend: single layer network value = Scale*tanh(Weights*features + Bias) with uniform policy
+// Encode maps the node into the input features and has to be provided by the game
+type LinearModel struct {
+	Weights []float64                       `json:"weights"`
+	Bias    float64                         `json:"bias"`
+	Scale   float64                         `json:"scale"`
+	Encode  func(node SearchNode) []float64 `json:"-"`
+}
+
+// Load weights exported by the training pipeline as JSON {"weights": [...], "bias": b, "scale": s}
+func LoadLinearModel(r io.Reader, encode func(node SearchNode) []float64) (*LinearModel, error) {
+	model := &LinearModel{Encode: encode}
+	if err := json.NewDecoder(r).Decode(model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (m *LinearModel) Predict(node SearchNode, children []SearchNode) (float64, []float64) {
+	features := m.Encode(node)
+	sum := m.Bias
+	for i := 0; i < len(features) && i < len(m.Weights); i++ {
+		sum += features[i] * m.Weights[i]
+	}
+	policy := make([]float64, len(children))
+	for i := range policy {
+		policy[i] = 1 / float64(len(children))
+	}
+	return m.Scale * math.Tanh(sum), policy
+}
@@ -0,0 +1,34 @@
+package csa
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTTTModel(t *testing.T) {
+	encode := func(node SearchNode) []float64 {
+		return []float64{float64(node.Score())}
+	}
+	model, err := LoadLinearModel(strings.NewReader(`{"weights": [10], "bias": 0, "scale": 10}`), encode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := tttNode{}
+	node.board[0][0] = cross
+	node.board[0][1] = cross
+	node.board[1][0] = circle
+	node.board[1][1] = circle
+	sn, score := Searcher{Evaluator: ModelEvaluator(model)}.MinimaxAlphaBetaPrunning(node, 1, false)
+	if sn.(tttNode).board[0][2] != cross || score != -10 {
+		t.Errorf("Cross did not win using model evaluator %s", sn)
+	}
+	mcts := MCTS{
+		Iterations:  200,
+		Exploration: 10,
+		Model:       model,
+	}
+	sn, _ = mcts.Search(node, true)
+	if sn.(tttNode).board[1][2] != circle {
+		t.Errorf("Circle did not win using model %s", sn)
+	}
+}
@@ -0,0 +1,42 @@
+package csa
+
+// Scoring of non-terminal nodes in which the player to move has no children
+type NoMovePolicy int
+
+const (
+	// Loss of the player to move, -WinScore/WinScore (adjusted by distance) if set,
+	// otherwise the extreme MinimaxInitScore
+	NoMoveLoss NoMovePolicy = iota
+	// Draw scored by searcher's DrawScore
+	NoMoveDraw
+	// The other player moves in the same node, if it cannot move either the node is scored as a leaf
+	// PlayerNode nodes decide the player to move themselves, so they cannot be passed and are scored as leaves too.
+	NoMovePass
+)
+
+// Score of a node without any children, pass continues the search of the node with the other player
+func (s *Searcher) noMoveScore(node, parent SearchNode, parentScore, ply int, maximizing bool, pass func() int) int {
+	if s.NoMoveScore != nil {
+		return s.NoMoveScore(node, maximizing)
+	}
+	switch s.NoMove {
+	case NoMoveDraw:
+		return s.DrawScore
+	case NoMovePass:
+		if _, ok := optionalNode(node).(PlayerNode); !ok && nodeGenerator(node)(!maximizing) != nil {
+			// passing is not a repetition of the node
+			s.popPath(node)
+			defer s.pushPath(node)
+			return pass()
+		}
+		// nobody can move, the expanded node is neither drawn nor in the tablebase
+		return s.leafScore(leafProbe{}, node, parent, parentScore, ply)
+	}
+	if s.WinScore > 0 {
+		if maximizing {
+			return s.winDistanceScore(-s.WinScore, ply)
+		}
+		return s.winDistanceScore(s.WinScore, ply)
+	}
+	return MinimaxInitScore(maximizing)
+}
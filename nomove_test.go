@@ -0,0 +1,40 @@
+package csa
+
+import "testing"
+
+// Node whose minimizing player to move is stuck, while the maximizing one could move to a drawn board
+type stuckPlayerNode struct{}
+
+func (node stuckPlayerNode) Score() int {
+	return 3
+}
+
+func (node stuckPlayerNode) IsTerminal() bool {
+	return false
+}
+
+func (node stuckPlayerNode) PlayerToMove() Player {
+	return MinimizingPlayer
+}
+
+func (node stuckPlayerNode) SearchNodeGenerator() SearchNodeGenerator {
+	generated := false
+	return func(maximizing bool) SearchNode {
+		if !maximizing || generated {
+			return nil
+		}
+		generated = true
+		return tttNode{board: [3][3]int{{cross, circle, cross}, {cross, circle, circle}, {circle, cross, cross}}}
+	}
+}
+
+func TestNoMovePassPlayerNode(t *testing.T) {
+	// the player of PlayerNode cannot be passed, the node is scored as a leaf
+	searcher := Searcher{NoMove: NoMovePass}
+	if best, score := searcher.MinimaxAlphaBetaPrunning(stuckPlayerNode{}, 3, true); best != nil || score != 3 {
+		t.Errorf("Expected no move with the leaf score 3, got %d", score)
+	}
+	if best, score := searcher.Minimax(stuckPlayerNode{}, 3, false); best != nil || score != 3 {
+		t.Errorf("Expected no move with the leaf score 3, got %d", score)
+	}
+}
@@ -0,0 +1,52 @@
+package csa
+
+// Kind of event of the search
+type EventKind int
+
+const (
+	// Node entered with the alpha-beta window
+	EventEnter EventKind = iota
+	// Node evaluated without searching its children, i.e. a leaf or a transposition table hit
+	EventLeaf
+	// Child of the node became the best one so far
+	EventBest
+	// Remaining children of the node are prunned
+	EventCutoff
+	// Node searched with its final score
+	EventLeave
+)
+
+var eventKindNames = []string{"enter", "leaf", "best", "cutoff", "leave"}
+
+func (kind EventKind) String() string {
+	if int(kind) < len(eventKindNames) {
+		return eventKindNames[kind]
+	}
+	return "unknown"
+}
+
+// Event of the search, Node is the child for EventBest and the concerned node otherwise
+// Every EventEnter is followed by EventLeaf or EventLeave of the same node, the events of its children are
+// between them. Unbounded window sides are math.MinInt and math.MaxInt.
+type SearchEvent struct {
+	Kind  EventKind
+	Node  SearchNode
+	Ply   int
+	Alpha int
+	Beta  int
+	Score int // of the leaf, the best child or the left node
+}
+
+// Optional observer of the events of Minimax and MinimaxAlphaBetaPrunning, e.g. TreeRecorder
+// Parallel searches call it from several goroutines in no particular order.
+type SearchObserver interface {
+	Observe(event SearchEvent)
+}
+
+// Inlined into the searches, so without the observer every event costs a single check
+func (s *Searcher) observe(kind EventKind, node SearchNode, ply, alpha, beta, score int) {
+	if s.Observer == nil {
+		return
+	}
+	s.Observer.Observe(SearchEvent{kind, node, ply, alpha, beta, score})
+}
@@ -0,0 +1,52 @@
+package csa
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// Panic of a worker goroutine, re-raised by the parallel search in the calling goroutine
+// once all the workers finished their tasks
+type PanicError struct {
+	Value any
+	Stack []byte // of the worker
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("csa: search worker panicked: %v", err.Value)
+}
+
+func (err *PanicError) Unwrap() error {
+	if wrapped, ok := err.Value.(error); ok {
+		return wrapped
+	}
+	return nil
+}
+
+// First panic of the tasks of a single search
+type panicTrap struct {
+	err atomic.Pointer[PanicError]
+}
+
+// Has to be deferred directly by the task
+func (trap *panicTrap) catch() {
+	if r := recover(); r != nil {
+		err, ok := r.(*PanicError)
+		if !ok {
+			err = &PanicError{r, debug.Stack()}
+		}
+		trap.err.CompareAndSwap(nil, err)
+	}
+}
+
+// Whether any task panicked, the remaining tasks do not have to be searched
+func (trap *panicTrap) failed() bool {
+	return trap.err.Load() != nil
+}
+
+func (trap *panicTrap) rethrow() {
+	if err := trap.err.Load(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,60 @@
+package csa
+
+import "testing"
+
+// Node with buggy evaluation
+type tttPanicNode struct {
+	tttNode
+}
+
+func (node tttPanicNode) Score() int {
+	if node.numberEmptySquares() == 4 {
+		panic("bad board")
+	}
+	return node.tttNode.Score()
+}
+
+func (node tttPanicNode) SearchNodeGenerator() SearchNodeGenerator {
+	generator := node.tttNode.SearchNodeGenerator()
+	return func(maximizing bool) SearchNode {
+		child := generator(maximizing)
+		if child == nil {
+			return nil
+		}
+		return tttPanicNode{child.(tttNode)}
+	}
+}
+
+func TestTTTWorkerPanic(t *testing.T) {
+	minimaxFns := []minimaxFn{
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			return MinimaxConcurrent(node, depth, maximizing, 3)
+		},
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			return Searcher{SplitDepth: 2}.MinimaxConcurrent(node, depth, maximizing, 3)
+		},
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			return MinimaxWorkStealing(node, depth, maximizing, 3)
+		},
+		func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+			engine := NewEngine(WithAlgorithm(AlgorithmLazySMP), WithWorkers(3), WithMaxDepth(depth))
+			defer engine.Close()
+			return engine.BestMove(node, maximizing)
+		},
+	}
+	for i, minimax := range minimaxFns {
+		func() {
+			defer func() {
+				r := recover()
+				// work stealing and Lazy SMP re-raise the original panic if it happened in the calling goroutine
+				if err, ok := r.(*PanicError); ok {
+					r = err.Value
+				}
+				if r != "bad board" {
+					t.Errorf("Search %d: expected worker panic, got %v", i, r)
+				}
+			}()
+			minimax(tttPanicNode{}, 9, true)
+		}()
+	}
+}
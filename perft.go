@@ -0,0 +1,44 @@
+package csa
+
+// Number of the nodes reachable in exactly given depth, for validating move generators against known counts
+// Terminal nodes are not expanded, nodes without children are counted only at the given depth.
+// All the generated children are counted, including the ones filtered out by SymmetryNode.
+func Perft(node SearchNode, depth int, maximizing bool) uint64 {
+	if depth <= 0 {
+		return 1
+	}
+	if node.IsTerminal() {
+		return 0
+	}
+	maximizing = playerToMove(node, maximizing)
+	count := uint64(0)
+	for generator := allChildrenGenerator(node); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			return count
+		}
+		count += Perft(childNode, depth-1, nextPlayer(childNode, maximizing))
+	}
+}
+
+// Perft count of a root child
+type PerftCount struct {
+	Node  SearchNode
+	Count uint64
+}
+
+// Perft counts split by the root children in the generator order, for finding the diverging move
+func PerftDivide(node SearchNode, depth int, maximizing bool) []PerftCount {
+	if depth <= 0 || node.IsTerminal() {
+		return nil
+	}
+	maximizing = playerToMove(node, maximizing)
+	var counts []PerftCount
+	for generator := allChildrenGenerator(node); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			return counts
+		}
+		counts = append(counts, PerftCount{childNode, Perft(childNode, depth-1, nextPlayer(childNode, maximizing))})
+	}
+}
@@ -0,0 +1,22 @@
+package csa
+
+import "testing"
+
+func TestTTTPerft(t *testing.T) {
+	// known counts of tic-tac-toe games, no game ends before the fifth move
+	for depth, expected := range map[int]uint64{0: 1, 1: 9, 2: 72, 5: 15120, 9: 127872} {
+		if count := Perft(tttNode{}, depth, true); count != expected {
+			t.Errorf("Perft(%d) = %d, expected %d", depth, count, expected)
+		}
+	}
+	total := uint64(0)
+	for _, count := range PerftDivide(tttNode{}, 3, true) {
+		if count.Count != 56 {
+			t.Errorf("Expected 56 nodes after %v", count.Node)
+		}
+		total += count.Count
+	}
+	if total != Perft(tttNode{}, 3, true) {
+		t.Error("Divided counts differ from perft")
+	}
+}
@@ -0,0 +1,90 @@
+package csa
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Estimate node's value as the average score of n uniformly random playouts to a terminal node
+// Intended as the Evaluator of nodes without hand written evaluation, the nodes have to implement PlayerNode
+// as the side to move is not known otherwise. The searches score the drawn playouts as the searcher's DrawScore
+// and give each worker its own generator seeded from rng, Evaluate outside of the searches scores them as zero.
+func PlayoutEvaluator(n int, rng *rand.Rand) Evaluator {
+	return &playoutEvaluator{n: n, rng: rng}
+}
+
+type playoutEvaluator struct {
+	n     int
+	mutex sync.Mutex // guards rng
+	rng   *rand.Rand
+}
+
+func (e *playoutEvaluator) Evaluate(node SearchNode) int {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.evaluate(node, 0, e.rng)
+}
+
+func (e *playoutEvaluator) evaluate(node SearchNode, drawScore int, rng *rand.Rand) int {
+	if e.n <= 0 || node.IsTerminal() {
+		return playoutScore(node, drawScore)
+	}
+	playerNode, ok := node.(PlayerNode)
+	if !ok {
+		panic("csa: PlayoutEvaluator needs PlayerNode nodes")
+	}
+	maximizing := playerNode.PlayerToMove() == MaximizingPlayer
+	total := 0
+	for i := 0; i < e.n; i++ {
+		total += playoutScore(randomPlayout(node, maximizing, 0, rng), drawScore)
+	}
+	return total / e.n
+}
+
+// Generator of a search worker, seeded from the shared one
+func (e *playoutEvaluator) workerRand() *rand.Rand {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return rand.New(rand.NewSource(e.rng.Int63()))
+}
+
+func playoutScore(node SearchNode, drawScore int) int {
+	if isDraw(node) {
+		return drawScore
+	}
+	return node.Score()
+}
+
+// Optional interface for nodes playing the random playouts faster than by generating all the children of every node,
+// e.g. by filling the empty squares of the board in a random order
+type PlayoutNode interface {
+	// Terminal node after uniformly random moves starting with the given player
+	RandomPlayout(maximizing bool, rng *rand.Rand) SearchNode
+}
+
+// Play uniformly random moves until terminal node (or maxDepth plies if positive) and return the final node
+func randomPlayout(node SearchNode, maximizing bool, maxDepth int, rng *rand.Rand) SearchNode {
+	if playoutNode, ok := node.(PlayoutNode); ok && maxDepth <= 0 && !node.IsTerminal() {
+		return playoutNode.RandomPlayout(playerToMove(node, maximizing), rng)
+	}
+	for depth := 0; maxDepth <= 0 || depth < maxDepth; depth++ {
+		if node.IsTerminal() {
+			break
+		}
+		maximizing = playerToMove(node, maximizing)
+		var children []SearchNode
+		for generator := nodeGenerator(node); ; {
+			childNode := generator(maximizing)
+			if childNode == nil {
+				break
+			}
+			children = append(children, childNode)
+		}
+		if len(children) == 0 {
+			break
+		}
+		node = children[rng.Intn(len(children))]
+		maximizing = nextPlayer(node, maximizing)
+	}
+	return node
+}
@@ -0,0 +1,46 @@
+package csa
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTTTPlayoutEvaluator(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	node := tttPlayerNode{player: MinimizingPlayer}
+	node.board[0][0] = cross
+	node.board[0][1] = cross
+	node.board[1][1] = circle
+	// cross to move has the upper hand
+	evaluator := PlayoutEvaluator(200, rng)
+	if score := evaluator.Evaluate(node); score >= 0 {
+		t.Errorf("Expected negative playout score %d", score)
+	}
+	node.board[0][2] = cross
+	if evaluator.Evaluate(node) != node.Score() {
+		t.Error("Terminal node must be evaluated by its score")
+	}
+	// every playout of the last empty square is a draw, scored by the searcher
+	node = tttPlayerNode{player: MaximizingPlayer}
+	node.board = [3][3]int{{cross, circle, cross}, {cross, circle, circle}, {circle, cross, empty}}
+	searcher := Searcher{Evaluator: PlayoutEvaluator(10, rng), DrawScore: 7}
+	if _, score := searcher.MinimaxAlphaBetaPrunning(node, 0, true); score != 7 {
+		t.Errorf("Expected the draw score of the drawn playouts, got %d", score)
+	}
+	// the last empty square completes the row of either player, the side to move is taken from the node
+	searcher = Searcher{Evaluator: PlayoutEvaluator(10, rng)}
+	node = tttPlayerNode{player: MaximizingPlayer}
+	node.board = [3][3]int{{cross, cross, empty}, {circle, cross, circle}, {cross, circle, circle}}
+	if _, score := searcher.MinimaxAlphaBetaPrunning(node, 0, false); score <= 0 {
+		t.Errorf("Expected positive playout score with circle to move, got %d", score)
+	}
+	node.player = MinimizingPlayer
+	if _, score := searcher.MinimaxAlphaBetaPrunning(node, 0, true); score >= 0 {
+		t.Errorf("Expected negative playout score with cross to move, got %d", score)
+	}
+	start := tttPlayerNode{player: MaximizingPlayer}
+	// each worker plays with its own generator
+	if best, _ := searcher.MinimaxConcurrent(start, 2, true, 4); best == nil {
+		t.Error("Expected any move")
+	}
+}
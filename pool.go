@@ -0,0 +1,39 @@
+package csa
+
+import (
+	"sync"
+)
+
+// Optional interface for nodes which can be reused once the search does not need them, e.g. returned to NodePool
+// The search recycles generated children except the returned best one, the root is never recycled.
+// Searches keeping the principal variation (Engine, Hint) do not recycle.
+type RecyclableNode interface {
+	Recycle()
+}
+
+// Pool of nodes of type T backed by sync.Pool, safe for concurrent use
+type NodePool[T any] struct {
+	pool sync.Pool
+}
+
+// Reused node with the contents it had when recycled, or a new zero one
+func (p *NodePool[T]) Get() *T {
+	if node, ok := p.pool.Get().(*T); ok {
+		return node
+	}
+	return new(T)
+}
+
+func (p *NodePool[T]) Put(node *T) {
+	p.pool.Put(node)
+}
+
+// Recycles the node if the search does not keep the lines referencing it
+func (s *Searcher) recycle(node SearchNode) {
+	if s.pv != nil {
+		return
+	}
+	if recyclableNode, ok := node.(RecyclableNode); ok {
+		recyclableNode.Recycle()
+	}
+}
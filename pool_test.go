@@ -0,0 +1,66 @@
+package csa
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+var tttNodePool NodePool[tttPooledNode]
+
+// Node allocated from the pool, panics when used after recycling
+type tttPooledNode struct {
+	tttNode
+	recycled bool
+	recycles *atomic.Int64
+}
+
+func (node *tttPooledNode) Score() int {
+	if node.recycled {
+		panic("recycled node evaluated")
+	}
+	return node.tttNode.Score()
+}
+
+func (node *tttPooledNode) SearchNodeGenerator() SearchNodeGenerator {
+	if node.recycled {
+		panic("recycled node expanded")
+	}
+	generator := node.tttNode.SearchNodeGenerator()
+	return func(maximizing bool) SearchNode {
+		child := generator(maximizing)
+		if child == nil {
+			return nil
+		}
+		pooled := tttNodePool.Get()
+		*pooled = tttPooledNode{child.(tttNode), false, node.recycles}
+		return pooled
+	}
+}
+
+func (node *tttPooledNode) Recycle() {
+	if node.recycled {
+		panic("node recycled twice")
+	}
+	node.recycled = true
+	node.recycles.Add(1)
+	tttNodePool.Put(node)
+}
+
+func TestTTTNodePool(t *testing.T) {
+	concurrent := func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+		return MinimaxConcurrent(node, depth, maximizing, 3)
+	}
+	for _, minimax := range []minimaxFn{Minimax, MinimaxAlphaBetaPrunning, concurrent} {
+		var recycles atomic.Int64
+		root := &tttPooledNode{recycles: &recycles}
+		root.board[0][0] = cross
+		expected, expectedScore := minimax(root.tttNode, 8, false)
+		best, score := minimax(root, 8, false)
+		if score != expectedScore || best.(*tttPooledNode).tttNode != expected || best.(*tttPooledNode).recycled {
+			t.Errorf("Pooled search differs %d %d%s%s", score, expectedScore, best, expected)
+		}
+		if recycles.Load() == 0 {
+			t.Error("Expected recycled nodes")
+		}
+	}
+}
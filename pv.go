@@ -0,0 +1,37 @@
+package csa
+
+// Triangular table of principal variations, lines[ply] is the best line found from the node in given ply
+type pvTable struct {
+	lines [][]SearchNode
+}
+
+// Forgets the line of the node being searched
+func (pv *pvTable) clear(ply int) {
+	if pv == nil {
+		return
+	}
+	for len(pv.lines) <= ply {
+		pv.lines = append(pv.lines, nil)
+	}
+	pv.lines[ply] = pv.lines[ply][:0]
+}
+
+// New best child of the node in given ply, continued by the child's line
+func (pv *pvTable) update(ply int, child SearchNode) {
+	if pv == nil {
+		return
+	}
+	var childLine []SearchNode
+	if ply+1 < len(pv.lines) {
+		childLine = pv.lines[ply+1]
+	}
+	pv.lines[ply] = append(append(pv.lines[ply][:0], child), childLine...)
+}
+
+// Copy of the line
+func (pv *pvTable) line(ply int) []SearchNode {
+	if pv == nil || len(pv.lines) <= ply {
+		return nil
+	}
+	return append([]SearchNode(nil), pv.lines[ply]...)
+}
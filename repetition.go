@@ -0,0 +1,60 @@
+package csa
+
+// Optional interface for nodes which can repeat, e.g. by moving pieces back and forth
+// Nodes with the same key are the same position with the same player to move.
+// A position occurring Repetitions times on the game path (History and the searched path) is a draw.
+type RepetitionNode interface {
+	RepetitionKey() uint64
+}
+
+// Occurrences of positions on the game path, shared by the nodes of a single search
+type repetitionPath map[uint64]int
+
+func newRepetitionPath(history []uint64) repetitionPath {
+	path := make(repetitionPath, len(history))
+	for _, key := range history {
+		path[key]++
+	}
+	return path
+}
+
+// Copy for a concurrent worker continuing the search from the same path
+func (path repetitionPath) clone() repetitionPath {
+	cloned := make(repetitionPath, len(path))
+	for key, count := range path {
+		cloned[key] = count
+	}
+	return cloned
+}
+
+func (s *Searcher) repetitions() int {
+	if s.Repetitions > 0 {
+		return s.Repetitions
+	}
+	return 2
+}
+
+// Whether the node in given ply repeats the game path often enough to be a draw, the root is always searched
+func (s *Searcher) isRepetition(node SearchNode, ply int) bool {
+	repetitionNode, ok := optionalNode(node).(RepetitionNode)
+	if !ok || ply == 0 || s.path == nil {
+		return false
+	}
+	return s.path[repetitionNode.RepetitionKey()]+1 >= s.repetitions()
+}
+
+// Adds the node to the searched path, every push is followed by pop once the node is searched
+func (s *Searcher) pushPath(node SearchNode) {
+	if repetitionNode, ok := optionalNode(node).(RepetitionNode); ok && s.path != nil {
+		s.path[repetitionNode.RepetitionKey()]++
+	}
+}
+
+func (s *Searcher) popPath(node SearchNode) {
+	if repetitionNode, ok := optionalNode(node).(RepetitionNode); ok && s.path != nil {
+		key := repetitionNode.RepetitionKey()
+		if s.path[key]--; s.path[key] == 0 {
+			delete(s.path, key)
+		}
+	}
+}
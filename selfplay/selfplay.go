@@ -0,0 +1,182 @@
+// Package selfplay generates training data by MCTS self-play and alternates it with training of the model
+//
+// Dataset is written as JSON lines, one sample per played position:
+//
+//	{"position": <Encode(node)>, "maximizing": true, "moves": ["..."], "policy": [0.7, 0.3], "outcome": 1}
+//
+// Moves are the fmt.Sprint of csa.MoveOf of the root children in the generator order, policy holds their visit
+// probabilities and outcome is the result of the game for the maximizing player, 1 for win, 0 for draw, -1 for loss.
+package selfplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+type Sample struct {
+	Node       csa.SearchNode `json:"-"`
+	Position   any            `json:"position"`
+	Maximizing bool           `json:"maximizing"` // player to move
+	Moves      []string       `json:"moves"`
+	Policy     []float64      `json:"policy"`
+	Outcome    int            `json:"outcome"`
+}
+
+// Trains the model from the samples of the finished generation, the returned model guides the next one
+type Trainer interface {
+	Train(samples []Sample) (csa.Model, error)
+}
+
+type TrainerFunc func(samples []Sample) (csa.Model, error)
+
+func (fn TrainerFunc) Train(samples []Sample) (csa.Model, error) {
+	return fn(samples)
+}
+
+type Loop struct {
+	New              func() csa.SearchNode     // initial node of a new game, the maximizing player moves first
+	Played           func(node csa.SearchNode) // optional, called with every played node
+	MCTS             csa.MCTS                  // search of the moves, its Model and Rand are replaced by the loop's
+	Model            csa.Model                 // of the first generation, nil for the playouts
+	Trainer          Trainer
+	Generations      int
+	Games            int                           // per generation
+	MaxPlies         int                           // longer games are draws, zero means unlimited
+	Temperature      float64                       // moves are sampled with probabilities of visits^(1/Temperature), zero plays the most visited
+	TemperaturePlies int                           // plies sampled with the temperature, the rest plays the most visited
+	Encode           func(node csa.SearchNode) any // position of the dataset, fmt.Sprint of the node if nil
+	Dataset          io.Writer                     // optional, every sample is written to it
+	Seed             int64
+}
+
+// Plays the generations, returns the model trained by the last one
+func (loop *Loop) Run() (csa.Model, error) {
+	rnd := rand.New(rand.NewSource(loop.Seed))
+	model := loop.Model
+	var encoder *json.Encoder
+	if loop.Dataset != nil {
+		encoder = json.NewEncoder(loop.Dataset)
+	}
+	for generation := 0; generation < loop.Generations; generation++ {
+		var samples []Sample
+		for game := 0; game < loop.Games; game++ {
+			gameSamples := loop.PlayGame(model, rnd)
+			if encoder != nil {
+				for _, sample := range gameSamples {
+					if err := encoder.Encode(sample); err != nil {
+						return model, err
+					}
+				}
+			}
+			samples = append(samples, gameSamples...)
+		}
+		trained, err := loop.Trainer.Train(samples)
+		if err != nil {
+			return model, fmt.Errorf("selfplay: generation %d: %w", generation, err)
+		}
+		model = trained
+	}
+	return model, nil
+}
+
+// Plays a single game guided by the model, returns its samples labeled by the outcome
+func (loop *Loop) PlayGame(model csa.Model, rnd *rand.Rand) []Sample {
+	mcts := loop.MCTS
+	mcts.Model, mcts.Rand = model, rnd
+	var samples []Sample
+	node, maximizing := loop.New(), true
+	for plies := 0; !node.IsTerminal() && (loop.MaxPlies <= 0 || plies < loop.MaxPlies); plies++ {
+		children, policy, _ := mcts.SearchPolicy(node, maximizing)
+		if len(children) == 0 {
+			break
+		}
+		sample := Sample{Node: node, Position: loop.encode(node), Maximizing: maximizing, Policy: policy}
+		for _, child := range children {
+			sample.Moves = append(sample.Moves, moveString(child))
+		}
+		samples = append(samples, sample)
+		temperature := loop.Temperature
+		if plies >= loop.TemperaturePlies {
+			temperature = 0
+		}
+		child := children[choose(policy, temperature, rnd)]
+		if loop.Played != nil {
+			loop.Played(child)
+		}
+		node, maximizing = child, nextPlayer(child, maximizing)
+	}
+	outcome := 0
+	if loop.MaxPlies <= 0 || len(samples) < loop.MaxPlies {
+		outcome = gameOutcome(node)
+	}
+	for i := range samples {
+		samples[i].Outcome = outcome
+	}
+	return samples
+}
+
+func (loop *Loop) encode(node csa.SearchNode) any {
+	if loop.Encode != nil {
+		return loop.Encode(node)
+	}
+	return fmt.Sprint(node)
+}
+
+// Index sampled with probabilities proportional to policy^(1/temperature), the most probable one for zero temperature
+func choose(policy []float64, temperature float64, rnd *rand.Rand) int {
+	best := 0
+	for i, probability := range policy {
+		if probability > policy[best] {
+			best = i
+		}
+	}
+	if temperature <= 0 || policy[best] == 0 {
+		return best
+	}
+	weights := make([]float64, len(policy))
+	sum := 0.0
+	for i, probability := range policy {
+		// relative to the best one, so small temperatures do not underflow
+		weights[i] = math.Pow(probability/policy[best], 1/temperature)
+		sum += weights[i]
+	}
+	threshold := rnd.Float64() * sum
+	for i, weight := range weights {
+		if threshold -= weight; threshold < 0 {
+			return i
+		}
+	}
+	return best
+}
+
+func gameOutcome(node csa.SearchNode) int {
+	if drawNode, ok := node.(csa.DrawNode); ok && drawNode.IsDraw() {
+		return 0
+	}
+	switch score := node.Score(); {
+	case score > 0:
+		return 1
+	case score < 0:
+		return -1
+	}
+	return 0
+}
+
+func moveString(node csa.SearchNode) string {
+	if move := csa.MoveOf(node); move != nil {
+		return fmt.Sprint(move)
+	}
+	return fmt.Sprint(node)
+}
+
+func nextPlayer(child csa.SearchNode, maximizing bool) bool {
+	if extraTurnNode, ok := child.(csa.ExtraTurnNode); ok && extraTurnNode.ExtraTurn() {
+		return maximizing
+	}
+	return !maximizing
+}
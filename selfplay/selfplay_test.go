@@ -0,0 +1,69 @@
+package selfplay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/internal/subtraction"
+)
+
+type uniformModel struct{}
+
+func (uniformModel) Predict(node csa.SearchNode, children []csa.SearchNode) (float64, []float64) {
+	policy := make([]float64, len(children))
+	for i := range policy {
+		policy[i] = 1 / float64(len(children))
+	}
+	return 0, policy
+}
+
+func TestLoop(t *testing.T) {
+	var dataset bytes.Buffer
+	var generations []int
+	loop := Loop{
+		New:  func() csa.SearchNode { return subtraction.New(10) },
+		MCTS: csa.MCTS{Iterations: 50, Exploration: 1, RootNoise: 0.25, NoiseAlpha: 0.3},
+		Trainer: TrainerFunc(func(samples []Sample) (csa.Model, error) {
+			generations = append(generations, len(samples))
+			return uniformModel{}, nil
+		}),
+		Generations:      3,
+		Games:            4,
+		Temperature:      1,
+		TemperaturePlies: 2,
+		Encode:           func(node csa.SearchNode) any { return node.(subtraction.Node).Stones },
+		Dataset:          &dataset,
+	}
+	model, err := loop.Run()
+	if err != nil || model == nil || len(generations) != 3 {
+		t.Fatalf("Unexpected training %v %v %v", model, err, generations)
+	}
+	lines := 0
+	for scanner := bufio.NewScanner(&dataset); scanner.Scan(); lines++ {
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			t.Fatal(err)
+		}
+		sum := 0.0
+		for _, probability := range sample.Policy {
+			sum += probability
+		}
+		if len(sample.Moves) != len(sample.Policy) || math.Abs(sum-1) > 1e-9 || sample.Outcome == 0 || sample.Position == nil {
+			t.Errorf("Invalid sample %s", scanner.Text())
+		}
+	}
+	if lines != generations[0]+generations[1]+generations[2] {
+		t.Errorf("Dataset has %d samples instead of %v", lines, generations)
+	}
+}
+
+func TestChoose(t *testing.T) {
+	policy := []float64{0.1, 0.6, 0.3}
+	if choose(policy, 0, nil) != 1 {
+		t.Error("Zero temperature has to choose the most visited move")
+	}
+}
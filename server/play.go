@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/catalog"
+)
+
+// Message of the play session over WebSocket, the type decides the used fields
+//
+// The client sends:
+//
+//	{"type": "new", "game": "connect4", "position": "", "moves": [], "engine": "second", "time_ms": 1000, "depth": 0}
+//	{"type": "move", "move": "4"}
+//	{"type": "go"}   the engine moves for the side to move
+//	{"type": "stop"} the thinking engine moves the best move of the finished depths
+//
+// The server sends the position after every change, info after every finished depth of the thinking engine,
+// the engine's move and the errors of the client's messages:
+//
+//	{"type": "position", "board": "...", "moves": ["4"], "to_move": "yellow", "legal": ["1", ...], "result": ""}
+//	{"type": "info", "depth": 7, "score": 12, "pv": ["3", "5"], "nodes": 1000, "nps": 100000, "time_ms": 10}
+//	{"type": "bestmove", "move": "3", "score": 12}
+//	{"type": "error", "error": "illegal move"}
+type Message struct {
+	Type string `json:"type"`
+
+	// new
+	Game     string   `json:"game,omitempty"`
+	Position string   `json:"position,omitempty"`
+	Engine   string   `json:"engine,omitempty"` // sides of the engine: first, second, both or none
+	Depth    int      `json:"depth,omitempty"`
+	TimeMs   int64    `json:"time_ms,omitempty"`
+	Moves    []string `json:"moves,omitempty"` // also of position
+
+	// move and bestmove
+	Move  string `json:"move,omitempty"`
+	Score int    `json:"score"` // also of info
+
+	// position
+	Board  string   `json:"board,omitempty"`
+	ToMove string   `json:"to_move,omitempty"`
+	Legal  []string `json:"legal,omitempty"`
+	Result string   `json:"result,omitempty"`
+
+	// info
+	PV    []string `json:"pv,omitempty"`
+	Nodes int64    `json:"nodes,omitempty"`
+	NPS   int64    `json:"nps,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// Game of a WebSocket connection, the engine thinks in its own goroutine
+type session struct {
+	server   *Server
+	ws       *websocket
+	client   string // of the queue
+	priority Priority
+	mutex    sync.Mutex // of the fields below, held while the position changes
+	game     catalog.Game
+	setup    Message // new message of the game
+	moves    []string
+	node     csa.SearchNode
+	// whether the maximizing player is to move
+	maximizing bool
+	engine     [2]bool       // sides played by the engine
+	cancel     func()        // of the thinking engine, nil if it is not thinking
+	done       chan struct{} // closed by the finished thinking
+}
+
+// Serves the play session of the WebSocket connection until it is closed
+func (server *Server) play(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrade(w, r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	defer ws.close()
+	client, priority := server.client(r)
+	s := &session{server: server, ws: ws, client: client, priority: priority}
+	defer s.stop()
+	for {
+		data, err := ws.read()
+		if err != nil {
+			return
+		}
+		var message Message
+		if err := json.Unmarshal(data, &message); err != nil {
+			s.send(Message{Type: "error", Error: "invalid message: " + err.Error()})
+			continue
+		}
+		if err := s.handle(message); err != nil {
+			s.send(Message{Type: "error", Error: err.Error()})
+		}
+	}
+}
+
+func (s *session) handle(message Message) error {
+	switch message.Type {
+	case "new":
+		s.stop()
+		return s.start(message)
+	case "stop":
+		s.stop()
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.node == nil {
+		return fmt.Errorf("no game, send new first")
+	}
+	if s.cancel != nil {
+		return fmt.Errorf("engine is thinking, send stop first")
+	}
+	switch message.Type {
+	case "move":
+		child := catalog.Child(s.node, s.maximizing, message.Move)
+		if child == nil || s.node.IsTerminal() {
+			return fmt.Errorf("illegal move %q", message.Move)
+		}
+		s.play(child)
+	case "go":
+		if s.node.IsTerminal() {
+			return fmt.Errorf("game is over")
+		}
+		s.think()
+	default:
+		return fmt.Errorf("unknown message type %q", message.Type)
+	}
+	return nil
+}
+
+func (s *session) start(message Message) error {
+	sides := map[string][2]bool{"first": {true, false}, "second": {false, true}, "both": {true, true}, "none": {}, "": {}}
+	engine, ok := sides[message.Engine]
+	if !ok {
+		return fmt.Errorf("unknown engine side %q", message.Engine)
+	}
+	game, ok := s.server.games()[message.Game]
+	if !ok {
+		return fmt.Errorf("unknown game %q", message.Game)
+	}
+	node, maximizing, err := game.Play(message.Position, message.Moves)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.game, s.setup, s.engine = game, message, engine
+	s.node, s.maximizing, s.moves = node, maximizing, append([]string{}, message.Moves...)
+	s.sendPosition()
+	s.thinkIfEngine()
+	return nil
+}
+
+// Plays the child, called with the mutex held
+func (s *session) play(child csa.SearchNode) {
+	s.moves = append(s.moves, catalog.MoveString(child))
+	s.node, s.maximizing = child, catalog.NextPlayer(child, s.maximizing)
+	s.sendPosition()
+	s.thinkIfEngine()
+}
+
+func (s *session) thinkIfEngine() {
+	side := 1
+	if s.maximizing {
+		side = 0
+	}
+	if s.engine[side] && !s.node.IsTerminal() {
+		s.think()
+	}
+}
+
+// Starts the engine's search of the current position, called with the mutex held
+func (s *session) think() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.cancel, s.done = cancel, done
+	request := AnalyzeRequest{Game: s.setup.Game, Position: s.setup.Position, Moves: append([]string{}, s.moves...),
+		Depth: s.setup.Depth, TimeMs: s.setup.TimeMs}
+	go func() {
+		defer close(done)
+		defer cancel()
+		response, err := s.server.queued(ctx, s.client, s.priority, request, func(progress Progress) {
+			s.send(Message{Type: "info", Depth: progress.Depth, Score: progress.Score, PV: progress.PV,
+				Nodes: progress.Nodes, NPS: progress.NPS, TimeMs: progress.TimeMs})
+		})
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.cancel = nil
+		if err != nil {
+			s.send(Message{Type: "error", Error: err.Error()})
+			return
+		}
+		child := catalog.Child(s.node, s.maximizing, response.Move)
+		if child == nil {
+			s.send(Message{Type: "error", Error: "engine has no move"})
+			return
+		}
+		s.send(Message{Type: "bestmove", Move: response.Move, Score: response.Score})
+		s.play(child)
+	}()
+}
+
+// Stops the thinking engine and waits until its move is played
+func (s *session) stop() {
+	s.mutex.Lock()
+	cancel, done := s.cancel, s.done
+	// the engine of the other side does not start thinking after the move
+	engine := s.engine
+	s.engine = [2]bool{}
+	s.mutex.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	s.mutex.Lock()
+	s.engine = engine
+	s.mutex.Unlock()
+}
+
+func (s *session) sendPosition() {
+	message := Message{Type: "position", Board: fmt.Sprint(s.node), Moves: s.moves}
+	if s.node.IsTerminal() {
+		message.Result = s.game.Result(s.node)
+	} else {
+		side := 1
+		if s.maximizing {
+			side = 0
+		}
+		message.ToMove = s.game.Players[side]
+		for _, child := range catalog.Children(s.node, s.maximizing) {
+			message.Legal = append(message.Legal, catalog.MoveString(child))
+		}
+	}
+	s.send(message)
+}
+
+func (s *session) send(message Message) {
+	data, _ := json.Marshal(message)
+	s.ws.write(data)
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+var priorityNames = [...]string{"low", "normal", "high"}
+
+func (priority Priority) String() string {
+	if priority < PriorityLow || priority > PriorityHigh {
+		return "unknown"
+	}
+	return priorityNames[priority]
+}
+
+// Priority of the name, normal if empty
+func ParsePriority(name string) (Priority, bool) {
+	if name == "" {
+		return PriorityNormal, true
+	}
+	for priority, priorityName := range priorityNames {
+		if priorityName == name {
+			return Priority(priority), true
+		}
+	}
+	return 0, false
+}
+
+// Job of the client rejected by the queue's rate limit
+type RateLimitError struct {
+	RetryAfter time.Duration // until the client can submit the next job
+}
+
+func (err *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded, retry after %s", err.RetryAfter.Round(time.Millisecond))
+}
+
+// Job of the client rejected because the client has too many waiting jobs
+var ErrQueueFull = errors.New("server: too many queued requests")
+
+// Runs the expensive jobs of many clients on shared hardware, the zero value runs one job at a time
+// The waiting jobs of the highest priority start first, the clients of the same priority take turns
+// so that a client with many waiting jobs does not delay the others.
+type Queue struct {
+	Concurrency int     // jobs running at once, at least one
+	Rate        float64 // jobs submitted per second by a client, zero means unlimited
+	Burst       int     // jobs a client can submit at once above the rate, at least one
+	MaxQueued   int     // waiting jobs of a client, zero means unlimited
+
+	mutex   sync.Mutex
+	running int
+	lanes   [PriorityHigh + 1]lane
+	buckets map[string]*bucket
+}
+
+// Waiting jobs of a priority, the clients take turns in the order of the ring
+type lane struct {
+	ring []string
+	jobs map[string][]*job
+}
+
+type job struct {
+	client   string
+	priority Priority
+	ready    chan struct{} // closed when the job can start
+}
+
+// Token bucket of a client's rate limit
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Runs the job of the client when its turn comes, the job's context is ctx
+// Fails without running the job with RateLimitError, ErrQueueFull or the error of ctx done before the job starts.
+func (queue *Queue) Do(ctx context.Context, client string, priority Priority, run func(ctx context.Context)) error {
+	j, err := queue.enqueue(client, priority)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-j.ready:
+	case <-ctx.Done():
+		queue.mutex.Lock()
+		removed := queue.remove(j)
+		queue.mutex.Unlock()
+		if !removed {
+			// started meanwhile
+			queue.finish()
+		}
+		return ctx.Err()
+	}
+	defer queue.finish()
+	run(ctx)
+	return nil
+}
+
+// Running and waiting jobs
+func (queue *Queue) Len() (int, int) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	waiting := 0
+	for _, lane := range queue.lanes {
+		for _, jobs := range lane.jobs {
+			waiting += len(jobs)
+		}
+	}
+	return queue.running, waiting
+}
+
+func (queue *Queue) enqueue(client string, priority Priority) (*job, error) {
+	priority = min(max(priority, PriorityLow), PriorityHigh)
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if queue.MaxQueued > 0 && queue.waiting(client) >= queue.MaxQueued {
+		return nil, ErrQueueFull
+	}
+	if err := queue.take(client, time.Now()); err != nil {
+		return nil, err
+	}
+	j := &job{client: client, priority: priority, ready: make(chan struct{})}
+	lane := &queue.lanes[priority]
+	if lane.jobs == nil {
+		lane.jobs = map[string][]*job{}
+	}
+	if len(lane.jobs[client]) == 0 {
+		lane.ring = append(lane.ring, client)
+	}
+	lane.jobs[client] = append(lane.jobs[client], j)
+	queue.dispatch()
+	return j, nil
+}
+
+func (queue *Queue) waiting(client string) int {
+	waiting := 0
+	for _, lane := range queue.lanes {
+		waiting += len(lane.jobs[client])
+	}
+	return waiting
+}
+
+// Takes a token of the client's bucket
+func (queue *Queue) take(client string, now time.Time) error {
+	if queue.Rate <= 0 {
+		return nil
+	}
+	burst := float64(max(queue.Burst, 1))
+	if queue.buckets == nil {
+		queue.buckets = map[string]*bucket{}
+	}
+	if len(queue.buckets) >= 1024 {
+		// the full buckets are the same as the missing ones
+		for name, b := range queue.buckets {
+			if b.tokens+now.Sub(b.last).Seconds()*queue.Rate >= burst {
+				delete(queue.buckets, name)
+			}
+		}
+	}
+	b, ok := queue.buckets[client]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		queue.buckets[client] = b
+	}
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*queue.Rate, burst)
+	b.last = now
+	if b.tokens < 1 {
+		seconds := (1 - b.tokens) / queue.Rate
+		return &RateLimitError{RetryAfter: time.Duration(math.Ceil(seconds * float64(time.Second)))}
+	}
+	b.tokens--
+	return nil
+}
+
+// Starts the waiting jobs while there are free slots, called with the mutex held
+func (queue *Queue) dispatch() {
+	for queue.running < max(queue.Concurrency, 1) {
+		lane := queue.nextLane()
+		if lane == nil {
+			return
+		}
+		client := lane.ring[0]
+		jobs := lane.jobs[client]
+		j := jobs[0]
+		lane.ring = lane.ring[1:]
+		if len(jobs) == 1 {
+			delete(lane.jobs, client)
+		} else {
+			lane.jobs[client] = jobs[1:]
+			lane.ring = append(lane.ring, client)
+		}
+		queue.running++
+		close(j.ready)
+	}
+}
+
+// Lane of the highest priority with a waiting job, nil if there is none
+func (queue *Queue) nextLane() *lane {
+	for priority := PriorityHigh; priority >= PriorityLow; priority-- {
+		if len(queue.lanes[priority].ring) > 0 {
+			return &queue.lanes[priority]
+		}
+	}
+	return nil
+}
+
+// Removes the waiting job, false if it has already started, called with the mutex held
+func (queue *Queue) remove(j *job) bool {
+	lane := &queue.lanes[j.priority]
+	jobs := lane.jobs[j.client]
+	for i, waiting := range jobs {
+		if waiting != j {
+			continue
+		}
+		if len(jobs) > 1 {
+			lane.jobs[j.client] = append(jobs[:i:i], jobs[i+1:]...)
+			return true
+		}
+		delete(lane.jobs, j.client)
+		for k, client := range lane.ring {
+			if client == j.client {
+				lane.ring = append(lane.ring[:k:k], lane.ring[k+1:]...)
+				break
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (queue *Queue) finish() {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.running--
+	queue.dispatch()
+}
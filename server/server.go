@@ -0,0 +1,260 @@
+// Package server serves the analysis of the bundled games by the csa engine over HTTP with JSON bodies,
+// e.g. as the backend of a web game:
+//
+//	POST /analyze {"game": "connect4", "position": "4453", "moves": ["4"], "time_ms": 500} → best move, score and PV
+//	GET /games → names of the games with their position formats
+//	GET /health → {"status": "ok"}
+//	GET /play → WebSocket of an interactive game with the engine streaming its search, see Message
+//
+// Every request searches by its own engine and is cancelled together with the request's context,
+// the result of the last finished depth is returned then. With the Queue, the searches of the requests and
+// of the play sessions wait for their turn and the clients over the rate limit get 429 Too Many Requests.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/catalog"
+)
+
+// Time of the requests without the time and depth limit
+const DefaultTime = time.Second
+
+// Position to analyze, the moves are played from the position in the game's notation
+type AnalyzeRequest struct {
+	Game      string   `json:"game"`
+	Position  string   `json:"position,omitempty"` // in the game's format, the initial one if empty
+	Moves     []string `json:"moves,omitempty"`
+	Depth     int      `json:"depth,omitempty"`     // zero means unlimited
+	TimeMs    int64    `json:"time_ms,omitempty"`   // zero means DefaultTime without the depth, unlimited with it
+	Algorithm string   `json:"algorithm,omitempty"` // csa.Algorithm's name, alpha-beta if empty
+	Priority  string   `json:"priority,omitempty"`  // low, normal or high, only lowers the client's priority in the queue
+}
+
+// Best move of the last finished depth with the score from the first (maximizing) player's point of view
+type AnalyzeResponse struct {
+	Move   string   `json:"move,omitempty"` // empty for the terminal position or without any move
+	Score  int      `json:"score"`
+	Depth  int      `json:"depth"`
+	PV     []string `json:"pv,omitempty"`
+	Nodes  int64    `json:"nodes"`
+	TimeMs int64    `json:"time_ms"`
+	Result string   `json:"result,omitempty"` // of the terminal position, e.g. white wins or draw
+}
+
+// Progress of the analysis after a finished depth of iterative deepening
+type Progress struct {
+	Depth  int      `json:"depth"`
+	Score  int      `json:"score"`
+	PV     []string `json:"pv"`
+	Nodes  int64    `json:"nodes"`
+	NPS    int64    `json:"nps"`
+	TimeMs int64    `json:"time_ms"`
+}
+
+// Analysis server, implements http.Handler
+// The limits cap the limits of the requests, zero means none.
+type Server struct {
+	Games    map[string]catalog.Game // catalog.Games if nil
+	MaxDepth int
+	MaxTime  time.Duration
+	TT       int    // entries of the transposition table of every request, none if zero
+	Workers  int    // of the parallel algorithms, zero means GOMAXPROCS
+	Queue    *Queue // of the searches, optional
+	// Client of the request and its priority in the queue, the host of the remote address with the normal priority if nil
+	Client func(r *http.Request) (string, Priority)
+}
+
+// Error of the request, e.g. an unknown game or an illegal move
+type RequestError struct {
+	Message string
+}
+
+func (err *RequestError) Error() string {
+	return err.Message
+}
+
+func requestErrorf(format string, args ...any) error {
+	return &RequestError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (server *Server) games() map[string]catalog.Game {
+	if server.Games == nil {
+		return catalog.Games
+	}
+	return server.Games
+}
+
+// Searches the position of the request until its limits or the context is done, progress is called after every depth
+// Fails with RequestError if the request is invalid.
+func (server *Server) Analyze(ctx context.Context, request AnalyzeRequest, progress func(Progress)) (AnalyzeResponse, error) {
+	game, ok := server.games()[request.Game]
+	if !ok {
+		return AnalyzeResponse{}, requestErrorf("unknown game %q", request.Game)
+	}
+	algorithm := csa.AlgorithmAlphaBeta
+	if request.Algorithm != "" {
+		if algorithm, ok = parseAlgorithm(request.Algorithm); !ok {
+			return AnalyzeResponse{}, requestErrorf("unknown algorithm %q", request.Algorithm)
+		}
+	}
+	if request.Depth < 0 || request.TimeMs < 0 {
+		return AnalyzeResponse{}, requestErrorf("negative limit")
+	}
+	node, maximizing, err := game.Play(request.Position, request.Moves)
+	if err != nil {
+		return AnalyzeResponse{}, requestErrorf("%v", err)
+	}
+	if node.IsTerminal() {
+		return AnalyzeResponse{Score: node.Score(), Result: game.Result(node)}, nil
+	}
+
+	limit := time.Duration(request.TimeMs) * time.Millisecond
+	if limit == 0 && request.Depth == 0 {
+		limit = DefaultTime
+	}
+	if server.MaxTime > 0 && (limit == 0 || limit > server.MaxTime) {
+		limit = server.MaxTime
+	}
+	depth := request.Depth
+	if server.MaxDepth > 0 && (depth == 0 || depth > server.MaxDepth) {
+		depth = server.MaxDepth
+	}
+	var last csa.SearchInfo
+	options := []csa.EngineOption{
+		csa.WithSearcher(csa.Searcher{WinScore: game.WinScore}),
+		csa.WithAlgorithm(algorithm),
+		csa.WithMaxDepth(depth),
+		csa.WithTimeLimit(limit),
+		csa.WithWorkers(server.Workers),
+		csa.WithInfo(func(info csa.SearchInfo) {
+			last = info
+			if progress != nil {
+				progress(Progress{Depth: info.Depth, Score: info.Score, PV: moves(info.PV), Nodes: info.Nodes,
+					NPS: info.NPS, TimeMs: info.Time.Milliseconds()})
+			}
+		}),
+	}
+	if server.TT > 0 {
+		options = append(options, csa.WithTT(server.TT))
+	}
+	engine := csa.NewEngine(options...)
+	defer engine.Close()
+	child, score := engine.BestMoveContext(ctx, node, maximizing)
+	response := AnalyzeResponse{Score: score, Depth: last.Depth, PV: moves(last.PV), Nodes: last.Nodes,
+		TimeMs: last.Time.Milliseconds()}
+	if child != nil {
+		response.Move = catalog.MoveString(child)
+	}
+	return response, nil
+}
+
+// Analysis of the client's request in the queue, the same as Analyze without the queue
+func (server *Server) queued(ctx context.Context, client string, priority Priority, request AnalyzeRequest, progress func(Progress)) (AnalyzeResponse, error) {
+	requested, ok := ParsePriority(request.Priority)
+	if !ok {
+		return AnalyzeResponse{}, requestErrorf("unknown priority %q", request.Priority)
+	}
+	if server.Queue == nil {
+		return server.Analyze(ctx, request, progress)
+	}
+	var response AnalyzeResponse
+	var err error
+	queueErr := server.Queue.Do(ctx, client, min(priority, requested), func(ctx context.Context) {
+		response, err = server.Analyze(ctx, request, progress)
+	})
+	if queueErr != nil {
+		return AnalyzeResponse{}, queueErr
+	}
+	return response, err
+}
+
+func (server *Server) client(r *http.Request) (string, Priority) {
+	if server.Client != nil {
+		return server.Client(r)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return host, PriorityNormal
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/analyze" && r.Method == http.MethodPost:
+		var request AnalyzeRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request: " + err.Error()})
+			return
+		}
+		client, priority := server.client(r)
+		response, err := server.queued(r.Context(), client, priority, request, nil)
+		var requestError *RequestError
+		var rateLimitError *RateLimitError
+		switch {
+		case errors.As(err, &requestError):
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.As(err, &rateLimitError):
+			seconds := (rateLimitError.RetryAfter + time.Second - 1) / time.Second
+			w.Header().Set("Retry-After", strconv.FormatInt(int64(seconds), 10))
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrQueueFull) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		case err != nil:
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		default:
+			writeJSON(w, http.StatusOK, response)
+		}
+	case r.URL.Path == "/games" && r.Method == http.MethodGet:
+		games := map[string]string{}
+		for name, game := range server.games() {
+			games[name] = game.Position
+		}
+		writeJSON(w, http.StatusOK, games)
+	case r.URL.Path == "/play":
+		server.play(w, r)
+	case r.URL.Path == "/health" && r.Method == http.MethodGet:
+		health := map[string]any{"status": "ok"}
+		if server.Queue != nil {
+			health["running"], health["queued"] = server.Queue.Len()
+		}
+		writeJSON(w, http.StatusOK, health)
+	case r.URL.Path == "/analyze" || r.URL.Path == "/games" || r.URL.Path == "/health":
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// Moves of the nodes in the games' notation
+func moves(nodes []csa.SearchNode) []string {
+	moves := make([]string, len(nodes))
+	for i, node := range nodes {
+		moves[i] = catalog.MoveString(node)
+	}
+	return moves
+}
+
+func parseAlgorithm(name string) (csa.Algorithm, bool) {
+	for algorithm := csa.Algorithm(0); algorithm.String() != "unknown"; algorithm++ {
+		if algorithm.String() == name {
+			return algorithm, true
+		}
+	}
+	return 0, false
+}
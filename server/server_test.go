@@ -0,0 +1,258 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func post(t *testing.T, url, body string) (*http.Response, AnalyzeResponse) {
+	resp, err := http.Post(url+"/analyze", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var response AnalyzeResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+	return resp, response
+}
+
+func TestAnalyze(t *testing.T) {
+	ts := httptest.NewServer(&Server{MaxDepth: 9, TT: 1 << 16})
+	defer ts.Close()
+	// X completes the first row
+	resp, response := post(t, ts.URL, `{"game": "tictactoe", "moves": ["a1", "b2", "b1", "c3"], "depth": 3}`)
+	if resp.StatusCode != http.StatusOK || response.Score <= 0 || len(response.PV) == 0 || response.PV[0] != response.Move {
+		t.Errorf("Expected the win, got %d %+v", resp.StatusCode, response)
+	}
+	resp, response = post(t, ts.URL, `{"game": "connect4", "position": "4444", "time_ms": 50}`)
+	if resp.StatusCode != http.StatusOK || response.Move == "" || response.Depth < 1 || response.Nodes == 0 {
+		t.Errorf("Expected the best move, got %d %+v", resp.StatusCode, response)
+	}
+	resp, response = post(t, ts.URL, `{"game": "nim", "position": "0,0"}`)
+	if resp.StatusCode != http.StatusOK || response.Move != "" || response.Result == "" {
+		t.Errorf("Expected the terminal position, got %d %+v", resp.StatusCode, response)
+	}
+	for _, body := range []string{`{"game": "go"}`, `{"game": "connect4", "moves": ["9"]}`, `{"game": `,
+		`{"game": "chess", "algorithm": "magic"}`} {
+		if resp, _ := post(t, ts.URL, body); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected bad request of %s, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	ts := httptest.NewServer(&Server{})
+	defer ts.Close()
+	for path, status := range map[string]int{"/health": http.StatusOK, "/games": http.StatusOK,
+		"/analyze": http.StatusMethodNotAllowed, "/foo": http.StatusNotFound} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("Expected %d of %s, got %d", status, path, resp.StatusCode)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	request := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(`{"game": "chess", "time_ms": 60000}`))
+	recorder := httptest.NewRecorder()
+	start := time.Now()
+	(&Server{}).ServeHTTP(recorder, request.WithContext(ctx))
+	if elapsed := time.Since(start); elapsed > 5*time.Second || recorder.Code != http.StatusOK {
+		t.Errorf("Expected the cancelled search, got %d after %s", recorder.Code, elapsed)
+	}
+}
+
+// Client side of the WebSocket, the frames are masked by zeros
+type client struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dial(t *testing.T, url string) *client {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(conn, "GET /play HTTP/1.1\r\nHost: csa\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	c := &client{conn: conn, reader: bufio.NewReader(conn)}
+	resp, err := http.ReadResponse(c.reader, nil)
+	if err != nil || resp.StatusCode != http.StatusSwitchingProtocols ||
+		resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Expected the handshake, got %v %v", resp, err)
+	}
+	return c
+}
+
+func (c *client) send(t *testing.T, message string) {
+	frame := []byte{0x81}
+	if len(message) < 126 {
+		frame = append(frame, 0x80|byte(len(message)))
+	} else {
+		frame = binary.BigEndian.AppendUint16(append(frame, 0x80|126), uint16(len(message)))
+	}
+	frame = append(append(frame, 0, 0, 0, 0), message...)
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Next message of the type, skips the other ones
+func (c *client) receive(t *testing.T, messageType string) Message {
+	c.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+			t.Fatal(err)
+		}
+		length := int(header[1])
+		if length == 126 {
+			var extended [2]byte
+			io.ReadFull(c.reader, extended[:])
+			length = int(binary.BigEndian.Uint16(extended[:]))
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.reader, payload); err != nil {
+			t.Fatal(err)
+		}
+		var message Message
+		if err := json.Unmarshal(payload, &message); err != nil {
+			t.Fatal(err)
+		}
+		if message.Type == messageType {
+			return message
+		}
+	}
+}
+
+func TestPlay(t *testing.T) {
+	ts := httptest.NewServer(&Server{})
+	defer ts.Close()
+	c := dial(t, ts.URL)
+	defer c.conn.Close()
+	c.send(t, `{"type": "move", "move": "4"}`)
+	if message := c.receive(t, "error"); message.Error == "" {
+		t.Error("Expected no game")
+	}
+	c.send(t, `{"type": "new", "game": "connect4", "engine": "second", "depth": 4}`)
+	if message := c.receive(t, "position"); message.ToMove != "red" || len(message.Legal) != 7 {
+		t.Errorf("Expected red to move, got %+v", message)
+	}
+	c.send(t, `{"type": "move", "move": "4"}`)
+	if message := c.receive(t, "info"); message.Depth != 1 || len(message.PV) == 0 {
+		t.Errorf("Expected the first depth, got %+v", message)
+	}
+	bestMove := c.receive(t, "bestmove")
+	if message := c.receive(t, "position"); message.ToMove != "red" || len(message.Moves) != 2 ||
+		message.Moves[1] != bestMove.Move {
+		t.Errorf("Expected the engine's move %s, got %+v", bestMove.Move, message)
+	}
+	c.send(t, `{"type": "move", "move": "8"}`)
+	if message := c.receive(t, "error"); message.Error != `illegal move "8"` {
+		t.Errorf("Expected the illegal move, got %+v", message)
+	}
+	// the engine thinks until stopped, then it plays for red
+	c.send(t, `{"type": "new", "game": "connect4", "engine": "first", "time_ms": 60000}`)
+	c.receive(t, "info")
+	c.send(t, `{"type": "stop"}`)
+	c.receive(t, "bestmove")
+	if message := c.receive(t, "position"); message.ToMove != "yellow" {
+		t.Errorf("Expected yellow to move, got %+v", message)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	queue := &Queue{Concurrency: 1, MaxQueued: 3}
+	started, release := make(chan struct{}), make(chan struct{})
+	go queue.Do(context.Background(), "a", PriorityNormal, func(ctx context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+	var order []string
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	submit := func(ctx context.Context, client string, priority Priority) chan error {
+		_, waiting := queue.Len()
+		result := make(chan error, 1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result <- queue.Do(ctx, client, priority, func(ctx context.Context) {
+				mutex.Lock()
+				order = append(order, client+" "+priority.String())
+				mutex.Unlock()
+			})
+		}()
+		for _, w := queue.Len(); w == waiting; _, w = queue.Len() {
+			time.Sleep(time.Millisecond)
+		}
+		return result
+	}
+	for i := 0; i < 3; i++ {
+		submit(context.Background(), "a", PriorityNormal)
+	}
+	if err := queue.Do(context.Background(), "a", PriorityNormal, func(context.Context) {}); err != ErrQueueFull {
+		t.Errorf("Expected the full queue, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := submit(ctx, "b", PriorityLow)
+	submit(context.Background(), "b", PriorityNormal)
+	submit(context.Background(), "c", PriorityHigh)
+	submit(context.Background(), "b", PriorityLow)
+	cancel()
+	if err := <-cancelled; err != context.Canceled {
+		t.Errorf("Expected the cancelled job, got %v", err)
+	}
+	close(release)
+	wg.Wait()
+	// the high priority first, then the clients take turns
+	if expected := "[c high a normal b normal a normal a normal b low]"; fmt.Sprint(order) != expected {
+		t.Errorf("Expected the order %s, got %v", expected, order)
+	}
+	if running, waiting := queue.Len(); running != 0 || waiting != 0 {
+		t.Errorf("Expected the empty queue, got %d running and %d waiting", running, waiting)
+	}
+
+	limited := &Queue{Concurrency: 2, Rate: 1, Burst: 2}
+	for i := 0; i < 2; i++ {
+		if err := limited.Do(context.Background(), "a", PriorityNormal, func(context.Context) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var rateLimitError *RateLimitError
+	if err := limited.Do(context.Background(), "a", PriorityNormal, func(context.Context) {}); !errors.As(err, &rateLimitError) ||
+		rateLimitError.RetryAfter <= 0 || rateLimitError.RetryAfter > time.Second {
+		t.Errorf("Expected the rate limit, got %v", err)
+	}
+	if err := limited.Do(context.Background(), "b", PriorityNormal, func(context.Context) {}); err != nil {
+		t.Errorf("Expected another client's job, got %v", err)
+	}
+
+	ts := httptest.NewServer(&Server{MaxDepth: 2, Queue: &Queue{Rate: 0.001}})
+	defer ts.Close()
+	if resp, _ := post(t, ts.URL, `{"game": "tictactoe", "priority": "low"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the analysis, got %d", resp.StatusCode)
+	}
+	if resp, _ := post(t, ts.URL, `{"game": "tictactoe"}`); resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("Expected too many requests, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,96 @@
+package csa
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Strength limit of Engine, e.g. for a casual opponent
+// The engine scores all root children and picks one of the best TopK randomly, the worse the child
+// the lower the probability depending on Temperature (in units of node's score). Zero temperature picks the best child.
+// With probability of BlunderChance the engine picks any child instead.
+type Skill struct {
+	Depth         int // max search depth, zero means the engine's depth
+	TopK          int // zero means all children
+	Temperature   float64
+	BlunderChance float64
+	Seed          int64 // of the random source of every engine, they pick the same moves in the same positions
+}
+
+// Skill of an engine with its own random source, engines of the same option can search concurrently
+type engineSkill struct {
+	Skill
+	rand *rand.Rand
+}
+
+func WithSkill(skill Skill) EngineOption {
+	return func(engine *Engine) {
+		engine.skill = &engineSkill{Skill: skill, rand: rand.New(rand.NewSource(skill.Seed))}
+	}
+}
+
+// Root children scored by iterative deepening under the time limit and the context like any other search,
+// one of them is picked by the skill
+func (engine *Engine) skillMove(ctx context.Context, node SearchNode, maximizing bool, soft, hard time.Duration) (SearchNode, int) {
+	skill := engine.skill
+	maxDepth := engine.depth()
+	if skill.Depth > 0 {
+		maxDepth = min(maxDepth, skill.Depth)
+	}
+	maximizing = playerToMove(node, maximizing)
+	s := engine.searcher.newSearch()
+	s.limits = &searchLimits{poll: engine.poll}
+	start := time.Now()
+	finished := 0
+	defer func() {
+		engine.stats.record(s.limits, start, finished)
+	}()
+	var children []Variation
+	for depth := 1; depth <= maxDepth && !s.isTerminal(node); depth++ {
+		s.limits.horizon.Store(0)
+		variations := s.rootVariations(node, depth, maximizing)
+		if s.limits.stopped.Load() {
+			break
+		}
+		children, finished = variations, depth
+		engine.log(ctx, slog.LevelDebug, "depth finished", "depth", depth, "nodes", s.limits.nodes.Load(),
+			"elapsed", time.Since(start))
+		if s.limits.horizon.Load() == 0 || (soft > 0 && time.Since(start) >= soft) || ctx.Err() != nil {
+			// whole game tree searched, out of time or cancelled
+			break
+		}
+		s.limits.abortAfter(ctx, start, hard)
+	}
+	if len(children) == 0 {
+		return engine.searcher.MinimaxAlphaBetaPrunning(node, 1, maximizing)
+	}
+	if skill.BlunderChance > 0 && skill.rand.Float64() < skill.BlunderChance {
+		child := children[skill.rand.Intn(len(children))]
+		return child.Node, child.Score
+	}
+	if skill.TopK > 0 {
+		children = children[:min(len(children), skill.TopK)]
+	}
+	if skill.Temperature <= 0 {
+		return children[0].Node, children[0].Score
+	}
+	// softmax of the losses against the best child
+	weights := make([]float64, len(children))
+	total := 0.0
+	for i, child := range children {
+		loss := math.Abs(float64(children[0].Score) - float64(child.Score))
+		weights[i] = math.Exp(-loss / skill.Temperature)
+		total += weights[i]
+	}
+	pick := skill.rand.Float64() * total
+	for i, weight := range weights {
+		if pick -= weight; pick < 0 {
+			return children[i].Node, children[i].Score
+		}
+	}
+	last := children[len(children)-1]
+	return last.Node, last.Score
+}
@@ -0,0 +1,68 @@
+package csa
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestTTTEngineSkill(t *testing.T) {
+	node := tttNode{}
+	node.board[0][0] = cross
+	node.board[1][1] = circle
+	node.board[2][2] = cross
+	picked := map[[3][3]int]int{}
+	engine := NewEngine(WithSkill(Skill{TopK: 5, Temperature: 3, Seed: 3}))
+	for i := 0; i < 200; i++ {
+		best, _ := engine.BestMove(node, true)
+		picked[best.(tttNode).board]++
+	}
+	// four equal draws and the better of the losing corners
+	if len(picked) != 5 {
+		t.Errorf("Expected five different moves, got %d", len(picked))
+	}
+	for board, count := range picked {
+		if (board[0][2] == circle || board[2][0] == circle) && count > 50 {
+			t.Errorf("Losing move picked too often %d", count)
+		}
+	}
+	// zero temperature plays the best move
+	engine = NewEngine(WithSkill(Skill{Depth: 9}))
+	if _, score := engine.BestMove(node, true); score != 0 {
+		t.Errorf("Expected a draw, got %d", score)
+	}
+	// always blunders
+	engine = NewEngine(WithSkill(Skill{BlunderChance: 1, Seed: 1}))
+	for i := 0; i < 20; i++ {
+		if best, _ := engine.BestMove(node, true); best == nil {
+			t.Fatal("Expected any move")
+		}
+	}
+	// cancelled search finishes only the first depth
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	engine = NewEngine(WithSkill(Skill{Temperature: 3}))
+	if best, _ := engine.BestMoveContext(ctx, tttNode{}, true); best == nil {
+		t.Fatal("Expected any move")
+	}
+	if depth := engine.Stats().Depth; depth != 1 {
+		t.Errorf("Expected depth 1 of the cancelled search, got %d", depth)
+	}
+	// engines of the same option search concurrently with their own random sources
+	option := WithSkill(Skill{TopK: 5, Temperature: 3})
+	var wg sync.WaitGroup
+	moves := make([]SearchNode, 4)
+	for i := range moves {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			moves[i], _ = NewEngine(option, WithMaxDepth(4)).BestMove(node, true)
+		}()
+	}
+	wg.Wait()
+	for _, move := range moves[1:] {
+		if move != moves[0] {
+			t.Error("Expected the same moves of the engines with the same seed")
+		}
+	}
+}
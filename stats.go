@@ -0,0 +1,55 @@
+package csa
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Counters of the engine's searches, e.g. for monitoring of long-running services
+type EngineStats struct {
+	Searches    int64 // finished searches
+	Nodes       int64 // searched by all the searches
+	NPS         int64 // of the last search
+	Depth       int   // last finished depth of the last search
+	TTProbes    int64
+	TTHits      int64 // probes deciding the searched node
+	Workers     int   // of the worker pool, zero before the first parallel search
+	BusyWorkers int   // workers searching at the moment
+}
+
+// Engine's counters updated atomically, so Stats can be read while the engine searches
+type engineStats struct {
+	searches atomic.Int64
+	nodes    atomic.Int64
+	nps      atomic.Int64
+	depth    atomic.Int64
+	tt       atomic.Pointer[transpositionTable]
+	pool     atomic.Pointer[workerPool]
+}
+
+// Statistics of the searches so far, safe for concurrent use with the search
+func (engine *Engine) Stats() EngineStats {
+	stats := EngineStats{
+		Searches: engine.stats.searches.Load(),
+		Nodes:    engine.stats.nodes.Load(),
+		NPS:      engine.stats.nps.Load(),
+		Depth:    int(engine.stats.depth.Load()),
+	}
+	if tt := engine.stats.tt.Load(); tt != nil {
+		stats.TTProbes = tt.probes.Load()
+		stats.TTHits = tt.hits.Load()
+	}
+	if pool := engine.stats.pool.Load(); pool != nil {
+		stats.Workers = pool.workers
+		stats.BusyWorkers = pool.workers - int(pool.idle.Load())
+	}
+	return stats
+}
+
+func (stats *engineStats) record(limits *searchLimits, start time.Time, depth int) {
+	nodes := limits.nodes.Load()
+	stats.searches.Add(1)
+	stats.nodes.Add(nodes)
+	stats.nps.Store(int64(float64(nodes) / max(time.Since(start).Seconds(), 1e-9)))
+	stats.depth.Store(int64(depth))
+}
@@ -0,0 +1,37 @@
+package csa
+
+// Optional interface for nodes of games with symmetries, e.g. rotations and reflections of the board
+// Equivalent nodes share the same CanonicalHash, typically the smallest Hash of all their symmetric variants.
+// The search generates only one of the equivalent children of such node and caches share their entries.
+type SymmetryNode interface {
+	CanonicalHash() uint64
+}
+
+// Hash identifying node's position up to symmetries, false if the node cannot be hashed
+func canonicalHash(node SearchNode) (uint64, bool) {
+	if symmetryNode, ok := optionalNode(node).(SymmetryNode); ok {
+		return symmetryNode.CanonicalHash(), true
+	}
+	if hashNode, ok := optionalNode(node).(HashNode); ok {
+		return hashNode.Hash(), true
+	}
+	return 0, false
+}
+
+// Generator skipping children equivalent to already generated siblings
+func symmetryGenerator(generator SearchNodeGenerator) SearchNodeGenerator {
+	seen := make(map[uint64]bool)
+	return func(maximizing bool) SearchNode {
+		for {
+			child := generator(maximizing)
+			symmetryNode, ok := optionalNode(child).(SymmetryNode)
+			if !ok {
+				return child
+			}
+			if hash := symmetryNode.CanonicalHash(); !seen[hash] {
+				seen[hash] = true
+				return child
+			}
+		}
+	}
+}
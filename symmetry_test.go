@@ -0,0 +1,62 @@
+package csa
+
+import "testing"
+
+// Node equivalent to its rotations and reflections
+type tttSymmetricNode struct {
+	tttNode
+}
+
+func (node tttSymmetricNode) CanonicalHash() uint64 {
+	board := node.board
+	hash := node.Hash()
+	for i := 0; i < 8; i++ {
+		// rotate, after four rotations reflect
+		var rotated [3][3]int
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 3; x++ {
+				rotated[x][2-y] = board[y][x]
+			}
+		}
+		if board = rotated; i == 3 {
+			board[0], board[2] = board[2], board[0]
+		}
+		hash = min(hash, tttNode{board}.Hash())
+	}
+	return hash
+}
+
+func (node tttSymmetricNode) SearchNodeGenerator() SearchNodeGenerator {
+	generator := node.tttNode.SearchNodeGenerator()
+	return func(maximizing bool) SearchNode {
+		child := generator(maximizing)
+		if child == nil {
+			return nil
+		}
+		return tttSymmetricNode{child.(tttNode)}
+	}
+}
+
+func TestTTTSymmetry(t *testing.T) {
+	// corner, edge and center
+	nodes, score := MinimaxAllBest(tttSymmetricNode{}, 9, true)
+	if len(nodes) != 3 || score != 0 {
+		t.Errorf("Expected three distinct moves, got %d with score %d", len(nodes), score)
+	}
+	corner := tttNode{board: [3][3]int{{circle}}}
+	for _, board := range [][3][3]int{{{}, {}, {circle}}, {{empty, empty, circle}}, {{}, {}, {empty, empty, circle}}} {
+		if (tttSymmetricNode{tttNode{board}}).CanonicalHash() != (tttSymmetricNode{corner}).CanonicalHash() {
+			t.Errorf("Corners must be equivalent %s", tttNode{board})
+		}
+	}
+	// same result with fewer evaluations
+	for _, maximizing := range []bool{true, false} {
+		cache := NewEvalCache(nil, 10000)
+		symmetricCache := NewEvalCache(nil, 10000)
+		_, score := Searcher{Evaluator: cache}.Minimax(tttNode{}, 9, maximizing)
+		_, symmetricScore := Searcher{Evaluator: symmetricCache}.Minimax(tttSymmetricNode{}, 9, maximizing)
+		if score != symmetricScore || symmetricCache.Len() >= cache.Len() {
+			t.Errorf("Expected score %d with less evaluations, got %d, %d >= %d", score, symmetricScore, symmetricCache.Len(), cache.Len())
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package csa
+
+import (
+	"math"
+)
+
+// Optional interface for nodes counting their pieces, e.g. to probe the tablebase only in the endgame
+type PieceCountNode interface {
+	PieceCount() int
+}
+
+// Exact result of the tablebase position for the maximizing player
+type TablebaseResult struct {
+	WDL int // 1 for win, 0 for draw, -1 for loss
+	DTM int // plies to the end of the won or lost game, zero if the tablebase knows only WDL
+}
+
+// Endgame tablebase consulted by the searches instead of the node's Score, e.g. built by retrograde analysis
+// Probing has to be cheap, the searches probe every searched node below the root once.
+type TablebaseProber interface {
+	// Result of the node, false if the node is not in the tablebase
+	Probe(node SearchNode) (TablebaseResult, bool)
+}
+
+// Tablebase wins are scored as 2*WinScore-DTM, so they are preferred over the searched wins and the faster ones
+// over the slower ones, WinScore should be set. Without it, math.MaxInt32 is used instead.
+func (s *Searcher) tablebaseScore(result TablebaseResult) int {
+	winScore := s.WinScore
+	if winScore <= 0 {
+		winScore = math.MaxInt32
+	}
+	switch {
+	case result.WDL > 0:
+		return 2*winScore - result.DTM
+	case result.WDL < 0:
+		return -2*winScore + result.DTM
+	}
+	return s.DrawScore
+}
+
+// Score of the node found in the tablebase, the root is always searched
+func (s *Searcher) probeTablebase(node SearchNode, ply int) (int, bool) {
+	if s.Tablebase == nil || ply == 0 {
+		return 0, false
+	}
+	if s.TablebasePieces > 0 {
+		if pieceNode, ok := node.(PieceCountNode); !ok || pieceNode.PieceCount() > s.TablebasePieces {
+			return 0, false
+		}
+	}
+	result, ok := s.Tablebase.Probe(node)
+	if !ok {
+		return 0, false
+	}
+	return s.tablebaseScore(result), true
+}
@@ -0,0 +1,82 @@
+package csa
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+// Tablebase of the positions with at most three empty squares solved by the full search, circle moves first
+type tttTablebase struct {
+	probes atomic.Int64
+}
+
+func (tablebase *tttTablebase) Probe(node SearchNode) (TablebaseResult, bool) {
+	tablebase.probes.Add(1)
+	n := node.(tttNode)
+	empty := n.numberEmptySquares()
+	if empty > 3 {
+		return TablebaseResult{}, false
+	}
+	_, score := MinimaxAlphaBetaPrunning(n, 9, empty%2 == 1)
+	switch {
+	case score > 0:
+		return TablebaseResult{WDL: 1}, true
+	case score < 0:
+		return TablebaseResult{WDL: -1}, true
+	}
+	return TablebaseResult{}, true
+}
+
+func TestTTTTablebase(t *testing.T) {
+	tablebase := &tttTablebase{}
+	searcher := Searcher{WinScore: 100, Tablebase: tablebase}
+	tablebaseWins := false
+	generator := tttNode{}.SearchNodeGenerator()
+	for first := generator(true); first != nil; first = generator(true) {
+		second := first.SearchNodeGenerator()
+		for node := second(false); node != nil; node = second(false) {
+			// two plies to the tablebase positions with three empty squares
+			third := node.SearchNodeGenerator()
+			for child := third(true); child != nil; child = third(true) {
+				fourth := child.SearchNodeGenerator()
+				for grandChild := fourth(false); grandChild != nil; grandChild = fourth(false) {
+					_, score := searcher.MinimaxAlphaBetaPrunning(grandChild, 2, true)
+					_, exact := MinimaxAlphaBetaPrunning(grandChild, 9, true)
+					if (score > 0) != (exact > 0) || (score < 0) != (exact < 0) {
+						t.Fatalf("Tablebase score %d differs from %d of\n%v", score, exact, grandChild)
+					}
+					// distance adjusted tablebase win
+					tablebaseWins = tablebaseWins || score == 2*100-2
+				}
+			}
+		}
+	}
+	if tablebase.probes.Load() == 0 || !tablebaseWins {
+		t.Error("Tablebase wins were never found")
+	}
+	if bestNode, _ := searcher.MinimaxAlphaBetaPrunning(tttNode{}, 9, true); bestNode == nil {
+		t.Error("Root is always searched")
+	}
+}
+
+// Counts the nodes entered below the root
+type enterCounter struct {
+	entered int64
+}
+
+func (counter *enterCounter) Observe(event SearchEvent) {
+	if event.Kind == EventEnter && event.Ply > 0 {
+		counter.entered++
+	}
+}
+
+func TestTTTTablebaseProbedOnce(t *testing.T) {
+	for _, depth := range []int{2, 9} {
+		tablebase, counter := &tttTablebase{}, &enterCounter{}
+		searcher := Searcher{Tablebase: tablebase, Observer: counter}
+		searcher.Minimax(tttNode{}, depth, true)
+		if probes := tablebase.probes.Load(); probes != counter.entered {
+			t.Errorf("Depth %d: expected one probe of each of %d searched nodes, got %d", depth, counter.entered, probes)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package csa
+
+import (
+	"math"
+)
+
+// Evaluator with tunable parameters, e.g. piece values
+type ParameterizedEvaluator interface {
+	Evaluator
+	Params() []float64
+	SetParams(params []float64)
+}
+
+// Evaluator summing the game's features of the node multiplied by the weights, e.g. material balance
+type LinearEvaluator struct {
+	Weights  []float64
+	Features func(node SearchNode) []float64
+}
+
+func (e *LinearEvaluator) Evaluate(node SearchNode) int {
+	features := e.Features(node)
+	sum := 0.0
+	for i := 0; i < len(features) && i < len(e.Weights); i++ {
+		sum += features[i] * e.Weights[i]
+	}
+	return int(math.Round(sum))
+}
+
+func (e *LinearEvaluator) Params() []float64 {
+	return append([]float64(nil), e.Weights...)
+}
+
+func (e *LinearEvaluator) SetParams(params []float64) {
+	e.Weights = append(e.Weights[:0], params...)
+}
+
+// Position labeled by the outcome of its game for the maximizing player, 1 for win, 0.5 for draw and 0 for loss
+// Results averaged over more games of the same position are fine as well.
+type LabeledPosition struct {
+	Node   SearchNode
+	Result float64
+}
+
+// Texel tuning of evaluation parameters, minimizes the mean squared error between the positions' results
+// and the win probabilities of their evaluations by local search changing one parameter at a time
+type TexelTuner struct {
+	Scale         float64 // of WinProbability, fitted by FitScale if zero
+	Step          float64 // initial change of the parameters, 1 if zero
+	MinStep       float64 // step is halved when no change helps, tuning stops below it, Step/1024 if zero
+	MaxIterations int     // zero means unlimited
+}
+
+// Tunes the evaluator's parameters in place, returns the final error
+func (tuner TexelTuner) Tune(evaluator ParameterizedEvaluator, positions []LabeledPosition) float64 {
+	if tuner.Scale <= 0 {
+		tuner.Scale = tuner.FitScale(evaluator, positions)
+	}
+	if tuner.Step <= 0 {
+		tuner.Step = 1
+	}
+	if tuner.MinStep <= 0 {
+		tuner.MinStep = tuner.Step / 1024
+	}
+	params := evaluator.Params()
+	bestError := texelError(evaluator, positions, tuner.Scale)
+	for iteration := 0; tuner.Step >= tuner.MinStep && (tuner.MaxIterations <= 0 || iteration < tuner.MaxIterations); iteration++ {
+		improved := false
+		for i := range params {
+			for _, delta := range []float64{tuner.Step, -tuner.Step} {
+				params[i] += delta
+				evaluator.SetParams(params)
+				if err := texelError(evaluator, positions, tuner.Scale); err < bestError {
+					bestError, improved = err, true
+					break
+				}
+				params[i] -= delta
+			}
+		}
+		if !improved {
+			tuner.Step /= 2
+		}
+	}
+	evaluator.SetParams(params)
+	return bestError
+}
+
+// Scale of WinProbability minimizing the error of the current evaluation, searched between 1 and 1e6
+func (tuner TexelTuner) FitScale(evaluator Evaluator, positions []LabeledPosition) float64 {
+	// ternary search of the unimodal error over the logarithm of the scale
+	low, high := 0.0, 6.0
+	for high-low > 1e-4 {
+		third := (high - low) / 3
+		if texelError(evaluator, positions, math.Pow(10, low+third)) < texelError(evaluator, positions, math.Pow(10, high-third)) {
+			high -= third
+		} else {
+			low += third
+		}
+	}
+	return math.Pow(10, (low+high)/2)
+}
+
+func texelError(evaluator Evaluator, positions []LabeledPosition, scale float64) float64 {
+	if len(positions) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, position := range positions {
+		diff := position.Result - WinProbability(evaluator.Evaluate(position.Node), scale)
+		sum += diff * diff
+	}
+	return sum / float64(len(positions))
+}
@@ -27,15 +27,25 @@ func (node tttNode) IsTerminal() bool {
 	return row || node.numberEmptySquares() == 0
 }
 
+func (node tttNode) IsDraw() bool {
+	if node.numberEmptySquares() > 0 {
+		return false
+	}
+	row, _ := node.anyFullRow()
+	return !row
+}
+
 func (node tttNode) SearchNodeGenerator() SearchNodeGenerator {
-	symbol := map[bool]int{true: circle, false: cross}
 	x, y := 0, 0
 	return func(maximizing bool) SearchNode {
 		for y < 3 {
 			for x < 3 {
 				if node.board[y][x] == empty {
 					nodeCopy := node
-					nodeCopy.board[y][x] = symbol[maximizing]
+					nodeCopy.board[y][x] = cross
+					if maximizing {
+						nodeCopy.board[y][x] = circle
+					}
 					x++
 					return nodeCopy
 				}
@@ -48,6 +58,37 @@ func (node tttNode) SearchNodeGenerator() SearchNodeGenerator {
 	}
 }
 
+// Generic Node[tttNode] variant of the generator
+func (node tttNode) Generator() func(maximizing bool) (tttNode, bool) {
+	x, y := 0, 0
+	return func(maximizing bool) (tttNode, bool) {
+		for ; y < 3; y, x = y+1, 0 {
+			for ; x < 3; x++ {
+				if node.board[y][x] == empty {
+					nodeCopy := node
+					nodeCopy.board[y][x] = cross
+					if maximizing {
+						nodeCopy.board[y][x] = circle
+					}
+					x++
+					return nodeCopy, true
+				}
+			}
+		}
+		return tttNode{}, false
+	}
+}
+
+func (node tttNode) Hash() uint64 {
+	hash := uint64(0)
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			hash = hash*3 + uint64(node.board[y][x]-cross)
+		}
+	}
+	return hash
+}
+
 func (node tttNode) String() string {
 	sb := strings.Builder{}
 	for y := 0; y < 3; y++ {
@@ -221,14 +262,12 @@ func TestTTTMinimaxAlphaBetaPrunning(t *testing.T) {
 }
 
 func TestTTTBestMinimaxVsWorseMinimax(t *testing.T) {
-	type minimax func(node SearchNode, depth int, maximizing bool) (SearchNode, int)
-
-	runTest := func(minimaxFn minimax, depths map[bool]int, moves int) {
+	runTest := func(minimax minimaxFn, depths map[bool]int, moves int) {
 		var sn SearchNode = tttNode{}
 		maximizing := false
 		// has to win in the least number of moves
 		for i := 0; i < moves; i++ {
-			newNode, _ := minimaxFn(sn, depths[maximizing], maximizing)
+			newNode, _ := minimax(sn, depths[maximizing], maximizing)
 			sn = newNode
 			maximizing = !maximizing
 		}
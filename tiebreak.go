@@ -0,0 +1,70 @@
+package csa
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Policy choosing among equally scored children, applied the same way by all minimax variants of the Searcher
+// The generic variants (MinimaxOf) have no Searcher and always choose the first generated child.
+type TieBreak int
+
+const (
+	// First generated child, i.e. the one with the lowest generator index
+	TieBreakFirst TieBreak = iota
+	// Last generated child
+	TieBreakLast
+)
+
+// Random keys of root children, the i-th child gets the i-th number drawn so the choice is reproducible
+// for given seed regardless of the order in which the children are compared
+type tieKeys struct {
+	rng  *rand.Rand
+	keys []int64
+}
+
+func (tk *tieKeys) key(index int) int64 {
+	for len(tk.keys) <= index {
+		tk.keys = append(tk.keys, tk.rng.Int63())
+	}
+	return tk.keys[index]
+}
+
+// Returns true if candidate should replace the current best child with the same score
+func (s *Searcher) preferTie(candidate, best SearchNode, candidateIndex, bestIndex, ply int) bool {
+	if s.TieBreaker != nil && (ply > 0 || s.rootKeys == nil) {
+		if s.TieBreaker(candidate, best) {
+			return true
+		}
+		if s.TieBreaker(best, candidate) {
+			return false
+		}
+	}
+	return s.preferTieIndex(candidateIndex, bestIndex, ply)
+}
+
+// Tie-breaking of the children known only by their generator index, the TieBreaker is not consulted
+func (s *Searcher) preferTieIndex(candidateIndex, bestIndex, ply int) bool {
+	if ply == 0 && s.rootKeys != nil {
+		return s.rootKeys.key(candidateIndex) < s.rootKeys.key(bestIndex)
+	}
+	if s.TieBreak == TieBreakLast {
+		return candidateIndex > bestIndex
+	}
+	return candidateIndex < bestIndex
+}
+
+// Alpha-beta window of a child, if the later child can win a tie its equal score has to be exact,
+// not just a bound, so the window is widened by one
+func (s *Searcher) tieWindow(alpha, beta int, maximizing, hasBest bool, ply int) (int, int) {
+	if !hasBest || (s.TieBreak == TieBreakFirst && s.TieBreaker == nil && (ply > 0 || s.rootKeys == nil)) {
+		return alpha, beta
+	}
+	if maximizing && alpha != math.MinInt {
+		return alpha - 1, beta
+	}
+	if !maximizing && beta != math.MaxInt {
+		return alpha, beta + 1
+	}
+	return alpha, beta
+}
@@ -0,0 +1,83 @@
+package csa
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTTTTieBreak(t *testing.T) {
+	for _, searcher := range []Searcher{
+		{TieBreak: TieBreakFirst},
+		{TieBreak: TieBreakLast},
+		{TieBreaker: func(a, b SearchNode) bool {
+			return a.(tttNode).Hash() > b.(tttNode).Hash()
+		}},
+	} {
+		searcher := searcher
+		minimaxFns := []minimaxFn{
+			searcher.Minimax,
+			searcher.MinimaxAlphaBetaPrunning,
+			searcher.MinimaxAlphaBetaIterative,
+			func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+				return searcher.MinimaxConcurrent(node, depth, maximizing, 3)
+			},
+			func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+				split := searcher
+				split.SplitDepth = 2
+				return split.MinimaxConcurrent(node, depth, maximizing, 3)
+			},
+			func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+				return searcher.MinimaxWorkStealing(node, depth, maximizing, 4)
+			},
+			func(node SearchNode, depth int, maximizing bool) (SearchNode, int) {
+				return searcher.MinimaxYBWC(node, depth, maximizing, 4)
+			},
+		}
+		var sn SearchNode = tttNode{}
+		maximizing := true
+		for !sn.IsTerminal() {
+			expected, expectedScore := minimaxFns[0](sn, 9, maximizing)
+			for _, minimax := range minimaxFns[1:] {
+				node, score := minimax(sn, 9, maximizing)
+				if node != expected || score != expectedScore {
+					t.Errorf("Algorithms chose different nodes %s%s", node, expected)
+				}
+			}
+			sn = expected
+			maximizing = !maximizing
+		}
+	}
+	// empty board is a draw, so the first and last squares are chosen
+	first, _ := Searcher{TieBreak: TieBreakFirst}.MinimaxAlphaBetaPrunning(tttNode{}, 9, false)
+	last, _ := Searcher{TieBreak: TieBreakLast}.MinimaxAlphaBetaPrunning(tttNode{}, 9, false)
+	if first.(tttNode).board[0][0] != cross || last.(tttNode).board[2][2] != cross {
+		t.Errorf("Invalid tie breaks %s%s", first, last)
+	}
+}
+
+func TestTTTRandomTies(t *testing.T) {
+	chosen := map[tttNode]bool{}
+	for seed := int64(0); seed < 10; seed++ {
+		node, score := Searcher{Rand: rand.New(rand.NewSource(seed))}.MinimaxAlphaBetaPrunning(tttNode{}, 9, true)
+		// same seed gives same choice
+		sameNode, _ := Searcher{Rand: rand.New(rand.NewSource(seed))}.Minimax(tttNode{}, 9, true)
+		concurrentNode, _ := Searcher{Rand: rand.New(rand.NewSource(seed))}.MinimaxConcurrent(tttNode{}, 9, true, 4)
+		if score != 0 || node != sameNode || node != concurrentNode {
+			t.Errorf("Seeded choice is not reproducible %s%s%s", node, sameNode, concurrentNode)
+		}
+		chosen[node.(tttNode)] = true
+	}
+	if len(chosen) < 3 {
+		t.Errorf("Random tie-breaking chose only %d different moves", len(chosen))
+	}
+	// non-equal moves are never chosen
+	node := tttNode{}
+	node.board[0][0] = cross
+	node.board[0][1] = cross
+	for seed := int64(0); seed < 5; seed++ {
+		sn, _ := Searcher{Rand: rand.New(rand.NewSource(seed))}.MinimaxAlphaBetaPrunning(node, 9, true)
+		if sn.(tttNode).board[0][2] != circle {
+			t.Errorf("Circle did not block %s", sn)
+		}
+	}
+}
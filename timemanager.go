@@ -0,0 +1,99 @@
+package csa
+
+import (
+	"reflect"
+	"time"
+)
+
+// Clock of the player to move
+type Clock struct {
+	MoveTime  time.Duration // fixed time per move, overrides the rest
+	Remaining time.Duration // time left until the next time control, zero means unlimited
+	Increment time.Duration // time added after every move
+	MovesToGo int           // moves until the next time control, zero means sudden death
+}
+
+// Decides how long to think about a move according to the clock and the search progress
+// Zero value plans for 30 more moves in sudden death.
+type TimeManager struct {
+	MovesLeft     int           // expected number of remaining moves in sudden death
+	Overhead      time.Duration // reserve per move, e.g. for communication with GUI
+	FailLowMargin int           // score drop between depths (in units of node's score) which extends the time, zero disables it
+}
+
+// Soft limit after which no new depth is started and hard limit which aborts the search
+// Zero limits mean unlimited time.
+func (tm TimeManager) Allocate(clock Clock) (time.Duration, time.Duration) {
+	if clock.MoveTime > 0 {
+		limit := max(clock.MoveTime-tm.Overhead, time.Millisecond)
+		return limit, limit
+	}
+	if clock.Remaining <= 0 {
+		return 0, 0
+	}
+	moves := clock.MovesToGo
+	if moves <= 0 {
+		moves = tm.MovesLeft
+	}
+	if moves <= 0 {
+		moves = 30
+	}
+	available := max(clock.Remaining-tm.Overhead, time.Millisecond)
+	hard := min(available/2, (available/time.Duration(moves)+clock.Increment)*4)
+	if moves == 1 {
+		// last move before the time control
+		hard = available
+	}
+	soft := min(available/time.Duration(moves)+clock.Increment*3/4, hard)
+	return soft, hard
+}
+
+// Results of the finished depths of iterative deepening
+type searchFeedback struct {
+	bestChanged  bool // the last depth changed the best child
+	scoreDrop    int  // how much the score got worse for the player to move in the last depth
+	stableDepths int  // number of depths in a row which kept the best child
+}
+
+func (feedback *searchFeedback) update(previous, best SearchNode, previousScore, score int, maximizing bool) {
+	feedback.bestChanged = !sameNode(previous, best)
+	if feedback.bestChanged {
+		feedback.stableDepths = 0
+	} else {
+		feedback.stableDepths++
+	}
+	feedback.scoreDrop = previousScore - score
+	if !maximizing {
+		feedback.scoreDrop = -feedback.scoreDrop
+	}
+}
+
+// Soft limit adjusted by the search feedback, unstable best child or failing score need more time
+func (tm TimeManager) extend(soft, hard time.Duration, feedback searchFeedback) time.Duration {
+	if soft <= 0 {
+		return 0
+	}
+	factor := 1.0
+	if feedback.bestChanged {
+		factor *= 1.5
+	} else if feedback.stableDepths >= 3 {
+		factor *= 0.7
+	}
+	if tm.FailLowMargin > 0 && feedback.scoreDrop >= tm.FailLowMargin {
+		factor *= 2
+	}
+	return min(time.Duration(float64(soft)*factor), hard)
+}
+
+// Whether the nodes are the same, nodes which cannot be compared are considered to be the same
+func sameNode(a, b SearchNode) bool {
+	hashA, okA := canonicalHash(a)
+	hashB, okB := canonicalHash(b)
+	if okA && okB {
+		return hashA == hashB
+	}
+	if a == nil || b == nil || reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return a == nil && b == nil
+	}
+	return !reflect.TypeOf(a).Comparable() || a == b
+}
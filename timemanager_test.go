@@ -0,0 +1,43 @@
+package csa
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeManagerAllocate(t *testing.T) {
+	tm := TimeManager{Overhead: 10 * time.Millisecond}
+	for _, test := range []struct {
+		clock      Clock
+		soft, hard time.Duration
+	}{
+		{Clock{MoveTime: time.Second}, 990 * time.Millisecond, 990 * time.Millisecond},
+		{Clock{}, 0, 0},
+		{Clock{Remaining: 3010 * time.Millisecond}, 100 * time.Millisecond, 400 * time.Millisecond},
+		{Clock{Remaining: 1010 * time.Millisecond, MovesToGo: 10, Increment: 100 * time.Millisecond}, 175 * time.Millisecond, 500 * time.Millisecond},
+		{Clock{Remaining: 1010 * time.Millisecond, MovesToGo: 1}, time.Second, time.Second},
+	} {
+		if soft, hard := tm.Allocate(test.clock); soft != test.soft || hard != test.hard {
+			t.Errorf("Clock %+v: expected %s/%s, got %s/%s", test.clock, test.soft, test.hard, soft, hard)
+		}
+	}
+}
+
+func TestTimeManagerExtend(t *testing.T) {
+	tm := TimeManager{FailLowMargin: 2}
+	soft, hard := 100*time.Millisecond, 300*time.Millisecond
+	if limit := tm.extend(soft, hard, searchFeedback{bestChanged: true}); limit != 150*time.Millisecond {
+		t.Errorf("Unstable best child should get more time, got %s", limit)
+	}
+	if limit := tm.extend(soft, hard, searchFeedback{stableDepths: 3}); limit != 70*time.Millisecond {
+		t.Errorf("Stable best child should get less time, got %s", limit)
+	}
+	if limit := tm.extend(soft, hard, searchFeedback{bestChanged: true, scoreDrop: 2}); limit != hard {
+		t.Errorf("Failing score should get up to the hard limit, got %s", limit)
+	}
+	feedback := searchFeedback{}
+	feedback.update(tttNode{}, tttNode{}, 5, 2, true)
+	if feedback.bestChanged || feedback.scoreDrop != 3 || feedback.stableDepths != 1 {
+		t.Errorf("Invalid feedback %+v", feedback)
+	}
+}
@@ -0,0 +1,232 @@
+package tournament
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/catalog"
+)
+
+// Clock of every player in the match games
+type TimeControl struct {
+	Base      time.Duration // initial time of a player, zero means no clock
+	Increment time.Duration // added after every move
+	MoveTime  time.Duration // fixed time per move used without the base time, zero means unlimited
+	Margin    time.Duration // overstepped time tolerated before the time forfeit
+}
+
+// Games of two engine configurations on a game of games/catalog, recorded for review
+// The players swap the sides after every game, the first player plays the maximizing side of the first game.
+type Match struct {
+	Game        string   // name in catalog.Games
+	Position    string   // start of the games in the game's format, the initial one if empty
+	Moves       []string // played from the position before the engines start, in the game's notation
+	Players     [2]Player
+	Games       int
+	TimeControl TimeControl
+	MaxPlies    int             // longer games are adjudicated as draws, zero means unlimited
+	Adjudicator csa.Adjudicator // ends the games by the engines' scores, never by the zero value
+	Event       string          // tag of the records
+	Records     io.Writer       // optional, every finished game is written to it as a PGN-style record
+}
+
+// Played move of a match game with the search of the engine
+type MatchMove struct {
+	Move  string // in the game's notation
+	Score int    // for the maximizing player
+	Depth int    // last finished depth, zero for the book moves
+	Time  time.Duration
+}
+
+type MatchGame struct {
+	Round        int
+	Maximizing   string // names of the players
+	Minimizing   string
+	Moves        []MatchMove // played by the engines, after the match's moves
+	Outcome      Outcome
+	Termination  string // normal, adjudication, time forfeit or no move
+	Adjudication string // reason of the adjudication, max plies or csa.Adjudication's reason
+}
+
+type MatchResult struct {
+	Score // of the first player
+	Games []MatchGame
+}
+
+// Plays the games, the record of every game is written right after it finishes
+func (match *Match) Run() (*MatchResult, error) {
+	game, ok := catalog.Games[match.Game]
+	if !ok {
+		return nil, fmt.Errorf("tournament: unknown game %q", match.Game)
+	}
+	if _, _, err := game.Play(match.Position, match.Moves); err != nil {
+		return nil, err
+	}
+	result := &MatchResult{}
+	for round := 1; round <= match.Games; round++ {
+		first := round%2 == 1
+		maximizing, minimizing := match.Players[0], match.Players[1]
+		if !first {
+			maximizing, minimizing = minimizing, maximizing
+		}
+		played := match.play(game, maximizing, minimizing)
+		played.Round = round
+		result.Games = append(result.Games, played)
+		result.add(played.Outcome, first)
+		if match.Records != nil {
+			if err := match.writeRecord(match.Records, game, played); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+func (match *Match) play(game catalog.Game, maximizingPlayer, minimizingPlayer Player) MatchGame {
+	played := MatchGame{Maximizing: maximizingPlayer.Name, Minimizing: minimizingPlayer.Name, Termination: "normal"}
+	var depth int
+	newEngine := func(player Player) *csa.Engine {
+		options := append([]csa.EngineOption{csa.WithSearcher(csa.Searcher{WinScore: game.WinScore})}, player.Options...)
+		// replaces the info callback of the options to record the depths
+		return csa.NewEngine(append(options, csa.WithInfo(func(info csa.SearchInfo) { depth = info.Depth }))...)
+	}
+	engines := [2]*csa.Engine{newEngine(maximizingPlayer), newEngine(minimizingPlayer)}
+	defer engines[0].Close()
+	defer engines[1].Close()
+	remaining := [2]time.Duration{match.TimeControl.Base, match.TimeControl.Base}
+	var history []csa.ScoredMove
+	node, maximizing, _ := game.Play(match.Position, match.Moves)
+	for !node.IsTerminal() {
+		if adjudication, ok := match.Adjudicator.Adjudicate(node, history); ok {
+			played.Termination, played.Adjudication = "adjudication", adjudication.Reason
+			played.Outcome = Outcome(adjudication.WDL)
+			return played
+		}
+		if match.MaxPlies > 0 && len(played.Moves) >= match.MaxPlies {
+			played.Termination, played.Adjudication = "adjudication", "max plies"
+			played.Outcome = OutcomeDraw
+			return played
+		}
+		side := 1
+		if maximizing {
+			side = 0
+		}
+		clock := csa.Clock{MoveTime: match.TimeControl.MoveTime}
+		if match.TimeControl.Base > 0 {
+			clock = csa.Clock{Remaining: remaining[side], Increment: match.TimeControl.Increment}
+		}
+		depth = 0
+		start := time.Now()
+		child, score := engines[side].BestMoveWithClock(node, maximizing, clock)
+		elapsed := time.Since(start)
+		if child == nil {
+			played.Termination = "no move"
+			played.Outcome = outcome(node, false)
+			return played
+		}
+		played.Moves = append(played.Moves, MatchMove{Move: catalog.MoveString(child), Score: score, Depth: depth, Time: elapsed})
+		history = append(history, csa.ScoredMove{Maximizing: maximizing, Score: score})
+		if match.TimeControl.Base > 0 {
+			if remaining[side] -= elapsed; remaining[side] < -match.TimeControl.Margin {
+				played.Termination = "time forfeit"
+				played.Outcome = OutcomeMinimizingWin
+				if !maximizing {
+					played.Outcome = OutcomeMaximizingWin
+				}
+				return played
+			}
+			remaining[side] += match.TimeControl.Increment
+		}
+		node, maximizing = child, catalog.NextPlayer(child, maximizing)
+	}
+	played.Outcome = outcome(node, false)
+	return played
+}
+
+var recordResults = map[Outcome]string{OutcomeMaximizingWin: "1-0", OutcomeDraw: "1/2-1/2", OutcomeMinimizingWin: "0-1"}
+
+// Writes the game as a PGN-style record, the tags of the players are the capitalized names of the game's sides
+// and the comment of every move holds the engine's score, depth and time, e.g. {+12/7 0.25s}
+func (match *Match) writeRecord(w io.Writer, game catalog.Game, played MatchGame) error {
+	event := match.Event
+	if event == "" {
+		event = "csa match"
+	}
+	sb := strings.Builder{}
+	tag := func(name, value string) {
+		fmt.Fprintf(&sb, "[%s %q]\n", name, value)
+	}
+	tag("Event", event)
+	tag("Game", match.Game)
+	tag("Round", fmt.Sprint(played.Round))
+	tag(capitalize(game.Players[0]), played.Maximizing)
+	tag(capitalize(game.Players[1]), played.Minimizing)
+	if match.Position != "" {
+		tag("FEN", match.Position)
+	}
+	if len(match.Moves) > 0 {
+		tag("Opening", strings.Join(match.Moves, " "))
+	}
+	if match.TimeControl.Base > 0 {
+		tag("TimeControl", fmt.Sprintf("%g+%g", match.TimeControl.Base.Seconds(), match.TimeControl.Increment.Seconds()))
+	} else if match.TimeControl.MoveTime > 0 {
+		tag("TimeControl", fmt.Sprintf("%g/move", match.TimeControl.MoveTime.Seconds()))
+	}
+	tag("Result", recordResults[played.Outcome])
+	tag("Termination", played.Termination)
+	if played.Adjudication != "" {
+		tag("Adjudication", played.Adjudication)
+	}
+	sb.WriteString("\n")
+	// the move numbers follow the sides of the played nodes, extra turns keep the number
+	node, maximizing, _ := game.Play(match.Position, match.Moves)
+	number, previous := 1, true
+	var line []string
+	for i, move := range played.Moves {
+		switch {
+		case i == 0 && !maximizing:
+			line = append(line, fmt.Sprintf("%d...", number))
+		case maximizing && (i == 0 || !previous):
+			if i > 0 {
+				number++
+			}
+			line = append(line, fmt.Sprintf("%d.", number))
+		}
+		line = append(line, move.Move, fmt.Sprintf("{%+d/%d %.2fs}", move.Score, move.Depth, move.Time.Seconds()))
+		child := catalog.Child(node, maximizing, move.Move)
+		previous = maximizing
+		node, maximizing = child, catalog.NextPlayer(child, maximizing)
+	}
+	line = append(line, recordResults[played.Outcome])
+	writeWrapped(&sb, line, 80)
+	sb.WriteString("\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func capitalize(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// Writes the words separated by spaces in lines of at most width characters
+func writeWrapped(sb *strings.Builder, words []string, width int) {
+	length := 0
+	for _, word := range words {
+		if length > 0 && length+1+len(word) > width {
+			sb.WriteString("\n")
+			length = 0
+		} else if length > 0 {
+			sb.WriteString(" ")
+			length++
+		}
+		sb.WriteString(word)
+		length += len(word)
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,126 @@
+package tournament
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+type SPRTDecision int
+
+const (
+	// Limit of games reached before any hypothesis was accepted
+	SPRTInconclusive SPRTDecision = iota
+	// Candidate is at least Elo1 stronger than the baseline
+	SPRTAcceptH1
+	// Candidate is at most Elo0 stronger than the baseline
+	SPRTAcceptH0
+)
+
+var sprtDecisionNames = []string{"inconclusive", "H1 accepted", "H0 accepted"}
+
+func (decision SPRTDecision) String() string {
+	if int(decision) < len(sprtDecisionNames) {
+		return sprtDecisionNames[decision]
+	}
+	return "unknown"
+}
+
+// Sequential probability ratio test of the candidate against the baseline, H0 is the Elo difference Elo0
+// and H1 is Elo1. The games are played in pairs from the same random opening with swapped sides until
+// the log-likelihood ratio crosses one of the bounds given by the error rates.
+type SPRT struct {
+	Game                Game
+	Candidate, Baseline Player
+	Elo0, Elo1          float64
+	Alpha, Beta         float64 // false positive and false negative rates, 0.05 if zero
+	MaxPairs            int     // zero means unlimited
+	Concurrency         int     // game pairs played at once, at least one
+	Seed                int64   // of the random openings
+}
+
+type SPRTResult struct {
+	Decision SPRTDecision
+	LLR      float64
+	Lower    float64      // bound accepting H0
+	Upper    float64      // bound accepting H1
+	Score    Score        // of the candidate
+	Games    []GameResult // in the order the game pairs finished
+}
+
+func (sprt *SPRT) Run() *SPRTResult {
+	alpha, beta := sprt.Alpha, sprt.Beta
+	if alpha <= 0 {
+		alpha = 0.05
+	}
+	if beta <= 0 {
+		beta = 0.05
+	}
+	result := &SPRTResult{Lower: math.Log(beta / (1 - alpha)), Upper: math.Log((1 - beta) / alpha)}
+	rnd := rand.New(rand.NewSource(sprt.Seed))
+	var mutex sync.Mutex
+	pairs := 0
+	stopped := false
+	var wg sync.WaitGroup
+	for worker := 0; worker < max(1, sprt.Concurrency); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mutex.Lock()
+				if stopped || (sprt.MaxPairs > 0 && pairs >= sprt.MaxPairs) {
+					mutex.Unlock()
+					return
+				}
+				pairs++
+				opening := sprt.Game.RandomOpening(rnd)
+				mutex.Unlock()
+				first := sprt.Game.Play(sprt.Candidate, sprt.Baseline, opening)
+				second := sprt.Game.Play(sprt.Baseline, sprt.Candidate, opening)
+				mutex.Lock()
+				if !stopped {
+					result.add(first, true)
+					result.add(second, false)
+					result.LLR = LLR(result.Score, sprt.Elo0, sprt.Elo1)
+					switch {
+					case result.LLR >= result.Upper:
+						result.Decision, stopped = SPRTAcceptH1, true
+					case result.LLR <= result.Lower:
+						result.Decision, stopped = SPRTAcceptH0, true
+					}
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return result
+}
+
+func (result *SPRTResult) add(game GameResult, candidateMaximizing bool) {
+	result.Games = append(result.Games, game)
+	result.Score.add(game.Outcome, candidateMaximizing)
+}
+
+// Log-likelihood ratio of the score under H1 with Elo difference elo1 against H0 with elo0,
+// normal approximation of the trinomial distribution of the game results
+// The variance of the score without different results is unknown, so one more draw is counted then.
+func LLR(score Score, elo0, elo1 float64) float64 {
+	wins, draws, losses := float64(score.Wins), float64(score.Draws), float64(score.Losses)
+	if score.Wins == score.Games() || score.Draws == score.Games() || score.Losses == score.Games() {
+		draws++
+	}
+	games := wins + draws + losses
+	mean := (wins + draws/2) / games
+	variance := (wins*math.Pow(1-mean, 2) + draws*math.Pow(0.5-mean, 2) + losses*math.Pow(mean, 2)) / games
+	if variance == 0 {
+		return 0
+	}
+	score0, score1 := expectedScore(elo0), expectedScore(elo1)
+	return games * (score1 - score0) * (2*mean - score0 - score1) / (2 * variance)
+}
+
+// Expected score of the player stronger by the Elo difference
+func expectedScore(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
@@ -0,0 +1,75 @@
+package tournament
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Tuned parameter, e.g. a pruning margin or the UCT constant
+type SPSAParam struct {
+	Name     string
+	Value    float64 // initial one
+	Min, Max float64
+	C        float64 // perturbation of the first iteration, decreasing by the iterations
+	R        float64 // learning rate of the first iteration, its step is R*C*C per unit of the gradient
+}
+
+// Simultaneous perturbation stochastic approximation tuning the parameters by self-play
+// Every iteration perturbs all the parameters at once in random directions and plays the game pairs
+// of the player with the added perturbation against the one with the subtracted perturbation,
+// the parameters move by the match result towards the better player.
+type SPSA struct {
+	Game       Game
+	Params     []SPSAParam
+	Player     func(params []float64) Player // engine configuration with given parameter values
+	Iterations int
+	Pairs      int   // game pairs per iteration, at least one
+	Seed       int64 // of the perturbations and random openings
+}
+
+// Standard decay exponents of the learning rate and the perturbation
+const (
+	spsaAlpha = 0.602
+	spsaGamma = 0.101
+)
+
+// Tuned parameter values
+func (spsa *SPSA) Run() []float64 {
+	rnd := rand.New(rand.NewSource(spsa.Seed))
+	values := make([]float64, len(spsa.Params))
+	for i, param := range spsa.Params {
+		values[i] = param.Value
+	}
+	// stability constant, tenth of the iterations as recommended
+	stability := float64(spsa.Iterations) / 10
+	for iteration := 0; iteration < spsa.Iterations; iteration++ {
+		plus := make([]float64, len(values))
+		minus := make([]float64, len(values))
+		perturbations := make([]float64, len(values))
+		for i, param := range spsa.Params {
+			c := param.C / math.Pow(float64(iteration+1), spsaGamma)
+			if rnd.Intn(2) == 0 {
+				c = -c
+			}
+			perturbations[i] = c
+			plus[i] = spsa.clamp(i, values[i]+c)
+			minus[i] = spsa.clamp(i, values[i]-c)
+		}
+		plusPlayer, minusPlayer := spsa.Player(plus), spsa.Player(minus)
+		result := 0
+		for pair := 0; pair < max(1, spsa.Pairs); pair++ {
+			opening := spsa.Game.RandomOpening(rnd)
+			result += int(spsa.Game.Play(plusPlayer, minusPlayer, opening).Outcome)
+			result -= int(spsa.Game.Play(minusPlayer, plusPlayer, opening).Outcome)
+		}
+		for i, param := range spsa.Params {
+			a := param.R * param.C * param.C * math.Pow((1+stability)/(float64(iteration+1)+stability), spsaAlpha)
+			values[i] = spsa.clamp(i, values[i]+a*float64(result)/(2*perturbations[i]))
+		}
+	}
+	return values
+}
+
+func (spsa *SPSA) clamp(i int, value float64) float64 {
+	return min(max(value, spsa.Params[i].Min), spsa.Params[i].Max)
+}
@@ -0,0 +1,274 @@
+// Package tournament plays csa engine configurations against each other, estimates their Elo differences
+// and tunes their parameters by self-play
+package tournament
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Game played by the engines, the maximizing player moves first
+type Game struct {
+	New          func() csa.SearchNode     // initial node of a new game
+	Played       func(node csa.SearchNode) // optional, called with every played node, e.g. to record the game history
+	MaxPlies     int                       // longer games are draws, zero means unlimited
+	OpeningPlies int                       // random plies before the engines start to play
+	Adjudicator  csa.Adjudicator           // ends the games by the engines' scores, never by the zero value
+}
+
+// Named engine configuration, every game is played by a new engine
+type Player struct {
+	Name    string
+	Options []csa.EngineOption
+}
+
+// Random children played before the engines start, as indices in the generator order
+type Opening []int
+
+type Outcome int
+
+const (
+	OutcomeMinimizingWin Outcome = iota - 1
+	OutcomeDraw
+	OutcomeMaximizingWin
+)
+
+type GameResult struct {
+	Maximizing, Minimizing string // names of the players
+	Opening                Opening
+	Outcome                Outcome
+	Plies                  int // including the opening
+}
+
+// Random opening of the game, shorter if the game ends before
+func (game Game) RandomOpening(rnd *rand.Rand) Opening {
+	var opening Opening
+	node, maximizing := game.New(), true
+	for len(opening) < game.OpeningPlies && !node.IsTerminal() {
+		children := childrenOf(node, maximizing)
+		if len(children) == 0 {
+			break
+		}
+		index := rnd.Intn(len(children))
+		opening = append(opening, index)
+		node, maximizing = children[index], nextPlayer(children[index], maximizing)
+	}
+	return opening
+}
+
+// Plays the game of the maximizing player against the minimizing one after the opening
+func (game Game) Play(maximizingPlayer, minimizingPlayer Player, opening Opening) GameResult {
+	result := GameResult{Maximizing: maximizingPlayer.Name, Minimizing: minimizingPlayer.Name, Opening: opening}
+	engines := [2]*csa.Engine{csa.NewEngine(maximizingPlayer.Options...), csa.NewEngine(minimizingPlayer.Options...)}
+	defer engines[0].Close()
+	defer engines[1].Close()
+	var history []csa.ScoredMove
+	node, maximizing := game.New(), true
+	for !node.IsTerminal() && (game.MaxPlies <= 0 || result.Plies < game.MaxPlies) {
+		if adjudication, ok := game.Adjudicator.Adjudicate(node, history); ok {
+			result.Outcome = Outcome(adjudication.WDL)
+			return result
+		}
+		var child csa.SearchNode
+		if result.Plies < len(opening) {
+			if children := childrenOf(node, maximizing); opening[result.Plies] < len(children) {
+				child = children[opening[result.Plies]]
+			}
+		} else {
+			side := 1
+			if maximizing {
+				side = 0
+			}
+			var score int
+			child, score = engines[side].BestMove(node, maximizing)
+			history = append(history, csa.ScoredMove{Maximizing: maximizing, Score: score})
+		}
+		if child == nil {
+			break
+		}
+		if game.Played != nil {
+			game.Played(child)
+		}
+		node, maximizing = child, nextPlayer(child, maximizing)
+		result.Plies++
+	}
+	result.Outcome = outcome(node, game.MaxPlies > 0 && result.Plies >= game.MaxPlies)
+	return result
+}
+
+func outcome(node csa.SearchNode, adjourned bool) Outcome {
+	if drawNode, ok := node.(csa.DrawNode); adjourned || (ok && drawNode.IsDraw()) {
+		return OutcomeDraw
+	}
+	switch score := node.Score(); {
+	case score > 0:
+		return OutcomeMaximizingWin
+	case score < 0:
+		return OutcomeMinimizingWin
+	}
+	return OutcomeDraw
+}
+
+// Round robin of the players, every pair plays Rounds pairs of games, each pair from the same random opening
+// with swapped sides
+type Tournament struct {
+	Game        Game
+	Players     []Player
+	Rounds      int
+	Concurrency int   // games played at once, at least one
+	Seed        int64 // of the random openings
+}
+
+// Wins, draws and losses of the first player against the second one
+type Score struct {
+	Wins, Draws, Losses int
+}
+
+func (score *Score) add(outcome Outcome, maximizing bool) {
+	if !maximizing {
+		outcome = -outcome
+	}
+	switch outcome {
+	case OutcomeMaximizingWin:
+		score.Wins++
+	case OutcomeDraw:
+		score.Draws++
+	default:
+		score.Losses++
+	}
+}
+
+func (score Score) Games() int {
+	return score.Wins + score.Draws + score.Losses
+}
+
+// Elo difference of the first player against the second one and its 95% confidence margin
+func (score Score) Elo() (float64, float64) {
+	return Elo(score.Wins, score.Draws, score.Losses)
+}
+
+type PairResult struct {
+	First, Second string
+	Score
+}
+
+type Standing struct {
+	Name string
+	Score
+}
+
+type Results struct {
+	Standings []Standing   // in the order of the players
+	Pairs     []PairResult // in the order of the played pairs
+	Games     []GameResult // in the order of the pairs and rounds
+}
+
+func (tournament *Tournament) Run() *Results {
+	type job struct {
+		first, second int
+		opening       Opening
+		swapped       bool
+		pair          int
+	}
+	var jobs []job
+	rnd := rand.New(rand.NewSource(tournament.Seed))
+	results := &Results{Standings: make([]Standing, len(tournament.Players))}
+	for i, player := range tournament.Players {
+		results.Standings[i].Name = player.Name
+		for j := i + 1; j < len(tournament.Players); j++ {
+			results.Pairs = append(results.Pairs, PairResult{First: player.Name, Second: tournament.Players[j].Name})
+			for round := 0; round < tournament.Rounds; round++ {
+				opening := tournament.Game.RandomOpening(rnd)
+				pair := len(results.Pairs) - 1
+				jobs = append(jobs, job{i, j, opening, false, pair}, job{i, j, opening, true, pair})
+			}
+		}
+	}
+	results.Games = make([]GameResult, len(jobs))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < max(1, tournament.Concurrency); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range queue {
+				job := jobs[index]
+				first, second := tournament.Players[job.first], tournament.Players[job.second]
+				if job.swapped {
+					first, second = second, first
+				}
+				results.Games[index] = tournament.Game.Play(first, second, job.opening)
+			}
+		}()
+	}
+	for index := range jobs {
+		queue <- index
+	}
+	close(queue)
+	wg.Wait()
+	for index, job := range jobs {
+		outcome := results.Games[index].Outcome
+		results.Pairs[job.pair].add(outcome, !job.swapped)
+		results.Standings[job.first].add(outcome, !job.swapped)
+		results.Standings[job.second].add(outcome, job.swapped)
+	}
+	return results
+}
+
+func (results *Results) String() string {
+	sb := strings.Builder{}
+	for _, pair := range results.Pairs {
+		elo, margin := pair.Elo()
+		fmt.Fprintf(&sb, "%s vs %s: +%d =%d -%d, Elo %+.1f ± %.1f\n", pair.First, pair.Second, pair.Wins, pair.Draws, pair.Losses, elo, margin)
+	}
+	return sb.String()
+}
+
+// Elo difference corresponding to the score of the games and its 95% confidence margin
+// Score without any loss, or without any win, has infinite difference and margin.
+func Elo(wins, draws, losses int) (float64, float64) {
+	games := float64(wins + draws + losses)
+	if games == 0 {
+		return 0, math.Inf(1)
+	}
+	score := (float64(wins) + float64(draws)/2) / games
+	if wins == 0 && draws == 0 || losses == 0 && draws == 0 {
+		return eloDiff(score), math.Inf(1)
+	}
+	variance := (float64(wins)*math.Pow(1-score, 2) + float64(draws)*math.Pow(0.5-score, 2) + float64(losses)*math.Pow(score, 2)) / games
+	deviation := math.Sqrt(variance / games)
+	margin := (eloDiff(score+1.96*deviation) - eloDiff(score-1.96*deviation)) / 2
+	return eloDiff(score), margin
+}
+
+// Elo difference of the player with the expected score
+func eloDiff(score float64) float64 {
+	if score <= 0 {
+		return math.Inf(-1)
+	}
+	if score >= 1 {
+		return math.Inf(1)
+	}
+	return -400 * math.Log10(1/score-1)
+}
+
+func childrenOf(node csa.SearchNode, maximizing bool) []csa.SearchNode {
+	var children []csa.SearchNode
+	generator := node.SearchNodeGenerator()
+	for child := generator(maximizing); child != nil; child = generator(maximizing) {
+		children = append(children, child)
+	}
+	return children
+}
+
+func nextPlayer(child csa.SearchNode, maximizing bool) bool {
+	if extraTurnNode, ok := child.(csa.ExtraTurnNode); ok && extraTurnNode.ExtraTurn() {
+		return maximizing
+	}
+	return !maximizing
+}
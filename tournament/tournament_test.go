@@ -0,0 +1,156 @@
+package tournament
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/connect4"
+	"github.com/stepulak/combinatorial-search-algoritms/internal/subtraction"
+)
+
+var takeGame = Game{
+	New:          func() csa.SearchNode { return subtraction.New(21) },
+	OpeningPlies: 2,
+}
+
+func TestElo(t *testing.T) {
+	if elo, margin := Elo(5, 10, 5); elo != 0 || margin <= 0 || math.IsInf(margin, 0) {
+		t.Errorf("Even score has Elo %f ± %f", elo, margin)
+	}
+	if elo, _ := Elo(3, 0, 1); math.Abs(elo-190.85) > 0.01 {
+		t.Errorf("Three of four games won gives %f", elo)
+	}
+	if elo, margin := Elo(4, 0, 0); !math.IsInf(elo, 1) || !math.IsInf(margin, 1) {
+		t.Errorf("All games won gives %f ± %f", elo, margin)
+	}
+}
+
+func TestTournament(t *testing.T) {
+	tournament := Tournament{
+		Game: takeGame,
+		Players: []Player{
+			{"perfect", []csa.EngineOption{csa.WithMaxDepth(21)}},
+			{"greedy", []csa.EngineOption{csa.WithMaxDepth(1)}},
+			{"perfect-too", []csa.EngineOption{csa.WithMaxDepth(21), csa.WithTT(1 << 10)}},
+		},
+		Rounds:      5,
+		Concurrency: 3,
+		Seed:        1,
+	}
+	results := tournament.Run()
+	if len(results.Games) != 30 || len(results.Pairs) != 3 {
+		t.Fatalf("Unexpected games %d and pairs %d", len(results.Games), len(results.Pairs))
+	}
+	for i, game := range results.Games {
+		if game.Outcome == OutcomeDraw || game.Opening == nil || game.Opening[0] != results.Games[i^1].Opening[0] {
+			t.Errorf("Unexpected game %+v", game)
+		}
+	}
+	// the perfect players win every position won for them, so they are even in a game pair
+	if pair := results.Pairs[1]; pair.Wins != pair.Losses || pair.Games() != 10 {
+		t.Errorf("Perfect players are not even, %+v", pair)
+	}
+	if pair := results.Pairs[0]; pair.Losses != 0 || pair.Wins <= 5 {
+		t.Errorf("Perfect player lost against greedy, %+v", pair)
+	}
+	if perfect, greedy := results.Standings[0], results.Standings[1]; perfect.Games() != 20 || perfect.Wins <= greedy.Wins || greedy.Losses < 10 {
+		t.Errorf("Unexpected standings %+v", results.Standings)
+	}
+	if !strings.Contains(results.String(), "perfect vs greedy: +") {
+		t.Errorf("Unexpected summary\n%s", results)
+	}
+}
+
+func TestSPRT(t *testing.T) {
+	perfect := Player{"perfect", []csa.EngineOption{csa.WithMaxDepth(21)}}
+	sprt := SPRT{
+		Game:        takeGame,
+		Candidate:   perfect,
+		Baseline:    Player{"greedy", []csa.EngineOption{csa.WithMaxDepth(1)}},
+		Elo0:        0,
+		Elo1:        100,
+		Concurrency: 2,
+		Seed:        1,
+	}
+	result := sprt.Run()
+	if result.Decision != SPRTAcceptH1 || result.LLR < result.Upper || result.Score.Games() != len(result.Games) {
+		t.Errorf("Perfect player not accepted as stronger, %+v", result)
+	}
+	sprt.Baseline = Player{"perfect-too", []csa.EngineOption{csa.WithMaxDepth(21), csa.WithTT(1 << 10)}}
+	result = sprt.Run()
+	if result.Decision != SPRTAcceptH0 || result.Score.Wins != result.Score.Losses {
+		t.Errorf("Equal player accepted as stronger, %s %+v", result.Decision, result.Score)
+	}
+	sprt.MaxPairs = 2
+	if result = sprt.Run(); result.Decision != SPRTInconclusive || result.Score.Games() != 4 {
+		t.Errorf("Expected inconclusive test after two pairs, %s %+v", result.Decision, result.Score)
+	}
+}
+
+func TestSPSA(t *testing.T) {
+	spsa := SPSA{
+		Game:   takeGame,
+		Params: []SPSAParam{{Name: "depth", Value: 2, Min: 1, Max: 21, C: 2, R: 0.5}},
+		Player: func(params []float64) Player {
+			return Player{Options: []csa.EngineOption{csa.WithMaxDepth(int(math.Round(params[0])))}}
+		},
+		Iterations: 40,
+		Seed:       1,
+	}
+	// deeper search is always better in the subtraction game
+	if values := spsa.Run(); values[0] < 8 {
+		t.Errorf("Depth tuned only to %f", values[0])
+	}
+}
+
+func TestMatch(t *testing.T) {
+	records := strings.Builder{}
+	match := Match{
+		Game: "connect4",
+		Players: [2]Player{
+			{"deep", []csa.EngineOption{csa.WithMaxDepth(6), csa.WithTT(1 << 14)}},
+			{"greedy", []csa.EngineOption{csa.WithMaxDepth(1)}},
+		},
+		Games:   4,
+		Event:   "test",
+		Records: &records,
+	}
+	result, err := match.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Games) != 4 || result.Losses != 0 || result.Wins == 0 {
+		t.Errorf("Unexpected result %+v", result.Score)
+	}
+	if game := result.Games[1]; game.Maximizing != "greedy" || game.Termination != "normal" || len(game.Moves) < 7 {
+		t.Errorf("Unexpected game %+v", game)
+	}
+	for _, text := range []string{`[Event "test"]`, `[Red "deep"]`, `[Yellow "greedy"]`, `[Round "4"]`, "1. ", "2. "} {
+		if !strings.Contains(records.String(), text) {
+			t.Errorf("Expected %s in the records\n%s", text, records.String())
+		}
+	}
+	if strings.Count(records.String(), "[Result ") != 4 {
+		t.Errorf("Expected 4 records\n%s", records.String())
+	}
+
+	// adjudications
+	match = Match{Game: "connect4", Moves: []string{"4"}, Players: match.Players, Games: 1, MaxPlies: 2}
+	if result, _ := match.Run(); result.Games[0].Adjudication != "max plies" || result.Draws != 1 || len(result.Games[0].Moves) != 2 {
+		t.Errorf("Expected the adjudicated draw, got %+v", result.Games[0])
+	}
+	match = Match{Game: "connect4", Players: match.Players, Games: 2, Adjudicator: csa.Adjudicator{DrawScore: connect4.WinScore / 2, DrawMoves: 4}}
+	if result, _ := match.Run(); result.Draws != 2 || result.Games[1].Adjudication != "draw" || len(result.Games[1].Moves) != 4 {
+		t.Errorf("Expected the adjudicated draws, got %+v", result)
+	}
+	match = Match{Game: "connect4", Players: match.Players, Games: 1, TimeControl: TimeControl{Base: time.Microsecond}}
+	if result, _ := match.Run(); result.Games[0].Termination != "time forfeit" || result.Losses != 1 {
+		t.Errorf("Expected the time forfeit, got %+v", result.Games[0])
+	}
+	if _, err := (&Match{Game: "unknown"}).Run(); err == nil {
+		t.Error("Expected the unknown game")
+	}
+}
@@ -0,0 +1,37 @@
+package csa
+
+import (
+	"context"
+)
+
+// Hook for tracing the engine's searches, e.g. an adapter of an OpenTelemetry tracer
+// The engine starts span "csa.BestMove" for every search and its child spans "csa.iteration"
+// for the depths of iterative deepening.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttribute(key string, value int64)
+	End()
+}
+
+// Tracer called by the engine's searches, the parent span of BestMoveContext is taken from its context
+func WithTracer(tracer Tracer) EngineOption {
+	return func(engine *Engine) {
+		engine.tracer = tracer
+	}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value int64) {}
+
+func (noopSpan) End() {}
+
+func (engine *Engine) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if engine.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return engine.tracer.Start(ctx, name)
+}
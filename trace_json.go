@@ -0,0 +1,130 @@
+package csa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errInvalidTrace = errors.New("csa: invalid search trace")
+
+// Search event as written by TraceWriter, one JSON object per line
+type TraceEvent struct {
+	Event string  `json:"event"`
+	Node  string  `json:"node"`
+	Hash  *uint64 `json:"hash,omitempty"` // of HashNode nodes
+	Ply   int     `json:"ply"`
+	Alpha int     `json:"alpha"`
+	Beta  int     `json:"beta"`
+	Score int     `json:"score"`
+}
+
+// Observer writing the search events as JSON lines, which can be replayed by ReplayTrace
+// Not safe for concurrent use, so it should observe sequential searches only.
+type TraceWriter struct {
+	encoder *json.Encoder
+	err     error
+}
+
+func NewTraceWriter(w io.Writer) *TraceWriter {
+	return &TraceWriter{encoder: json.NewEncoder(w)}
+}
+
+func (writer *TraceWriter) Observe(event SearchEvent) {
+	if writer.err != nil {
+		return
+	}
+	traceEvent := TraceEvent{
+		Event: event.Kind.String(),
+		Node:  fmt.Sprint(event.Node),
+		Ply:   event.Ply,
+		Alpha: event.Alpha,
+		Beta:  event.Beta,
+		Score: event.Score,
+	}
+	if hashNode, ok := event.Node.(HashNode); ok {
+		hash := hashNode.Hash()
+		traceEvent.Hash = &hash
+	}
+	writer.err = writer.encoder.Encode(traceEvent)
+}
+
+// First error of writing the trace
+func (writer *TraceWriter) Err() error {
+	return writer.err
+}
+
+// Node of the search tree reconstructed from the trace
+type ReplayedNode struct {
+	TraceEvent // of entering the node, with the final score
+	Leaf       bool
+	Cutoff     bool // remaining children were prunned
+	Children   []*ReplayedNode
+	Best       *ReplayedNode   // best child, nil for leaves
+	BestSoFar  []*ReplayedNode // children in the order they became the best one
+}
+
+// Line of the best children starting with the node's best child
+func (node *ReplayedNode) PV() []*ReplayedNode {
+	var line []*ReplayedNode
+	for child := node.Best; child != nil; child = child.Best {
+		line = append(line, child)
+	}
+	return line
+}
+
+// Reconstructs the searched tree from the trace written by TraceWriter, returns its root
+func ReplayTrace(r io.Reader) (*ReplayedNode, error) {
+	decoder := json.NewDecoder(r)
+	var root, lastChild *ReplayedNode
+	var stack []*ReplayedNode
+	for {
+		var event TraceEvent
+		if err := decoder.Decode(&event); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		var top *ReplayedNode
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+		switch event.Event {
+		case EventEnter.String():
+			node := &ReplayedNode{TraceEvent: event}
+			if top != nil {
+				top.Children = append(top.Children, node)
+			} else if root != nil {
+				return nil, fmt.Errorf("%w: second root %s", errInvalidTrace, event.Node)
+			} else {
+				root = node
+			}
+			stack = append(stack, node)
+		case EventBest.String(), EventCutoff.String():
+			if top == nil {
+				return nil, fmt.Errorf("%w: %s outside of any node", errInvalidTrace, event.Event)
+			}
+			if event.Event == EventCutoff.String() {
+				top.Cutoff = true
+			} else if lastChild != nil {
+				top.Best = lastChild
+				top.BestSoFar = append(top.BestSoFar, lastChild)
+			}
+		case EventLeaf.String(), EventLeave.String():
+			if top == nil {
+				return nil, fmt.Errorf("%w: %s outside of any node", errInvalidTrace, event.Event)
+			}
+			top.Score = event.Score
+			top.Leaf = event.Event == EventLeaf.String()
+			stack = stack[:len(stack)-1]
+			lastChild = top
+		default:
+			return nil, fmt.Errorf("%w: unknown event %s", errInvalidTrace, event.Event)
+		}
+	}
+	if root == nil || len(stack) > 0 {
+		return nil, fmt.Errorf("%w: incomplete search", errInvalidTrace)
+	}
+	return root, nil
+}
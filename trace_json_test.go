@@ -0,0 +1,32 @@
+package csa
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTTTTraceReplay(t *testing.T) {
+	node := tttNode{}
+	node.board = [3][3]int{{cross, circle, cross}, {circle, cross, empty}, {empty, empty, circle}}
+	var trace bytes.Buffer
+	writer := NewTraceWriter(&trace)
+	bestNode, score := Searcher{Observer: writer}.MinimaxAlphaBetaPrunning(node, 9, true)
+	if writer.Err() != nil {
+		t.Fatal(writer.Err())
+	}
+	root, err := ReplayTrace(&trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.Score != score || root.Best == nil || root.Best.Node != fmt.Sprint(bestNode) {
+		t.Errorf("Replayed %v %d, searched %v %d", root.Best, root.Score, bestNode, score)
+	}
+	if root.BestSoFar[len(root.BestSoFar)-1] != root.Best || len(root.PV()) == 0 {
+		t.Error("Invalid replayed best children")
+	}
+	if _, err := ReplayTrace(strings.NewReader(`{"event":"enter","node":"x"}`)); err == nil {
+		t.Error("Expected error of incomplete trace")
+	}
+}
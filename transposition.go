@@ -0,0 +1,140 @@
+package csa
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+type ttBound uint8
+
+const (
+	ttExact ttBound = iota + 1
+	ttLower         // score is at least the stored one
+	ttUpper         // score is at most the stored one
+)
+
+type ttEntry struct {
+	key   uint64
+	depth int
+	score int
+	bound ttBound
+}
+
+// Fixed size transposition table of alpha-beta results keyed by node's hash and the player to move,
+// safe for concurrent use
+// The newer entry always replaces the older one with the same slot.
+type transpositionTable struct {
+	mutex   sync.Mutex
+	entries []ttEntry
+	probes  atomic.Int64
+	hits    atomic.Int64
+}
+
+func newTranspositionTable(size int) *transpositionTable {
+	if size <= 0 {
+		return nil
+	}
+	return &transpositionTable{entries: make([]ttEntry, size)}
+}
+
+// Key of the node with given player to move, false if the node cannot be hashed
+func (tt *transpositionTable) key(node SearchNode, maximizing bool) (uint64, bool) {
+	if tt == nil {
+		return 0, false
+	}
+	hash, ok := canonicalHash(node)
+	if maximizing {
+		// any odd constant, so the same position with the other player to move has different key
+		hash ^= 0x9e3779b97f4a7c15
+	}
+	return hash, ok
+}
+
+// Stored score of the node searched at least to given depth, if it decides the alpha-beta window
+// Returns also whether the stored search reached all the leaves, i.e. it was not cut by the depth.
+func (tt *transpositionTable) probe(key uint64, depth, alpha, beta int) (int, bool, bool) {
+	tt.probes.Add(1)
+	tt.mutex.Lock()
+	entry := tt.entries[key%uint64(len(tt.entries))]
+	tt.mutex.Unlock()
+	if entry.bound == 0 || entry.key != key || entry.depth < depth {
+		return 0, false, false
+	}
+	switch {
+	case entry.bound == ttExact,
+		entry.bound == ttLower && entry.score >= beta,
+		entry.bound == ttUpper && entry.score <= alpha:
+		tt.hits.Add(1)
+		return entry.score, entry.depth == math.MaxInt, true
+	}
+	return 0, false, false
+}
+
+func (tt *transpositionTable) store(key uint64, depth, score int, bound ttBound) {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+	tt.entries[key%uint64(len(tt.entries))] = ttEntry{key, depth, score, bound}
+}
+
+func (tt *transpositionTable) clear() {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+	clear(tt.entries)
+}
+
+// Stored score of the node if it decides the alpha-beta window, the root is always searched
+func (s *Searcher) probeTT(key uint64, hashed bool, depth, ply, alpha, beta int) (int, bool) {
+	if !hashed || ply == 0 {
+		return 0, false
+	}
+	score, complete, found := s.tt.probe(key, depth, alpha, beta)
+	if found && !complete {
+		s.limits.horizon.Add(1)
+	}
+	return s.ttScoreOut(score, ply), found
+}
+
+// Stores the result of the node searched with the given alpha-beta window unless the search was stopped
+// Score outside of the window (or missing best child) only means that the value is beyond the window,
+// e.g. the failed children return the initial score, so the window itself is stored as the bound.
+// Horizon is the number of cut or repeated leaves before the node was searched.
+func (s *Searcher) storeTT(key uint64, hashed bool, horizon int64, depth, ply, alpha, beta int, maximizing, hasBest bool, score int) {
+	if !hashed || s.limits.isStopped() {
+		return
+	}
+	if s.limits.horizonCount() == horizon {
+		// no leaf was cut by the depth nor drawn by repetition, the result holds for any depth and path
+		depth = math.MaxInt
+	}
+	bound := ttExact
+	switch {
+	case (!hasBest && maximizing) || (hasBest && score <= alpha):
+		score, bound = alpha, ttUpper
+	case !hasBest || score >= beta:
+		score, bound = beta, ttLower
+	}
+	s.tt.store(key, depth, s.ttScoreIn(score, ply), bound)
+}
+
+// Win scores in the table are relative to the stored node, not to the root, so they can be reused in other plies
+// Scores above WinScore/2 are considered to be distance adjusted wins.
+func (s *Searcher) ttScoreIn(score, ply int) int {
+	if s.WinScore > 0 && score >= s.WinScore/2 && score != math.MaxInt {
+		return score + ply
+	}
+	if s.WinScore > 0 && score <= -s.WinScore/2 && score != math.MinInt {
+		return score - ply
+	}
+	return score
+}
+
+func (s *Searcher) ttScoreOut(score, ply int) int {
+	if s.WinScore > 0 && score >= s.WinScore/2 && score != math.MaxInt {
+		return score - ply
+	}
+	if s.WinScore > 0 && score <= -s.WinScore/2 && score != math.MinInt {
+		return score + ply
+	}
+	return score
+}
@@ -0,0 +1,77 @@
+package csa
+
+import "testing"
+
+// Position of a small game given by its graph, each position knows its player and may be reached by several paths
+type graphNode struct {
+	graph *gameGraph
+	name  string
+}
+
+type gameGraph struct {
+	moves   map[string][]string
+	scores  map[string]int // of the terminal positions
+	players map[string]Player
+}
+
+func (node graphNode) Score() int {
+	return node.graph.scores[node.name]
+}
+
+func (node graphNode) IsTerminal() bool {
+	return len(node.graph.moves[node.name]) == 0
+}
+
+func (node graphNode) PlayerToMove() Player {
+	return node.graph.players[node.name]
+}
+
+func (node graphNode) Hash() uint64 {
+	hash := uint64(0)
+	for _, c := range node.name {
+		hash = hash*31 + uint64(c)
+	}
+	return hash
+}
+
+func (node graphNode) RepetitionKey() uint64 {
+	return node.Hash()
+}
+
+func (node graphNode) SearchNodeGenerator() SearchNodeGenerator {
+	moves := node.graph.moves[node.name]
+	return func(maximizing bool) SearchNode {
+		if len(moves) == 0 {
+			return nil
+		}
+		child := graphNode{node.graph, moves[0]}
+		moves = moves[1:]
+		return child
+	}
+}
+
+func TestTTRepetitionNotComplete(t *testing.T) {
+	graph := &gameGraph{
+		moves: map[string][]string{
+			"root": {"a", "win"},
+			"a":    {"n"},
+			"b":    {"n"},
+			"n":    {"root", "loss"},
+		},
+		scores: map[string]int{"win": 9, "loss": -5},
+		players: map[string]Player{
+			"root": MaximizingPlayer,
+			"a":    MinimizingPlayer,
+			"b":    MinimizingPlayer,
+			"n":    MaximizingPlayer,
+		},
+	}
+	engine := NewEngine(WithMaxDepth(10), WithTT(1<<10))
+	if _, score := engine.BestMove(graphNode{graph, "root"}, true); score != 9 {
+		t.Errorf("Expected the win, got %d", score)
+	}
+	// n was drawn by the repetition of the root, which is not on the path from b
+	if _, score := engine.BestMove(graphNode{graph, "b"}, false); score != 9 {
+		t.Errorf("Expected the win through the root, got %d", score)
+	}
+}
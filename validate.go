@@ -0,0 +1,147 @@
+package csa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind of broken SearchNode contract found by ValidateNode
+type ViolationKind int
+
+const (
+	// Generator did not return nil after maxValidatedChildren children
+	ViolationGenerator ViolationKind = iota
+	// Terminal node generates children, the searches never expand it, but other tools may
+	ViolationTerminalChildren
+	// Repeated Score or IsTerminal call of the same node returns different result
+	ViolationScore
+	// Score differs from the parent's score plus IncrementalNode's ScoreDelta
+	ViolationScoreDelta
+	// Repeated generation returns different children or in different order
+	ViolationOrder
+	// Node's method panicked
+	ViolationPanic
+)
+
+var violationKindNames = []string{"generator", "terminal-children", "score", "score-delta", "order", "panic"}
+
+func (kind ViolationKind) String() string {
+	if int(kind) < len(violationKindNames) {
+		return violationKindNames[kind]
+	}
+	return "unknown"
+}
+
+// Max number of children of a single node, more children are considered a generator which does not terminate
+const maxValidatedChildren = 1 << 16
+
+// Broken contract of the node at the end of the line from the validated root
+type Violation struct {
+	Kind    ViolationKind
+	Line    []SearchNode // children from the root to the broken node, empty for the root itself
+	Message string
+}
+
+func (violation Violation) Error() string {
+	line := make([]string, len(violation.Line))
+	for i, node := range violation.Line {
+		if move := MoveOf(node); move != nil {
+			line[i] = fmt.Sprint(move)
+		} else {
+			line[i] = fmt.Sprintf("%q", fmt.Sprint(node))
+		}
+	}
+	return fmt.Sprintf("%s after [%s]: %s", violation.Kind, strings.Join(line, " "), violation.Message)
+}
+
+// Checks the invariants of the node and its descendants up to the given depth, the maximizing player is to move
+// in the root unless it is a PlayerNode. Every node is generated twice, so it is meant for small depths only.
+func ValidateNode(node SearchNode, depth int, maximizing bool) []Violation {
+	validator := &nodeValidator{}
+	validator.validate(node, nil, depth, playerToMove(node, maximizing))
+	return validator.violations
+}
+
+type nodeValidator struct {
+	line       []SearchNode
+	violations []Violation
+}
+
+func (validator *nodeValidator) report(kind ViolationKind, format string, args ...any) {
+	line := append([]SearchNode(nil), validator.line...)
+	validator.violations = append(validator.violations, Violation{kind, line, fmt.Sprintf(format, args...)})
+}
+
+func (validator *nodeValidator) validate(node, parent SearchNode, depth int, maximizing bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			validator.report(ViolationPanic, "%v", r)
+		}
+	}()
+	score, terminal := node.Score(), node.IsTerminal()
+	if node.Score() != score || node.IsTerminal() != terminal {
+		validator.report(ViolationScore, "repeated calls return different score or terminal state")
+	}
+	if incNode, ok := node.(IncrementalNode); ok && parent != nil {
+		if delta := incNode.ScoreDelta(parent); parent.Score()+delta != score {
+			validator.report(ViolationScoreDelta, "parent's score %d plus delta %d is not the score %d", parent.Score(), delta, score)
+		}
+	}
+	children, ok := validator.children(node, maximizing)
+	if !ok {
+		return
+	}
+	if terminal {
+		if len(children) > 0 {
+			validator.report(ViolationTerminalChildren, "terminal node generates %d children", len(children))
+		}
+		return
+	}
+	if again, ok := validator.children(node, maximizing); ok && !sameChildren(children, again) {
+		validator.report(ViolationOrder, "repeated generation returns different children")
+	}
+	if depth <= 0 {
+		return
+	}
+	for _, childNode := range children {
+		validator.line = append(validator.line, childNode)
+		validator.validate(childNode, node, depth-1, playerToMove(childNode, nextPlayer(childNode, maximizing)))
+		validator.line = validator.line[:len(validator.line)-1]
+	}
+}
+
+// All the node's children, false if the generator does not terminate
+func (validator *nodeValidator) children(node SearchNode, maximizing bool) ([]SearchNode, bool) {
+	var children []SearchNode
+	for generator := allChildrenGenerator(node); ; {
+		childNode := generator(maximizing)
+		if childNode == nil {
+			return children, true
+		}
+		if len(children) == maxValidatedChildren {
+			validator.report(ViolationGenerator, "generator does not stop after %d children", maxValidatedChildren)
+			return nil, false
+		}
+		children = append(children, childNode)
+	}
+}
+
+func sameChildren(children, others []SearchNode) bool {
+	if len(children) != len(others) {
+		return false
+	}
+	for i := range children {
+		if nodeIdentity(children[i]) != nodeIdentity(others[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash of HashNode, textual representation otherwise
+func nodeIdentity(node SearchNode) string {
+	if hashNode, ok := node.(HashNode); ok {
+		return fmt.Sprint(hashNode.Hash())
+	}
+	return fmt.Sprintf("%#v", node)
+}
@@ -0,0 +1,53 @@
+package csa
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Node breaking the contract: unstable score and children generated in random order
+type tttBrokenNode struct {
+	tttNode
+	calls *int
+}
+
+func (node tttBrokenNode) Score() int {
+	*node.calls++
+	return *node.calls
+}
+
+func (node tttBrokenNode) SearchNodeGenerator() SearchNodeGenerator {
+	var children []SearchNode
+	generator := node.tttNode.SearchNodeGenerator()
+	for child := generator(true); child != nil; child = generator(true) {
+		children = append(children, child)
+	}
+	rand.Shuffle(len(children), func(i, j int) { children[i], children[j] = children[j], children[i] })
+	return func(maximizing bool) SearchNode {
+		if len(children) == 0 {
+			return nil
+		}
+		child := children[0]
+		children = children[1:]
+		return child
+	}
+}
+
+func TestTTTValidateNode(t *testing.T) {
+	violations := ValidateNode(tttNode{}, 6, true)
+	if len(violations) == 0 {
+		t.Error("Won tic-tac-toe nodes still generate children")
+	}
+	for _, violation := range violations {
+		if violation.Kind != ViolationTerminalChildren || len(violation.Line) < 5 {
+			t.Errorf("Unexpected violation %v", violation)
+		}
+	}
+	kinds := map[ViolationKind]bool{}
+	for _, violation := range ValidateNode(tttBrokenNode{calls: new(int)}, 0, true) {
+		kinds[violation.Kind] = true
+	}
+	if !kinds[ViolationScore] || !kinds[ViolationOrder] {
+		t.Errorf("Expected score and order violations, got %v", kinds)
+	}
+}
@@ -0,0 +1,23 @@
+package csa
+
+import (
+	"math"
+)
+
+// Logistic model of maximizing player's win probability p = 1 / (1 + 10^(-score/scale))
+// Scale is the score advantage giving 10:1 odds, e.g. 400 for centipawn-like scores.
+func WinProbability(score int, scale float64) float64 {
+	return 1 / (1 + math.Pow(10, -float64(score)/scale))
+}
+
+// Inverse of WinProbability, certain win or loss gives MinimaxInitScore of the losing side
+func ScoreFromWinProbability(probability, scale float64) int {
+	if probability <= 0 {
+		return math.MinInt
+	}
+	if probability >= 1 {
+		return math.MaxInt
+	}
+	score := -scale * math.Log10(1/probability-1)
+	return int(math.Round(score))
+}
@@ -0,0 +1,26 @@
+package csa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWinProbability(t *testing.T) {
+	if WinProbability(0, 400) != 0.5 {
+		t.Error("Zero score must be even")
+	}
+	if p := WinProbability(400, 400); math.Abs(p-10.0/11) > 1e-9 {
+		t.Errorf("Scale must give 10:1 odds, got %f", p)
+	}
+	if WinProbability(-200, 400)+WinProbability(200, 400) != 1 {
+		t.Error("Probabilities must be symmetric")
+	}
+	for _, score := range []int{-1000, -57, 0, 1, 350} {
+		if s := ScoreFromWinProbability(WinProbability(score, 400), 400); s != score {
+			t.Errorf("Conversion is not inverse, %d != %d", s, score)
+		}
+	}
+	if ScoreFromWinProbability(0, 400) != math.MinInt || ScoreFromWinProbability(1, 400) != math.MaxInt {
+		t.Error("Certain result must give extreme score")
+	}
+}
@@ -0,0 +1,315 @@
+// Package xboard implements the XBoard/CECP protocol (version 2) for the engines of any csa game, e.g. over stdin and stdout.
+package xboard
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+)
+
+// Game played over the protocol, e.g. chess.Protocol
+type Game interface {
+	// Initial position and whether the maximizing player moves first
+	Start() (csa.SearchNode, bool)
+	// Position of the setboard command and whether the maximizing player moves
+	Position(setup string) (csa.SearchNode, bool, error)
+	// Child after the move in the notation of the game, error if the move is illegal
+	Play(node csa.SearchNode, maximizing bool, move string) (csa.SearchNode, error)
+	// Move from the node to the child in the notation of the game
+	Notation(node, child csa.SearchNode) string
+	// Result of the terminal position, e.g. "1-0 {White mates}"
+	Result(node csa.SearchNode, maximizing bool) string
+}
+
+// Engine speaking the XBoard protocol, the GUI sends the moves with the usermove prefix or without it.
+// The engine thinks about the moves of one side, it plays the second player after the new command.
+// Pondering and the analyze mode are not supported.
+type Engine struct {
+	Name    string
+	Variant string             // of the features, e.g. "normal" for chess, omitted if empty
+	Game    Game               // e.g. chess.Protocol{}
+	Options []csa.EngineOption // of the engine, the depth and time limits are set by the sd, st, level and time commands
+
+	writer     *bufio.Writer
+	mutex      sync.Mutex // of the writer
+	state      sync.Mutex // of the game played by the running search
+	history    []csa.SearchNode
+	maximizing bool // player to move
+	engineSide bool // player played by the engine
+	force      bool // the engine plays neither side
+	post       bool // thinking output
+	over       bool // the game has ended
+	depth      int
+	moveTime   time.Duration
+	increment  time.Duration
+	movesPer   int // moves per the time control, zero means the whole game
+	remaining  time.Duration
+	engine     *csa.Engine
+	cancel     context.CancelFunc
+	discard    bool          // the running search does not play its move
+	done       chan struct{} // closed by the finished search
+}
+
+// Serves the commands until quit or the end of the input
+func (xb *Engine) Run(r io.Reader, w io.Writer) error {
+	xb.writer = bufio.NewWriter(w)
+	xb.reset()
+	defer xb.abort()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		command, args, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		args = strings.TrimSpace(args)
+		switch command {
+		case "protover":
+			xb.features()
+		case "new":
+			xb.abort()
+			xb.reset()
+			if xb.engine != nil {
+				xb.engine.Clear()
+			}
+		case "force":
+			xb.abort()
+			xb.force = true
+		case "go":
+			xb.abort()
+			xb.force, xb.engineSide = false, xb.maximizing
+			xb.think()
+		case "playother":
+			xb.abort()
+			xb.force, xb.engineSide = false, !xb.maximizing
+		case "?":
+			xb.stop()
+		case "ping":
+			// the pong follows the move of the running search
+			if xb.cancel != nil {
+				<-xb.done
+			}
+			xb.send("pong %s", args)
+		case "setboard":
+			xb.abort()
+			node, maximizing, err := xb.Game.Position(args)
+			if err != nil {
+				xb.send("tellusererror Illegal position: %v", err)
+				continue
+			}
+			xb.history, xb.maximizing, xb.over = []csa.SearchNode{node}, maximizing, node.IsTerminal()
+		case "usermove":
+			xb.abort()
+			xb.userMove(args)
+		case "undo", "remove":
+			xb.abort()
+			plies := 1
+			if command == "remove" {
+				plies = 2
+			}
+			for ; plies > 0 && len(xb.history) > 1; plies-- {
+				xb.history, xb.maximizing, xb.over = xb.history[:len(xb.history)-1], !xb.maximizing, false
+			}
+		case "sd":
+			xb.abort()
+			xb.depth, _ = strconv.Atoi(args)
+			xb.closeEngine()
+		case "st":
+			seconds, _ := strconv.ParseFloat(args, 64)
+			xb.moveTime = time.Duration(seconds * float64(time.Second))
+		case "level":
+			xb.setLevel(args)
+		case "time":
+			centiseconds, _ := strconv.Atoi(args)
+			xb.remaining = time.Duration(centiseconds) * 10 * time.Millisecond
+		case "post", "nopost":
+			xb.state.Lock()
+			xb.post = command == "post"
+			xb.state.Unlock()
+		case "result":
+			xb.abort()
+			xb.over = true
+		case "quit":
+			return nil
+		case "", "xboard", "accepted", "rejected", "otim", "hard", "easy", "random", "computer", "name", "rating", "ics":
+		default:
+			// the GUIs not accepting the usermove feature send the bare moves
+			xb.state.Lock()
+			_, err := xb.Game.Play(xb.node(), xb.maximizing, command)
+			xb.state.Unlock()
+			if err == nil && args == "" {
+				xb.abort()
+				xb.userMove(command)
+				continue
+			}
+			xb.send("Error (unknown command): %s", command)
+		}
+	}
+	return scanner.Err()
+}
+
+func (xb *Engine) send(format string, args ...any) {
+	xb.mutex.Lock()
+	defer xb.mutex.Unlock()
+	fmt.Fprintf(xb.writer, format+"\n", args...)
+	xb.writer.Flush()
+}
+
+func (xb *Engine) features() {
+	variants := ""
+	if xb.Variant != "" {
+		variants = fmt.Sprintf(" variants=%q", xb.Variant)
+	}
+	xb.send("feature myname=%q ping=1 setboard=1 usermove=1 playother=1 sigint=0 sigterm=0 colors=0 analyze=0 reuse=1%s done=1",
+		xb.Name, variants)
+}
+
+// Initial position without the depth limit, the engine plays the second player
+func (xb *Engine) reset() {
+	node, maximizing := xb.Game.Start()
+	xb.history, xb.maximizing, xb.over = []csa.SearchNode{node}, maximizing, false
+	xb.force, xb.engineSide = false, !maximizing
+	if xb.depth != 0 {
+		xb.depth = 0
+		xb.closeEngine()
+	}
+}
+
+func (xb *Engine) node() csa.SearchNode {
+	return xb.history[len(xb.history)-1]
+}
+
+// Plays the move of the GUI and answers it if it is engine's turn
+func (xb *Engine) userMove(move string) {
+	if xb.over {
+		xb.send("Illegal move (game over): %s", move)
+		return
+	}
+	child, err := xb.Game.Play(xb.node(), xb.maximizing, move)
+	if err != nil {
+		xb.send("Illegal move: %s", move)
+		return
+	}
+	xb.play(child)
+	xb.think()
+}
+
+// Appends the child to the game and reports its result
+func (xb *Engine) play(child csa.SearchNode) {
+	xb.history, xb.maximizing = append(xb.history, child), !xb.maximizing
+	if child.IsTerminal() {
+		xb.over = true
+		xb.send("%s", xb.Game.Result(child, xb.maximizing))
+	}
+}
+
+// Parses "level MPS BASE INC", the base time is in minutes or minutes:seconds
+func (xb *Engine) setLevel(args string) {
+	fields := strings.Fields(args)
+	if len(fields) != 3 {
+		xb.send("Error (invalid level): %s", args)
+		return
+	}
+	xb.movesPer, _ = strconv.Atoi(fields[0])
+	minutes, seconds, _ := strings.Cut(fields[1], ":")
+	base, _ := strconv.ParseFloat(minutes, 64)
+	extra, _ := strconv.ParseFloat(seconds, 64)
+	increment, _ := strconv.ParseFloat(fields[2], 64)
+	xb.remaining = time.Duration((base*60 + extra) * float64(time.Second))
+	xb.increment = time.Duration(increment * float64(time.Second))
+}
+
+// Clock of the engine's next move
+func (xb *Engine) clock() csa.Clock {
+	clock := csa.Clock{MoveTime: xb.moveTime, Remaining: xb.remaining, Increment: xb.increment}
+	if xb.movesPer > 0 {
+		played := (len(xb.history) - 1) / 2
+		clock.MovesToGo = xb.movesPer - played%xb.movesPer
+	}
+	return clock
+}
+
+// Starts the search if the engine is to move, the search plays its move unless aborted
+func (xb *Engine) think() {
+	if xb.force || xb.over || xb.maximizing != xb.engineSide {
+		return
+	}
+	if xb.engine == nil {
+		options := append([]csa.EngineOption{}, xb.Options...)
+		xb.engine = csa.NewEngine(append(options, csa.WithMaxDepth(xb.depth), csa.WithInfo(xb.info))...)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if soft, _ := (csa.TimeManager{}).Allocate(xb.clock()); soft > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), soft)
+	}
+	xb.cancel, xb.discard, xb.done = cancel, false, make(chan struct{})
+	node, maximizing, done := xb.node(), xb.maximizing, xb.done
+	go func() {
+		defer close(done)
+		child, _ := xb.engine.BestMoveContext(ctx, node, maximizing)
+		// the extra turns of the player belong to the same move
+		for next := child; next != nil; {
+			extra, ok := next.(csa.ExtraTurnNode)
+			if !ok || !extra.ExtraTurn() || next.IsTerminal() {
+				break
+			}
+			if next, _ = xb.engine.BestMoveContext(ctx, next, maximizing); next != nil {
+				child = next
+			}
+		}
+		xb.state.Lock()
+		defer xb.state.Unlock()
+		if child == nil || xb.discard {
+			return
+		}
+		xb.send("move %s", xb.Game.Notation(node, child))
+		xb.play(child)
+	}()
+}
+
+// Thinking output of the post mode, the score is from engine's point of view
+func (xb *Engine) info(info csa.SearchInfo) {
+	xb.state.Lock()
+	defer xb.state.Unlock()
+	if !xb.post {
+		return
+	}
+	score := info.Score
+	if !xb.engineSide {
+		score = -score
+	}
+	var pv []string
+	parent := xb.node()
+	for _, child := range info.PV {
+		pv = append(pv, xb.Game.Notation(parent, child))
+		parent = child
+	}
+	xb.send("%d %d %d %d %s", info.Depth, score, info.Time.Milliseconds()/10, info.Nodes, strings.Join(pv, " "))
+}
+
+// Stops the running search and waits for its move
+func (xb *Engine) stop() {
+	if xb.cancel != nil {
+		xb.cancel()
+		<-xb.done
+		xb.cancel = nil
+	}
+}
+
+// Stops the running search without playing its move
+func (xb *Engine) abort() {
+	xb.state.Lock()
+	xb.discard = true
+	xb.state.Unlock()
+	xb.stop()
+}
+
+func (xb *Engine) closeEngine() {
+	if xb.engine != nil {
+		xb.engine.Close()
+		xb.engine = nil
+	}
+}
@@ -0,0 +1,70 @@
+package xboard
+
+import (
+	"strings"
+	"testing"
+
+	csa "github.com/stepulak/combinatorial-search-algoritms"
+	"github.com/stepulak/combinatorial-search-algoritms/games/chess"
+)
+
+func run(t *testing.T, lines ...string) (*Engine, []string) {
+	xb := &Engine{Name: "csa engine", Variant: "normal", Game: chess.Protocol{}, Options: []csa.EngineOption{csa.WithTT(1 << 12)}}
+	var out strings.Builder
+	if err := xb.Run(strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatal(err)
+	}
+	return xb, strings.Split(strings.TrimSpace(out.String()), "\n")
+}
+
+func TestGame(t *testing.T) {
+	xb, lines := run(t, "xboard", "protover 2", "accepted usermove", "new", "sd 2", "post", "usermove e2e4", "ping 1", "quit")
+	if lines[0] != `feature myname="csa engine" ping=1 setboard=1 usermove=1 playother=1 sigint=0 sigterm=0 colors=0 analyze=0 reuse=1 variants="normal" done=1` {
+		t.Errorf("Invalid features %s", lines[0])
+	}
+	// thinking output of both depths, the move and the pong after it
+	if len(lines) != 5 || !strings.HasPrefix(lines[1], "1 ") || !strings.HasPrefix(lines[2], "2 ") ||
+		!strings.HasPrefix(lines[3], "move ") || lines[4] != "pong 1" {
+		t.Fatalf("Invalid output\n%s", strings.Join(lines, "\n"))
+	}
+	if len(xb.history) != 3 || !xb.maximizing {
+		t.Errorf("Expected white to move after two plies, got %d", len(xb.history))
+	}
+	// the move in the reply is legal
+	if _, err := (chess.Protocol{}).Play(xb.history[1], false, strings.TrimPrefix(lines[3], "move ")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	xb, lines := run(t, "new", "force", "e2e4", "usermove e7e4", "usermove e7e5", "remove", "undo", "foo", "ping 2")
+	expected := []string{"Illegal move: e7e4", "Error (unknown command): foo", "pong 2"}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Invalid output\n%s", strings.Join(lines, "\n"))
+	}
+	if len(xb.history) != 1 || !xb.maximizing {
+		t.Error("Expected the initial position")
+	}
+	// the engine plays white and mates in one
+	_, lines = run(t, "new", "sd 3", "setboard 6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", "go", "ping 3")
+	if strings.Join(lines, "\n") != "move a1a8\n1-0 {White mates}\npong 3" {
+		t.Errorf("Invalid output\n%s", strings.Join(lines, "\n"))
+	}
+	_, lines = run(t, "setboard 8/8/8/8 w - -", "level 40 0:30 0", "st 1", "playother", "usermove a2a3")
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "tellusererror Illegal position") {
+		t.Errorf("Invalid output\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestClock(t *testing.T) {
+	xb := &Engine{Game: chess.Protocol{}}
+	xb.reset()
+	xb.setLevel("40 1:30 2")
+	if clock := xb.clock(); clock.Remaining.Seconds() != 90 || clock.Increment.Seconds() != 2 || clock.MovesToGo != 40 {
+		t.Errorf("Invalid clock %+v", clock)
+	}
+	xb.history = make([]csa.SearchNode, 82)
+	if clock := xb.clock(); clock.MovesToGo != 40-(81/2)%40 {
+		t.Errorf("Invalid moves to go %d", clock.MovesToGo)
+	}
+}